@@ -26,10 +26,10 @@ type MongoService struct {
 }
 
 type PaginatedResult struct {
-	Page       int64       `json:"page"`
-	Limit      int64       `json:"limit"`
-	Total      int64       `json:"total"`
-	perPage    int64       `json:"per_page"`
+	Page       int64 `json:"page"`
+	Limit      int64 `json:"limit"`
+	Total      int64 `json:"total"`
+	perPage    int64
 	TotalPages int64       `json:"total_pages"`
 	Data       interface{} `json:"data"`
 }
@@ -76,6 +76,11 @@ func InitMongoService() (*MongoService, error) {
 	return Mongo, err
 }
 
+// Close memutus koneksi MongoDB
+func (m *MongoService) Close(ctx context.Context) error {
+	return m.Client.Disconnect(ctx)
+}
+
 // =========================
 // Generic CRUD Methods
 // =========================
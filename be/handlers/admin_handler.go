@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/db"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// AdminHandler handles administration that users may not do themselves,
+// such as assigning organisations. Devices are shared with the members of
+// their organisation, so only an admin may put users and devices in one.
+type AdminHandler struct {
+	mongo   *db.MongoService
+	devices ports.DeviceRepository
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(mongo *db.MongoService, devices ports.DeviceRepository) *AdminHandler {
+	return &AdminHandler{
+		mongo:   mongo,
+		devices: devices,
+	}
+}
+
+// organizationRequest is the body of an organisation assignment; empty removes it
+type organizationRequest struct {
+	Organization string `json:"organization"`
+}
+
+// SetUserOrganization handles PUT /admin/users/:username/organization - Put a user in an organisation
+//
+// The organisation is part of the user's next login token.
+func (h *AdminHandler) SetUserOrganization(c *gin.Context) {
+	var req organizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	username := c.Param("username")
+	organization := strings.TrimSpace(req.Organization)
+
+	update := bson.M{"$set": bson.M{"organization": organization}}
+	if organization == "" {
+		update = bson.M{"$unset": bson.M{"organization": ""}}
+	}
+
+	result, err := h.mongo.Database.Collection("users").UpdateOne(c.Request.Context(), bson.M{"username": username}, update)
+	if err != nil {
+		handleError(c, apperrors.NewDatabaseError("Failed to update user", err))
+		return
+	}
+	if result.MatchedCount == 0 {
+		handleError(c, apperrors.NewNotFoundError("User"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User organization updated",
+		"data":    gin.H{"username": username, "organization": organization},
+	})
+}
+
+// SetDeviceOrganization handles PUT /admin/devices/:name/organization - Share a device with an organisation
+func (h *AdminHandler) SetDeviceOrganization(c *gin.Context) {
+	var req organizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	device, err := h.devices.FindByName(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	device.Organization = strings.TrimSpace(req.Organization)
+	if err := h.devices.Update(c.Request.Context(), device); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device organization updated",
+		"data":    deviceResponse(device),
+	})
+}
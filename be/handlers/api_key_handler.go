@@ -37,7 +37,7 @@ func (h *APIKeyHandler) GenerateKey(c *gin.Context) {
 	// Get username from context (set by JWT middleware)
 	username, exists := c.Get("username")
 	if !exists {
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "user not authenticated"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "user not authenticated"))
 		return
 	}
 
@@ -46,7 +46,7 @@ func (h *APIKeyHandler) GenerateKey(c *gin.Context) {
 	// Parse request body
 	var req domain.CreateAPIKeyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		handleError(c, errors.Wrap(err, errors.ErrTypeValidation, "invalid request body"))
+		handleError(c, errors.Wrap(err, errors.ErrorTypeValidation, "invalid request body"))
 		return
 	}
 
@@ -68,7 +68,7 @@ func (h *APIKeyHandler) ListKeys(c *gin.Context) {
 	// Get username from context
 	username, exists := c.Get("username")
 	if !exists {
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "user not authenticated"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "user not authenticated"))
 		return
 	}
 
@@ -96,7 +96,7 @@ func (h *APIKeyHandler) GetKey(c *gin.Context) {
 	// Get username from context
 	username, exists := c.Get("username")
 	if !exists {
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "user not authenticated"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "user not authenticated"))
 		return
 	}
 
@@ -121,7 +121,7 @@ func (h *APIKeyHandler) GetKey(c *gin.Context) {
 	}
 
 	if foundKey == nil {
-		handleError(c, errors.New(errors.ErrTypeNotFound, "API key not found"))
+		handleError(c, errors.New(errors.ErrorTypeNotFound, "API key not found"))
 		return
 	}
 
@@ -136,7 +136,7 @@ func (h *APIKeyHandler) UpdateKey(c *gin.Context) {
 	// Get username from context
 	username, exists := c.Get("username")
 	if !exists {
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "user not authenticated"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "user not authenticated"))
 		return
 	}
 
@@ -146,7 +146,7 @@ func (h *APIKeyHandler) UpdateKey(c *gin.Context) {
 	// Parse request body
 	var req domain.UpdateAPIKeyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		handleError(c, errors.Wrap(err, errors.ErrTypeValidation, "invalid request body"))
+		handleError(c, errors.Wrap(err, errors.ErrorTypeValidation, "invalid request body"))
 		return
 	}
 
@@ -168,7 +168,7 @@ func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
 	// Get username from context
 	username, exists := c.Get("username")
 	if !exists {
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "user not authenticated"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "user not authenticated"))
 		return
 	}
 
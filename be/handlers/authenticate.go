@@ -40,10 +40,12 @@ func (h *AuthenticateHandler) Register(mongo *db.MongoService, c *gin.Context) e
 		c.JSON(500, gin.H{"error": "Failed to hash password"})
 		return err
 	}
-	_, err = h.mongo.InsertOne(context.Background(), "users", map[string]string{
+	// Organisation tidak diambil dari form; hanya admin yang boleh mengaturnya
+	user := map[string]string{
 		"username": username,
 		"password": string(hashedPassword),
-	})
+	}
+	_, err = h.mongo.InsertOne(context.Background(), "users", user)
 
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Failed to register user"})
@@ -73,8 +75,9 @@ func (h *AuthenticateHandler) Authenticate(c *gin.Context) error {
 		return err
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(username)
+	// Generate JWT token; devices owned by the user's organisation are shared with them
+	organization, _ := users[0]["organization"].(string)
+	token, err := utils.GenerateToken(username, organization)
 
 	c.SetCookie(
 		"jwt",
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast"
+	broadcastDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// CampaignHandler handles broadcast campaigns of a device
+type CampaignHandler struct {
+	campaigns *broadcast.CampaignService
+}
+
+// NewCampaignHandler creates a new CampaignHandler
+func NewCampaignHandler(campaigns *broadcast.CampaignService) *CampaignHandler {
+	return &CampaignHandler{campaigns: campaigns}
+}
+
+// recipientInputRequest is a recipient given by phone number or JID
+type recipientInputRequest struct {
+	To        string            `json:"to" binding:"required"`
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+// recipientSourcesRequest selects the recipients to add to a campaign
+type recipientSourcesRequest struct {
+	Numbers     []recipientInputRequest `json:"numbers" binding:"dive"`
+	Groups      []string                `json:"groups"`
+	ContactTags []string                `json:"contact_tags"`
+	SegmentIDs  []string                `json:"segment_ids"`
+}
+
+// toSources converts the request to recipient sources
+func (r *recipientSourcesRequest) toSources() broadcast.RecipientSources {
+	numbers := make([]broadcast.RecipientInput, 0, len(r.Numbers))
+	for _, number := range r.Numbers {
+		numbers = append(numbers, broadcast.RecipientInput{
+			To:        number.To,
+			Name:      number.Name,
+			Variables: number.Variables,
+		})
+	}
+
+	return broadcast.RecipientSources{
+		Numbers:     numbers,
+		Groups:      r.Groups,
+		ContactTags: r.ContactTags,
+		SegmentIDs:  r.SegmentIDs,
+	}
+}
+
+// createCampaignRequest is the body of a campaign creation
+type createCampaignRequest struct {
+	Name            string                   `json:"name" binding:"required"`
+	Template        string                   `json:"template" binding:"required"`
+	Typing          *bool                    `json:"typing"`
+	IntervalSeconds *int                     `json:"interval_seconds"`
+	JitterSeconds   *int                     `json:"jitter_seconds"`
+	Recipients      *recipientSourcesRequest `json:"recipients"`
+}
+
+// CreateCampaign handles POST /whatsapp/:device/campaigns - Create a draft campaign
+//
+// Recipients may be given here or added later; the campaign is sent once started.
+// The template may use {variable} placeholders, e.g. {name}, filled in per recipient.
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	var req createCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	campaign := &broadcastDomain.Campaign{
+		DeviceName:      c.Param("device"),
+		Name:            req.Name,
+		Template:        req.Template,
+		Typing:          true,
+		IntervalSeconds: broadcast.DefaultIntervalSeconds,
+		JitterSeconds:   broadcast.DefaultJitterSeconds,
+		CreatedBy:       c.GetString("username"),
+	}
+	if req.Typing != nil {
+		campaign.Typing = *req.Typing
+	}
+	if req.IntervalSeconds != nil {
+		campaign.IntervalSeconds = *req.IntervalSeconds
+	}
+	if req.JitterSeconds != nil {
+		campaign.JitterSeconds = *req.JitterSeconds
+	}
+
+	if err := h.campaigns.Create(campaign); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"message": "Campaign created",
+		"data":    h.campaignResponse(campaign, broadcastDomain.CampaignStats{}),
+	}
+
+	if req.Recipients != nil {
+		sources := req.Recipients.toSources()
+		if !sources.Empty() {
+			result, err := h.campaigns.AddRecipients(c.Request.Context(), campaign.DeviceName, campaign.ID, sources)
+			if err != nil {
+				handleError(c, err)
+				return
+			}
+			response["recipients"] = addResultResponse(result)
+			response["data"] = h.campaignResponse(campaign, broadcastDomain.CampaignStats{
+				Total:   int64(result.Added),
+				Pending: int64(result.Added),
+			})
+		}
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListCampaigns handles GET /whatsapp/:device/campaigns - List the device's campaigns, newest first
+//
+// Query: status, page and limit
+func (h *CampaignHandler) ListCampaigns(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 20)
+
+	campaigns, stats, total, err := h.campaigns.List(c.Param("device"), broadcastDomain.CampaignStatus(c.Query("status")), int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		data = append(data, h.campaignResponse(campaign, stats[campaign.ID]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// GetCampaign handles GET /whatsapp/:device/campaigns/:id - Get a campaign with its progress
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	campaign, stats, err := h.campaigns.Get(c.Param("device"), c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.campaignResponse(campaign, stats)})
+}
+
+// DeleteCampaign handles DELETE /whatsapp/:device/campaigns/:id - Delete a campaign that is not running
+func (h *CampaignHandler) DeleteCampaign(c *gin.Context) {
+	if err := h.campaigns.Delete(c.Param("device"), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Campaign deleted"})
+}
+
+// AddRecipients handles POST /whatsapp/:device/campaigns/:id/recipients - Add recipients
+//
+// Body: numbers ([{to, name, variables}]), groups (JIDs), contact_tags and/or segment_ids
+func (h *CampaignHandler) AddRecipients(c *gin.Context) {
+	var req recipientSourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	result, err := h.campaigns.AddRecipients(c.Request.Context(), c.Param("device"), c.Param("id"), req.toSources())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recipients added",
+		"data":    addResultResponse(result),
+	})
+}
+
+// ImportRecipients handles POST /whatsapp/:device/campaigns/:id/recipients/csv - Add recipients from a CSV file
+//
+// Multipart form field: file. The header row names the columns; a phone (or
+// to, number, jid) column is required and every column becomes a template variable.
+func (h *CampaignHandler) ImportRecipients(c *gin.Context) {
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("file is required"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.campaigns.ImportCSV(c.Param("device"), c.Param("id"), file)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recipients imported",
+		"data":    addResultResponse(result),
+	})
+}
+
+// ListRecipients handles GET /whatsapp/:device/campaigns/:id/recipients - List recipients in sending order
+//
+// Query: status (pending, sent, failed or skipped), page and limit
+func (h *CampaignHandler) ListRecipients(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 50)
+	status := broadcastDomain.RecipientStatus(c.Query("status"))
+
+	recipients, stats, err := h.campaigns.Recipients(c.Param("device"), c.Param("id"), status, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(recipients))
+	for _, recipient := range recipients {
+		data = append(data, gin.H{
+			"id":         recipient.ID,
+			"position":   recipient.Position,
+			"to":         recipient.To,
+			"is_group":   recipient.IsGroup,
+			"name":       recipient.Name,
+			"variables":  recipient.Variables,
+			"status":     recipient.Status,
+			"message":    recipient.Message,
+			"error":      recipient.Error,
+			"attempts":   recipient.Attempts,
+			"sent_at":    recipient.SentAt,
+			"updated_at": recipient.UpdatedAt,
+		})
+	}
+
+	total := stats.Total
+	switch status {
+	case broadcastDomain.RecipientPending:
+		total = stats.Pending
+	case broadcastDomain.RecipientSent:
+		total = stats.Sent
+	case broadcastDomain.RecipientFailed:
+		total = stats.Failed
+	case broadcastDomain.RecipientSkipped:
+		total = stats.Skipped
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// StartCampaign handles POST /whatsapp/:device/campaigns/:id/start - Start sending a draft campaign
+func (h *CampaignHandler) StartCampaign(c *gin.Context) {
+	campaign, err := h.campaigns.Start(c.Param("device"), c.Param("id"))
+	h.respondStatus(c, campaign, err, "Campaign started")
+}
+
+// PauseCampaign handles POST /whatsapp/:device/campaigns/:id/pause - Pause a running campaign
+func (h *CampaignHandler) PauseCampaign(c *gin.Context) {
+	campaign, err := h.campaigns.Pause(c.Param("device"), c.Param("id"), c.GetString("username"))
+	h.respondStatus(c, campaign, err, "Campaign paused")
+}
+
+// ResumeCampaign handles POST /whatsapp/:device/campaigns/:id/resume - Resume a paused campaign
+func (h *CampaignHandler) ResumeCampaign(c *gin.Context) {
+	campaign, err := h.campaigns.Resume(c.Param("device"), c.Param("id"))
+	h.respondStatus(c, campaign, err, "Campaign resumed")
+}
+
+// CancelCampaign handles POST /whatsapp/:device/campaigns/:id/cancel - Cancel a campaign, skipping pending recipients
+func (h *CampaignHandler) CancelCampaign(c *gin.Context) {
+	campaign, err := h.campaigns.Cancel(c.Param("device"), c.Param("id"), c.GetString("username"))
+	h.respondStatus(c, campaign, err, "Campaign cancelled")
+}
+
+// respondStatus responds to a change of a campaign's status with the campaign and its progress
+func (h *CampaignHandler) respondStatus(c *gin.Context, campaign *broadcastDomain.Campaign, err error, message string) {
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	_, stats, err := h.campaigns.Get(campaign.DeviceName, campaign.ID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    h.campaignResponse(campaign, stats),
+	})
+}
+
+// campaignResponse converts a campaign and its recipient counts to their JSON representation
+func (h *CampaignHandler) campaignResponse(campaign *broadcastDomain.Campaign, stats broadcastDomain.CampaignStats) gin.H {
+	variables := broadcastDomain.TemplateVariables(campaign.Template)
+	if variables == nil {
+		variables = []string{}
+	}
+
+	return gin.H{
+		"id":                 campaign.ID,
+		"device_name":        campaign.DeviceName,
+		"name":               campaign.Name,
+		"template":           campaign.Template,
+		"variables":          variables,
+		"typing":             campaign.Typing,
+		"interval_seconds":   campaign.IntervalSeconds,
+		"jitter_seconds":     campaign.JitterSeconds,
+		"status":             campaign.Status,
+		"status_reason":      campaign.StatusReason,
+		"waiting_for_device": h.campaigns.WaitingForDevice(campaign),
+		"recipients": gin.H{
+			"total":   stats.Total,
+			"pending": stats.Pending,
+			"sent":    stats.Sent,
+			"failed":  stats.Failed,
+			"skipped": stats.Skipped,
+		},
+		"created_by":  campaign.CreatedBy,
+		"started_at":  campaign.StartedAt,
+		"finished_at": campaign.FinishedAt,
+		"created_at":  campaign.CreatedAt,
+		"updated_at":  campaign.UpdatedAt,
+	}
+}
+
+// addResultResponse converts the result of adding recipients to its JSON representation
+func addResultResponse(result *broadcast.AddResult) gin.H {
+	rejected := make([]gin.H, 0, len(result.Rejected))
+	for _, rejection := range result.Rejected {
+		entry := gin.H{
+			"input":  rejection.Input,
+			"reason": rejection.Reason,
+		}
+		if rejection.Row > 0 {
+			entry["row"] = rejection.Row
+		}
+		rejected = append(rejected, entry)
+	}
+
+	return gin.H{
+		"added":      result.Added,
+		"duplicates": result.Duplicates,
+		"rejected":   rejected,
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/message"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// maxChatExportSize limits uploaded exports; zips with media can be large
+const maxChatExportSize = 100 << 20
+
+// ChatImportHandler handles backfilling messages from exported WhatsApp chats
+type ChatImportHandler struct {
+	importChat *message.ImportChatUseCase
+}
+
+// NewChatImportHandler creates a new ChatImportHandler
+func NewChatImportHandler(importChat *message.ImportChatUseCase) *ChatImportHandler {
+	return &ChatImportHandler{importChat: importChat}
+}
+
+// ImportChat handles POST /quick_response/import - Backfill reports from a chat export
+//
+// Multipart form: file (.txt or .zip from "Export chat"), device, chat (JID of the
+// exported chat), timezone (default Asia/Jakarta). Query parameters: dry_run (default true)
+func (h *ChatImportHandler) ImportChat(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("dry_run must be a boolean"))
+		return
+	}
+
+	loc, err := time.LoadLocation(c.DefaultPostForm("timezone", "Asia/Jakarta"))
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid timezone"))
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("file is required"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxChatExportSize+1))
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to read chat export", err))
+		return
+	}
+	if len(data) > maxChatExportSize {
+		handleError(c, apperrors.NewValidationError("Chat export is too large"))
+		return
+	}
+
+	result, err := h.importChat.Execute(c.Request.Context(), message.ImportChatInput{
+		Data:       data,
+		DeviceName: c.PostForm("device"),
+		ChatJID:    c.PostForm("chat"),
+		Location:   loc,
+		DryRun:     dryRun,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	previews := make([]gin.H, 0, len(result.Previews))
+	for _, imported := range result.Previews {
+		records := make([]gin.H, 0, len(imported.Previews))
+		for _, preview := range imported.Previews {
+			records = append(records, gin.H{
+				"processor": preview.Processor,
+				"record":    previewRecord(preview),
+			})
+		}
+		previews = append(previews, gin.H{
+			"line":       imported.Line,
+			"message_id": imported.MessageID,
+			"timestamp":  imported.Timestamp,
+			"sender":     imported.Sender,
+			"records":    records,
+		})
+	}
+
+	failures := make([]gin.H, 0, len(result.Errors))
+	for _, importErr := range result.Errors {
+		failures = append(failures, gin.H{
+			"line":      importErr.Line,
+			"timestamp": importErr.Timestamp,
+			"sender":    importErr.Sender,
+			"error":     importErr.Error,
+		})
+	}
+
+	msg := "Chat export imported"
+	if result.DryRun {
+		msg = "Dry run completed, nothing was saved"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": msg,
+		"data": gin.H{
+			"dry_run":   result.DryRun,
+			"total":     result.Total,
+			"matched":   result.Matched,
+			"saveable":  result.Saveable,
+			"processed": result.Processed,
+			"failed":    result.Failed,
+			"previews":  previews,
+			"errors":    failures,
+		},
+	})
+}
+
+// previewRecord converts a processor preview to its JSON representation
+func previewRecord(preview domain.MessagePreview) interface{} {
+	qr, ok := preview.Result.(*qrDomain.QuickResponse)
+	if !ok {
+		return preview.Result
+	}
+
+	return gin.H{
+		"message_id":  qr.MessageID,
+		"device_name": qr.DeviceName,
+		"sender":      qr.Sender,
+		"officer": gin.H{
+			"name":       qr.Officer.Name,
+			"position":   qr.Officer.Position,
+			"assignment": qr.Officer.Assignment,
+		},
+		"activity": gin.H{
+			"method":         qr.Activity.Method,
+			"activity_type":  qr.Activity.ActivityType,
+			"irrigation_di":  qr.Activity.IrrigationDI,
+			"channel":        qr.Activity.Channel,
+			"building_route": qr.Activity.BuildingRoute,
+			"location":       qr.Activity.Location,
+			"watershed_unit": qr.Activity.WatershedUnit,
+		},
+		"output": gin.H{
+			"area_size":        qr.Output.AreaSize,
+			"channel_length":   qr.Output.ChannelLength,
+			"leaks_closed":     qr.Output.LeaksClosed,
+			"sediment_removed": qr.Output.SedimentRemoved,
+			"trash_cleared":    qr.Output.TrashCleared,
+			"tree_cut_removed": qr.Output.TreeCutRemoved,
+		},
+		"created_at": qr.CreatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// ContactHandler handles the contact directory of a device
+type ContactHandler struct {
+	directory *waUsecase.ContactDirectoryUseCase
+	sync      *waUsecase.SyncContactsUseCase
+}
+
+// NewContactHandler creates a new ContactHandler
+func NewContactHandler(directory *waUsecase.ContactDirectoryUseCase, sync *waUsecase.SyncContactsUseCase) *ContactHandler {
+	return &ContactHandler{
+		directory: directory,
+		sync:      sync,
+	}
+}
+
+// updateContactRequest is the body of a contact update; omitted fields are left unchanged
+type updateContactRequest struct {
+	Tags  *[]string `json:"tags"`
+	Notes *string   `json:"notes"`
+}
+
+// ListContacts handles GET /whatsapp/:device/contacts - List the device's contacts, sorted by name
+//
+// Query: search (part of a name or the phone number), tag, page and limit
+func (h *ContactHandler) ListContacts(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 20)
+
+	filter := &domain.ContactFilter{
+		DeviceName: c.Param("device"),
+		Search:     c.Query("search"),
+		Tag:        c.Query("tag"),
+	}
+
+	contacts, total, err := h.directory.List(c.Request.Context(), filter, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(contacts))
+	for _, contact := range contacts {
+		data = append(data, contactResponse(contact))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// GetContact handles GET /whatsapp/:device/contacts/:jid - Get a contact by JID or phone number
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	contact, err := h.directory.Get(c.Request.Context(), c.Param("device"), c.Param("jid"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contactResponse(contact)})
+}
+
+// UpdateContact handles PUT /whatsapp/:device/contacts/:jid - Set the tags and/or notes of a contact
+func (h *ContactHandler) UpdateContact(c *gin.Context) {
+	var req updateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	contact, err := h.directory.Annotate(c.Request.Context(), c.Param("device"), c.Param("jid"), domain.ContactAnnotations{
+		Tags:  req.Tags,
+		Notes: req.Notes,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contact updated",
+		"data":    contactResponse(contact),
+	})
+}
+
+// ListTags handles GET /whatsapp/:device/contacts/tags - List the tags used in the device's contacts
+func (h *ContactHandler) ListTags(c *gin.Context) {
+	tags, err := h.directory.Tags(c.Request.Context(), c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// SyncContacts handles POST /whatsapp/:device/contacts/sync - Copy the device's contact store into the directory now
+func (h *ContactHandler) SyncContacts(c *gin.Context) {
+	count, err := h.sync.Execute(c.Request.Context(), c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contacts synced",
+		"data":    gin.H{"synced": count},
+	})
+}
+
+// contactResponse converts a contact to its JSON representation
+func contactResponse(contact *domain.Contact) gin.H {
+	return gin.H{
+		"jid":           contact.JID,
+		"phone":         contact.Phone,
+		"name":          contact.DisplayName(),
+		"full_name":     contact.FullName,
+		"first_name":    contact.FirstName,
+		"push_name":     contact.PushName,
+		"business_name": contact.BusinessName,
+		"tags":          contact.Tags,
+		"notes":         contact.Notes,
+		"synced_at":     contact.SyncedAt,
+		"created_at":    contact.CreatedAt,
+		"updated_at":    contact.UpdatedAt,
+	}
+}
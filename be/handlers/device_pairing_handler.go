@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// eventKeepAliveInterval keeps idle event streams open through proxies
+const eventKeepAliveInterval = 25 * time.Second
+
+// DevicePairingHandler handles phone-number pairing, connection history and the device event stream
+type DevicePairingHandler struct {
+	whatsapp ports.WhatsAppService
+	events   domain.DeviceEventStream
+	streams  context.Context // Open event streams end when it is done
+}
+
+// NewDevicePairingHandler creates a new DevicePairingHandler
+func NewDevicePairingHandler(whatsapp ports.WhatsAppService, events domain.DeviceEventStream, streams context.Context) *DevicePairingHandler {
+	return &DevicePairingHandler{whatsapp: whatsapp, events: events, streams: streams}
+}
+
+// pairPhoneRequest is the body of a phone-number pairing request
+type pairPhoneRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// PairPhone handles POST /whatsapp/:device/pair-phone - Request a link code for phone-number pairing
+func (h *DevicePairingHandler) PairPhone(c *gin.Context) {
+	var req pairPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("phone is required"))
+		return
+	}
+
+	pairing, err := h.whatsapp.PairPhone(c.Request.Context(), c.Param("device"), req.Phone)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Enter the code on the phone under Linked devices > Link with phone number",
+		"data": gin.H{
+			"device":     pairing.DeviceName,
+			"phone":      pairing.Phone,
+			"code":       pairing.Code,
+			"expires_at": pairing.ExpiresAt,
+		},
+	})
+}
+
+// GetConnection handles GET /whatsapp/:device/connection - Show connection state and recent transitions
+func (h *DevicePairingHandler) GetConnection(c *gin.Context) {
+	deviceName := c.Param("device")
+
+	info, err := h.whatsapp.GetConnectionInfo(deviceName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	history := h.whatsapp.GetConnectionHistory(deviceName)
+	transitions := make([]gin.H, 0, len(history))
+	for _, transition := range history {
+		transitions = append(transitions, transitionResponse(transition))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"device":       info.DeviceName,
+			"status":       info.Status,
+			"jid":          info.JID,
+			"is_connected": info.IsConnected,
+			"transitions":  transitions,
+		},
+	})
+}
+
+// Disconnect handles GET /whatsapp/:device/disconnect - Disconnect the device, keeping its session
+func (h *DevicePairingHandler) Disconnect(c *gin.Context) {
+	if err := h.whatsapp.DisconnectDevice(c.Request.Context(), c.Param("device")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Device disconnected",
+	})
+}
+
+// StreamEvents handles GET /whatsapp/:device/events - Stream device lifecycle events (Server-Sent Events)
+func (h *DevicePairingHandler) StreamEvents(c *gin.Context) {
+	deviceName := c.Param("device")
+
+	events, unsubscribe := h.events.Subscribe(deviceName)
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// Start with the current state so clients don't have to poll for it
+	c.SSEvent("status", gin.H{
+		"device":    deviceName,
+		"connected": h.whatsapp.IsDeviceConnected(deviceName),
+		"timestamp": time.Now(),
+	})
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), deviceEventResponse(event))
+			return true
+
+		case <-keepAlive.C:
+			c.SSEvent("keepalive", gin.H{"timestamp": time.Now()})
+			return true
+
+		case <-c.Request.Context().Done():
+			return false
+
+		case <-h.streams.Done():
+			return false
+		}
+	})
+}
+
+// deviceEventResponse converts a device event to its JSON representation
+func deviceEventResponse(event domain.DeviceEvent) gin.H {
+	response := gin.H{
+		"device":    event.DeviceName,
+		"type":      event.Type,
+		"timestamp": event.Timestamp,
+	}
+	if event.JID != "" {
+		response["jid"] = event.JID
+	}
+	if event.Data != "" {
+		response["data"] = event.Data
+	}
+	if event.LastSeen != nil {
+		response["last_seen"] = event.LastSeen
+	}
+	return response
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
+)
+
+const (
+	// backupPassphraseHeader carries the passphrase of a session backup
+	backupPassphraseHeader = "X-Backup-Passphrase"
+
+	// maxSessionArchiveSize bounds the size of an uploaded session archive
+	maxSessionArchiveSize = 64 << 20
+)
+
+// DeviceRegistryHandler handles device registry requests. Devices created here
+// get a WhatsApp client; deleting one removes its session store.
+type DeviceRegistryHandler struct {
+	createDevice *device.CreateDeviceUseCase
+	getDevice    *device.GetDeviceUseCase
+	listDevices  *device.ListDevicesUseCase
+	updateDevice *device.UpdateDeviceUseCase
+	deleteDevice *device.DeleteDeviceUseCase
+	reconcile    *device.ReconcileDevicesUseCase
+	backup       *device.BackupDeviceUseCase
+	restore      *device.RestoreDeviceUseCase
+}
+
+// NewDeviceRegistryHandler creates a new DeviceRegistryHandler
+func NewDeviceRegistryHandler(
+	createDevice *device.CreateDeviceUseCase,
+	getDevice *device.GetDeviceUseCase,
+	listDevices *device.ListDevicesUseCase,
+	updateDevice *device.UpdateDeviceUseCase,
+	deleteDevice *device.DeleteDeviceUseCase,
+	reconcile *device.ReconcileDevicesUseCase,
+	backup *device.BackupDeviceUseCase,
+	restore *device.RestoreDeviceUseCase,
+) *DeviceRegistryHandler {
+	return &DeviceRegistryHandler{
+		createDevice: createDevice,
+		getDevice:    getDevice,
+		listDevices:  listDevices,
+		updateDevice: updateDevice,
+		deleteDevice: deleteDevice,
+		reconcile:    reconcile,
+		backup:       backup,
+		restore:      restore,
+	}
+}
+
+// createDeviceRequest represents the body of a create request
+type createDeviceRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	ProxyURL    string `json:"proxy_url"`
+}
+
+// updateDeviceRequest represents the body of an update request
+type updateDeviceRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Status      *string `json:"status" binding:"omitempty,oneof=active inactive"`
+
+	PresencePolicy *string `json:"presence_policy" binding:"omitempty,oneof=on_send online offline"`
+	ProxyURL       *string `json:"proxy_url"` // Empty string removes the proxy
+}
+
+// CreateDevice handles POST /devices - Register a device and create its WhatsApp client
+func (h *DeviceRegistryHandler) CreateDevice(c *gin.Context) {
+	var req createDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	// Caller from context (set by JWT middleware); the device is shared with the caller's organisation
+	caller := middlewares.GetCallerFromContext(c)
+	if caller.Username == "" {
+		handleError(c, apperrors.NewUnauthorizedError("user not authenticated"))
+		return
+	}
+
+	created, err := h.createDevice.Execute(c.Request.Context(), domain.CreateDeviceRequest{
+		Name:         req.Name,
+		Owner:        caller.Username,
+		Organization: caller.Organization,
+		Description:  req.Description,
+		ProxyURL:     req.ProxyURL,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Device created successfully",
+		"data":    deviceResponse(created),
+	})
+}
+
+// ListDevices handles GET /devices - List the registered devices of the caller and their organisation
+//
+// Query parameters: owner, status, page, limit (default 20)
+func (h *DeviceRegistryHandler) ListDevices(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 20)
+
+	filter := &domain.DeviceFilter{
+		Owner:  c.Query("owner"),
+		Status: domain.DeviceStatus(c.Query("status")),
+	}
+
+	devices, total, err := h.listDevices.Execute(c.Request.Context(), middlewares.GetCallerFromContext(c), filter, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(devices))
+	for _, d := range devices {
+		data = append(data, deviceResponse(d))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// GetDevice handles GET /devices/:id - Get a device the caller owns
+func (h *DeviceRegistryHandler) GetDevice(c *gin.Context) {
+	found, err := h.getDevice.Execute(c.Request.Context(), c.Param("id"), middlewares.GetCallerFromContext(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deviceResponse(found)})
+}
+
+// UpdateDevice handles PUT /devices/:id - Update a device's name, description, status, presence policy or proxy
+func (h *DeviceRegistryHandler) UpdateDevice(c *gin.Context) {
+	var req updateDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	update := domain.UpdateDeviceRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		ProxyURL:    req.ProxyURL,
+	}
+	if req.Status != nil {
+		status := domain.DeviceStatus(*req.Status)
+		update.Status = &status
+	}
+	if req.PresencePolicy != nil {
+		policy := domain.PresencePolicy(*req.PresencePolicy)
+		update.PresencePolicy = &policy
+	}
+
+	updated, err := h.updateDevice.Execute(c.Request.Context(), c.Param("id"), middlewares.GetCallerFromContext(c), update)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device updated successfully",
+		"data":    deviceResponse(updated),
+	})
+}
+
+// DeleteDevice handles DELETE /devices/:id - Delete a device and its WhatsApp session
+func (h *DeviceRegistryHandler) DeleteDevice(c *gin.Context) {
+	if err := h.deleteDevice.Execute(c.Request.Context(), c.Param("id"), middlewares.GetCallerFromContext(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device deleted successfully"})
+}
+
+// Reconcile handles POST /admin/devices/reconcile - Match all device records with session stores
+func (h *DeviceRegistryHandler) Reconcile(c *gin.Context) {
+	report, err := h.reconcile.Execute(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Devices reconciled",
+		"data": gin.H{
+			"loaded":           report.Loaded,
+			"failed":           report.Failed,
+			"held_elsewhere":   report.HeldElsewhere,
+			"orphaned_stores":  report.OrphanedStores,
+			"orphaned_records": report.OrphanedRecords,
+		},
+	})
+}
+
+// Backup handles GET /devices/:id/backup - Download the device's WhatsApp session as an encrypted archive
+//
+// The passphrase is read from the X-Backup-Passphrase header. The device keeps
+// running; use POST /devices/:id/transfer to move it to another server.
+func (h *DeviceRegistryHandler) Backup(c *gin.Context) {
+	if transfer, _ := strconv.ParseBool(c.Query("transfer")); transfer {
+		handleError(c, apperrors.NewValidationError("Transfer backups stop the device; use POST /devices/:id/transfer"))
+		return
+	}
+	h.sendBackup(c, false)
+}
+
+// Transfer handles POST /devices/:id/transfer - Download the device's WhatsApp session for another server
+//
+// The passphrase is read from the X-Backup-Passphrase header. The device is
+// stopped and marked transferred, ready to be restored on another server.
+func (h *DeviceRegistryHandler) Transfer(c *gin.Context) {
+	h.sendBackup(c, true)
+}
+
+// sendBackup writes the device's sealed session archive as the response
+func (h *DeviceRegistryHandler) sendBackup(c *gin.Context, transfer bool) {
+	archive, sealed, err := h.backup.Execute(c.Request.Context(), c.Param("id"), middlewares.GetCallerFromContext(c),
+		c.GetHeader(backupPassphraseHeader), transfer)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%s.wabackup", archive.DeviceName, archive.CreatedAt.UTC().Format("20060102T150405Z"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Header("X-Session-Transfer", strconv.FormatBool(archive.Transfer))
+	c.Data(http.StatusOK, "application/octet-stream", sealed)
+}
+
+// Restore handles POST /devices/:id/restore - Load an encrypted session archive into the device
+//
+// Multipart form fields: archive (file), passphrase, force (restore a plain
+// backup whose source may still be running)
+func (h *DeviceRegistryHandler) Restore(c *gin.Context) {
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("archive file is required"))
+		return
+	}
+	if fileHeader.Size > maxSessionArchiveSize {
+		handleError(c, apperrors.NewValidationError("archive file is too large"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to open archive file", err))
+		return
+	}
+	defer file.Close()
+
+	sealed, err := io.ReadAll(io.LimitReader(file, maxSessionArchiveSize))
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to read archive file", err))
+		return
+	}
+
+	force, _ := strconv.ParseBool(c.DefaultPostForm("force", "false"))
+
+	restored, err := h.restore.Execute(c.Request.Context(), c.Param("id"), middlewares.GetCallerFromContext(c),
+		sealed, c.PostForm("passphrase"), force)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device session restored",
+		"data":    deviceResponse(restored),
+	})
+}
+
+// deviceResponse converts a device to its JSON representation
+func deviceResponse(d *domain.Device) gin.H {
+	return gin.H{
+		"id":           d.ID,
+		"name":         d.Name,
+		"owner":        d.Owner,
+		"organization": d.Organization,
+		"description":  d.Description,
+		"status":       d.Status,
+		"jid":          d.JID,
+		"created_at":   d.CreatedAt,
+		"updated_at":   d.UpdatedAt,
+
+		"presence_policy":        presencePolicy(d.PresencePolicy),
+		"presence_subscriptions": d.PresenceSubscriptions,
+		"proxy_url":              redactProxyURL(d.ProxyURL),
+	}
+}
+
+// presencePolicy returns the policy a device follows, filling in the default
+func presencePolicy(policy domain.PresencePolicy) domain.PresencePolicy {
+	if policy == "" {
+		return domain.PresenceOnSend
+	}
+	return policy
+}
+
+// redactProxyURL hides the password of a proxy URL
+func redactProxyURL(proxyURL string) string {
+	if proxyURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Redacted()
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
+)
+
+// DigestScheduleHandler handles Quick Response digest schedule requests.
+// Digests are posted only from devices the caller owns.
+type DigestScheduleHandler struct {
+	repository qrDomain.DigestScheduleRepository
+	scheduler  *quickresponse.DigestScheduler
+	authorize  *device.AuthorizeDeviceUseCase
+}
+
+// NewDigestScheduleHandler creates a new DigestScheduleHandler
+func NewDigestScheduleHandler(repository qrDomain.DigestScheduleRepository, scheduler *quickresponse.DigestScheduler, authorize *device.AuthorizeDeviceUseCase) *DigestScheduleHandler {
+	return &DigestScheduleHandler{
+		repository: repository,
+		scheduler:  scheduler,
+		authorize:  authorize,
+	}
+}
+
+// digestScheduleRequest represents the body of create and update requests
+type digestScheduleRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	Frequency         string   `json:"frequency" binding:"required,oneof=daily weekly"`
+	Weekday           int      `json:"weekday"`
+	TimeOfDay         string   `json:"time_of_day" binding:"required"`
+	Timezone          string   `json:"timezone"`
+	DeviceName        string   `json:"device_name" binding:"required"`
+	GroupJIDs         []string `json:"group_jids" binding:"required,min=1"`
+	IncludeAttachment bool     `json:"include_attachment"`
+	Enabled           *bool    `json:"enabled"`
+}
+
+// apply copies the request onto a schedule
+func (r *digestScheduleRequest) apply(schedule *qrDomain.DigestSchedule) {
+	schedule.Name = r.Name
+	schedule.Frequency = qrDomain.DigestFrequency(r.Frequency)
+	schedule.Weekday = time.Weekday(r.Weekday)
+	schedule.TimeOfDay = r.TimeOfDay
+	schedule.Timezone = r.Timezone
+	schedule.DeviceName = r.DeviceName
+	schedule.GroupJIDs = r.GroupJIDs
+	schedule.IncludeAttachment = r.IncludeAttachment
+	if r.Enabled != nil {
+		schedule.Enabled = *r.Enabled
+	}
+}
+
+// CreateSchedule handles POST /quick_response/digests - Create a digest schedule
+func (h *DigestScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req digestScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if _, err := h.authorize.Execute(c.Request.Context(), req.DeviceName, middlewares.GetCallerFromContext(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	schedule := &qrDomain.DigestSchedule{Enabled: true, CreatedBy: c.GetString("username")}
+	req.apply(schedule)
+
+	if err := schedule.Validate(); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := h.repository.Create(schedule); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Digest schedule created successfully",
+		"data":    digestScheduleResponse(schedule),
+	})
+}
+
+// ListSchedules handles GET /quick_response/digests - List digest schedules
+func (h *DigestScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.repository.FindAll()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(schedules))
+	for _, schedule := range schedules {
+		data = append(data, digestScheduleResponse(schedule))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetSchedule handles GET /quick_response/digests/:id - Get a digest schedule
+func (h *DigestScheduleHandler) GetSchedule(c *gin.Context) {
+	schedule, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": digestScheduleResponse(schedule)})
+}
+
+// UpdateSchedule handles PUT /quick_response/digests/:id - Update a digest schedule
+func (h *DigestScheduleHandler) UpdateSchedule(c *gin.Context) {
+	schedule, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var req digestScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if _, err := h.authorize.Execute(c.Request.Context(), req.DeviceName, middlewares.GetCallerFromContext(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	req.apply(schedule)
+
+	if err := schedule.Validate(); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := h.repository.Update(schedule); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Digest schedule updated successfully",
+		"data":    digestScheduleResponse(schedule),
+	})
+}
+
+// DeleteSchedule handles DELETE /quick_response/digests/:id - Delete a digest schedule
+func (h *DigestScheduleHandler) DeleteSchedule(c *gin.Context) {
+	if err := h.repository.Delete(c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Digest schedule deleted successfully"})
+}
+
+// RunSchedule handles POST /quick_response/digests/:id/run - Post a digest immediately
+func (h *DigestScheduleHandler) RunSchedule(c *gin.Context) {
+	schedule, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := h.scheduler.RunNow(c.Request.Context(), schedule); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Digest posted successfully"})
+}
+
+// digestScheduleResponse converts a schedule to its JSON representation
+func digestScheduleResponse(schedule *qrDomain.DigestSchedule) gin.H {
+	response := gin.H{
+		"id":                 schedule.ID,
+		"name":               schedule.Name,
+		"frequency":          schedule.Frequency,
+		"weekday":            int(schedule.Weekday),
+		"time_of_day":        schedule.TimeOfDay,
+		"timezone":           schedule.Timezone,
+		"device_name":        schedule.DeviceName,
+		"group_jids":         schedule.GroupJIDs,
+		"include_attachment": schedule.IncludeAttachment,
+		"enabled":            schedule.Enabled,
+		"last_run_at":        schedule.LastRunAt,
+		"created_by":         schedule.CreatedBy,
+		"created_at":         schedule.CreatedAt,
+		"updated_at":         schedule.UpdatedAt,
+	}
+
+	if schedule.Enabled {
+		since := schedule.UpdatedAt
+		if schedule.LastRunAt != nil && schedule.LastRunAt.After(since) {
+			since = *schedule.LastRunAt
+		}
+		response["next_run_at"] = schedule.NextRun(since)
+	}
+
+	return response
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// handleError writes an application error as a JSON response using its mapped HTTP status
+func handleError(c *gin.Context, err error) {
+	appErr := apperrors.GetAppError(err)
+	c.AbortWithStatusJSON(appErr.StatusCode, gin.H{"error": appErr})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// GroupHandler handles administering the groups of a device
+type GroupHandler struct {
+	whatsapp ports.WhatsAppService
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(whatsapp ports.WhatsAppService) *GroupHandler {
+	return &GroupHandler{whatsapp: whatsapp}
+}
+
+// createGroupRequest is the body of a group creation
+type createGroupRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Participants []string `json:"participants"`
+}
+
+// updateGroupRequest is the body of a group update; omitted fields are left unchanged
+type updateGroupRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Announce    *bool   `json:"announce"`
+	Locked      *bool   `json:"locked"`
+}
+
+// updateParticipantsRequest is the body of a change to the members of a group
+type updateParticipantsRequest struct {
+	Action       domain.GroupParticipantAction `json:"action" binding:"required"`
+	Participants []string                      `json:"participants" binding:"required"`
+}
+
+// joinGroupRequest is the body of joining a group by invite link
+type joinGroupRequest struct {
+	Link string `json:"link" binding:"required"`
+}
+
+// ListGroups handles GET /whatsapp/:device/groups - List the device's groups, sorted by name
+//
+// Query: search (part of the name), admin (true for groups the device is an
+// admin of), page and limit
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 20)
+	admin, _ := strconv.ParseBool(c.Query("admin"))
+
+	filter := domain.GroupFilter{
+		Search:    c.Query("search"),
+		AdminOnly: admin,
+	}
+
+	groups, total, err := h.whatsapp.ListGroups(c.Request.Context(), c.Param("device"), filter, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(groups))
+	for i := range groups {
+		data = append(data, groupResponse(&groups[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// CreateGroup handles POST /whatsapp/:device/groups - Create a group with the device as admin
+//
+// Participants may be JIDs or phone numbers in international format.
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var req createGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("name is required"))
+		return
+	}
+
+	group, err := h.whatsapp.CreateGroup(c.Request.Context(), c.Param("device"), req.Name, req.Participants)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Group created",
+		"data":    groupResponse(group),
+	})
+}
+
+// GetGroup handles GET /whatsapp/:device/groups/:group - Get a group with its members and settings
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	group, err := h.whatsapp.GetGroup(c.Request.Context(), c.Param("device"), c.Param("group"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": groupResponse(group)})
+}
+
+// UpdateGroup handles PUT /whatsapp/:device/groups/:group - Change the name, description, announce-only and/or locked mode
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	var req updateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	group, err := h.whatsapp.UpdateGroup(c.Request.Context(), c.Param("device"), c.Param("group"), domain.GroupUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Announce:    req.Announce,
+		Locked:      req.Locked,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group updated",
+		"data":    groupResponse(group),
+	})
+}
+
+// UpdateParticipants handles POST /whatsapp/:device/groups/:group/participants - Add, remove, promote or demote members
+//
+// The change is applied per person; the response lists who failed and why.
+func (h *GroupHandler) UpdateParticipants(c *gin.Context) {
+	var req updateParticipantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("action and participants are required"))
+		return
+	}
+
+	results, err := h.whatsapp.UpdateGroupParticipants(c.Request.Context(), c.Param("device"), c.Param("group"), req.Participants, req.Action)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		item := gin.H{"jid": result.JID, "success": result.Success}
+		if !result.Success {
+			item["error_code"] = result.ErrorCode
+			failed++
+		}
+		data = append(data, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group participants updated",
+		"data":    data,
+		"failed":  failed,
+	})
+}
+
+// SetPhoto handles PUT /whatsapp/:device/groups/:group/photo - Change the group picture
+//
+// Multipart form field: photo (JPEG or PNG file)
+func (h *GroupHandler) SetPhoto(c *gin.Context) {
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("photo file is required"))
+		return
+	}
+	if fileHeader.Size > maxProfilePhotoSize {
+		handleError(c, apperrors.NewValidationError("photo file is too large"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to open photo file", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxProfilePhotoSize))
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to read photo file", err))
+		return
+	}
+
+	pictureID, err := h.whatsapp.SetGroupPhoto(c.Request.Context(), c.Param("device"), c.Param("group"), data)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Group photo updated",
+		"data":    gin.H{"picture_id": pictureID},
+	})
+}
+
+// GetInviteLink handles GET /whatsapp/:device/groups/:group/invite-link - Get the invite link of a group
+func (h *GroupHandler) GetInviteLink(c *gin.Context) {
+	h.inviteLink(c, false)
+}
+
+// ResetInviteLink handles POST /whatsapp/:device/groups/:group/invite-link/reset - Revoke the invite link and create a new one
+func (h *GroupHandler) ResetInviteLink(c *gin.Context) {
+	h.inviteLink(c, true)
+}
+
+func (h *GroupHandler) inviteLink(c *gin.Context, reset bool) {
+	link, err := h.whatsapp.GetGroupInviteLink(c.Request.Context(), c.Param("device"), c.Param("group"), reset)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	message := "Invite link retrieved"
+	if reset {
+		message = "Invite link reset"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    gin.H{"invite_link": link},
+	})
+}
+
+// JoinGroup handles POST /whatsapp/:device/groups/join - Join a group by invite link
+func (h *GroupHandler) JoinGroup(c *gin.Context) {
+	var req joinGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("link is required"))
+		return
+	}
+
+	jid, err := h.whatsapp.JoinGroup(c.Request.Context(), c.Param("device"), req.Link)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Joined group",
+		"data":    gin.H{"jid": jid},
+	})
+}
+
+// LeaveGroup handles POST /whatsapp/:device/groups/:group/leave - Leave a group
+func (h *GroupHandler) LeaveGroup(c *gin.Context) {
+	if err := h.whatsapp.LeaveGroup(c.Request.Context(), c.Param("device"), c.Param("group")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left group"})
+}
+
+// groupResponse converts a group to its JSON representation
+func groupResponse(group *domain.WhatsAppGroup) gin.H {
+	return gin.H{
+		"jid":          group.JID,
+		"name":         group.Name,
+		"topic":        group.Topic,
+		"owner_jid":    group.OwnerJID,
+		"participants": group.Participants,
+		"admins":       group.Admins,
+		"member_count": len(group.Participants),
+		"is_admin":     group.IsAdmin,
+		"is_announce":  group.IsAnnounce,
+		"is_locked":    group.IsLocked,
+		"is_ephemeral": group.IsEphemeral,
+		"created_at":   group.CreatedAt,
+	}
+}
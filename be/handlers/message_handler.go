@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// maxSendFileSize bounds the size of files sent through /send_message
+const maxSendFileSize = 64 << 20
+
+// MessageHandler handles sending messages through the devices' WhatsApp clients
+type MessageHandler struct {
+	whatsapp ports.WhatsAppService
+}
+
+// NewMessageHandler creates a new MessageHandler
+func NewMessageHandler(whatsapp ports.WhatsAppService) *MessageHandler {
+	return &MessageHandler{whatsapp: whatsapp}
+}
+
+// SendMessage handles POST /send_message/:device - Send a text or file message
+//
+// Form: to, message, receiver_type (individual or group), message_type (text
+// or file; default text), typing ("true" to show typing first), and for files
+// file, filename and caption
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	params := domain.SendMessageParams{
+		DeviceName:   c.Param("device"),
+		To:           c.PostForm("to"),
+		Message:      c.PostForm("message"),
+		ReceiverType: domain.ReceiverType(c.DefaultPostForm("receiver_type", string(domain.ReceiverIndividual))),
+		MessageType:  domain.MessageType(c.DefaultPostForm("message_type", string(domain.MessageTypeText))),
+		Typing:       c.DefaultPostForm("typing", "false") == "true",
+	}
+
+	if params.MessageType == domain.MessageTypeFile {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			handleError(c, apperrors.NewValidationError("File is required for type=file"))
+			return
+		}
+		if fileHeader.Size > maxSendFileSize {
+			handleError(c, apperrors.NewValidationError("File is too large"))
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to open file", err))
+			return
+		}
+		defer file.Close()
+
+		params.FileData, err = io.ReadAll(io.LimitReader(file, maxSendFileSize))
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to read file", err))
+			return
+		}
+		if len(params.FileData) == 0 {
+			handleError(c, apperrors.NewValidationError("File is empty"))
+			return
+		}
+
+		params.FileName = c.PostForm("filename")
+		if params.FileName == "" {
+			params.FileName = fileHeader.Filename
+		}
+		if !strings.Contains(params.FileName, ".") {
+			params.FileName += path.Ext(fileHeader.Filename)
+		}
+
+		params.Caption = c.PostForm("caption")
+		if params.Caption == "" {
+			params.Caption = params.FileName
+		}
+	}
+
+	if err := h.whatsapp.SendMessage(c.Request.Context(), params); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pesan berhasil dikirim"})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler handles device traffic and health metrics
+type MetricsHandler struct {
+	metrics domain.DeviceMetricsReader
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+func NewMetricsHandler(metrics domain.DeviceMetricsReader) *MetricsHandler {
+	return &MetricsHandler{metrics: metrics}
+}
+
+// Prometheus handles GET /metrics - Metrics of every loaded device in the Prometheus text format
+func (h *MetricsHandler) Prometheus(c *gin.Context) {
+	c.Header("Content-Type", prometheusContentType)
+	c.Status(http.StatusOK)
+
+	if err := h.metrics.WritePrometheus(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// ListDeviceMetrics handles GET /metrics/devices - Metrics of every loaded device as JSON
+func (h *MetricsHandler) ListDeviceMetrics(c *gin.Context) {
+	all := h.metrics.AllDeviceMetrics()
+
+	data := make([]gin.H, 0, len(all))
+	for i := range all {
+		data = append(data, deviceMetricsResponse(&all[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetDeviceMetrics handles GET /whatsapp/:device/metrics - Metrics of a device as JSON
+func (h *MetricsHandler) GetDeviceMetrics(c *gin.Context) {
+	deviceName := c.Param("device")
+
+	metrics, ok := h.metrics.DeviceMetrics(deviceName)
+	if !ok {
+		handleError(c, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName)))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deviceMetricsResponse(metrics)})
+}
+
+// deviceMetricsResponse converts device metrics to their JSON representation
+func deviceMetricsResponse(metrics *domain.DeviceMetrics) gin.H {
+	processors := make([]gin.H, 0, len(metrics.Processors))
+	for _, p := range metrics.Processors {
+		processors = append(processors, gin.H{
+			"processor":          p.Processor,
+			"processed":          p.Processed,
+			"failed":             p.Failed,
+			"average_latency_ms": p.AverageLatency.Milliseconds(),
+			"max_latency_ms":     p.MaxLatency.Milliseconds(),
+		})
+	}
+
+	response := gin.H{
+		"device":            metrics.DeviceName,
+		"status":            metrics.Status,
+		"connected":         metrics.Connected,
+		"messages_sent":     metrics.MessagesSent,
+		"messages_received": metrics.MessagesReceived,
+		"send_failures":     metrics.SendFailures,
+		"reconnects":        metrics.Reconnects,
+		"queue_depth":       metrics.QueueDepth,
+		"processing":        metrics.Processing,
+		"processors":        processors,
+	}
+	if metrics.LastSentAt != nil {
+		response["last_sent_at"] = metrics.LastSentAt
+		response["seconds_since_last_sent"] = int(time.Since(*metrics.LastSentAt).Seconds())
+	}
+	if metrics.LastReceivedAt != nil {
+		response["last_received_at"] = metrics.LastReceivedAt
+		response["seconds_since_last_received"] = int(time.Since(*metrics.LastReceivedAt).Seconds())
+	}
+	return response
+}
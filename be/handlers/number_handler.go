@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// NumberHandler handles checking phone numbers against WhatsApp
+type NumberHandler struct {
+	check *waUsecase.CheckNumbersUseCase
+}
+
+// NewNumberHandler creates a new NumberHandler
+func NewNumberHandler(check *waUsecase.CheckNumbersUseCase) *NumberHandler {
+	return &NumberHandler{check: check}
+}
+
+// checkNumbersRequest is the body of a number check
+type checkNumbersRequest struct {
+	Numbers []string `json:"numbers" binding:"required"`
+}
+
+// CheckNumbers handles POST /whatsapp/:device/numbers/check - Check which phone numbers are on WhatsApp
+//
+// Numbers may be in international (+62 812...) or national (0812...) format.
+// Invalid numbers get an error in their result instead of failing the request.
+func (h *NumberHandler) CheckNumbers(c *gin.Context) {
+	var req checkNumbersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	results, err := h.check.Execute(c.Request.Context(), c.Param("device"), req.Numbers)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	registered := 0
+	data := make([]gin.H, 0, len(results))
+	for _, result := range results {
+		if result.Registered {
+			registered++
+		}
+		data = append(data, gin.H{
+			"input":         result.Input,
+			"phone":         result.Phone,
+			"jid":           result.JID,
+			"registered":    result.Registered,
+			"business_name": result.BusinessName,
+			"error":         result.Error,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       data,
+		"total":      len(data),
+		"registered": registered,
+	})
+}
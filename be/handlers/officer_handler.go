@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/validator"
+)
+
+// OfficerHandler handles Quick Response officer roster requests
+type OfficerHandler struct {
+	repository qrDomain.OfficerRepository
+}
+
+// NewOfficerHandler creates a new OfficerHandler
+func NewOfficerHandler(repository qrDomain.OfficerRepository) *OfficerHandler {
+	return &OfficerHandler{repository: repository}
+}
+
+// officerRequest represents the body of create and update requests
+type officerRequest struct {
+	Name       string `json:"name" binding:"required"`
+	JID        string `json:"jid"` // JID or phone number in international format
+	Position   string `json:"position"`
+	Assignment string `json:"assignment"`
+	Cadence    string `json:"cadence" binding:"required,oneof=daily weekly"`
+	Weekday    int    `json:"weekday"`
+	Active     *bool  `json:"active"`
+}
+
+// apply copies the request onto an officer
+func (r *officerRequest) apply(officer *qrDomain.Officer) error {
+	jid := strings.TrimSpace(r.JID)
+	if jid != "" && !strings.Contains(jid, "@") {
+		jid = strings.TrimPrefix(jid, "+") + "@s.whatsapp.net"
+	}
+	if jid != "" && !validator.ValidateWhatsAppJID(jid) {
+		return apperrors.NewValidationError("Invalid WhatsApp JID: " + r.JID)
+	}
+
+	officer.Name = strings.TrimSpace(r.Name)
+	officer.JID = jid
+	officer.Position = r.Position
+	officer.Assignment = r.Assignment
+	officer.Cadence = qrDomain.ReportCadence(r.Cadence)
+	officer.Weekday = time.Weekday(r.Weekday)
+	if r.Active != nil {
+		officer.Active = *r.Active
+	}
+
+	if err := officer.Validate(); err != nil {
+		return apperrors.NewValidationError(err.Error())
+	}
+	return nil
+}
+
+// CreateOfficer handles POST /quick_response/officers - Add an officer to the roster
+func (h *OfficerHandler) CreateOfficer(c *gin.Context) {
+	var req officerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	officer := &qrDomain.Officer{Active: true}
+	if err := req.apply(officer); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := h.repository.Create(officer); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Officer created successfully",
+		"data":    officerResponse(officer),
+	})
+}
+
+// ListOfficers handles GET /quick_response/officers - List the officer roster
+func (h *OfficerHandler) ListOfficers(c *gin.Context) {
+	officers, err := h.repository.FindAll()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(officers))
+	for _, officer := range officers {
+		data = append(data, officerResponse(officer))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetOfficer handles GET /quick_response/officers/:id - Get an officer
+func (h *OfficerHandler) GetOfficer(c *gin.Context) {
+	officer, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": officerResponse(officer)})
+}
+
+// UpdateOfficer handles PUT /quick_response/officers/:id - Update an officer
+func (h *OfficerHandler) UpdateOfficer(c *gin.Context) {
+	officer, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var req officerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := req.apply(officer); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := h.repository.Update(officer); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Officer updated successfully",
+		"data":    officerResponse(officer),
+	})
+}
+
+// DeleteOfficer handles DELETE /quick_response/officers/:id - Remove an officer from the roster
+func (h *OfficerHandler) DeleteOfficer(c *gin.Context) {
+	if err := h.repository.Delete(c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Officer deleted successfully"})
+}
+
+// officerResponse converts an officer to its JSON representation
+func officerResponse(officer *qrDomain.Officer) gin.H {
+	return gin.H{
+		"id":         officer.ID,
+		"name":       officer.Name,
+		"jid":        officer.JID,
+		"position":   officer.Position,
+		"assignment": officer.Assignment,
+		"cadence":    officer.Cadence,
+		"weekday":    int(officer.Weekday),
+		"active":     officer.Active,
+		"created_at": officer.CreatedAt,
+		"updated_at": officer.UpdatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// PresenceHandler handles the presence of a device and of the contacts it follows
+type PresenceHandler struct {
+	whatsapp ports.WhatsAppService
+	follow   *waUsecase.FollowPresenceUseCase
+	get      *waUsecase.GetPresenceUseCase
+}
+
+// NewPresenceHandler creates a new PresenceHandler
+func NewPresenceHandler(whatsapp ports.WhatsAppService, follow *waUsecase.FollowPresenceUseCase, get *waUsecase.GetPresenceUseCase) *PresenceHandler {
+	return &PresenceHandler{whatsapp: whatsapp, follow: follow, get: get}
+}
+
+// setPresenceRequest is the body of a presence change
+type setPresenceRequest struct {
+	Available *bool `json:"available" binding:"required"`
+}
+
+// SetPresence handles PUT /whatsapp/:device/presence - Mark the device available or unavailable
+func (h *PresenceHandler) SetPresence(c *gin.Context) {
+	var req setPresenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("available is required"))
+		return
+	}
+
+	if err := h.whatsapp.SetPresence(c.Request.Context(), c.Param("device"), *req.Available); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Presence updated",
+		"data":    gin.H{"available": *req.Available},
+	})
+}
+
+// FollowPresence handles POST /whatsapp/:device/presence/:jid - Follow a contact's online status
+func (h *PresenceHandler) FollowPresence(c *gin.Context) {
+	jid, err := h.follow.Follow(c.Request.Context(), c.Param("device"), c.Param("jid"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Following contact presence; updates arrive on the device event stream",
+		"data":    gin.H{"jid": jid},
+	})
+}
+
+// UnfollowPresence handles DELETE /whatsapp/:device/presence/:jid - Stop following a contact's online status
+func (h *PresenceHandler) UnfollowPresence(c *gin.Context) {
+	if err := h.follow.Unfollow(c.Request.Context(), c.Param("device"), c.Param("jid")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stopped following contact presence"})
+}
+
+// GetPresence handles GET /whatsapp/:device/presence/:jid - Get the last known online status of a followed contact
+func (h *PresenceHandler) GetPresence(c *gin.Context) {
+	presence, err := h.get.Execute(c.Request.Context(), c.Param("device"), c.Param("jid"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	status := "unknown"
+	if !presence.UpdatedAt.IsZero() {
+		status = "unavailable"
+		if presence.Available {
+			status = "available"
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"jid":        presence.JID,
+			"status":     status,
+			"last_seen":  presence.LastSeen,
+			"updated_at": presence.UpdatedAt,
+		},
+	})
+}
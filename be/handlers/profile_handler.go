@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// maxProfilePhotoSize bounds the size of an uploaded profile picture
+const maxProfilePhotoSize = 10 << 20
+
+// ProfileHandler handles the profile of a device and profile pictures of its contacts and groups
+type ProfileHandler struct {
+	get     *waUsecase.GetProfileUseCase
+	update  *waUsecase.UpdateProfileUseCase
+	photo   *waUsecase.SetProfilePhotoUseCase
+	picture *waUsecase.ProfilePictureUseCase
+}
+
+// NewProfileHandler creates a new ProfileHandler
+func NewProfileHandler(
+	get *waUsecase.GetProfileUseCase,
+	update *waUsecase.UpdateProfileUseCase,
+	photo *waUsecase.SetProfilePhotoUseCase,
+	picture *waUsecase.ProfilePictureUseCase,
+) *ProfileHandler {
+	return &ProfileHandler{
+		get:     get,
+		update:  update,
+		photo:   photo,
+		picture: picture,
+	}
+}
+
+// updateProfileRequest is the body of a profile update; omitted fields are left unchanged
+type updateProfileRequest struct {
+	PushName *string `json:"push_name"`
+	About    *string `json:"about"`
+}
+
+// GetProfile handles GET /whatsapp/:device/profile - Get the display name, about text and picture ID of the device
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	profile, err := h.get.Execute(c.Request.Context(), c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": profileResponse(profile)})
+}
+
+// UpdateProfile handles PUT /whatsapp/:device/profile - Change the display name and/or about text of the device
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	var req updateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("Invalid request body"))
+		return
+	}
+
+	profile, err := h.update.Execute(c.Request.Context(), c.Param("device"), waUsecase.ProfileUpdate{
+		PushName: req.PushName,
+		About:    req.About,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Profile updated",
+		"data":    profileResponse(profile),
+	})
+}
+
+// SetPhoto handles PUT /whatsapp/:device/profile/photo - Change the profile picture of the device
+//
+// Multipart form field: photo (JPEG or PNG file)
+func (h *ProfileHandler) SetPhoto(c *gin.Context) {
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("photo file is required"))
+		return
+	}
+	if fileHeader.Size > maxProfilePhotoSize {
+		handleError(c, apperrors.NewValidationError("photo file is too large"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to open photo file", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxProfilePhotoSize))
+	if err != nil {
+		handleError(c, apperrors.NewInternalError("Failed to read photo file", err))
+		return
+	}
+
+	pictureID, err := h.photo.Execute(c.Request.Context(), c.Param("device"), data)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Profile picture updated",
+		"data":    gin.H{"picture_id": pictureID},
+	})
+}
+
+// GetProfilePicture handles GET /whatsapp/:device/profile-pictures/:jid - Get a contact's or group's profile picture
+//
+// Query parameters: preview (thumbnail instead of full size), refresh (check
+// WhatsApp for a new picture even if the cached copy is recent)
+func (h *ProfileHandler) GetProfilePicture(c *gin.Context) {
+	picture, err := h.fetchPicture(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"jid":        picture.JID,
+			"picture_id": picture.ID,
+			"type":       picture.Type,
+			"url":        picture.URL,
+			"fetched_at": picture.FetchedAt,
+		},
+	})
+}
+
+// GetProfilePictureImage handles GET /whatsapp/:device/profile-pictures/:jid/image - Serve the cached profile picture
+//
+// Takes the same query parameters as GetProfilePicture.
+func (h *ProfileHandler) GetProfilePictureImage(c *gin.Context) {
+	picture, err := h.fetchPicture(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Header("ETag", strconv.Quote(picture.ID))
+	c.File(picture.LocalPath)
+}
+
+// fetchPicture runs the profile picture use case for the request
+func (h *ProfileHandler) fetchPicture(c *gin.Context) (*domain.ProfilePicture, error) {
+	preview, _ := strconv.ParseBool(c.DefaultQuery("preview", "false"))
+	refresh, _ := strconv.ParseBool(c.DefaultQuery("refresh", "false"))
+
+	return h.picture.Execute(c.Request.Context(), c.Param("device"), c.Param("jid"), preview, refresh)
+}
+
+// profileResponse converts a device profile to its JSON representation
+func profileResponse(profile *domain.DeviceProfile) gin.H {
+	return gin.H{
+		"device":     profile.DeviceName,
+		"jid":        profile.JID,
+		"push_name":  profile.PushName,
+		"about":      profile.About,
+		"picture_id": profile.PictureID,
+	}
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/qrimage"
+)
+
+const (
+	// maxPairingWait bounds how long a long-poll for the next QR code is held open
+	maxPairingWait = 60 * time.Second
+
+	// maxQRImageSize bounds the size of PNG QR codes
+	maxQRImageSize = 1024
+)
+
+// QR code output formats
+const (
+	qrFormatJSON   = "json"   // Session only
+	qrFormatBase64 = "base64" // Session with the PNG as a data URI
+	qrFormatPNG    = "png"
+	qrFormatSVG    = "svg"
+	qrFormatASCII  = "ascii" // Block characters for terminals
+)
+
+// QRPairingHandler handles pairing devices by QR code
+type QRPairingHandler struct {
+	pairing *waUsecase.QRPairingUseCase
+	streams context.Context // Long-polls and streams end when it is done
+}
+
+// NewQRPairingHandler creates a new QRPairingHandler
+func NewQRPairingHandler(pairing *waUsecase.QRPairingUseCase, streams context.Context) *QRPairingHandler {
+	return &QRPairingHandler{pairing: pairing, streams: streams}
+}
+
+// waitContext bounds a wait for the next QR code by the request, the timeout
+// and the server shutting down
+func (h *QRPairingHandler) waitContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	stop := context.AfterFunc(h.streams, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// GetPairing handles GET /whatsapp/:device/pairing - Show the latest QR pairing session without starting one
+func (h *QRPairingHandler) GetPairing(c *gin.Context) {
+	session, err := h.pairing.Get(c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": pairingResponse(session)})
+}
+
+// GetQRCode handles GET /whatsapp/:device/pairing/qr - Get the current QR code, starting a pairing session if needed
+//
+// Query parameters: format (json, base64, png, svg or ascii; default json),
+// size (PNG width in pixels), after and wait (long-poll: hold the request up to
+// wait seconds until a code newer than the after-th is issued or the session ends)
+func (h *QRPairingHandler) GetQRCode(c *gin.Context) {
+	format := c.DefaultQuery("format", qrFormatJSON)
+	if !validQRFormat(format) {
+		handleError(c, apperrors.NewValidationError("format must be json, base64, png, svg or ascii"))
+		return
+	}
+	after, _ := strconv.Atoi(c.DefaultQuery("after", "0"))
+	waitSeconds, _ := strconv.Atoi(c.DefaultQuery("wait", "0"))
+
+	session, err := h.currentSession(c.Request.Context(), c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if waitSeconds > 0 && session.IsActive() && session.Sequence <= after {
+		wait := time.Duration(waitSeconds) * time.Second
+		if wait > maxPairingWait {
+			wait = maxPairingWait
+		}
+		ctx, cancel := h.waitContext(c, wait)
+		defer cancel()
+
+		session, err = h.pairing.Wait(ctx, c.Param("device"), after)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+	}
+
+	renderPairing(c, session, format)
+}
+
+// GetLegacyQRCode handles GET /whatsapp/:device/qrcode - Get the current QR code as an image, starting a pairing session if needed
+//
+// Kept for clients of the first QR endpoint: format defaults to png, and a
+// paired device answers "Device already connected" instead of an error.
+func (h *QRPairingHandler) GetLegacyQRCode(c *gin.Context) {
+	format := c.DefaultQuery("format", qrFormatPNG)
+	if !validQRFormat(format) {
+		handleError(c, apperrors.NewValidationError("format must be png, svg, base64 or ascii"))
+		return
+	}
+
+	// Starting a session for a paired device is a conflict
+	session, err := h.currentSession(c.Request.Context(), c.Param("device"))
+	if err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeConflict {
+		handleError(c, err)
+		return
+	}
+	if err != nil || session.Status == domain.PairingSuccess {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"message": "Device already connected",
+		})
+		return
+	}
+
+	renderPairing(c, session, format)
+}
+
+// StreamQRCode handles GET /whatsapp/:device/pairing/qr/stream - Stream QR codes as they are issued (Server-Sent Events)
+//
+// Sends a "qr" event with the session and the PNG as a data URI for every
+// code, then a "pairing" event with the outcome, after which the stream ends.
+func (h *QRPairingHandler) StreamQRCode(c *gin.Context) {
+	deviceName := c.Param("device")
+
+	session, err := h.currentSession(c.Request.Context(), deviceName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	sent := 0
+	c.Stream(func(w io.Writer) bool {
+		if !session.IsActive() {
+			c.SSEvent("pairing", pairingResponse(session))
+			return false
+		}
+		if session.Sequence > sent {
+			c.SSEvent("qr", pairingImageResponse(session))
+			sent = session.Sequence
+		}
+
+		ctx, cancel := h.waitContext(c, eventKeepAliveInterval)
+		defer cancel()
+
+		session, err = h.pairing.Wait(ctx, deviceName, sent)
+		if err != nil || c.Request.Context().Err() != nil || h.streams.Err() != nil {
+			return false
+		}
+		if session.IsActive() && session.Sequence == sent {
+			c.SSEvent("keepalive", gin.H{"timestamp": time.Now()})
+		}
+		return true
+	})
+}
+
+// currentSession returns the pairing session to show: the active one, the
+// successful one, or a new one when the last has expired or failed
+func (h *QRPairingHandler) currentSession(ctx context.Context, deviceName string) (*domain.PairingSession, error) {
+	session, err := h.pairing.Get(deviceName)
+	if err == nil && (session.IsActive() || session.Status == domain.PairingSuccess) {
+		return session, nil
+	}
+	return h.pairing.Start(ctx, deviceName)
+}
+
+// validQRFormat reports whether format is a known QR code output format
+func validQRFormat(format string) bool {
+	switch format {
+	case qrFormatJSON, qrFormatBase64, qrFormatPNG, qrFormatSVG, qrFormatASCII:
+		return true
+	}
+	return false
+}
+
+// renderPairing writes the pairing session in the requested format. Image
+// formats carry the session in X-Pairing-* headers and need a current code.
+func renderPairing(c *gin.Context, session *domain.PairingSession, format string) {
+	c.Header("Cache-Control", "no-store")
+
+	switch format {
+	case qrFormatJSON:
+		c.JSON(http.StatusOK, gin.H{"data": pairingResponse(session)})
+		return
+	case qrFormatBase64:
+		c.JSON(http.StatusOK, gin.H{"data": pairingImageResponse(session)})
+		return
+	}
+
+	if session.Code == "" {
+		handleError(c, apperrors.New(apperrors.ErrorTypeConflict, "Pairing session has no current QR code").
+			WithDetails("status", session.Status))
+		return
+	}
+
+	c.Header("X-Pairing-Status", string(session.Status))
+	c.Header("X-Pairing-Sequence", strconv.Itoa(session.Sequence))
+	c.Header("X-Pairing-Expires-At", session.ExpiresAt.UTC().Format(time.RFC3339))
+
+	switch format {
+	case qrFormatPNG:
+		size, _ := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(qrimage.DefaultSize)))
+		if size <= 0 || size > maxQRImageSize {
+			size = qrimage.DefaultSize
+		}
+		png, err := qrimage.PNG(session.Code, size)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to render QR code", err))
+			return
+		}
+		c.Data(http.StatusOK, "image/png", png)
+
+	case qrFormatSVG:
+		svg, err := qrimage.SVG(session.Code)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to render QR code", err))
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml", []byte(svg))
+
+	case qrFormatASCII:
+		text, err := qrimage.Terminal(session.Code)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to render QR code", err))
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+	}
+}
+
+// pairingResponse converts a pairing session to its JSON representation
+func pairingResponse(session *domain.PairingSession) gin.H {
+	response := gin.H{
+		"device":     session.DeviceName,
+		"status":     session.Status,
+		"sequence":   session.Sequence,
+		"started_at": session.StartedAt,
+	}
+	if session.Code != "" {
+		response["qr_code"] = session.Code
+		response["expires_at"] = session.ExpiresAt
+		response["expires_in"] = int(time.Until(session.ExpiresAt).Seconds())
+	}
+	if session.EndedAt != nil {
+		response["ended_at"] = session.EndedAt
+	}
+	if session.JID != "" {
+		response["jid"] = session.JID
+	}
+	if session.Error != "" {
+		response["error"] = session.Error
+	}
+	return response
+}
+
+// pairingImageResponse adds the current QR code as a PNG data URI to the session
+func pairingImageResponse(session *domain.PairingSession) gin.H {
+	response := pairingResponse(session)
+	if session.Code == "" {
+		return response
+	}
+
+	if png, err := qrimage.PNG(session.Code, qrimage.DefaultSize); err == nil {
+		response["image"] = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	}
+	return response
+}
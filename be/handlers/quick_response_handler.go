@@ -6,18 +6,37 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ubaidillahfaris/whatsapp.git/db"
 	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type QuickResponseHandler struct {
+	repository qrDomain.QuickResponseRepository
 }
 
-func NewQuickResponseHandler() *QuickResponseHandler {
-	return &QuickResponseHandler{}
+// NewQuickResponseHandler membuat handler Quick Response. Dengan repository,
+// laporan dibaca dalam kedua skema dan selalu dikembalikan dalam bentuk lama.
+func NewQuickResponseHandler(repository qrDomain.QuickResponseRepository) *QuickResponseHandler {
+	return &QuickResponseHandler{repository: repository}
 }
 
 func (q *QuickResponseHandler) GetAll(c *gin.Context) {
 	skip, limit := helpers.GetPagination(c, 20)
+	if q.repository != nil {
+		reports, err := q.repository.FindAll(int(skip), int(limit))
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+
+		data := make([]gin.H, 0, len(reports))
+		for _, report := range reports {
+			data = append(data, legacyQuickResponse(report))
+		}
+		c.JSON(http.StatusOK, gin.H{"quick_response": data})
+		return
+	}
+
 	qr, err := db.Mongo.FindAll(c.Request.Context(), "quick_responses", nil, &skip, &limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -42,3 +61,38 @@ func (q *QuickResponseHandler) DeleteId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Quick response deleted"})
 }
+
+// legacyQuickResponse mengubah laporan ke bentuk dokumen lama (models.QuickResponse)
+// yang dikembalikan GET /quick_response/ sejak awal
+func legacyQuickResponse(report *qrDomain.QuickResponse) gin.H {
+	return gin.H{
+		"_id":         report.ID,
+		"message_id":  report.MessageID,
+		"device_name": report.DeviceName,
+		"sender":      report.Sender,
+		"petugas": gin.H{
+			"nama":         report.Officer.Name,
+			"jabatan":      report.Officer.Position,
+			"di_penugasan": report.Officer.Assignment,
+		},
+		"identifikasi_kegiatan_qr": gin.H{
+			"metode_penugasan":      report.Activity.Method,
+			"kegiatan_qr":           report.Activity.ActivityType,
+			"di_qr":                 report.Activity.IrrigationDI,
+			"saluran_qr":            report.Activity.Channel,
+			"ruas_bangunan_qr":      report.Activity.BuildingRoute,
+			"desa_kecamatan_kab_qr": report.Activity.Location,
+			"upt_psda_ws":           report.Activity.WatershedUnit,
+		},
+		"output_kegiatan_qr": gin.H{
+			"luas_area_kegiatan":  report.Output.AreaSize,
+			"panjang_saluran":     report.Output.ChannelLength,
+			"menutup_bocoran":     report.Output.LeaksClosed,
+			"angkat_sedimen":      report.Output.SedimentRemoved,
+			"pembersihan_sampah":  report.Output.TrashCleared,
+			"angkat_potong_pohon": report.Output.TreeCutRemoved,
+		},
+		"revision":   report.Revision,
+		"created_at": report.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// QuickResponseMigrationHandler handles Quick Response schema migration requests
+type QuickResponseMigrationHandler struct {
+	migrator qrDomain.SchemaMigrator
+}
+
+// NewQuickResponseMigrationHandler creates a new QuickResponseMigrationHandler
+func NewQuickResponseMigrationHandler(migrator qrDomain.SchemaMigrator) *QuickResponseMigrationHandler {
+	return &QuickResponseMigrationHandler{migrator: migrator}
+}
+
+// GetStatus handles GET /quick_response/migrations/v2 - Show migration progress
+func (h *QuickResponseMigrationHandler) GetStatus(c *gin.Context) {
+	progress, remaining, err := h.migrator.Status(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"remaining": remaining,
+			"progress":  migrationProgressResponse(progress),
+		},
+	})
+}
+
+// Migrate handles POST /quick_response/migrations/v2 - Convert legacy documents
+//
+// Query parameters: dry_run (default true), batch_size (default 500), max_batches (default 0 = all)
+// Documents that cannot be converted are marked with migration_skipped and
+// no longer count as remaining.
+func (h *QuickResponseMigrationHandler) Migrate(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		handleError(c, apperrors.NewValidationError("dry_run must be a boolean"))
+		return
+	}
+	batchSize, err := strconv.Atoi(c.DefaultQuery("batch_size", "500"))
+	if err != nil || batchSize <= 0 {
+		handleError(c, apperrors.NewValidationError("batch_size must be a positive integer"))
+		return
+	}
+	maxBatches, err := strconv.Atoi(c.DefaultQuery("max_batches", "0"))
+	if err != nil || maxBatches < 0 {
+		handleError(c, apperrors.NewValidationError("max_batches must be a non-negative integer"))
+		return
+	}
+
+	report, err := h.migrator.Migrate(c.Request.Context(), qrDomain.MigrationOptions{
+		BatchSize:  batchSize,
+		MaxBatches: maxBatches,
+		DryRun:     dryRun,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	samples := make([]gin.H, 0, len(report.Samples))
+	for _, sample := range report.Samples {
+		samples = append(samples, gin.H{
+			"id":     sample.ID,
+			"before": sample.Before,
+			"after":  sample.After,
+		})
+	}
+
+	message := "Migration batch completed"
+	if report.DryRun {
+		message = "Dry run completed, no documents were changed"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data": gin.H{
+			"dry_run":   report.DryRun,
+			"remaining": report.Remaining,
+			"batches":   report.Batches,
+			"migrated":  report.Migrated,
+			"skipped":   report.Skipped,
+			"failed":    report.Failed,
+			"done":      report.Done,
+			"samples":   samples,
+			"progress":  migrationProgressResponse(report.Progress),
+		},
+	})
+}
+
+// migrationProgressResponse converts migration progress to its JSON representation
+func migrationProgressResponse(progress *qrDomain.MigrationProgress) gin.H {
+	return gin.H{
+		"name":         progress.Name,
+		"status":       progress.Status,
+		"last_id":      progress.LastID,
+		"migrated":     progress.Migrated,
+		"skipped":      progress.Skipped,
+		"failed":       progress.Failed,
+		"last_error":   progress.LastError,
+		"started_at":   progress.StartedAt,
+		"updated_at":   progress.UpdatedAt,
+		"completed_at": progress.CompletedAt,
+	}
+}
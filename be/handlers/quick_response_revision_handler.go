@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+)
+
+// QuickResponseRevisionHandler exposes the correction history of Quick Response reports
+type QuickResponseRevisionHandler struct {
+	repository qrDomain.QuickResponseRepository
+}
+
+// NewQuickResponseRevisionHandler creates a new QuickResponseRevisionHandler
+func NewQuickResponseRevisionHandler(repository qrDomain.QuickResponseRepository) *QuickResponseRevisionHandler {
+	return &QuickResponseRevisionHandler{repository: repository}
+}
+
+// GetRevisions handles GET /quick_response/:id/revisions - List corrections of a report
+func (h *QuickResponseRevisionHandler) GetRevisions(c *gin.Context) {
+	qr, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	revisions := make([]gin.H, 0, len(qr.Revisions))
+	for _, rev := range qr.Revisions {
+		changes := make([]gin.H, 0, len(rev.Changes))
+		for _, change := range rev.Changes {
+			changes = append(changes, gin.H{
+				"field":     change.Field,
+				"old_value": change.OldValue,
+				"new_value": change.NewValue,
+			})
+		}
+
+		revisions = append(revisions, gin.H{
+			"number":     rev.Number,
+			"message_id": rev.MessageID,
+			"changes":    changes,
+			"created_at": rev.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":         qr.ID,
+		"message_id": qr.MessageID,
+		"sender":     qr.Sender,
+		"revision":   qr.Revision,
+		"revisions":  revisions,
+		"created_at": qr.CreatedAt,
+		"updated_at": qr.UpdatedAt,
+	})
+}
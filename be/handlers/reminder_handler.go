@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
+)
+
+// ReminderHandler handles missing-report reminder requests. Reminders are
+// sent only from a device the caller owns.
+type ReminderHandler struct {
+	repository qrDomain.ReminderRepository
+	service    *quickresponse.ReminderService
+	authorize  *device.AuthorizeDeviceUseCase
+}
+
+// NewReminderHandler creates a new ReminderHandler
+func NewReminderHandler(repository qrDomain.ReminderRepository, service *quickresponse.ReminderService, authorize *device.AuthorizeDeviceUseCase) *ReminderHandler {
+	return &ReminderHandler{
+		repository: repository,
+		service:    service,
+		authorize:  authorize,
+	}
+}
+
+// reminderConfigRequest represents the body of a reminder config update
+type reminderConfigRequest struct {
+	Enabled         bool   `json:"enabled"`
+	DeviceName      string `json:"device_name"`
+	CutoffTime      string `json:"cutoff_time" binding:"required"`
+	Timezone        string `json:"timezone"`
+	QuietHoursStart string `json:"quiet_hours_start"`
+	QuietHoursEnd   string `json:"quiet_hours_end"`
+	MaxPerDay       int    `json:"max_per_day" binding:"required"`
+	IntervalMinutes int    `json:"interval_minutes"`
+	Message         string `json:"message"`
+}
+
+// GetConfig handles GET /quick_response/reminders/config - Get reminder settings
+func (h *ReminderHandler) GetConfig(c *gin.Context) {
+	config, err := h.repository.GetConfig()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reminderConfigResponse(config)})
+}
+
+// UpdateConfig handles PUT /quick_response/reminders/config - Update reminder settings
+func (h *ReminderHandler) UpdateConfig(c *gin.Context) {
+	var req reminderConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	config := &qrDomain.ReminderConfig{
+		Enabled:         req.Enabled,
+		DeviceName:      req.DeviceName,
+		CutoffTime:      req.CutoffTime,
+		Timezone:        req.Timezone,
+		QuietHoursStart: req.QuietHoursStart,
+		QuietHoursEnd:   req.QuietHoursEnd,
+		MaxPerDay:       req.MaxPerDay,
+		IntervalMinutes: req.IntervalMinutes,
+		Message:         req.Message,
+	}
+
+	if err := config.Validate(); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	// A disabled config may leave the device unset
+	if config.DeviceName != "" {
+		if _, err := h.authorize.Execute(c.Request.Context(), config.DeviceName, middlewares.GetCallerFromContext(c)); err != nil {
+			handleError(c, err)
+			return
+		}
+	}
+
+	if err := h.repository.SaveConfig(config); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reminder config updated successfully",
+		"data":    reminderConfigResponse(config),
+	})
+}
+
+// ListLogs handles GET /quick_response/reminders/logs - List sent reminders
+func (h *ReminderHandler) ListLogs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	logs, err := h.repository.FindLogs(offset, limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(logs))
+	for _, log := range logs {
+		data = append(data, gin.H{
+			"id":           log.ID,
+			"officer_id":   log.OfficerID,
+			"officer_name": log.OfficerName,
+			"jid":          log.JID,
+			"device_name":  log.DeviceName,
+			"deadline":     log.Deadline,
+			"message":      log.Message,
+			"status":       log.Status,
+			"error":        log.Error,
+			"created_at":   log.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// RunReminders handles POST /quick_response/reminders/run - Send due reminders immediately
+func (h *ReminderHandler) RunReminders(c *gin.Context) {
+	result, err := h.service.Run(c.Request.Context(), time.Now())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reminders processed",
+		"data": gin.H{
+			"checked":     result.Checked,
+			"missing":     result.Missing,
+			"sent":        result.Sent,
+			"failed":      result.Failed,
+			"skipped":     result.Skipped,
+			"quiet_hours": result.Quiet,
+			"offline":     result.Offline,
+		},
+	})
+}
+
+// reminderConfigResponse converts the reminder config to its JSON representation
+func reminderConfigResponse(config *qrDomain.ReminderConfig) gin.H {
+	return gin.H{
+		"enabled":           config.Enabled,
+		"device_name":       config.DeviceName,
+		"cutoff_time":       config.CutoffTime,
+		"timezone":          config.Timezone,
+		"quiet_hours_start": config.QuietHoursStart,
+		"quiet_hours_end":   config.QuietHoursEnd,
+		"max_per_day":       config.MaxPerDay,
+		"interval_minutes":  config.IntervalMinutes,
+		"message":           config.Message,
+		"updated_at":        config.UpdatedAt,
+	}
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/helpers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule"
+	scheduleDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
+)
+
+// ScheduledMessageHandler handles messages sent later or on a recurring
+// schedule. Callers only see the schedules of devices they own.
+type ScheduledMessageHandler struct {
+	schedules *schedule.ScheduleService
+	authorize *device.AuthorizeDeviceUseCase
+}
+
+// NewScheduledMessageHandler creates a new ScheduledMessageHandler
+func NewScheduledMessageHandler(schedules *schedule.ScheduleService, authorize *device.AuthorizeDeviceUseCase) *ScheduledMessageHandler {
+	return &ScheduledMessageHandler{
+		schedules: schedules,
+		authorize: authorize,
+	}
+}
+
+// createScheduledMessageRequest is the body of a scheduled message, as JSON
+// or as a multipart form with a file
+type createScheduledMessageRequest struct {
+	Device       string `json:"device" form:"device" binding:"required"`
+	To           string `json:"to" form:"to" binding:"required"`
+	ReceiverType string `json:"receiver_type" form:"receiver_type"`
+	Type         string `json:"type" form:"type"`
+	Message      string `json:"message" form:"message"`
+	SendAt       string `json:"send_at" form:"send_at"`
+	Cron         string `json:"cron" form:"cron"`
+	Timezone     string `json:"timezone" form:"timezone"`
+}
+
+// CreateScheduledMessage handles POST /scheduled-messages - Schedule a message once or on a recurrence
+//
+// Body: device, to, receiver_type (individual or group), message, and either
+// send_at (e.g. "2026-11-02 09:00" or RFC 3339) or cron (e.g. "0 9 * * fri"),
+// with an optional timezone. Media is sent as a multipart form with a file
+// field and type (image, video, audio or file); message is then its caption.
+func (h *ScheduledMessageHandler) CreateScheduledMessage(c *gin.Context) {
+	var req createScheduledMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		handleError(c, apperrors.NewValidationError("device and to are required"))
+		return
+	}
+
+	if _, err := h.authorize.Execute(c.Request.Context(), req.Device, middlewares.GetCallerFromContext(c)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	message := &scheduleDomain.ScheduledMessage{
+		DeviceName:  req.Device,
+		To:          req.To,
+		IsGroup:     req.ReceiverType == "group",
+		ContentType: scheduleDomain.ContentText,
+		Message:     req.Message,
+		Cron:        strings.TrimSpace(req.Cron),
+		Timezone:    req.Timezone,
+		CreatedBy:   c.GetString("username"),
+	}
+
+	if req.SendAt != "" {
+		sendAt, err := h.schedules.ParseSendAt(req.SendAt, req.Timezone)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		message.SendAt = &sendAt
+	}
+
+	var media []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if fileHeader.Size > scheduleDomain.MaxMediaSize {
+			handleError(c, apperrors.NewValidationError("file is too large"))
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to open file", err))
+			return
+		}
+		defer file.Close()
+
+		media, err = io.ReadAll(io.LimitReader(file, scheduleDomain.MaxMediaSize))
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("Failed to read file", err))
+			return
+		}
+
+		message.FileName = fileHeader.Filename
+		message.ContentType = scheduleDomain.ContentFile
+		if req.Type != "" {
+			message.ContentType = scheduleDomain.ContentType(req.Type)
+		}
+	} else if req.Type != "" {
+		message.ContentType = scheduleDomain.ContentType(req.Type)
+	}
+
+	if err := h.schedules.Create(message, media); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Message scheduled",
+		"data":    scheduledMessageResponse(message),
+	})
+}
+
+// ListScheduledMessages handles GET /scheduled-messages - List scheduled messages, newest first
+//
+// Query: device (all schedules of a device the caller owns; otherwise the
+// caller's own schedules), status, page and limit
+func (h *ScheduledMessageHandler) ListScheduledMessages(c *gin.Context) {
+	skip, limit := helpers.GetPagination(c, 20)
+
+	filter := scheduleDomain.ScheduleFilter{
+		Status: scheduleDomain.ScheduleStatus(c.Query("status")),
+	}
+	if deviceName := c.Query("device"); deviceName != "" {
+		if _, err := h.authorize.Execute(c.Request.Context(), deviceName, middlewares.GetCallerFromContext(c)); err != nil {
+			handleError(c, err)
+			return
+		}
+		filter.DeviceName = deviceName
+	} else {
+		filter.CreatedBy = c.GetString("username")
+	}
+
+	messages, total, err := h.schedules.List(filter, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(messages))
+	for _, message := range messages {
+		data = append(data, scheduledMessageResponse(message))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// GetScheduledMessage handles GET /scheduled-messages/:id - Get a scheduled message
+func (h *ScheduledMessageHandler) GetScheduledMessage(c *gin.Context) {
+	message, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scheduledMessageResponse(message)})
+}
+
+// ListRuns handles GET /scheduled-messages/:id/runs - List the runs of a scheduled message, newest first
+//
+// Query: page and limit
+func (h *ScheduledMessageHandler) ListRuns(c *gin.Context) {
+	message, ok := h.find(c)
+	if !ok {
+		return
+	}
+
+	skip, limit := helpers.GetPagination(c, 50)
+	runs, total, err := h.schedules.Runs(message.ID, int(skip), int(limit))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(runs))
+	for _, run := range runs {
+		data = append(data, gin.H{
+			"id":            run.ID,
+			"to":            run.To,
+			"scheduled_for": run.ScheduledFor,
+			"ran_at":        run.RanAt,
+			"status":        run.Status,
+			"error":         run.Error,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+	})
+}
+
+// PauseScheduledMessage handles POST /scheduled-messages/:id/pause - Stop a scheduled message until resumed
+func (h *ScheduledMessageHandler) PauseScheduledMessage(c *gin.Context) {
+	if _, ok := h.find(c); !ok {
+		return
+	}
+
+	message, err := h.schedules.Pause(c.Param("id"), c.GetString("username"))
+	h.respondStatus(c, message, err, "Scheduled message paused")
+}
+
+// ResumeScheduledMessage handles POST /scheduled-messages/:id/resume - Resume a paused scheduled message
+func (h *ScheduledMessageHandler) ResumeScheduledMessage(c *gin.Context) {
+	if _, ok := h.find(c); !ok {
+		return
+	}
+
+	message, err := h.schedules.Resume(c.Param("id"))
+	h.respondStatus(c, message, err, "Scheduled message resumed")
+}
+
+// CancelScheduledMessage handles POST /scheduled-messages/:id/cancel - Cancel a scheduled message for good
+func (h *ScheduledMessageHandler) CancelScheduledMessage(c *gin.Context) {
+	if _, ok := h.find(c); !ok {
+		return
+	}
+
+	message, err := h.schedules.Cancel(c.Param("id"), c.GetString("username"))
+	h.respondStatus(c, message, err, "Scheduled message cancelled")
+}
+
+// find retrieves the scheduled message of the request if the caller owns its
+// device, and responds with the error otherwise
+func (h *ScheduledMessageHandler) find(c *gin.Context) (*scheduleDomain.ScheduledMessage, bool) {
+	message, err := h.schedules.Get(c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return nil, false
+	}
+
+	if _, err := h.authorize.Execute(c.Request.Context(), message.DeviceName, middlewares.GetCallerFromContext(c)); err != nil {
+		handleError(c, err)
+		return nil, false
+	}
+	return message, true
+}
+
+// respondStatus responds to a change of a scheduled message's status
+func (h *ScheduledMessageHandler) respondStatus(c *gin.Context, message *scheduleDomain.ScheduledMessage, err error, text string) {
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": text,
+		"data":    scheduledMessageResponse(message),
+	})
+}
+
+// scheduledMessageResponse converts a scheduled message to its JSON representation
+func scheduledMessageResponse(message *scheduleDomain.ScheduledMessage) gin.H {
+	receiverType := "individual"
+	if message.IsGroup {
+		receiverType = "group"
+	}
+
+	return gin.H{
+		"id":              message.ID,
+		"device":          message.DeviceName,
+		"to":              message.To,
+		"receiver_type":   receiverType,
+		"type":            message.ContentType,
+		"message":         message.Message,
+		"file_name":       message.FileName,
+		"file_size":       message.MediaSize,
+		"send_at":         message.SendAt,
+		"cron":            message.Cron,
+		"timezone":        message.Timezone,
+		"status":          message.Status,
+		"status_reason":   message.StatusReason,
+		"next_run_at":     message.NextRunAt,
+		"last_run_at":     message.LastRunAt,
+		"last_run_status": message.LastRunStatus,
+		"last_error":      message.LastError,
+		"run_count":       message.RunCount,
+		"created_by":      message.CreatedBy,
+		"created_at":      message.CreatedAt,
+		"updated_at":      message.UpdatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	broadcastDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// SegmentHandler handles the saved contact segments of a device
+type SegmentHandler struct {
+	repository broadcastDomain.SegmentRepository
+}
+
+// NewSegmentHandler creates a new SegmentHandler
+func NewSegmentHandler(repository broadcastDomain.SegmentRepository) *SegmentHandler {
+	return &SegmentHandler{repository: repository}
+}
+
+// segmentRequest is the body of segment create and update requests
+type segmentRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Tag    string `json:"tag"`
+	Search string `json:"search"`
+}
+
+// apply copies the request onto a segment
+func (r *segmentRequest) apply(segment *broadcastDomain.Segment) {
+	segment.Name = r.Name
+	segment.Tag = r.Tag
+	segment.Search = r.Search
+}
+
+// CreateSegment handles POST /whatsapp/:device/segments - Save a selection of contacts by tag and/or search
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var req segmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	segment := &broadcastDomain.Segment{DeviceName: c.Param("device"), CreatedBy: c.GetString("username")}
+	req.apply(segment)
+
+	if err := segment.Validate(); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := h.repository.Create(segment); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Segment created",
+		"data":    segmentResponse(segment),
+	})
+}
+
+// ListSegments handles GET /whatsapp/:device/segments - List the device's segments
+func (h *SegmentHandler) ListSegments(c *gin.Context) {
+	segments, err := h.repository.FindByDevice(c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(segments))
+	for _, segment := range segments {
+		data = append(data, segmentResponse(segment))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// UpdateSegment handles PUT /whatsapp/:device/segments/:id - Update a segment
+func (h *SegmentHandler) UpdateSegment(c *gin.Context) {
+	segment, err := h.find(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var req segmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+	req.apply(segment)
+
+	if err := segment.Validate(); err != nil {
+		handleError(c, apperrors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := h.repository.Update(segment); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Segment updated",
+		"data":    segmentResponse(segment),
+	})
+}
+
+// DeleteSegment handles DELETE /whatsapp/:device/segments/:id - Delete a segment
+func (h *SegmentHandler) DeleteSegment(c *gin.Context) {
+	segment, err := h.find(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if err := h.repository.Delete(segment.ID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Segment deleted"})
+}
+
+// find retrieves the segment of the request's device; segments of other devices are not found
+func (h *SegmentHandler) find(c *gin.Context) (*broadcastDomain.Segment, error) {
+	segment, err := h.repository.FindByID(c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	if segment.DeviceName != c.Param("device") {
+		return nil, apperrors.NewNotFoundError("Segment")
+	}
+	return segment, nil
+}
+
+// segmentResponse converts a segment to its JSON representation
+func segmentResponse(segment *broadcastDomain.Segment) gin.H {
+	return gin.H{
+		"id":         segment.ID,
+		"name":       segment.Name,
+		"tag":        segment.Tag,
+		"search":     segment.Search,
+		"created_by": segment.CreatedBy,
+		"created_at": segment.CreatedAt,
+		"updated_at": segment.UpdatedAt,
+	}
+}
@@ -10,6 +10,6 @@ type SendMessageHandler struct {
 
 func NewSendMessageHandler(manager *services.WhatsAppManager) *SendMessageHandler {
 	return &SendMessageHandler{
-		ManagerSvc: services.NewWhatsAppManager(),
+		ManagerSvc: manager,
 	}
 }
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// SessionHandler handles persisted WhatsApp session and uptime requests
+type SessionHandler struct {
+	status *waUsecase.SessionStatusUseCase
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(status *waUsecase.SessionStatusUseCase) *SessionHandler {
+	return &SessionHandler{status: status}
+}
+
+// GetStatus handles GET /whatsapp/:device/status - Get the session, live status and uptime of a device
+func (h *SessionHandler) GetStatus(c *gin.Context) {
+	status, err := h.status.Execute(c.Request.Context(), c.Param("device"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var session gin.H
+	if status.Session != nil {
+		session = gin.H{
+			"jid":               status.Session.JID,
+			"status":            status.Session.Status,
+			"last_connected":    status.Session.LastConnected,
+			"last_disconnected": status.Session.LastDisconnected,
+			"store_db_path":     status.Session.StoreDBPath,
+			"created_at":        status.Session.CreatedAt,
+			"updated_at":        status.Session.UpdatedAt,
+		}
+	}
+
+	uptime := make([]gin.H, 0, len(status.Uptime))
+	for _, report := range status.Uptime {
+		uptime = append(uptime, uptimeResponse(report))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"device": status.Live.DeviceName,
+			"live": gin.H{
+				"status":       status.Live.Status,
+				"jid":          status.Live.JID,
+				"is_connected": status.Live.IsConnected,
+			},
+			"session": session,
+			"uptime":  uptime,
+		},
+	})
+}
+
+// GetUptime handles GET /whatsapp/:device/uptime - Get the connection timeline and uptime of a device
+//
+// Query parameters: from, to (RFC 3339, default the last 24 hours)
+func (h *SessionHandler) GetUptime(c *gin.Context) {
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			handleError(c, apperrors.NewValidationError("to must be an RFC 3339 timestamp"))
+			return
+		}
+		to = parsed
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			handleError(c, apperrors.NewValidationError("from must be an RFC 3339 timestamp"))
+			return
+		}
+		from = parsed
+	}
+
+	report, err := h.status.Uptime(c.Request.Context(), c.Param("device"), from, to)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	transitions := make([]gin.H, 0, len(report.Transitions))
+	for _, transition := range report.Transitions {
+		transitions = append(transitions, transitionResponse(transition))
+	}
+
+	data := uptimeResponse(*report)
+	data["transitions"] = transitions
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// uptimeResponse converts an uptime report to its JSON representation
+func uptimeResponse(report domain.UptimeReport) gin.H {
+	return gin.H{
+		"from":              report.From,
+		"to":                report.To,
+		"connected_seconds": int64(report.Connected / time.Second),
+		"ratio":             report.Ratio,
+	}
+}
+
+// transitionResponse converts a connection transition to its JSON representation
+func transitionResponse(transition domain.ConnectionTransition) gin.H {
+	return gin.H{
+		"from":      transition.From,
+		"to":        transition.To,
+		"reason":    transition.Reason,
+		"attempt":   transition.Attempt,
+		"timestamp": transition.Timestamp,
+	}
+}
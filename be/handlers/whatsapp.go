@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/qrimage"
+	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
 	"github.com/ubaidillahfaris/whatsapp.git/services"
 )
 
@@ -18,17 +22,27 @@ type WhatsAppHandler struct {
 	manager *services.WhatsAppManager
 }
 
-// NewWhatsAppHandler menginisialisasi handler dengan WhatsAppManager tunggal.
-func NewWhatsAppHandler() *WhatsAppHandler {
+// NewWhatsAppHandler menginisialisasi handler dengan WhatsAppManager yang diberikan.
+func NewWhatsAppHandler(manager *services.WhatsAppManager) *WhatsAppHandler {
 	return &WhatsAppHandler{
-		manager: services.GetWhatsAppManager(),
+		manager: manager,
 	}
 }
 
-// getOrCreateDevice mencari atau membuat instance WhatsApp berdasarkan nama device.
-func (h *WhatsAppHandler) getOrCreateDevice(deviceName string) (*services.WhatsAppService, error) {
-	ctx := context.Background()
-	return h.manager.GetOrCreateDevice(ctx, deviceName)
+// getDevice mengambil instance WhatsApp untuk device yang sudah diotorisasi
+// DeviceOwnershipMiddleware. Nama device yang tidak terdaftar tidak pernah dibuat.
+func (h *WhatsAppHandler) getDevice(c *gin.Context) (*services.WhatsAppService, error) {
+	registered, ok := middlewares.GetDeviceFromContext(c)
+	if !ok {
+		return nil, apperrors.NewNotFoundError("Device")
+	}
+
+	svc, err := h.manager.GetOrCreateDevice(context.Background(), registered.Name)
+	if err != nil {
+		return nil, apperrors.NewWhatsAppError("failed to get device", err)
+	}
+	svc.SetPresencePolicy(registered.PresencePolicy)
+	return svc, nil
 }
 
 // 📋 Handler: Ambil daftar semua device aktif.
@@ -41,6 +55,8 @@ func (h *WhatsAppHandler) ListDevices(c *gin.Context) {
 }
 
 // 🔑 Handler: Generate QR untuk login WhatsApp.
+//
+// Query format: png (default), svg, base64 (JSON berisi data URI PNG) atau ascii.
 func (h *WhatsAppHandler) GenerateQR(c *gin.Context) {
 	deviceName := c.Param("device")
 	if deviceName == "" {
@@ -49,12 +65,9 @@ func (h *WhatsAppHandler) GenerateQR(c *gin.Context) {
 	}
 
 	// Ambil atau buat instance device
-	svc, err := h.getOrCreateDevice(deviceName)
+	svc, err := h.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "failed to get or create device",
-			"details": err.Error(),
-		})
+		handleError(c, err)
 		return
 	}
 
@@ -88,17 +101,49 @@ func (h *WhatsAppHandler) GenerateQR(c *gin.Context) {
 		return
 	}
 
-	// Convert QR string ke PNG
-	png, err := (&services.QrCode{Code: qr}).ToPNG()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "failed to generate QR image",
-			"details": err.Error(),
+	// Kode QR berganti secara berkala, jangan di-cache
+	c.Header("Cache-Control", "no-store")
+
+	switch c.DefaultQuery("format", qrFormatPNG) {
+	case qrFormatPNG:
+		png, err := qrimage.PNG(qr, qrimage.DefaultSize)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("failed to generate QR image", err))
+			return
+		}
+		c.Data(http.StatusOK, "image/png", png)
+
+	case qrFormatSVG:
+		svg, err := qrimage.SVG(qr)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("failed to generate QR image", err))
+			return
+		}
+		c.Data(http.StatusOK, "image/svg+xml", []byte(svg))
+
+	case qrFormatBase64:
+		png, err := qrimage.PNG(qr, qrimage.DefaultSize)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("failed to generate QR image", err))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"qr_code": qr,
+			"image":   "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
 		})
-		return
-	}
 
-	c.Data(http.StatusOK, "image/png", png)
+	case qrFormatASCII:
+		text, err := qrimage.Terminal(qr)
+		if err != nil {
+			handleError(c, apperrors.NewInternalError("failed to generate QR image", err))
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+
+	default:
+		handleError(c, apperrors.NewValidationError("format must be png, svg, base64 or ascii"))
+	}
 }
 
 // 📡 Handler: Cek status koneksi WhatsApp per device.
@@ -109,9 +154,9 @@ func (h *WhatsAppHandler) GetStatus(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.getOrCreateDevice(deviceName)
+	svc, err := h.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get device", "details": err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -129,9 +174,9 @@ func (h *WhatsAppHandler) Disconnect(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.getOrCreateDevice(deviceName)
+	svc, err := h.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get device", "details": err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -152,12 +197,11 @@ func (h *WhatsAppHandler) ListContacts(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.getOrCreateDevice(deviceName)
+	svc, err := h.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleError(c, err)
 		return
 	}
-	return
 
 	contacts, err := svc.ListContacts()
 	if err != nil {
@@ -179,9 +223,9 @@ func (h *WhatsAppHandler) ListGroups(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.getOrCreateDevice(deviceName)
+	svc, err := h.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -199,7 +243,6 @@ func (h *WhatsAppHandler) ListGroups(c *gin.Context) {
 
 func (s *WhatsAppHandler) SendMessage(c *gin.Context) {
 	ctx := context.Background()
-	deviceID := c.Param("device")
 	to := c.PostForm("to")
 	text := c.PostForm("message")
 	receiver_type := c.PostForm("receiver_type")
@@ -207,9 +250,9 @@ func (s *WhatsAppHandler) SendMessage(c *gin.Context) {
 	typing := c.DefaultPostForm("typing", "false") == "true"
 
 	// Ambil device
-	svc, err := s.manager.GetOrCreateDevice(ctx, deviceID)
+	svc, err := s.getDevice(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal ambil device: " + err.Error()})
+		handleError(c, err)
 		return
 	}
 
@@ -263,6 +306,11 @@ func (s *WhatsAppHandler) SendMessage(c *gin.Context) {
 
 	err = svc.MessageSvc.SendMessage(ctx, to, text, typing, receiver_type, messageType, fileData, filename, caption)
 	if err != nil {
+		// Nomor tidak valid / tidak terdaftar dikembalikan sebagai error validasi
+		if apperrors.IsAppError(err) {
+			handleError(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal kirim pesan: " + err.Error()})
 		return
 	}
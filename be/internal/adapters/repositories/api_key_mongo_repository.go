@@ -30,7 +30,7 @@ func NewAPIKeyMongoRepository(db *mongo.Database, log *logger.Logger) (*APIKeyMo
 
 	// Create indexes
 	if err := repo.createIndexes(context.Background()); err != nil {
-		return nil, errors.Wrap(err, errors.ErrTypeDatabase, "failed to create indexes")
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to create indexes")
 	}
 
 	return repo, nil
@@ -78,14 +78,14 @@ func (r *APIKeyMongoRepository) Create(ctx context.Context, apiKey *domain.APIKe
 	_, err := r.collection.InsertOne(ctx, apiKey)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			r.logger.Error("Duplicate API key", err)
-			return errors.New(errors.ErrTypeValidation, "API key already exists")
+			r.logger.Error("Duplicate API key: %v", err)
+			return errors.New(errors.ErrorTypeValidation, "API key already exists")
 		}
-		r.logger.Error("Failed to create API key", err)
-		return errors.Wrap(err, errors.ErrTypeDatabase, "failed to create API key")
+		r.logger.Error("Failed to create API key: %v", err)
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "failed to create API key")
 	}
 
-	r.logger.Info("API key created", logger.Fields{"id": apiKey.ID, "owner": apiKey.Owner})
+	r.logger.WithFields(map[string]interface{}{"id": apiKey.ID, "owner": apiKey.Owner}).Info("API key created")
 	return nil
 }
 
@@ -96,10 +96,10 @@ func (r *APIKeyMongoRepository) GetByID(ctx context.Context, id string) (*domain
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&apiKey)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New(errors.ErrTypeNotFound, "API key not found")
+			return nil, errors.New(errors.ErrorTypeNotFound, "API key not found")
 		}
-		r.logger.Error("Failed to get API key by ID", err, logger.Fields{"id": id})
-		return nil, errors.Wrap(err, errors.ErrTypeDatabase, "failed to get API key")
+		r.logger.WithFields(map[string]interface{}{"id": id}).Error("Failed to get API key by ID: %v", err)
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to get API key")
 	}
 
 	return &apiKey, nil
@@ -112,10 +112,10 @@ func (r *APIKeyMongoRepository) GetByKey(ctx context.Context, key string) (*doma
 	err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&apiKey)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New(errors.ErrTypeNotFound, "API key not found")
+			return nil, errors.New(errors.ErrorTypeNotFound, "API key not found")
 		}
-		r.logger.Error("Failed to get API key by key", err)
-		return nil, errors.Wrap(err, errors.ErrTypeDatabase, "failed to get API key")
+		r.logger.Error("Failed to get API key by key: %v", err)
+		return nil, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to get API key")
 	}
 
 	return &apiKey, nil
@@ -128,8 +128,8 @@ func (r *APIKeyMongoRepository) List(ctx context.Context, owner string, limit, o
 	// Count total documents
 	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
-		r.logger.Error("Failed to count API keys", err, logger.Fields{"owner": owner})
-		return nil, 0, errors.Wrap(err, errors.ErrTypeDatabase, "failed to count API keys")
+		r.logger.WithFields(map[string]interface{}{"owner": owner}).Error("Failed to count API keys: %v", err)
+		return nil, 0, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to count API keys")
 	}
 
 	// Set default limit if not provided
@@ -145,18 +145,18 @@ func (r *APIKeyMongoRepository) List(ctx context.Context, owner string, limit, o
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		r.logger.Error("Failed to list API keys", err, logger.Fields{"owner": owner})
-		return nil, 0, errors.Wrap(err, errors.ErrTypeDatabase, "failed to list API keys")
+		r.logger.WithFields(map[string]interface{}{"owner": owner}).Error("Failed to list API keys: %v", err)
+		return nil, 0, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to list API keys")
 	}
 	defer cursor.Close(ctx)
 
 	var apiKeys []*domain.APIKey
 	if err := cursor.All(ctx, &apiKeys); err != nil {
-		r.logger.Error("Failed to decode API keys", err)
-		return nil, 0, errors.Wrap(err, errors.ErrTypeDatabase, "failed to decode API keys")
+		r.logger.Error("Failed to decode API keys: %v", err)
+		return nil, 0, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to decode API keys")
 	}
 
-	r.logger.Debug("Listed API keys", logger.Fields{"owner": owner, "count": len(apiKeys), "total": total})
+	r.logger.WithFields(map[string]interface{}{"owner": owner, "count": len(apiKeys), "total": total}).Debug("Listed API keys")
 	return apiKeys, total, nil
 }
 
@@ -169,15 +169,15 @@ func (r *APIKeyMongoRepository) Update(ctx context.Context, apiKey *domain.APIKe
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		r.logger.Error("Failed to update API key", err, logger.Fields{"id": apiKey.ID})
-		return errors.Wrap(err, errors.ErrTypeDatabase, "failed to update API key")
+		r.logger.WithFields(map[string]interface{}{"id": apiKey.ID}).Error("Failed to update API key: %v", err)
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "failed to update API key")
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New(errors.ErrTypeNotFound, "API key not found")
+		return errors.New(errors.ErrorTypeNotFound, "API key not found")
 	}
 
-	r.logger.Info("API key updated", logger.Fields{"id": apiKey.ID})
+	r.logger.WithFields(map[string]interface{}{"id": apiKey.ID}).Info("API key updated")
 	return nil
 }
 
@@ -187,15 +187,15 @@ func (r *APIKeyMongoRepository) Delete(ctx context.Context, id string) error {
 
 	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
-		r.logger.Error("Failed to delete API key", err, logger.Fields{"id": id})
-		return errors.Wrap(err, errors.ErrTypeDatabase, "failed to delete API key")
+		r.logger.WithFields(map[string]interface{}{"id": id}).Error("Failed to delete API key: %v", err)
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "failed to delete API key")
 	}
 
 	if result.DeletedCount == 0 {
-		return errors.New(errors.ErrTypeNotFound, "API key not found")
+		return errors.New(errors.ErrorTypeNotFound, "API key not found")
 	}
 
-	r.logger.Info("API key deleted", logger.Fields{"id": id})
+	r.logger.WithFields(map[string]interface{}{"id": id}).Info("API key deleted")
 	return nil
 }
 
@@ -212,12 +212,12 @@ func (r *APIKeyMongoRepository) UpdateLastUsed(ctx context.Context, key string)
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		r.logger.Error("Failed to update last used timestamp", err)
-		return errors.Wrap(err, errors.ErrTypeDatabase, "failed to update last used timestamp")
+		r.logger.Error("Failed to update last used timestamp: %v", err)
+		return errors.Wrap(err, errors.ErrorTypeDatabase, "failed to update last used timestamp")
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New(errors.ErrTypeNotFound, "API key not found")
+		return errors.New(errors.ErrorTypeNotFound, "API key not found")
 	}
 
 	return nil
@@ -243,12 +243,12 @@ func (r *APIKeyMongoRepository) CleanupExpired(ctx context.Context) (int64, erro
 
 	result, err := r.collection.UpdateMany(ctx, filter, update)
 	if err != nil {
-		r.logger.Error("Failed to cleanup expired API keys", err)
-		return 0, errors.Wrap(err, errors.ErrTypeDatabase, "failed to cleanup expired API keys")
+		r.logger.Error("Failed to cleanup expired API keys: %v", err)
+		return 0, errors.Wrap(err, errors.ErrorTypeDatabase, "failed to cleanup expired API keys")
 	}
 
 	if result.ModifiedCount > 0 {
-		r.logger.Info("Cleaned up expired API keys", logger.Fields{"count": result.ModifiedCount})
+		r.logger.WithFields(map[string]interface{}{"count": result.ModifiedCount}).Info("Cleaned up expired API keys")
 	}
 
 	return result.ModifiedCount, nil
@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// contactBatchSize is the number of contacts written per bulk write
+const contactBatchSize = 500
+
+// ContactMongoRepository implements ContactRepository using MongoDB. The
+// contacts of all devices share one collection, keyed by device and JID.
+type ContactMongoRepository struct {
+	collection *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoContact represents the MongoDB document structure for contacts
+type mongoContact struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceName   string             `bson:"device_name"`
+	JID          string             `bson:"jid"`
+	Phone        string             `bson:"phone,omitempty"`
+	FullName     string             `bson:"full_name,omitempty"`
+	FirstName    string             `bson:"first_name,omitempty"`
+	PushName     string             `bson:"push_name,omitempty"`
+	BusinessName string             `bson:"business_name,omitempty"`
+	SortName     string             `bson:"sort_name"` // Lowercase display name
+	Tags         []string           `bson:"tags"`
+	Notes        string             `bson:"notes,omitempty"`
+	SyncedAt     int64              `bson:"synced_at"`
+	CreatedAt    int64              `bson:"created_at"`
+	UpdatedAt    int64              `bson:"updated_at"`
+}
+
+// NewContactMongoRepository creates a new MongoDB contact repository
+func NewContactMongoRepository(db *mongo.Database) ports.ContactRepository {
+	collection := db.Collection("contacts")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on device and JID (unique); a device knows a person once
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "device_name", Value: 1}, {Key: "jid", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Index on device and display name, for listing
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "device_name", Value: 1}, {Key: "sort_name", Value: 1}},
+	})
+
+	// Index on device and tags
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "device_name", Value: 1}, {Key: "tags", Value: 1}},
+	})
+
+	return &ContactMongoRepository{
+		collection: collection,
+		logger:     logger.New("ContactRepository"),
+	}
+}
+
+// SaveSynced creates or updates contacts with the phone and names from WhatsApp
+func (r *ContactMongoRepository) SaveSynced(ctx context.Context, contacts []*domain.Contact) error {
+	now := time.Now().Unix()
+
+	for start := 0; start < len(contacts); start += contactBatchSize {
+		end := start + contactBatchSize
+		if end > len(contacts) {
+			end = len(contacts)
+		}
+
+		models := make([]mongo.WriteModel, 0, end-start)
+		for _, contact := range contacts[start:end] {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"device_name": contact.DeviceName, "jid": contact.JID}).
+				SetUpdate(bson.M{
+					"$set": bson.M{
+						"phone":         contact.Phone,
+						"full_name":     contact.FullName,
+						"first_name":    contact.FirstName,
+						"push_name":     contact.PushName,
+						"business_name": contact.BusinessName,
+						"sort_name":     strings.ToLower(contact.DisplayName()),
+						"synced_at":     contact.SyncedAt.Unix(),
+						"updated_at":    now,
+					},
+					"$setOnInsert": bson.M{
+						"tags":       []string{},
+						"created_at": now,
+					},
+				}).
+				SetUpsert(true))
+		}
+
+		if _, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			r.logger.Error("Failed to save contacts: %v", err)
+			return apperrors.NewDatabaseError("Failed to save contacts", err)
+		}
+	}
+
+	return nil
+}
+
+// FindByJID retrieves a contact of a device
+func (r *ContactMongoRepository) FindByJID(ctx context.Context, deviceName, jid string) (*domain.Contact, error) {
+	var doc mongoContact
+	err := r.collection.FindOne(ctx, bson.M{"device_name": deviceName, "jid": jid}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Contact")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find contact: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve contact", err)
+	}
+
+	return r.toDomainEntity(&doc), nil
+}
+
+// FindAll retrieves the contacts matching the filter, sorted by display name
+func (r *ContactMongoRepository) FindAll(ctx context.Context, filter *domain.ContactFilter, skip, limit int) ([]*domain.Contact, error) {
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "sort_name", Value: 1}, {Key: "jid", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, r.buildFilter(filter), opts)
+	if err != nil {
+		r.logger.Error("Failed to find contacts: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve contacts", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.Contact
+	for cursor.Next(ctx) {
+		var doc mongoContact
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode contact: %v", err)
+			continue
+		}
+		results = append(results, r.toDomainEntity(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate contacts", err)
+	}
+
+	return results, nil
+}
+
+// Count counts the contacts matching the filter
+func (r *ContactMongoRepository) Count(ctx context.Context, filter *domain.ContactFilter) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, r.buildFilter(filter))
+	if err != nil {
+		r.logger.Error("Failed to count contacts: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to count contacts", err)
+	}
+
+	return count, nil
+}
+
+// Annotate sets the tags and/or notes of a contact and returns it
+func (r *ContactMongoRepository) Annotate(ctx context.Context, deviceName, jid string, annotations domain.ContactAnnotations) (*domain.Contact, error) {
+	set := bson.M{"updated_at": time.Now().Unix()}
+	if annotations.Tags != nil {
+		set["tags"] = *annotations.Tags
+	}
+	if annotations.Notes != nil {
+		set["notes"] = *annotations.Notes
+	}
+
+	var doc mongoContact
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"device_name": deviceName, "jid": jid},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Contact")
+	}
+	if err != nil {
+		r.logger.Error("Failed to annotate contact: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to update contact", err)
+	}
+
+	return r.toDomainEntity(&doc), nil
+}
+
+// Tags lists the distinct tags used in a device's contacts
+func (r *ContactMongoRepository) Tags(ctx context.Context, deviceName string) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "tags", bson.M{"device_name": deviceName})
+	if err != nil {
+		r.logger.Error("Failed to list contact tags: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve contact tags", err)
+	}
+
+	tags := make([]string, 0, len(values))
+	for _, value := range values {
+		if tag, ok := value.(string); ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// buildFilter converts a contact filter to a MongoDB filter. The search
+// matches any name, and the phone number when it contains digits, so that
+// "+62 812" finds 62812...
+func (r *ContactMongoRepository) buildFilter(filter *domain.ContactFilter) bson.M {
+	mongoFilter := bson.M{}
+	if filter == nil {
+		return mongoFilter
+	}
+
+	mongoFilter["device_name"] = filter.DeviceName
+	if filter.Tag != "" {
+		mongoFilter["tags"] = filter.Tag
+	}
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+		or := bson.A{
+			bson.M{"full_name": pattern},
+			bson.M{"first_name": pattern},
+			bson.M{"push_name": pattern},
+			bson.M{"business_name": pattern},
+		}
+		if digits := strings.Map(func(ch rune) rune {
+			if ch >= '0' && ch <= '9' {
+				return ch
+			}
+			return -1
+		}, search); digits != "" {
+			or = append(or, bson.M{"phone": primitive.Regex{Pattern: digits}})
+		}
+		mongoFilter["$or"] = or
+	}
+
+	return mongoFilter
+}
+
+// toDomainEntity converts a MongoDB document to a domain contact
+func (r *ContactMongoRepository) toDomainEntity(doc *mongoContact) *domain.Contact {
+	tags := doc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &domain.Contact{
+		ID:           doc.ID.Hex(),
+		DeviceName:   doc.DeviceName,
+		JID:          doc.JID,
+		Phone:        doc.Phone,
+		FullName:     doc.FullName,
+		FirstName:    doc.FirstName,
+		PushName:     doc.PushName,
+		BusinessName: doc.BusinessName,
+		Tags:         tags,
+		Notes:        doc.Notes,
+		SyncedAt:     time.Unix(doc.SyncedAt, 0),
+		CreatedAt:    time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:    time.Unix(doc.UpdatedAt, 0),
+	}
+}
@@ -14,8 +14,25 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// deviceReferenceCollections keep records under a device's name in their
+// device_name field
+var deviceReferenceCollections = []string{
+	"contacts",
+	"whatsapp_sessions",
+	"whatsapp_connection_history",
+	"quick_responses",
+	"qr_reminder_settings",
+	"qr_reminder_logs",
+	"qr_digest_schedules",
+	"scheduled_messages",
+	"scheduled_message_runs",
+	"broadcast_campaigns",
+	"broadcast_segments",
+}
+
 // DeviceMongoRepository implements DeviceRepository using MongoDB
 type DeviceMongoRepository struct {
+	db         *mongo.Database
 	collection *mongo.Collection
 	logger     *logger.Logger
 }
@@ -29,7 +46,14 @@ type mongoDevice struct {
 	Status      string             `bson:"status"`
 	JID         string             `bson:"jid,omitempty"`
 	CreatedAt   int64              `bson:"created_at"`
-	UpdatedAt   int64              `bson:"updated_at"`
+
+	Organization string `bson:"organization,omitempty"`
+	UpdatedAt    int64  `bson:"updated_at"`
+
+	PresencePolicy        string   `bson:"presence_policy,omitempty"`
+	PresenceSubscriptions []string `bson:"presence_subscriptions,omitempty"`
+
+	ProxyURL string `bson:"proxy_url,omitempty"`
 }
 
 // NewDeviceMongoRepository creates a new MongoDB device repository
@@ -52,6 +76,7 @@ func NewDeviceMongoRepository(db *mongo.Database) ports.DeviceRepository {
 	})
 
 	return &DeviceMongoRepository{
+		db:         db,
 		collection: collection,
 		logger:     logger.New("DeviceRepository"),
 	}
@@ -122,16 +147,7 @@ func (r *DeviceMongoRepository) FindByName(ctx context.Context, name string) (*d
 
 // FindAll retrieves all devices with optional filters
 func (r *DeviceMongoRepository) FindAll(ctx context.Context, filter *domain.DeviceFilter, skip, limit int) ([]*domain.Device, error) {
-	// Build filter
-	mongoFilter := bson.M{}
-	if filter != nil {
-		if filter.Owner != "" {
-			mongoFilter["owner"] = filter.Owner
-		}
-		if filter.Status != "" {
-			mongoFilter["status"] = string(filter.Status)
-		}
-	}
+	mongoFilter := deviceFilter(filter)
 
 	opts := options.Find().
 		SetSkip(int64(skip)).
@@ -180,6 +196,13 @@ func (r *DeviceMongoRepository) Update(ctx context.Context, device *domain.Devic
 			"status":      string(device.Status),
 			"jid":         device.JID,
 			"updated_at":  time.Now().Unix(),
+
+			"organization": device.Organization,
+
+			"presence_policy":        string(device.PresencePolicy),
+			"presence_subscriptions": device.PresenceSubscriptions,
+
+			"proxy_url": device.ProxyURL,
 		},
 	}
 
@@ -235,16 +258,7 @@ func (r *DeviceMongoRepository) Delete(ctx context.Context, id string) error {
 
 // Count counts devices with optional filter
 func (r *DeviceMongoRepository) Count(ctx context.Context, filter *domain.DeviceFilter) (int64, error) {
-	// Build filter
-	mongoFilter := bson.M{}
-	if filter != nil {
-		if filter.Owner != "" {
-			mongoFilter["owner"] = filter.Owner
-		}
-		if filter.Status != "" {
-			mongoFilter["status"] = string(filter.Status)
-		}
-	}
+	mongoFilter := deviceFilter(filter)
 
 	count, err := r.collection.CountDocuments(ctx, mongoFilter)
 	if err != nil {
@@ -317,6 +331,30 @@ func (r *DeviceMongoRepository) UpdateStatus(ctx context.Context, id string, sta
 	return nil
 }
 
+// RenameReferences updates the device_name of the records that other
+// collections keep for the device
+func (r *DeviceMongoRepository) RenameReferences(ctx context.Context, from, to string) error {
+	r.logger.WithFields(map[string]interface{}{"from": from, "to": to}).Info("Renaming device references")
+
+	for _, name := range deviceReferenceCollections {
+		result, err := r.db.Collection(name).UpdateMany(ctx,
+			bson.M{"device_name": from},
+			bson.M{"$set": bson.M{"device_name": to}})
+		if err != nil {
+			r.logger.WithField("collection", name).Error("Failed to rename device references: %v", err)
+			return apperrors.NewDatabaseError("Failed to rename device references in "+name, err)
+		}
+		if result.ModifiedCount > 0 {
+			r.logger.WithFields(map[string]interface{}{
+				"collection": name,
+				"count":      result.ModifiedCount,
+			}).Debug("Device references renamed")
+		}
+	}
+
+	return nil
+}
+
 // toMongoDocument converts domain entity to MongoDB document
 func (r *DeviceMongoRepository) toMongoDocument(device *domain.Device) *mongoDevice {
 	doc := &mongoDevice{
@@ -325,6 +363,13 @@ func (r *DeviceMongoRepository) toMongoDocument(device *domain.Device) *mongoDev
 		Description: device.Description,
 		Status:      string(device.Status),
 		JID:         device.JID,
+
+		Organization: device.Organization,
+
+		PresencePolicy:        string(device.PresencePolicy),
+		PresenceSubscriptions: device.PresenceSubscriptions,
+
+		ProxyURL: device.ProxyURL,
 	}
 
 	if device.ID != "" {
@@ -354,5 +399,35 @@ func (r *DeviceMongoRepository) toDomainEntity(doc *mongoDevice) *domain.Device
 		JID:         doc.JID,
 		CreatedAt:   time.Unix(doc.CreatedAt, 0),
 		UpdatedAt:   time.Unix(doc.UpdatedAt, 0),
+
+		Organization: doc.Organization,
+
+		PresencePolicy:        domain.PresencePolicy(doc.PresencePolicy),
+		PresenceSubscriptions: doc.PresenceSubscriptions,
+
+		ProxyURL: doc.ProxyURL,
+	}
+}
+
+// deviceFilter converts a device filter to a MongoDB filter
+func deviceFilter(filter *domain.DeviceFilter) bson.M {
+	mongoFilter := bson.M{}
+	if filter == nil {
+		return mongoFilter
+	}
+
+	if filter.Owner != "" {
+		mongoFilter["owner"] = filter.Owner
+	}
+	if filter.Status != "" {
+		mongoFilter["status"] = string(filter.Status)
+	}
+	if caller := filter.AccessibleTo; caller != nil {
+		accessible := bson.A{bson.M{"owner": caller.Username}}
+		if caller.Organization != "" {
+			accessible = append(accessible, bson.M{"organization": caller.Organization})
+		}
+		mongoFilter["$or"] = accessible
 	}
+	return mongoFilter
 }
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseMongoRepository implements LeaseRepository using MongoDB
+type LeaseMongoRepository struct {
+	collection *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoLease represents the MongoDB document structure for device leases
+type mongoLease struct {
+	DeviceName string `bson:"device_name"`
+	Holder     string `bson:"holder"`
+	Address    string `bson:"address,omitempty"`
+	AcquiredAt int64  `bson:"acquired_at"` // Unix milliseconds
+	RenewedAt  int64  `bson:"renewed_at"`
+	ExpiresAt  int64  `bson:"expires_at"`
+}
+
+// NewLeaseMongoRepository creates a new MongoDB device lease repository
+func NewLeaseMongoRepository(db *mongo.Database) ports.LeaseRepository {
+	collection := db.Collection("device_leases")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on device name (unique); a device has at most one lease
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "device_name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &LeaseMongoRepository{
+		collection: collection,
+		logger:     logger.New("LeaseRepository"),
+	}
+}
+
+// Acquire claims the lease of a device for holder
+func (r *LeaseMongoRepository) Acquire(ctx context.Context, deviceName, holder, address string, ttl time.Duration) (*domain.DeviceLease, bool, error) {
+	now := time.Now()
+
+	// Extend a lease the holder already has
+	var doc mongoLease
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"device_name": deviceName, "holder": holder},
+		bson.M{"$set": bson.M{
+			"address":    address,
+			"renewed_at": now.UnixMilli(),
+			"expires_at": now.Add(ttl).UnixMilli(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err == nil {
+		return r.toDomainLease(&doc), true, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		r.logger.Error("Failed to renew lease: %v", err)
+		return nil, false, apperrors.NewDatabaseError("Failed to acquire lease", err)
+	}
+
+	// Take over an expired lease or create one. A live lease of another holder
+	// does not match, so the upsert collides with it on the unique index.
+	err = r.collection.FindOneAndUpdate(ctx,
+		bson.M{"device_name": deviceName, "expires_at": bson.M{"$lte": now.UnixMilli()}},
+		bson.M{"$set": bson.M{
+			"holder":      holder,
+			"address":     address,
+			"acquired_at": now.UnixMilli(),
+			"renewed_at":  now.UnixMilli(),
+			"expires_at":  now.Add(ttl).UnixMilli(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetUpsert(true),
+	).Decode(&doc)
+	if err == nil {
+		return r.toDomainLease(&doc), true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		r.logger.Error("Failed to acquire lease: %v", err)
+		return nil, false, apperrors.NewDatabaseError("Failed to acquire lease", err)
+	}
+
+	current, err := r.Find(ctx, deviceName)
+	if err != nil {
+		return nil, false, err
+	}
+	return current, false, nil
+}
+
+// Renew extends a lease held by holder
+func (r *LeaseMongoRepository) Renew(ctx context.Context, deviceName, holder string, ttl time.Duration) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"device_name": deviceName, "holder": holder},
+		bson.M{"$set": bson.M{
+			"renewed_at": now.UnixMilli(),
+			"expires_at": now.Add(ttl).UnixMilli(),
+		}},
+	)
+	if err != nil {
+		r.logger.Error("Failed to renew lease: %v", err)
+		return apperrors.NewDatabaseError("Failed to renew lease", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperrors.NewNotFoundError("Lease")
+	}
+
+	return nil
+}
+
+// Release gives up a lease held by holder
+func (r *LeaseMongoRepository) Release(ctx context.Context, deviceName, holder string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"device_name": deviceName, "holder": holder}); err != nil {
+		r.logger.Error("Failed to release lease: %v", err)
+		return apperrors.NewDatabaseError("Failed to release lease", err)
+	}
+
+	return nil
+}
+
+// Find retrieves the lease of a device
+func (r *LeaseMongoRepository) Find(ctx context.Context, deviceName string) (*domain.DeviceLease, error) {
+	var doc mongoLease
+	err := r.collection.FindOne(ctx, bson.M{"device_name": deviceName}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Lease")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find lease: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve lease", err)
+	}
+
+	return r.toDomainLease(&doc), nil
+}
+
+// toDomainLease converts a MongoDB document to a domain lease
+func (r *LeaseMongoRepository) toDomainLease(doc *mongoLease) *domain.DeviceLease {
+	return &domain.DeviceLease{
+		DeviceName: doc.DeviceName,
+		Holder:     doc.Holder,
+		Address:    doc.Address,
+		AcquiredAt: time.UnixMilli(doc.AcquiredAt),
+		RenewedAt:  time.UnixMilli(doc.RenewedAt),
+		ExpiresAt:  time.UnixMilli(doc.ExpiresAt),
+	}
+}
@@ -0,0 +1,388 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WhatsAppSessionMongoRepository implements WhatsAppSessionRepository using MongoDB
+type WhatsAppSessionMongoRepository struct {
+	sessions *mongo.Collection
+	history  *mongo.Collection
+	logger   *logger.Logger
+}
+
+// mongoSession represents the MongoDB document structure for sessions
+type mongoSession struct {
+	DeviceName       string `bson:"device_name"`
+	JID              string `bson:"jid,omitempty"`
+	Status           string `bson:"status"`
+	QRCode           string `bson:"qr_code,omitempty"`
+	LastConnected    *int64 `bson:"last_connected,omitempty"`
+	LastDisconnected *int64 `bson:"last_disconnected,omitempty"`
+	HeartbeatAt      *int64 `bson:"heartbeat_at,omitempty"`
+	StoreDBPath      string `bson:"store_db_path"`
+	CreatedAt        int64  `bson:"created_at"`
+	UpdatedAt        int64  `bson:"updated_at"`
+}
+
+// mongoTransition represents the MongoDB document structure for connection history
+type mongoTransition struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceName string             `bson:"device_name"`
+	From       string             `bson:"from"`
+	To         string             `bson:"to"`
+	Reason     string             `bson:"reason,omitempty"`
+	Attempt    int                `bson:"attempt,omitempty"`
+	Timestamp  int64              `bson:"timestamp"`   // Unix milliseconds; transitions often share a second
+	RecordedAt time.Time          `bson:"recorded_at"` // The timestamp as a date, for expiry
+}
+
+// NewWhatsAppSessionMongoRepository creates a new MongoDB session repository.
+// Connection history older than historyRetention is deleted by MongoDB; zero
+// keeps it forever.
+func NewWhatsAppSessionMongoRepository(db *mongo.Database, historyRetention time.Duration) ports.WhatsAppSessionRepository {
+	sessions := db.Collection("whatsapp_sessions")
+	history := db.Collection("whatsapp_connection_history")
+	log := logger.New("WhatsAppSessionRepository")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on device name (unique)
+	_, _ = sessions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "device_name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Index on status and heartbeat, for finding sessions left connected
+	_, _ = sessions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "heartbeat_at", Value: 1}},
+	})
+
+	// Index on device name and time for timelines
+	_, _ = history.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "device_name", Value: 1}, {Key: "timestamp", Value: 1}},
+	})
+
+	if historyRetention > 0 {
+		// Transitions recorded before recorded_at existed expire like the rest
+		_, err := history.UpdateMany(ctx,
+			bson.M{"recorded_at": bson.M{"$exists": false}},
+			bson.A{bson.M{"$set": bson.M{"recorded_at": bson.M{"$toDate": "$timestamp"}}}})
+		if err != nil {
+			log.Warn("Failed to date connection history for expiry: %v", err)
+		}
+
+		_, err = history.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "recorded_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(historyRetention / time.Second)),
+		})
+		if err != nil {
+			log.Warn("Failed to create connection history expiry index: %v", err)
+		}
+	}
+
+	return &WhatsAppSessionMongoRepository{
+		sessions: sessions,
+		history:  history,
+		logger:   log,
+	}
+}
+
+// Save saves or updates a WhatsApp session
+func (r *WhatsAppSessionMongoRepository) Save(ctx context.Context, session *domain.WhatsAppSession) error {
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	session.UpdatedAt = now
+
+	doc := mongoSession{
+		DeviceName:       session.DeviceName,
+		JID:              session.JID,
+		Status:           string(session.Status),
+		QRCode:           session.QRCode,
+		LastConnected:    toUnixPtr(session.LastConnected),
+		LastDisconnected: toUnixPtr(session.LastDisconnected),
+		HeartbeatAt:      toUnixPtr(session.HeartbeatAt),
+		StoreDBPath:      session.StoreDBPath,
+		CreatedAt:        session.CreatedAt.Unix(),
+		UpdatedAt:        session.UpdatedAt.Unix(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.sessions.ReplaceOne(ctx, bson.M{"device_name": session.DeviceName}, doc, opts); err != nil {
+		r.logger.Error("Failed to save session: %v", err)
+		return apperrors.NewDatabaseError("Failed to save session", err)
+	}
+
+	return nil
+}
+
+// FindByDeviceName retrieves a session by device name
+func (r *WhatsAppSessionMongoRepository) FindByDeviceName(ctx context.Context, deviceName string) (*domain.WhatsAppSession, error) {
+	var doc mongoSession
+	err := r.sessions.FindOne(ctx, bson.M{"device_name": deviceName}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Session")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find session: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve session", err)
+	}
+
+	return r.toDomainSession(&doc), nil
+}
+
+// FindAll retrieves all sessions
+func (r *WhatsAppSessionMongoRepository) FindAll(ctx context.Context) ([]*domain.WhatsAppSession, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "device_name", Value: 1}})
+
+	cursor, err := r.sessions.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		r.logger.Error("Failed to find sessions: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve sessions", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.WhatsAppSession, 0)
+	for cursor.Next(ctx) {
+		var doc mongoSession
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Error("Failed to decode session: %v", err)
+			continue
+		}
+		results = append(results, r.toDomainSession(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to iterate sessions", err)
+	}
+
+	return results, nil
+}
+
+// Delete removes a session; its connection history is kept for uptime reporting
+func (r *WhatsAppSessionMongoRepository) Delete(ctx context.Context, deviceName string) error {
+	result, err := r.sessions.DeleteOne(ctx, bson.M{"device_name": deviceName})
+	if err != nil {
+		r.logger.Error("Failed to delete session: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete session", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return apperrors.NewNotFoundError("Session")
+	}
+
+	return nil
+}
+
+// UpdateStatus updates the connection status of a session
+func (r *WhatsAppSessionMongoRepository) UpdateStatus(ctx context.Context, deviceName string, status domain.ConnectionStatus) error {
+	return r.update(ctx, deviceName, bson.M{"status": string(status)})
+}
+
+// UpdateJID updates the JID of a session
+func (r *WhatsAppSessionMongoRepository) UpdateJID(ctx context.Context, deviceName string, jid string) error {
+	return r.update(ctx, deviceName, bson.M{"jid": jid})
+}
+
+// update sets fields on an existing session
+func (r *WhatsAppSessionMongoRepository) update(ctx context.Context, deviceName string, fields bson.M) error {
+	fields["updated_at"] = time.Now().Unix()
+
+	result, err := r.sessions.UpdateOne(ctx, bson.M{"device_name": deviceName}, bson.M{"$set": fields})
+	if err != nil {
+		r.logger.Error("Failed to update session: %v", err)
+		return apperrors.NewDatabaseError("Failed to update session", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return apperrors.NewNotFoundError("Session")
+	}
+
+	return nil
+}
+
+// RecordTransition appends a connection state transition to the device's history
+func (r *WhatsAppSessionMongoRepository) RecordTransition(ctx context.Context, transition domain.ConnectionTransition) error {
+	doc := mongoTransition{
+		DeviceName: transition.DeviceName,
+		From:       string(transition.From),
+		To:         string(transition.To),
+		Reason:     transition.Reason,
+		Attempt:    transition.Attempt,
+		Timestamp:  transition.Timestamp.UnixMilli(),
+		RecordedAt: transition.Timestamp,
+	}
+
+	if _, err := r.history.InsertOne(ctx, doc); err != nil {
+		r.logger.Error("Failed to record connection transition: %v", err)
+		return apperrors.NewDatabaseError("Failed to record connection transition", err)
+	}
+
+	return nil
+}
+
+// FindTransitions retrieves the transitions of a device within [from, to), oldest first
+func (r *WhatsAppSessionMongoRepository) FindTransitions(ctx context.Context, deviceName string, from, to time.Time) ([]domain.ConnectionTransition, error) {
+	filter := bson.M{
+		"device_name": deviceName,
+		"timestamp":   bson.M{"$gte": from.UnixMilli(), "$lt": to.UnixMilli()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.history.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find connection transitions: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve connection history", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]domain.ConnectionTransition, 0)
+	for cursor.Next(ctx) {
+		var doc mongoTransition
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Error("Failed to decode connection transition: %v", err)
+			continue
+		}
+		results = append(results, toDomainTransition(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to iterate connection history", err)
+	}
+
+	return results, nil
+}
+
+// FindLastTransitionBefore retrieves the latest transition of a device before t, or nil if there is none
+func (r *WhatsAppSessionMongoRepository) FindLastTransitionBefore(ctx context.Context, deviceName string, t time.Time) (*domain.ConnectionTransition, error) {
+	filter := bson.M{
+		"device_name": deviceName,
+		"timestamp":   bson.M{"$lt": t.UnixMilli()},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+
+	var doc mongoTransition
+	err := r.history.FindOne(ctx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to find connection transition: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve connection history", err)
+	}
+
+	transition := toDomainTransition(&doc)
+	return &transition, nil
+}
+
+// Heartbeat records that the devices, persisted as connected, were still connected at t
+func (r *WhatsAppSessionMongoRepository) Heartbeat(ctx context.Context, deviceNames []string, t time.Time) error {
+	if len(deviceNames) == 0 {
+		return nil
+	}
+
+	_, err := r.sessions.UpdateMany(ctx, bson.M{
+		"device_name": bson.M{"$in": deviceNames},
+		"status":      string(domain.StatusConnected),
+	}, bson.M{"$set": bson.M{"heartbeat_at": t.Unix()}})
+	if err != nil {
+		r.logger.Error("Failed to record session heartbeat: %v", err)
+		return apperrors.NewDatabaseError("Failed to record session heartbeat", err)
+	}
+
+	return nil
+}
+
+// FindStaleConnected retrieves the sessions persisted as connected whose last heartbeat is before t
+func (r *WhatsAppSessionMongoRepository) FindStaleConnected(ctx context.Context, before time.Time) ([]*domain.WhatsAppSession, error) {
+	filter := bson.M{
+		"status": string(domain.StatusConnected),
+		"$or": bson.A{
+			bson.M{"heartbeat_at": bson.M{"$lt": before.Unix()}},
+			bson.M{"heartbeat_at": bson.M{"$exists": false}, "updated_at": bson.M{"$lt": before.Unix()}},
+		},
+	}
+
+	cursor, err := r.sessions.Find(ctx, filter)
+	if err != nil {
+		r.logger.Error("Failed to find stale sessions: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve sessions", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.WhatsAppSession, 0)
+	for cursor.Next(ctx) {
+		var doc mongoSession
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Error("Failed to decode session: %v", err)
+			continue
+		}
+		results = append(results, r.toDomainSession(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to iterate sessions", err)
+	}
+
+	return results, nil
+}
+
+// toDomainSession converts a MongoDB document to a domain session
+func (r *WhatsAppSessionMongoRepository) toDomainSession(doc *mongoSession) *domain.WhatsAppSession {
+	return &domain.WhatsAppSession{
+		DeviceName:       doc.DeviceName,
+		JID:              doc.JID,
+		Status:           domain.ConnectionStatus(doc.Status),
+		QRCode:           doc.QRCode,
+		LastConnected:    fromUnixPtr(doc.LastConnected),
+		LastDisconnected: fromUnixPtr(doc.LastDisconnected),
+		HeartbeatAt:      fromUnixPtr(doc.HeartbeatAt),
+		StoreDBPath:      doc.StoreDBPath,
+		CreatedAt:        time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:        time.Unix(doc.UpdatedAt, 0),
+	}
+}
+
+// toDomainTransition converts a MongoDB document to a domain transition
+func toDomainTransition(doc *mongoTransition) domain.ConnectionTransition {
+	return domain.ConnectionTransition{
+		DeviceName: doc.DeviceName,
+		From:       domain.ConnectionStatus(doc.From),
+		To:         domain.ConnectionStatus(doc.To),
+		Reason:     doc.Reason,
+		Attempt:    doc.Attempt,
+		Timestamp:  time.UnixMilli(doc.Timestamp),
+	}
+}
+
+// toUnixPtr converts an optional time to Unix seconds
+func toUnixPtr(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+// fromUnixPtr converts optional Unix seconds to a time
+func fromUnixPtr(unix *int64) *time.Time {
+	if unix == nil {
+		return nil
+	}
+	t := time.Unix(*unix, 0)
+	return &t
+}
@@ -3,34 +3,47 @@ package whatsapp
 import (
 	"context"
 	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/inflight"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/binary/proto"
-	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
+	"google.golang.org/protobuf/proto"
 )
 
 // Client is the WhatsApp client adapter using whatsmeow
 type Client struct {
 	deviceName string
 	client     *whatsmeow.Client
-	store      *sqlstore.Container
+	store      sessionstore.Backend
 	logger     *logger.Logger
 
+	// Context of the current connection: Disconnect cancels it and Connect
+	// renews it, so a disconnected client can connect again
+	ctxMu  sync.Mutex
+	parent context.Context
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	// QR code management
-	qrMu     sync.Mutex
-	latestQR string
+	// QR pairing; qrMu serialises starting a pairing and requesting link codes
+	qrMu           sync.Mutex
+	pairingMu      sync.Mutex
+	pairing        *domain.PairingSession
+	pairingChanged chan struct{} // Closed and replaced whenever the session changes
 
 	// Connection state
 	connMu      sync.RWMutex
@@ -39,17 +52,45 @@ type Client struct {
 	// Event handler
 	eventHandler domain.WhatsAppEventHandler
 
-	// Message processing semaphore
-	sem chan struct{}
+	// Connection supervisor; nil leaves reconnects to whatsmeow
+	supervisor *Supervisor
+
+	// Traffic metrics; nil when not collected
+	metrics *Metrics
+
+	// Presence policy and the contacts whose presence is followed, by JID
+	presenceMu     sync.RWMutex
+	presencePolicy domain.PresencePolicy
+	presences      map[string]domain.ContactPresence
+
+	// Metadata of the joined groups
+	groups *groupCache
+
+	// Phone numbers checked for a WhatsApp account
+	numbers *numberCache
+
+	// Message processing semaphore, and the messages waiting for it
+	sem    chan struct{}
+	queued atomic.Int32
+
+	// Messages being processed or waiting for the semaphore, and sends in progress
+	work inflight.Tracker
 }
 
 // ClientConfig holds configuration for creating a new client
 type ClientConfig struct {
-	DeviceName       string
-	StoresDir        string
-	EventHandler     domain.WhatsAppEventHandler
-	MaxConcurrency   int
-	LogLevel         string
+	DeviceName     string
+	Store          sessionstore.Backend
+	EventHandler   domain.WhatsAppEventHandler
+	MaxConcurrency int
+	LogLevel       string
+	Supervisor     *Supervisor
+	Metrics        *Metrics
+
+	PresencePolicy        domain.PresencePolicy
+	PresenceSubscriptions []string // JIDs to follow the presence of once connected
+
+	ProxyURL string // Validated HTTP(S) or SOCKS5 proxy URL; empty connects directly
 }
 
 // NewClient creates a new WhatsApp client
@@ -63,45 +104,55 @@ func NewClient(ctx context.Context, config ClientConfig) (*Client, error) {
 	if config.LogLevel == "" {
 		config.LogLevel = "ERROR"
 	}
-	if config.StoresDir == "" {
-		config.StoresDir = "./stores"
+	if config.Store == nil {
+		return nil, apperrors.NewValidationError("A session store is required")
 	}
 
 	// Create context
 	clientCtx, cancel := context.WithCancel(ctx)
 
-	// Setup SQLite store
-	dbPath := fmt.Sprintf("file:%s/%s_store.db?_foreign_keys=on", config.StoresDir, config.DeviceName)
-	container, err := sqlstore.New(clientCtx, "sqlite3", dbPath,
-		waLog.Stdout("DB-"+config.DeviceName, config.LogLevel, true))
-	if err != nil {
-		cancel()
-		return nil, apperrors.NewDatabaseError("Failed to create SQLite store", err)
-	}
-
 	// Get or create device
-	deviceStore, err := container.GetFirstDevice(clientCtx)
+	deviceStore, err := config.Store.Open(clientCtx, config.DeviceName)
 	if err != nil {
 		cancel()
-		return nil, apperrors.NewDatabaseError("Failed to get device from store", err)
-	}
-	if deviceStore == nil {
-		deviceStore = container.NewDevice()
+		return nil, err
 	}
 
 	// Create whatsmeow client
 	clientLog := waLog.Stdout("Client-"+config.DeviceName, "INFO", true)
 	waClient := whatsmeow.NewClient(deviceStore, clientLog)
+	if config.Supervisor != nil {
+		// The supervisor reconnects with its own backoff
+		waClient.EnableAutoReconnect = false
+	}
+
+	if config.ProxyURL != "" {
+		if err := waClient.SetProxyAddress(config.ProxyURL); err != nil {
+			cancel()
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid proxy for device '%s': %v", config.DeviceName, err))
+		}
+	}
 
 	client := &Client{
 		deviceName:   config.DeviceName,
 		client:       waClient,
-		store:        container,
+		store:        config.Store,
 		logger:       log,
+		parent:       ctx,
 		ctx:          clientCtx,
 		cancel:       cancel,
 		eventHandler: config.EventHandler,
+		supervisor:   config.Supervisor,
+		metrics:      config.Metrics,
+		groups:       newGroupCache(),
+		numbers:      newNumberCache(),
 		sem:          make(chan struct{}, config.MaxConcurrency),
+
+		presencePolicy: config.PresencePolicy,
+		presences:      make(map[string]domain.ContactPresence),
+	}
+	for _, jid := range config.PresenceSubscriptions {
+		client.presences[jid] = domain.ContactPresence{JID: jid}
 	}
 
 	// Register event handlers
@@ -119,13 +170,57 @@ func (c *Client) registerEventHandlers() {
 			c.handleConnected()
 
 		case *events.Disconnected:
-			c.handleDisconnected()
+			c.handleConnectionLost(domain.StatusDisconnected, "connection closed unexpectedly", true)
+
+		case *events.ConnectFailure:
+			reason := fmt.Sprintf("connect failure %s", v.Reason)
+			if v.Message != "" {
+				reason += ": " + v.Message
+			}
+			c.handleConnectionLost(domain.StatusFailed, reason, true)
+
+		case *events.StreamReplaced:
+			// Reconnecting would take the session back from the other connection
+			c.handleConnectionLost(domain.StatusDisconnected, "session replaced by another connection", false)
+
+		case *events.ClientOutdated:
+			c.handleConnectionLost(domain.StatusFailed, "client version rejected as outdated, upgrade whatsmeow", false)
+
+		case *events.TemporaryBan:
+			c.handleTemporaryBan(v)
+
+		case *events.LoggedOut:
+			c.handleLoggedOut(v)
 
 		case *events.Message:
 			c.handleMessage(v)
 
-		case *events.QR:
-			c.handleQRCode(v)
+		case *events.PairSuccess:
+			c.handlePairSuccess(v)
+
+		case *events.PairError:
+			c.handlePairError(v)
+
+		case *events.Presence:
+			c.handlePresence(v)
+
+		case *events.Contact:
+			c.handleContact(v)
+
+		case *events.PushName:
+			c.handlePushName(v)
+
+		case *events.BusinessName:
+			c.handleBusinessName(v)
+
+		case *events.AppStateSyncComplete:
+			c.handleAppStateSyncComplete(v)
+
+		case *events.JoinedGroup:
+			c.groups.put(&v.GroupInfo)
+
+		case *events.GroupInfo:
+			c.handleGroupInfo(v)
 		}
 	})
 }
@@ -143,21 +238,90 @@ func (c *Client) handleConnected() {
 
 	c.logger.WithField("jid", jid).Success("Device connected")
 
+	if c.client.Store.ID != nil {
+		c.bindStore(*c.client.Store.ID)
+	}
+
+	if c.supervisor != nil {
+		c.supervisor.handleConnected(c)
+	}
+
+	// Subscriptions and presence do not outlive a connection
+	go c.restorePresence()
+
+	// Group changes made while offline may have been missed
+	c.groups.invalidate()
+
 	if c.eventHandler != nil {
 		c.eventHandler.OnConnected(c.deviceName, jid)
 	}
 }
 
-// handleDisconnected handles disconnection event
-func (c *Client) handleDisconnected() {
+// handleConnectionLost handles a dropped or refused connection. Only paired
+// devices are reconnected; an unpaired device just had its login window close.
+func (c *Client) handleConnectionLost(status domain.ConnectionStatus, reason string, retry bool) {
+	c.connMu.Lock()
+	c.isConnected = false
+	c.connMu.Unlock()
+
+	c.logger.WithField("reason", reason).Warn("Device disconnected")
+
+	// Disconnect was requested
+	if c.connection().Err() != nil {
+		return
+	}
+
+	if c.supervisor == nil {
+		if c.eventHandler != nil {
+			c.eventHandler.OnDisconnected(c.deviceName, reason)
+		}
+		return
+	}
+
+	c.supervisor.handleDisconnected(c, status, reason, retry && c.client.Store.ID != nil)
+}
+
+// handleTemporaryBan handles a temporary ban from WhatsApp
+func (c *Client) handleTemporaryBan(evt *events.TemporaryBan) {
+	c.connMu.Lock()
+	c.isConnected = false
+	c.connMu.Unlock()
+
+	c.logger.Error("Device temporarily banned: %s", evt.String())
+
+	if c.supervisor == nil {
+		if c.eventHandler != nil {
+			c.eventHandler.OnDisconnected(c.deviceName, evt.String())
+		}
+		return
+	}
+
+	c.supervisor.handleTemporaryBan(c, evt.String(), evt.Expire)
+}
+
+// handleLoggedOut handles the device being unlinked from the phone
+func (c *Client) handleLoggedOut(evt *events.LoggedOut) {
 	c.connMu.Lock()
 	c.isConnected = false
 	c.connMu.Unlock()
 
-	c.logger.Warn("Device disconnected")
+	reason := "logged out from the phone"
+	if evt.OnConnect {
+		reason = fmt.Sprintf("logged out: %s", evt.Reason)
+	}
+
+	c.logger.Warn("Device logged out: %s", reason)
+
+	if c.supervisor == nil {
+		if c.eventHandler != nil {
+			c.eventHandler.OnDisconnected(c.deviceName, reason)
+		}
+	} else {
+		c.supervisor.handleLoggedOut(c, reason)
+	}
 
 	if c.eventHandler != nil {
-		c.eventHandler.OnDisconnected(c.deviceName, "Connection lost")
+		c.eventHandler.OnLoggedOut(c.deviceName, reason)
 	}
 }
 
@@ -168,10 +332,21 @@ func (c *Client) handleMessage(evt *events.Message) {
 		return
 	}
 
-	// Extract message content
+	if messageType := receivedMessageType(evt.Message); messageType != "" && c.metrics != nil {
+		c.metrics.messageReceived(c.deviceName, messageType)
+	}
+
+	// Extract message content; replies arrive as extended text messages
 	content := ""
+	quotedID := ""
 	if evt.Message != nil {
 		content = evt.Message.GetConversation()
+		if ext := evt.Message.GetExtendedTextMessage(); ext != nil {
+			if content == "" {
+				content = ext.GetText()
+			}
+			quotedID = ext.GetContextInfo().GetStanzaID()
+		}
 	}
 
 	// Skip empty messages
@@ -185,35 +360,62 @@ func (c *Client) handleMessage(evt *events.Message) {
 	}).Info("Received message")
 
 	// Process message with semaphore for rate limiting
+	c.work.Start()
+	c.queued.Add(1)
 	go func() {
+		defer c.work.Done()
+
 		c.sem <- struct{}{}
+		c.queued.Add(-1)
 		defer func() { <-c.sem }()
 
 		if c.eventHandler != nil {
+			receiverType := domain.ReceiverIndividual
+			if evt.Info.IsGroup {
+				receiverType = domain.ReceiverGroup
+			}
+
 			msg := domain.WhatsAppMessage{
-				ID:        evt.Info.ID,
-				From:      evt.Info.Sender.String(),
-				To:        c.GetJID(),
-				Type:      domain.MessageTypeText,
-				Content:   content,
-				Timestamp: evt.Info.Timestamp,
-				IsFromMe:  evt.Info.IsFromMe,
+				ID:              evt.Info.ID,
+				From:            evt.Info.Sender.String(),
+				To:              c.GetJID(),
+				Type:            domain.MessageTypeText,
+				Content:         content,
+				QuotedMessageID: quotedID,
+				Timestamp:       evt.Info.Timestamp,
+				IsFromMe:        evt.Info.IsFromMe,
+				ReceiverType:    receiverType,
 			}
 			c.eventHandler.OnMessage(c.deviceName, msg)
 		}
 	}()
 }
 
-// handleQRCode handles QR code event
-func (c *Client) handleQRCode(evt *events.QR) {
-	c.qrMu.Lock()
-	c.latestQR = evt.Codes[len(evt.Codes)-1]
-	c.qrMu.Unlock()
+// handlePairSuccess handles a completed QR or phone-number pairing
+func (c *Client) handlePairSuccess(evt *events.PairSuccess) {
+	c.logger.WithField("jid", evt.ID.String()).Success("Device paired")
+
+	c.bindStore(evt.ID)
+
+	if c.eventHandler != nil {
+		c.eventHandler.OnPairSuccess(c.deviceName, evt.ID.String())
+	}
+}
+
+// bindStore records the paired JID in the session store so that the device
+// is found again on restart
+func (c *Client) bindStore(jid types.JID) {
+	if err := c.store.Bind(c.connection(), c.deviceName, jid); err != nil {
+		c.logger.Warn("Failed to bind session store: %v", err)
+	}
+}
 
-	c.logger.Info("QR code received")
+// handlePairError handles a pairing that could not be completed locally
+func (c *Client) handlePairError(evt *events.PairError) {
+	c.logger.Error("Pairing failed: %v", evt.Error)
 
 	if c.eventHandler != nil {
-		c.eventHandler.OnQRCode(c.deviceName, c.latestQR)
+		c.eventHandler.OnPairError(c.deviceName, evt.Error)
 	}
 }
 
@@ -224,6 +426,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	if c.client.IsConnected() {
 		return apperrors.New(apperrors.ErrorTypeConflict, "Client already connected")
 	}
+	c.renewConnection()
 
 	if err := c.client.Connect(); err != nil {
 		c.logger.Error("Failed to connect: %v", err)
@@ -247,7 +450,9 @@ func (c *Client) Disconnect(ctx context.Context) error {
 		c.client.Disconnect()
 	}
 
+	c.ctxMu.Lock()
 	c.cancel()
+	c.ctxMu.Unlock()
 
 	c.connMu.Lock()
 	c.isConnected = false
@@ -257,6 +462,57 @@ func (c *Client) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// connection returns the context of the current connection, which is done
+// once Disconnect is called
+func (c *Client) connection() context.Context {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+	return c.ctx
+}
+
+// renewConnection starts a new connection context if Disconnect ended the
+// last one, and returns the current one
+func (c *Client) renewConnection() context.Context {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	if c.ctx.Err() != nil {
+		c.ctx, c.cancel = context.WithCancel(c.parent)
+	}
+	return c.ctx
+}
+
+// queueDepth returns the number of received messages waiting for a processing slot
+func (c *Client) queueDepth() int {
+	return int(c.queued.Load())
+}
+
+// processing returns the number of received messages being processed
+func (c *Client) processing() int {
+	return len(c.sem)
+}
+
+// recordSend records the outcome of sending a message of the given type
+func (c *Client) recordSend(messageType domain.MessageType, err error) {
+	if c.metrics == nil {
+		return
+	}
+	if err != nil {
+		c.metrics.sendFailed(c.deviceName, err)
+		return
+	}
+	c.metrics.messageSent(c.deviceName, messageType)
+}
+
+// Drain waits until the messages received so far are processed and the sends
+// in progress are done, or until ctx is done
+func (c *Client) Drain(ctx context.Context) error {
+	if pending := c.work.Count(); pending > 0 {
+		c.logger.WithField("pending", pending).Info("Waiting for pending work")
+	}
+	return c.work.Wait(ctx)
+}
+
 // IsConnected returns the connection status
 func (c *Client) IsConnected() bool {
 	c.connMu.RLock()
@@ -269,67 +525,77 @@ func (c *Client) GetConnectionStatus() domain.ConnectionStatus {
 	if c.client == nil {
 		return domain.StatusDisconnected
 	}
-	if c.client.Store.ID == nil {
-		return domain.StatusDisconnected
-	}
 	if c.IsConnected() {
 		return domain.StatusConnected
 	}
+
+	// Reconnecting, logged out and banned devices report why they are offline
+	if c.supervisor != nil {
+		if status := c.supervisor.Status(c.deviceName); status != "" && status != domain.StatusConnected {
+			return status
+		}
+	}
 	return domain.StatusDisconnected
 }
 
-// GetQRCode generates and returns a QR code for pairing
+// GetQRCode returns the current QR code of the pairing session, starting
+// one if none is waiting for a scan
 func (c *Client) GetQRCode(ctx context.Context) (*domain.QRCodeResponse, error) {
+	session, err := c.StartPairing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session.Code == "" {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, "Device already logged in")
+	}
+
+	return &domain.QRCodeResponse{
+		DeviceName: c.deviceName,
+		QRCode:     session.Code,
+		ExpiresAt:  session.ExpiresAt,
+		Timeout:    int(time.Until(session.ExpiresAt).Seconds()),
+	}, nil
+}
+
+// PairPhone requests a link code for pairing with the given phone number
+// (international format, digits only) instead of scanning a QR code
+func (c *Client) PairPhone(ctx context.Context, phone string) (string, error) {
 	c.qrMu.Lock()
 	defer c.qrMu.Unlock()
 
-	c.logger.Info("Generating QR code")
+	c.logger.Info("Requesting pairing code")
 
-	// Check if already logged in
-	if c.client.Store.ID != nil && c.client.IsConnected() {
-		return nil, apperrors.New(apperrors.ErrorTypeConflict, "Device already logged in")
+	if c.client.Store.ID != nil {
+		return "", apperrors.New(apperrors.ErrorTypeConflict, "Device already logged in")
 	}
 
-	// Return cached QR if available
-	if c.latestQR != "" {
-		return &domain.QRCodeResponse{
-			DeviceName: c.deviceName,
-			QRCode:     c.latestQR,
-			Timeout:    30,
-			ExpiresAt:  time.Now().Add(30 * time.Second),
-		}, nil
+	// whatsmeow needs the login websocket to be open and to have produced its
+	// first QR code before a link code can be requested
+	if !c.client.IsConnected() {
+		if _, err := c.startPairing(ctx); err != nil {
+			return "", err
+		}
 	}
 
-	// Get QR channel
-	qrChan, _ := c.client.GetQRChannel(c.ctx)
-
-	// Connect to get QR code
-	if err := c.client.Connect(); err != nil {
-		c.logger.Error("Failed to connect for QR generation: %v", err)
-		return nil, apperrors.NewConnectionError("Failed to connect for QR generation", err)
-	}
-
-	// Wait for QR code event
-	select {
-	case evt := <-qrChan:
-		if evt.Event == "code" {
-			c.latestQR = evt.Code
-			c.logger.Success("QR code generated")
-			return &domain.QRCodeResponse{
-				DeviceName: c.deviceName,
-				QRCode:     evt.Code,
-				Timeout:    30,
-				ExpiresAt:  evt.Timeout,
-			}, nil
-		}
-		return nil, apperrors.NewWhatsAppError(fmt.Sprintf("Unknown QR event: %s", evt.Event), nil)
+	code, err := c.client.PairPhone(ctx, phone, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+	if err != nil {
+		c.logger.Error("Failed to request pairing code: %v", err)
+		return "", apperrors.NewWhatsAppError("Failed to request pairing code", err)
+	}
 
-	case <-time.After(30 * time.Second):
-		return nil, apperrors.NewWhatsAppError("Timeout waiting for QR code", nil)
+	c.logger.Success("Pairing code generated")
+	return code, nil
+}
 
-	case <-ctx.Done():
-		return nil, ctx.Err()
+// SetProxy routes the client through a proxy, or directly for an empty URL.
+// Media transfers use it at once; the websocket from the next connect.
+func (c *Client) SetProxy(proxyURL string) error {
+	if err := c.client.SetProxyAddress(proxyURL); err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("Invalid proxy: %v", err))
 	}
+
+	c.logger.WithField("proxied", proxyURL != "").Info("Proxy updated")
+	return nil
 }
 
 // GetJID returns the WhatsApp JID of the device
@@ -357,13 +623,17 @@ func (c *Client) GetDeviceInfo() *domain.DeviceInfo {
 }
 
 // SendTextMessage sends a text message
-func (c *Client) SendTextMessage(ctx context.Context, to, message string, receiverType domain.ReceiverType) error {
+func (c *Client) SendTextMessage(ctx context.Context, to, message string, receiverType domain.ReceiverType) (err error) {
 	c.logger.WithFields(map[string]interface{}{
 		"to":      to,
 		"message": message,
 		"type":    receiverType,
 	}).Info("Sending text message")
 
+	c.work.Start()
+	defer c.work.Done()
+	defer func() { c.recordSend(domain.MessageTypeText, err) }()
+
 	if !c.IsConnected() {
 		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
 	}
@@ -379,6 +649,7 @@ func (c *Client) SendTextMessage(ctx context.Context, to, message string, receiv
 		Conversation: &message,
 	}
 
+	c.presenceBeforeSend()
 	_, err = c.client.SendMessage(ctx, jid, msg)
 	if err != nil {
 		c.logger.Error("Failed to send message: %v", err)
@@ -390,16 +661,118 @@ func (c *Client) SendTextMessage(ctx context.Context, to, message string, receiv
 }
 
 // SendFileMessage sends a file message
-func (c *Client) SendFileMessage(ctx context.Context, params domain.SendMessageParams) error {
+func (c *Client) SendFileMessage(ctx context.Context, params domain.SendMessageParams) (err error) {
 	c.logger.WithFields(map[string]interface{}{
 		"to":   params.To,
 		"file": params.FileName,
 		"type": params.MessageType,
 	}).Info("Sending file message")
 
-	// Implementation would handle file upload and sending
-	// This is a placeholder - full implementation would read file, upload, and send
-	return apperrors.New(apperrors.ErrorTypeInternal, "File sending not yet implemented in adapter")
+	c.work.Start()
+	defer c.work.Done()
+	defer func() { c.recordSend(sentFileType(params.MessageType), err) }()
+
+	if !c.IsConnected() {
+		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	// Parse JID
+	jid, err := parseJID(params.To)
+	if err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("Invalid JID: %s", params.To))
+	}
+
+	// Use in-memory data when provided, otherwise read from disk
+	data := params.FileData
+	if len(data) == 0 && params.MediaPath != "" {
+		data, err = os.ReadFile(params.MediaPath)
+		if err != nil {
+			return apperrors.NewValidationError(fmt.Sprintf("Failed to read file: %s", params.MediaPath))
+		}
+	}
+	if len(data) == 0 {
+		return apperrors.NewValidationError("File data is required")
+	}
+
+	fileName := params.FileName
+	if fileName == "" {
+		fileName = filepath.Base(params.MediaPath)
+	}
+	mimeType := detectMimeType(data, fileName)
+
+	var msg *waProto.Message
+	switch params.MessageType {
+	case domain.MessageTypeImage:
+		resp, err := c.client.Upload(ctx, data, whatsmeow.MediaImage)
+		if err != nil {
+			return apperrors.NewWhatsAppError("Failed to upload image", err)
+		}
+		msg = &waProto.Message{ImageMessage: &waProto.ImageMessage{
+			Caption:       proto.String(params.Caption),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(resp.URL),
+			DirectPath:    proto.String(resp.DirectPath),
+			MediaKey:      resp.MediaKey,
+			FileEncSHA256: resp.FileEncSHA256,
+			FileSHA256:    resp.FileSHA256,
+			FileLength:    proto.Uint64(resp.FileLength),
+		}}
+	case domain.MessageTypeVideo:
+		resp, err := c.client.Upload(ctx, data, whatsmeow.MediaVideo)
+		if err != nil {
+			return apperrors.NewWhatsAppError("Failed to upload video", err)
+		}
+		msg = &waProto.Message{VideoMessage: &waProto.VideoMessage{
+			Caption:       proto.String(params.Caption),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(resp.URL),
+			DirectPath:    proto.String(resp.DirectPath),
+			MediaKey:      resp.MediaKey,
+			FileEncSHA256: resp.FileEncSHA256,
+			FileSHA256:    resp.FileSHA256,
+			FileLength:    proto.Uint64(resp.FileLength),
+		}}
+	case domain.MessageTypeAudio:
+		resp, err := c.client.Upload(ctx, data, whatsmeow.MediaAudio)
+		if err != nil {
+			return apperrors.NewWhatsAppError("Failed to upload audio", err)
+		}
+		msg = &waProto.Message{AudioMessage: &waProto.AudioMessage{
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(resp.URL),
+			DirectPath:    proto.String(resp.DirectPath),
+			MediaKey:      resp.MediaKey,
+			FileEncSHA256: resp.FileEncSHA256,
+			FileSHA256:    resp.FileSHA256,
+			FileLength:    proto.Uint64(resp.FileLength),
+		}}
+	default:
+		resp, err := c.client.Upload(ctx, data, whatsmeow.MediaDocument)
+		if err != nil {
+			return apperrors.NewWhatsAppError("Failed to upload file", err)
+		}
+		msg = &waProto.Message{DocumentMessage: &waProto.DocumentMessage{
+			Caption:       proto.String(params.Caption),
+			FileName:      proto.String(fileName),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(resp.URL),
+			DirectPath:    proto.String(resp.DirectPath),
+			MediaKey:      resp.MediaKey,
+			FileEncSHA256: resp.FileEncSHA256,
+			FileSHA256:    resp.FileSHA256,
+			FileLength:    proto.Uint64(resp.FileLength),
+		}}
+	}
+
+	c.presenceBeforeSend()
+	_, err = c.client.SendMessage(ctx, jid, msg)
+	if err != nil {
+		c.logger.Error("Failed to send file message: %v", err)
+		return apperrors.NewWhatsAppError("Failed to send file message", err)
+	}
+
+	c.logger.Success("File message sent")
+	return nil
 }
 
 // GetContacts retrieves all contacts
@@ -418,7 +791,10 @@ func (c *Client) GetContacts(ctx context.Context) ([]domain.WhatsAppContact, err
 
 	contacts := make([]domain.WhatsAppContact, 0, len(contactsMap))
 	for jid, info := range contactsMap {
-		name := info.PushName
+		name := info.FullName
+		if name == "" {
+			name = info.PushName
+		}
 		if name == "" {
 			name = jid.User
 		}
@@ -426,6 +802,9 @@ func (c *Client) GetContacts(ctx context.Context) ([]domain.WhatsAppContact, err
 		contacts = append(contacts, domain.WhatsAppContact{
 			JID:          jid.String(),
 			Name:         name,
+			FullName:     info.FullName,
+			FirstName:    info.FirstName,
+			PushName:     info.PushName,
 			BusinessName: info.BusinessName,
 		})
 	}
@@ -434,7 +813,7 @@ func (c *Client) GetContacts(ctx context.Context) ([]domain.WhatsAppContact, err
 	return contacts, nil
 }
 
-// GetGroups retrieves all groups
+// GetGroups retrieves all groups the device has joined, from the group cache
 func (c *Client) GetGroups(ctx context.Context) ([]domain.WhatsAppGroup, error) {
 	c.logger.Info("Retrieving groups")
 
@@ -442,73 +821,21 @@ func (c *Client) GetGroups(ctx context.Context) ([]domain.WhatsAppGroup, error)
 		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
 	}
 
-	joinedGroups, err := c.client.GetJoinedGroups(ctx)
+	infos, err := c.joinedGroups(ctx)
 	if err != nil {
 		c.logger.Error("Failed to get groups: %v", err)
 		return nil, apperrors.NewWhatsAppError("Failed to retrieve groups", err)
 	}
 
-	groups := make([]domain.WhatsAppGroup, 0, len(joinedGroups))
-	for _, jid := range joinedGroups {
-		// Get group info with retry logic
-		groupInfo, err := c.getGroupInfoWithRetry(ctx, jid)
-		if err != nil {
-			c.logger.Warn("Failed to get info for group %s: %v", jid.String(), err)
-			continue
-		}
-
-		if groupInfo == nil {
-			continue
-		}
-
-		participants := make([]string, 0, len(groupInfo.Participants))
-		for _, p := range groupInfo.Participants {
-			participants = append(participants, p.JID.String())
-		}
-
-		groups = append(groups, domain.WhatsAppGroup{
-			JID:          jid.String(),
-			Name:         groupInfo.Name,
-			Topic:        groupInfo.Topic,
-			OwnerJID:     groupInfo.OwnerJID.String(),
-			Participants: participants,
-			IsAnnounce:   groupInfo.IsAnnounce,
-			IsLocked:     groupInfo.IsLocked,
-			IsEphemeral:  groupInfo.IsEphemeral,
-			CreatedAt:    groupInfo.GroupCreated,
-		})
+	groups := make([]domain.WhatsAppGroup, 0, len(infos))
+	for _, info := range infos {
+		groups = append(groups, c.groupFromInfo(info))
 	}
 
 	c.logger.WithField("count", len(groups)).Success("Groups retrieved")
 	return groups, nil
 }
 
-// getGroupInfoWithRetry retrieves group info with retry logic
-func (c *Client) getGroupInfoWithRetry(ctx context.Context, jid types.JID) (*types.GroupInfo, error) {
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		info, err := c.client.GetGroupInfo(ctx, jid)
-		if err == nil {
-			return info, nil
-		}
-		if i < maxRetries-1 {
-			time.Sleep(time.Second * time.Duration(i+1))
-		}
-	}
-	return nil, fmt.Errorf("failed after %d retries", maxRetries)
-}
-
-// SetPresence sets the presence status
-func (c *Client) SetPresence(ctx context.Context, available bool) error {
-	if !c.IsConnected() {
-		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
-	}
-
-	// Implement presence setting using whatsmeow
-	// This is a placeholder
-	return nil
-}
-
 // SendTyping sends typing indicator
 func (c *Client) SendTyping(ctx context.Context, to string, typing bool) error {
 	if !c.IsConnected() {
@@ -535,6 +862,27 @@ func (c *Client) SendTyping(ctx context.Context, to string, typing bool) error {
 	return nil
 }
 
+// sentFileType returns the metrics type of a file message; anything that is
+// not an image, video or audio is sent as a document
+func sentFileType(messageType domain.MessageType) domain.MessageType {
+	switch messageType {
+	case domain.MessageTypeImage, domain.MessageTypeVideo, domain.MessageTypeAudio:
+		return messageType
+	}
+	return domain.MessageTypeFile
+}
+
+// detectMimeType detects the MIME type from the content, falling back to the file extension
+func detectMimeType(data []byte, fileName string) string {
+	mimeType := http.DetectContentType(data)
+	if mimeType == "application/octet-stream" || strings.HasPrefix(mimeType, "application/zip") {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			mimeType = byExt
+		}
+	}
+	return mimeType
+}
+
 // parseJID parses a string JID into types.JID
 func parseJID(jidStr string) (types.JID, error) {
 	jid, err := types.ParseJID(jidStr)
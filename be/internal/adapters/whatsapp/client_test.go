@@ -0,0 +1,275 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
+)
+
+// newTestClient creates a client over a SQLite store in a temporary directory
+func newTestClient(t *testing.T, proxyURL string) *Client {
+	t.Helper()
+
+	store, err := sessionstore.New(context.Background(), sessionstore.Config{StoresDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open session store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	client, err := NewClient(context.Background(), ClientConfig{
+		DeviceName: "proxy-test",
+		Store:      store,
+		ProxyURL:   proxyURL,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+// mediaRequest makes a media download request, which goes through the
+// client's proxy like the websocket does
+func mediaRequest(c *Client, target string) (*http.Response, error) {
+	return c.client.DangerousInternals().DoMediaDownloadRequest(context.Background(), target)
+}
+
+// connectProxy is an HTTP proxy stand-in that records CONNECT requests and refuses them
+type connectProxy struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	tunnels []string
+}
+
+func newConnectProxy(t *testing.T) *connectProxy {
+	p := &connectProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		p.mu.Lock()
+		p.tunnels = append(p.tunnels, r.Host)
+		p.mu.Unlock()
+		http.Error(w, "tunnel refused", http.StatusForbidden)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *connectProxy) Tunnels() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.tunnels...)
+}
+
+// socksProxy is a SOCKS5 stand-in without authentication that records the
+// addresses it connects to and relays the connections
+type socksProxy struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	dials []string
+}
+
+func newSOCKSProxy(t *testing.T) *socksProxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	p := &socksProxy{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go p.serve(conn)
+		}
+	}()
+	return p
+}
+
+func (p *socksProxy) URL() string {
+	return "socks5://" + p.listener.Addr().String()
+}
+
+func (p *socksProxy) Dials() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.dials...)
+}
+
+func (p *socksProxy) serve(conn net.Conn) {
+	defer conn.Close()
+
+	// Greeting: version, method count, methods; choose no authentication
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil || header[0] != 5 {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return
+	}
+
+	// Request: version, command, reserved, address type, address, port
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil || request[1] != 1 {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return
+		}
+		host = string(name)
+	case 4:
+		ip := make([]byte, net.IPv6len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+	p.mu.Lock()
+	p.dials = append(p.dials, address)
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", address)
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// newTarget starts a server standing in for the WhatsApp media servers
+func newTarget(t *testing.T) *httptest.Server {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "media")
+	}))
+	t.Cleanup(target.Close)
+	return target
+}
+
+func TestNewClientUsesHTTPProxy(t *testing.T) {
+	proxy := newConnectProxy(t)
+	target := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(target.Close)
+
+	client := newTestClient(t, proxy.URL)
+
+	if _, err := mediaRequest(client, target.URL); err == nil {
+		t.Fatal("request succeeded although the proxy refused the tunnel")
+	}
+
+	targetURL, _ := url.Parse(target.URL)
+	tunnels := proxy.Tunnels()
+	if len(tunnels) != 1 || tunnels[0] != targetURL.Host {
+		t.Errorf("proxy tunnels = %v, want [%s]", tunnels, targetURL.Host)
+	}
+}
+
+func TestNewClientUsesSOCKS5Proxy(t *testing.T) {
+	proxy := newSOCKSProxy(t)
+	target := newTarget(t)
+
+	client := newTestClient(t, proxy.URL())
+
+	resp, err := mediaRequest(client, target.URL)
+	if err != nil {
+		t.Fatalf("request through SOCKS5 proxy: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "media" {
+		t.Errorf("body = %q, want %q", body, "media")
+	}
+
+	targetURL, _ := url.Parse(target.URL)
+	dials := proxy.Dials()
+	if len(dials) != 1 || dials[0] != targetURL.Host {
+		t.Errorf("proxy dials = %v, want [%s]", dials, targetURL.Host)
+	}
+}
+
+func TestSetProxyChangesRoute(t *testing.T) {
+	socks := newSOCKSProxy(t)
+	connect := newConnectProxy(t)
+	target := newTarget(t)
+
+	client := newTestClient(t, socks.URL())
+
+	// Clearing the proxy connects directly
+	if err := client.SetProxy(""); err != nil {
+		t.Fatalf("SetProxy(\"\"): %v", err)
+	}
+	resp, err := mediaRequest(client, target.URL)
+	if err != nil {
+		t.Fatalf("direct request: %v", err)
+	}
+	resp.Body.Close()
+	if dials := socks.Dials(); len(dials) != 0 {
+		t.Errorf("SOCKS5 proxy used after it was cleared: %v", dials)
+	}
+
+	// Switching to an HTTP proxy sends HTTPS traffic through a CONNECT tunnel
+	if err := client.SetProxy(connect.URL); err != nil {
+		t.Fatalf("SetProxy(%q): %v", connect.URL, err)
+	}
+	tlsTarget := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsTarget.Close)
+	if _, err := mediaRequest(client, tlsTarget.URL); err == nil {
+		t.Fatal("request succeeded although the proxy refused the tunnel")
+	}
+	if tunnels := connect.Tunnels(); len(tunnels) != 1 {
+		t.Errorf("proxy tunnels = %v, want one", tunnels)
+	}
+}
+
+func TestSetProxyRejectsUnsupportedScheme(t *testing.T) {
+	client := newTestClient(t, "")
+
+	if err := client.SetProxy("ftp://127.0.0.1:21"); err == nil {
+		t.Error("SetProxy accepted an ftp proxy")
+	}
+}
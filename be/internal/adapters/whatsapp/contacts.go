@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleContact passes on a change to an address book entry, synced from the
+// phone. Entries of a full sync are passed on at once when it completes.
+func (c *Client) handleContact(evt *events.Contact) {
+	if evt.Action == nil || evt.FromFullSync {
+		return
+	}
+
+	fullName := evt.Action.GetFullName()
+	firstName := evt.Action.GetFirstName()
+
+	c.contactUpdated(evt.JID, domain.ContactUpdate{
+		FullName:  &fullName,
+		FirstName: &firstName,
+		Timestamp: evt.Timestamp,
+	})
+}
+
+// handlePushName passes on a new name a contact set for themselves
+func (c *Client) handlePushName(evt *events.PushName) {
+	pushName := evt.NewPushName
+	update := domain.ContactUpdate{PushName: &pushName, Timestamp: time.Now()}
+	if evt.Message != nil {
+		update.Timestamp = evt.Message.Timestamp
+	}
+
+	c.contactUpdated(evt.JID, update)
+}
+
+// handleBusinessName passes on a new verified name of a business account
+func (c *Client) handleBusinessName(evt *events.BusinessName) {
+	businessName := evt.NewBusinessName
+	update := domain.ContactUpdate{BusinessName: &businessName, Timestamp: time.Now()}
+	if evt.Message != nil {
+		update.Timestamp = evt.Message.Timestamp
+	}
+
+	c.contactUpdated(evt.JID, update)
+}
+
+// handleAppStateSyncComplete reports a full sync of the contact list
+func (c *Client) handleAppStateSyncComplete(evt *events.AppStateSyncComplete) {
+	if evt.Name == appstate.WAPatchCriticalUnblockLow && c.eventHandler != nil {
+		c.eventHandler.OnContactsSynced(c.deviceName)
+	}
+}
+
+// contactUpdated reports a change to a person's names to the event handler;
+// groups, broadcasts and the device itself are not contacts
+func (c *Client) contactUpdated(jid types.JID, update domain.ContactUpdate) {
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return
+	}
+	if c.isOwnJID(jid) || c.eventHandler == nil {
+		return
+	}
+
+	update.JID = jid.ToNonAD().String()
+	c.eventHandler.OnContactUpdated(c.deviceName, update)
+}
@@ -1,31 +1,54 @@
 package whatsapp
 
 import (
+	"sync"
+	"time"
+
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
 )
 
 // EventHandler handles WhatsApp events
 type EventHandler struct {
-	logger             *logger.Logger
-	messageRegistry    domain.MessageProcessorRegistry
-	messageHandlers    []MessageHandlerFunc
-	connectionHandlers []ConnectionHandlerFunc
+	logger              *logger.Logger
+	messageRegistry     domain.MessageProcessorRegistry
+	messageHandlers     []MessageHandlerFunc
+	connectionHandlers  []ConnectionHandlerFunc
+	deviceHandlers      []DeviceEventHandlerFunc
+	contactHandlers     []ContactHandlerFunc
+	contactSyncHandlers []func(deviceName string)
+
+	// Device event stream subscribers, by device name
+	subMu       sync.RWMutex
+	subscribers map[string]map[chan domain.DeviceEvent]struct{}
 }
 
+// eventBufferSize is the number of events buffered per subscriber; slower subscribers miss events
+const eventBufferSize = 16
+
 // MessageHandlerFunc is a function that handles incoming messages
 type MessageHandlerFunc func(deviceName string, message domain.WhatsAppMessage) error
 
 // ConnectionHandlerFunc is a function that handles connection events
 type ConnectionHandlerFunc func(deviceName string, connected bool)
 
+// DeviceEventHandlerFunc is a function that handles device lifecycle events
+type DeviceEventHandlerFunc func(event domain.DeviceEvent)
+
+// ContactHandlerFunc is a function that handles changes to the names of contacts
+type ContactHandlerFunc func(deviceName string, update domain.ContactUpdate)
+
 // NewEventHandler creates a new event handler
 func NewEventHandler(messageRegistry domain.MessageProcessorRegistry) *EventHandler {
 	return &EventHandler{
-		logger:             logger.New("EventHandler"),
-		messageRegistry:    messageRegistry,
-		messageHandlers:    make([]MessageHandlerFunc, 0),
-		connectionHandlers: make([]ConnectionHandlerFunc, 0),
+		logger:              logger.New("EventHandler"),
+		messageRegistry:     messageRegistry,
+		messageHandlers:     make([]MessageHandlerFunc, 0),
+		connectionHandlers:  make([]ConnectionHandlerFunc, 0),
+		deviceHandlers:      make([]DeviceEventHandlerFunc, 0),
+		contactHandlers:     make([]ContactHandlerFunc, 0),
+		contactSyncHandlers: make([]func(deviceName string), 0),
+		subscribers:         make(map[string]map[chan domain.DeviceEvent]struct{}),
 	}
 }
 
@@ -39,6 +62,21 @@ func (h *EventHandler) RegisterConnectionHandler(handler ConnectionHandlerFunc)
 	h.connectionHandlers = append(h.connectionHandlers, handler)
 }
 
+// RegisterDeviceEventHandler registers a handler for every device lifecycle event
+func (h *EventHandler) RegisterDeviceEventHandler(handler DeviceEventHandlerFunc) {
+	h.deviceHandlers = append(h.deviceHandlers, handler)
+}
+
+// RegisterContactHandler registers a handler for changes to the names of contacts
+func (h *EventHandler) RegisterContactHandler(handler ContactHandlerFunc) {
+	h.contactHandlers = append(h.contactHandlers, handler)
+}
+
+// RegisterContactSyncHandler registers a handler for full syncs of a device's contact list
+func (h *EventHandler) RegisterContactSyncHandler(handler func(deviceName string)) {
+	h.contactSyncHandlers = append(h.contactSyncHandlers, handler)
+}
+
 // OnConnected handles connection event
 func (h *EventHandler) OnConnected(deviceName, jid string) {
 	h.logger.WithFields(map[string]interface{}{
@@ -46,6 +84,8 @@ func (h *EventHandler) OnConnected(deviceName, jid string) {
 		"jid":    jid,
 	}).Success("Device connected")
 
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventConnected, JID: jid})
+
 	// Notify connection handlers
 	for _, handler := range h.connectionHandlers {
 		handler(deviceName, true)
@@ -59,6 +99,8 @@ func (h *EventHandler) OnDisconnected(deviceName string, reason string) {
 		"reason": reason,
 	}).Warn("Device disconnected")
 
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventDisconnected, Data: reason})
+
 	// Notify connection handlers
 	for _, handler := range h.connectionHandlers {
 		handler(deviceName, false)
@@ -68,7 +110,37 @@ func (h *EventHandler) OnDisconnected(deviceName string, reason string) {
 // OnQRCode handles QR code event
 func (h *EventHandler) OnQRCode(deviceName, qrCode string) {
 	h.logger.WithField("device", deviceName).Info("QR code received")
-	// QR code can be handled by specific use case or handler
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventQRCode, Data: qrCode})
+}
+
+// OnPairSuccess handles a completed QR or phone-number pairing
+func (h *EventHandler) OnPairSuccess(deviceName, jid string) {
+	h.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    jid,
+	}).Success("Device paired")
+
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventPairSuccess, JID: jid})
+}
+
+// OnPairError handles a pairing that the server accepted but could not be completed locally
+func (h *EventHandler) OnPairError(deviceName string, err error) {
+	h.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"error":  err.Error(),
+	}).Error("Device pairing failed")
+
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventPairError, Data: err.Error()})
+}
+
+// OnLoggedOut handles the device being unlinked from the phone
+func (h *EventHandler) OnLoggedOut(deviceName string, reason string) {
+	h.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"reason": reason,
+	}).Warn("Device logged out")
+
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventLoggedOut, Data: reason})
 }
 
 // OnMessage handles incoming message event
@@ -81,12 +153,13 @@ func (h *EventHandler) OnMessage(deviceName string, message domain.WhatsAppMessa
 
 	// Convert to IncomingMessage for processing
 	incomingMsg := domain.IncomingMessage{
-		ID:         message.ID,
-		DeviceName: deviceName,
-		From:       message.From,
-		Content:    message.Content,
-		Timestamp:  message.Timestamp,
-		IsGroup:    message.ReceiverType == domain.ReceiverGroup,
+		ID:              message.ID,
+		DeviceName:      deviceName,
+		From:            message.From,
+		Content:         message.Content,
+		QuotedMessageID: message.QuotedMessageID,
+		Timestamp:       message.Timestamp,
+		IsGroup:         message.ReceiverType == domain.ReceiverGroup,
 	}
 
 	// Process through message registry
@@ -110,10 +183,103 @@ func (h *EventHandler) OnMessage(deviceName string, message domain.WhatsAppMessa
 	}
 }
 
+// OnPresence handles an online status update of a followed contact
+func (h *EventHandler) OnPresence(deviceName string, presence domain.ContactPresence) {
+	state := "unavailable"
+	if presence.Available {
+		state = "available"
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"device":   deviceName,
+		"jid":      presence.JID,
+		"presence": state,
+	}).Debug("Contact presence updated")
+
+	h.publish(domain.DeviceEvent{
+		DeviceName: deviceName,
+		Type:       domain.DeviceEventPresence,
+		JID:        presence.JID,
+		Data:       state,
+		LastSeen:   presence.LastSeen,
+	})
+}
+
+// OnContactUpdated handles a change to the names of a contact
+func (h *EventHandler) OnContactUpdated(deviceName string, update domain.ContactUpdate) {
+	h.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    update.JID,
+	}).Debug("Contact updated")
+
+	for _, handler := range h.contactHandlers {
+		handler(deviceName, update)
+	}
+}
+
+// OnContactsSynced handles a full sync of a device's contact list
+func (h *EventHandler) OnContactsSynced(deviceName string) {
+	h.logger.WithField("device", deviceName).Info("Contact list synced")
+
+	for _, handler := range h.contactSyncHandlers {
+		handler(deviceName)
+	}
+}
+
 // OnError handles error event
 func (h *EventHandler) OnError(deviceName string, err error) {
 	h.logger.WithFields(map[string]interface{}{
 		"device": deviceName,
 		"error":  err.Error(),
 	}).Error("WhatsApp error occurred")
+
+	h.publish(domain.DeviceEvent{DeviceName: deviceName, Type: domain.DeviceEventError, Data: err.Error()})
+}
+
+// Subscribe returns the device's events and a function that ends the subscription
+func (h *EventHandler) Subscribe(deviceName string) (<-chan domain.DeviceEvent, func()) {
+	ch := make(chan domain.DeviceEvent, eventBufferSize)
+
+	h.subMu.Lock()
+	if h.subscribers[deviceName] == nil {
+		h.subscribers[deviceName] = make(map[chan domain.DeviceEvent]struct{})
+	}
+	h.subscribers[deviceName][ch] = struct{}{}
+	h.subMu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.subMu.Lock()
+			delete(h.subscribers[deviceName], ch)
+			if len(h.subscribers[deviceName]) == 0 {
+				delete(h.subscribers, deviceName)
+			}
+			h.subMu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// publish passes an event to the device event handlers and delivers it to the
+// device's subscribers without blocking the caller
+func (h *EventHandler) publish(event domain.DeviceEvent) {
+	event.Timestamp = time.Now()
+
+	for _, handler := range h.deviceHandlers {
+		handler(event)
+	}
+
+	h.subMu.RLock()
+	defer h.subMu.RUnlock()
+
+	for ch := range h.subscribers[event.DeviceName] {
+		select {
+		case ch <- event:
+		default:
+			h.logger.WithField("device", event.DeviceName).Warn("Device event dropped for slow subscriber")
+		}
+	}
 }
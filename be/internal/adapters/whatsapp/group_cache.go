@@ -0,0 +1,162 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// groupCacheTTL is how long the joined groups are served before they are
+// listed again; group events keep them current in between
+const groupCacheTTL = 15 * time.Minute
+
+// groupCache holds the metadata of the groups a device has joined. It is
+// filled from a single GetJoinedGroups request and kept current by group
+// events; groups an event changed are marked stale and fetched again on the
+// next read.
+type groupCache struct {
+	loadMu sync.Mutex // Serialises reads that may reload, so concurrent readers share one request
+
+	mu       sync.Mutex
+	groups   map[types.JID]*types.GroupInfo
+	stale    map[types.JID]bool
+	loadedAt time.Time // Zero until loaded and after invalidate
+}
+
+func newGroupCache() *groupCache {
+	return &groupCache{
+		groups: make(map[types.JID]*types.GroupInfo),
+		stale:  make(map[types.JID]bool),
+	}
+}
+
+// expired reports whether the groups must be listed again
+func (gc *groupCache) expired() bool {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.loadedAt.IsZero() || time.Since(gc.loadedAt) > groupCacheTTL
+}
+
+// replace swaps the cache for a fresh listing. Groups marked stale meanwhile
+// stay marked, since their event may be newer than the listing.
+func (gc *groupCache) replace(infos []*types.GroupInfo) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	gc.groups = make(map[types.JID]*types.GroupInfo, len(infos))
+	for _, info := range infos {
+		gc.groups[info.JID] = info
+	}
+	gc.loadedAt = time.Now()
+}
+
+// put stores the current metadata of a group
+func (gc *groupCache) put(info *types.GroupInfo) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	gc.groups[info.JID] = info
+	delete(gc.stale, info.JID)
+}
+
+// markStale has a group fetched again on the next read
+func (gc *groupCache) markStale(jid types.JID) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.stale[jid] = true
+}
+
+// remove drops a group the device is no longer a member of
+func (gc *groupCache) remove(jid types.JID) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	delete(gc.groups, jid)
+	delete(gc.stale, jid)
+}
+
+// invalidate empties the cache so that the next read lists the groups again
+func (gc *groupCache) invalidate() {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	gc.groups = make(map[types.JID]*types.GroupInfo)
+	gc.stale = make(map[types.JID]bool)
+	gc.loadedAt = time.Time{}
+}
+
+// staleGroups returns the groups to fetch again
+func (gc *groupCache) staleGroups() []types.JID {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	jids := make([]types.JID, 0, len(gc.stale))
+	for jid := range gc.stale {
+		jids = append(jids, jid)
+	}
+	return jids
+}
+
+// list returns the cached groups in no particular order
+func (gc *groupCache) list() []*types.GroupInfo {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	infos := make([]*types.GroupInfo, 0, len(gc.groups))
+	for _, info := range gc.groups {
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// joinedGroups returns the metadata of the groups the device has joined,
+// listing them in one request when the cache has expired and fetching only
+// the groups that changed since
+func (c *Client) joinedGroups(ctx context.Context) ([]*types.GroupInfo, error) {
+	c.groups.loadMu.Lock()
+	defer c.groups.loadMu.Unlock()
+
+	if c.groups.expired() {
+		infos, err := c.client.GetJoinedGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.groups.replace(infos)
+		c.logger.WithField("count", len(infos)).Debug("Group metadata cached")
+	}
+
+	for _, jid := range c.groups.staleGroups() {
+		info, err := c.client.GetGroupInfo(jid)
+		switch {
+		case err == nil:
+			c.groups.put(info)
+		case errors.Is(err, whatsmeow.ErrNotInGroup), errors.Is(err, whatsmeow.ErrGroupNotFound):
+			c.groups.remove(jid)
+		default:
+			// Served as last known and tried again on the next read
+			c.logger.WithField("group", jid.String()).Warn("Failed to refresh group metadata: %v", err)
+		}
+	}
+
+	return c.groups.list(), nil
+}
+
+// handleGroupInfo keeps the group cache current with a change to a group
+func (c *Client) handleGroupInfo(evt *events.GroupInfo) {
+	if evt.Delete != nil {
+		c.groups.remove(evt.JID)
+		return
+	}
+	for _, jid := range evt.Leave {
+		if c.isOwnJID(jid) {
+			c.groups.remove(evt.JID)
+			return
+		}
+	}
+	c.groups.markStale(evt.JID)
+}
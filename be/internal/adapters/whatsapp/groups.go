@@ -0,0 +1,320 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// CreateGroup creates a group with the device as its owner and admin
+func (c *Client) CreateGroup(ctx context.Context, name string, participants []string) (*domain.WhatsAppGroup, error) {
+	c.logger.WithFields(map[string]interface{}{
+		"name":         name,
+		"participants": len(participants),
+	}).Info("Creating group")
+
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	jids, err := parseParticipantJIDs(participants)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.client.CreateGroup(ctx, whatsmeow.ReqCreateGroup{Name: name, Participants: jids})
+	if err != nil {
+		c.logger.Error("Failed to create group: %v", err)
+		return nil, groupError("Failed to create group", err)
+	}
+
+	c.groups.put(info)
+	group := c.groupFromInfo(info)
+	c.logger.WithField("group", group.JID).Success("Group created")
+	return &group, nil
+}
+
+// GetGroup retrieves a group the device is a member of
+func (c *Client) GetGroup(ctx context.Context, groupJID string) (*domain.WhatsAppGroup, error) {
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	jid, err := parseGroupJID(groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.client.GetGroupInfo(jid)
+	if err != nil {
+		return nil, groupError("Failed to retrieve group", err)
+	}
+
+	c.groups.put(info)
+	group := c.groupFromInfo(info)
+	return &group, nil
+}
+
+// UpdateGroupParticipants adds, removes, promotes or demotes group members.
+// WhatsApp applies the change per person, so some may fail while others succeed.
+func (c *Client) UpdateGroupParticipants(ctx context.Context, groupJID string, participants []string, action domain.GroupParticipantAction) ([]domain.GroupParticipantResult, error) {
+	c.logger.WithFields(map[string]interface{}{
+		"group":        groupJID,
+		"action":       action,
+		"participants": len(participants),
+	}).Info("Updating group participants")
+
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	jid, err := parseGroupJID(groupJID)
+	if err != nil {
+		return nil, err
+	}
+	jids, err := parseParticipantJIDs(participants)
+	if err != nil {
+		return nil, err
+	}
+
+	var change whatsmeow.ParticipantChange
+	switch action {
+	case domain.GroupParticipantAdd:
+		change = whatsmeow.ParticipantChangeAdd
+	case domain.GroupParticipantRemove:
+		change = whatsmeow.ParticipantChangeRemove
+	case domain.GroupParticipantPromote:
+		change = whatsmeow.ParticipantChangePromote
+	case domain.GroupParticipantDemote:
+		change = whatsmeow.ParticipantChangeDemote
+	default:
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Unknown participant action: %s", action))
+	}
+
+	changed, err := c.client.UpdateGroupParticipants(jid, jids, change)
+	if err != nil {
+		c.logger.Error("Failed to update group participants: %v", err)
+		return nil, groupError("Failed to update group participants", err)
+	}
+	c.groups.markStale(jid)
+
+	results := make([]domain.GroupParticipantResult, 0, len(changed))
+	for _, participant := range changed {
+		results = append(results, domain.GroupParticipantResult{
+			JID:       participantJID(participant).String(),
+			Success:   participant.Error == 0,
+			ErrorCode: participant.Error,
+		})
+	}
+
+	c.logger.WithField("group", groupJID).Success("Group participants updated")
+	return results, nil
+}
+
+// SetGroupName changes the subject of a group
+func (c *Client) SetGroupName(ctx context.Context, groupJID, name string) error {
+	return c.updateGroup(groupJID, "Failed to set group name", func(jid types.JID) error {
+		return c.client.SetGroupName(jid, name)
+	})
+}
+
+// SetGroupDescription changes the description of a group; an empty one removes it
+func (c *Client) SetGroupDescription(ctx context.Context, groupJID, description string) error {
+	return c.updateGroup(groupJID, "Failed to set group description", func(jid types.JID) error {
+		return c.client.SetGroupTopic(jid, "", "", description)
+	})
+}
+
+// SetGroupPhoto sets a JPEG image as the group picture and returns its ID
+func (c *Client) SetGroupPhoto(ctx context.Context, groupJID string, jpeg []byte) (string, error) {
+	var pictureID string
+	err := c.updateGroup(groupJID, "Failed to set group photo", func(jid types.JID) error {
+		var err error
+		pictureID, err = c.client.SetGroupPhoto(jid, jpeg)
+		return err
+	})
+	return pictureID, err
+}
+
+// SetGroupAnnounce sets whether only admins may send messages to a group
+func (c *Client) SetGroupAnnounce(ctx context.Context, groupJID string, announce bool) error {
+	return c.updateGroup(groupJID, "Failed to change announce mode", func(jid types.JID) error {
+		return c.client.SetGroupAnnounce(jid, announce)
+	})
+}
+
+// SetGroupLocked sets whether only admins may change the group info
+func (c *Client) SetGroupLocked(ctx context.Context, groupJID string, locked bool) error {
+	return c.updateGroup(groupJID, "Failed to change locked mode", func(jid types.JID) error {
+		return c.client.SetGroupLocked(jid, locked)
+	})
+}
+
+// GetGroupInviteLink returns the invite link of a group; reset revokes the
+// current link and creates a new one
+func (c *Client) GetGroupInviteLink(ctx context.Context, groupJID string, reset bool) (string, error) {
+	var link string
+	err := c.updateGroup(groupJID, "Failed to get invite link", func(jid types.JID) error {
+		var err error
+		link, err = c.client.GetGroupInviteLink(jid, reset)
+		return err
+	})
+	return link, err
+}
+
+// JoinGroupWithLink joins a group by its invite link or code and returns the
+// group's JID. Groups that approve new members return the JID of the request.
+func (c *Client) JoinGroupWithLink(ctx context.Context, link string) (string, error) {
+	c.logger.Info("Joining group by invite link")
+
+	if !c.IsConnected() {
+		return "", apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	jid, err := c.client.JoinGroupWithLink(link)
+	if err != nil {
+		c.logger.Error("Failed to join group: %v", err)
+		return "", groupError("Failed to join group", err)
+	}
+
+	c.logger.WithField("group", jid.String()).Success("Joined group")
+	return jid.String(), nil
+}
+
+// LeaveGroup leaves a group
+func (c *Client) LeaveGroup(ctx context.Context, groupJID string) error {
+	var left types.JID
+	err := c.updateGroup(groupJID, "Failed to leave group", func(jid types.JID) error {
+		left = jid
+		return c.client.LeaveGroup(jid)
+	})
+	if err == nil {
+		c.groups.remove(left)
+	}
+	return err
+}
+
+// updateGroup runs a change to a group the device is connected to, mapping
+// WhatsApp's refusals to application errors. The group's cached metadata is
+// fetched again on the next listing.
+func (c *Client) updateGroup(groupJID, failure string, update func(jid types.JID) error) error {
+	if !c.IsConnected() {
+		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	jid, err := parseGroupJID(groupJID)
+	if err != nil {
+		return err
+	}
+
+	if err := update(jid); err != nil {
+		c.logger.WithField("group", groupJID).Error("%s: %v", failure, err)
+		return groupError(failure, err)
+	}
+	c.groups.markStale(jid)
+	return nil
+}
+
+// groupFromInfo converts whatsmeow group info to a domain group
+func (c *Client) groupFromInfo(info *types.GroupInfo) domain.WhatsAppGroup {
+	group := domain.WhatsAppGroup{
+		JID:          info.JID.String(),
+		Name:         info.Name,
+		Topic:        info.Topic,
+		OwnerJID:     info.OwnerJID.String(),
+		Participants: make([]string, 0, len(info.Participants)),
+		IsAnnounce:   info.IsAnnounce,
+		IsLocked:     info.IsLocked,
+		IsEphemeral:  info.IsEphemeral,
+		CreatedAt:    info.GroupCreated,
+	}
+
+	for _, participant := range info.Participants {
+		jid := participantJID(participant)
+		group.Participants = append(group.Participants, jid.String())
+		if !participant.IsAdmin && !participant.IsSuperAdmin {
+			continue
+		}
+
+		group.Admins = append(group.Admins, jid.String())
+		if c.isOwnJID(participant.JID) || c.isOwnJID(participant.PhoneNumber) || c.isOwnJID(participant.LID) {
+			group.IsAdmin = true
+		}
+	}
+
+	return group
+}
+
+// isOwnJID reports whether jid is the device's own phone number or LID
+func (c *Client) isOwnJID(jid types.JID) bool {
+	if jid.IsEmpty() {
+		return false
+	}
+	jid = jid.ToNonAD()
+	if c.client.Store.ID != nil && jid == c.client.Store.ID.ToNonAD() {
+		return true
+	}
+	lid := c.client.Store.GetLID()
+	return !lid.IsEmpty() && jid == lid.ToNonAD()
+}
+
+// participantJID returns the phone number JID of a participant when WhatsApp
+// shares it, so that members are listed the same way as contacts
+func participantJID(participant types.GroupParticipant) types.JID {
+	if !participant.PhoneNumber.IsEmpty() {
+		return participant.PhoneNumber
+	}
+	return participant.JID
+}
+
+// parseGroupJID parses a group JID, accepting the ID without the @g.us server
+func parseGroupJID(groupJID string) (types.JID, error) {
+	if groupJID == "" {
+		return types.EmptyJID, apperrors.NewValidationError("Group JID is required")
+	}
+
+	jid, err := types.ParseJID(groupJID)
+	if err == nil && jid.User == "" {
+		// ParseJID takes a bare ID for a server
+		jid = types.NewJID(groupJID, types.GroupServer)
+	}
+	if err != nil || jid.Server != types.GroupServer {
+		return types.EmptyJID, apperrors.NewValidationError(fmt.Sprintf("Invalid group JID: %s", groupJID))
+	}
+	return jid, nil
+}
+
+// parseParticipantJIDs parses the JIDs of group members
+func parseParticipantJIDs(participants []string) ([]types.JID, error) {
+	jids := make([]types.JID, 0, len(participants))
+	for _, participant := range participants {
+		jid, err := types.ParseJID(participant)
+		if err != nil || jid.User == "" || (jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer) {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid participant JID: %s", participant))
+		}
+		jids = append(jids, jid)
+	}
+	return jids, nil
+}
+
+// groupError maps whatsmeow's group errors to application errors
+func groupError(message string, err error) error {
+	switch {
+	case errors.Is(err, whatsmeow.ErrGroupNotFound):
+		return apperrors.NewNotFoundError("Group")
+	case errors.Is(err, whatsmeow.ErrNotInGroup):
+		return apperrors.NewForbiddenError("Device is not a member of the group")
+	case errors.Is(err, whatsmeow.ErrGroupInviteLinkUnauthorized), errors.Is(err, whatsmeow.ErrIQNotAuthorized):
+		return apperrors.NewForbiddenError("Device is not an admin of the group")
+	case errors.Is(err, whatsmeow.ErrInviteLinkRevoked):
+		return apperrors.NewValidationError("Invite link has been revoked")
+	case errors.Is(err, whatsmeow.ErrInviteLinkInvalid):
+		return apperrors.NewValidationError("Invite link is invalid")
+	}
+	return apperrors.NewWhatsAppError(message, err)
+}
@@ -0,0 +1,270 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// leaseOpTimeout bounds a single lease query
+const leaseOpTimeout = 5 * time.Second
+
+// LeaseConfig identifies this replica and how long its leases last
+type LeaseConfig struct {
+	ReplicaID string
+	Address   string // Base URL other replicas forward requests to
+	TTL       time.Duration
+}
+
+// LeaseKeeper lets several replicas share one device registry. The manager
+// only creates a client once the keeper holds the device's lease; the keeper
+// renews held leases every third of their TTL, unloads clients whose lease was
+// lost and takes over the devices of replicas that stopped renewing.
+type LeaseKeeper struct {
+	leases  ports.LeaseRepository
+	devices ports.DeviceRepository
+	manager *Manager
+	config  LeaseConfig
+	logger  *logger.Logger
+
+	mu   sync.Mutex
+	held map[string]time.Time // Expiry of each held lease as of its last acquire or renewal
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewLeaseKeeper creates a lease keeper and has the manager claim device
+// leases through it
+func NewLeaseKeeper(leases ports.LeaseRepository, devices ports.DeviceRepository, manager *Manager, config LeaseConfig) *LeaseKeeper {
+	if config.TTL <= 0 {
+		config.TTL = 30 * time.Second
+	}
+
+	k := &LeaseKeeper{
+		leases:  leases,
+		devices: devices,
+		manager: manager,
+		config:  config,
+		logger:  logger.New("LeaseKeeper").WithField("replica", config.ReplicaID),
+		held:    make(map[string]time.Time),
+	}
+
+	manager.mu.Lock()
+	manager.leases = k
+	manager.mu.Unlock()
+
+	return k
+}
+
+// Claim acquires the lease of a device, or returns a conflict error naming the
+// replica that holds it
+func (k *LeaseKeeper) Claim(ctx context.Context, deviceName string) error {
+	ctx, cancel := context.WithTimeout(ctx, leaseOpTimeout)
+	defer cancel()
+
+	started := time.Now()
+	lease, acquired, err := k.leases.Acquire(ctx, deviceName, k.config.ReplicaID, k.config.Address, k.config.TTL)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' is running on replica '%s'", deviceName, lease.Holder))
+	}
+
+	k.mu.Lock()
+	k.held[deviceName] = started.Add(k.config.TTL)
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Release gives up the lease of a device so that another replica may take it
+func (k *LeaseKeeper) Release(deviceName string) {
+	k.mu.Lock()
+	delete(k.held, deviceName)
+	k.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseOpTimeout)
+	defer cancel()
+
+	if err := k.leases.Release(ctx, deviceName, k.config.ReplicaID); err != nil {
+		k.logger.WithField("device", deviceName).Warn("Failed to release lease: %v", err)
+	}
+}
+
+// Start runs the heartbeat until Stop is called
+func (k *LeaseKeeper) Start() {
+	k.stop = make(chan struct{})
+	k.done = make(chan struct{})
+
+	go func() {
+		defer close(k.done)
+
+		ticker := time.NewTicker(k.config.TTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-k.stop:
+				return
+			case <-ticker.C:
+				k.renew()
+				k.takeOver()
+			}
+		}
+	}()
+
+	k.logger.WithField("ttl", k.config.TTL.String()).Info("Lease keeper started")
+}
+
+// Stop ends the heartbeat. The leases are kept until Release or ReleaseAll,
+// so that no other replica opens a session still connected here.
+func (k *LeaseKeeper) Stop() {
+	if k.stop == nil {
+		return
+	}
+	close(k.stop)
+	<-k.done
+	k.stop = nil
+}
+
+// ReleaseAll gives up every lease held by this replica
+func (k *LeaseKeeper) ReleaseAll() {
+	for _, deviceName := range k.heldDevices() {
+		k.Release(deviceName)
+	}
+	k.logger.Info("Leases released")
+}
+
+// renew extends the held leases and unloads the clients of those that were
+// lost, or that expired because they could not be renewed in time
+func (k *LeaseKeeper) renew() {
+	for _, deviceName := range k.heldDevices() {
+		started := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), leaseOpTimeout)
+		err := k.leases.Renew(ctx, deviceName, k.config.ReplicaID, k.config.TTL)
+		cancel()
+
+		if err == nil {
+			k.mu.Lock()
+			if _, held := k.held[deviceName]; held {
+				k.held[deviceName] = started.Add(k.config.TTL)
+			}
+			k.mu.Unlock()
+			continue
+		}
+		log := k.logger.WithField("device", deviceName)
+		if apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			if time.Now().Before(k.expiry(deviceName)) {
+				// Keep running; the lease is still ours until it expires
+				log.Warn("Failed to renew lease: %v", err)
+				continue
+			}
+
+			// Another replica may take the device over from now on
+			log.Warn("Lease expired without renewal, unloading client: %v", err)
+			k.unload(deviceName)
+			continue
+		}
+
+		// Another replica took the device over, most likely after this one
+		// missed renewals, so its session must not stay open here as well
+		log.Warn("Lease lost, unloading client")
+		k.unload(deviceName)
+	}
+}
+
+// expiry returns when the lease of a device expires unless renewed
+func (k *LeaseKeeper) expiry(deviceName string) time.Time {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.held[deviceName]
+}
+
+// unload forgets a lease that is no longer ours and unloads its client
+func (k *LeaseKeeper) unload(deviceName string) {
+	k.mu.Lock()
+	delete(k.held, deviceName)
+	k.mu.Unlock()
+
+	if err := k.manager.UnloadClient(context.Background(), deviceName); err != nil &&
+		apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+		k.logger.WithField("device", deviceName).Error("Failed to unload client: %v", err)
+	}
+}
+
+// takeOver loads the registered devices that no live replica holds, connecting
+// those that are paired
+func (k *LeaseKeeper) takeOver() {
+	ctx, cancel := context.WithTimeout(context.Background(), k.config.TTL/3)
+	defer cancel()
+
+	devices, err := k.devices.FindAll(ctx, nil, 0, 0)
+	if err != nil {
+		k.logger.Warn("Failed to list devices: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, device := range devices {
+		if device.Status == domain.DeviceStatusDeleted || device.Status == domain.DeviceStatusTransferred {
+			continue
+		}
+		if _, loaded := k.manager.GetClient(device.Name); loaded {
+			continue
+		}
+
+		lease, err := k.leases.Find(ctx, device.Name)
+		if err == nil && lease.IsLive(now) && lease.Holder != k.config.ReplicaID {
+			continue
+		}
+		if err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			k.logger.WithField("device", device.Name).Warn("Failed to look up lease: %v", err)
+			continue
+		}
+
+		log := k.logger.WithField("device", device.Name)
+		client, err := k.manager.CreateClient(ctx, device.Name)
+		if err != nil {
+			if apperrors.GetAppError(err).Type != apperrors.ErrorTypeConflict {
+				log.Warn("Failed to load device: %v", err)
+			}
+			continue
+		}
+
+		if lease != nil && lease.Holder != k.config.ReplicaID {
+			log.WithField("previous_holder", lease.Holder).Info("Took over device")
+		} else {
+			log.Info("Loaded device")
+		}
+
+		if device.JID == "" {
+			continue
+		}
+		if err := client.Connect(ctx); err != nil {
+			log.Warn("Failed to connect device: %v", err)
+		}
+	}
+}
+
+// heldDevices returns the names of the devices whose lease this replica holds
+func (k *LeaseKeeper) heldDevices() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	deviceNames := make([]string, 0, len(k.held))
+	for deviceName := range k.held {
+		deviceNames = append(deviceNames, deviceName)
+	}
+	sort.Strings(deviceNames)
+	return deviceNames
+}
@@ -3,12 +3,12 @@ package whatsapp
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/config"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
@@ -17,92 +17,187 @@ import (
 // Manager manages multiple WhatsApp clients
 type Manager struct {
 	clients      map[string]*Client
+	creating     map[string]chan struct{} // Closed once the client of the device is created or has failed
+	closed       bool                     // Set by DisconnectAll; no clients are created afterwards
 	mu           sync.RWMutex
 	logger       *logger.Logger
 	eventHandler domain.WhatsAppEventHandler
 	config       *config.Config
+	supervisor   *Supervisor
+	store        sessionstore.Backend
+	devices      ports.DeviceRepository
+	leases       *LeaseKeeper // Set when replicas share the registry
+	metrics      *Metrics
 }
 
-// NewManager creates a new WhatsApp manager
-func NewManager(eventHandler domain.WhatsAppEventHandler) *Manager {
-	return &Manager{
+// NewManager creates a new WhatsApp manager. Clients take their per-device
+// settings from the device registry.
+func NewManager(eventHandler domain.WhatsAppEventHandler, store sessionstore.Backend, devices ports.DeviceRepository) *Manager {
+	m := &Manager{
 		clients:      make(map[string]*Client),
+		creating:     make(map[string]chan struct{}),
 		logger:       logger.New("WhatsAppManager"),
 		eventHandler: eventHandler,
 		config:       config.Get(),
+		store:        store,
+		devices:      devices,
 	}
-}
 
-// LoadExistingDevices loads existing devices from stores directory
-func (m *Manager) LoadExistingDevices(ctx context.Context) error {
-	m.logger.Info("Loading existing devices from stores directory")
+	m.supervisor = NewSupervisor(m, eventHandler, SupervisorConfig{
+		InitialBackoff: m.config.WhatsApp.ReconnectInitialBackoff,
+		MaxBackoff:     m.config.WhatsApp.ReconnectMaxBackoff,
+		Jitter:         0.2,
+	})
+	m.metrics = newMetrics(m)
 
-	storesDir := m.config.WhatsApp.StoresDir
+	return m
+}
 
-	// Create stores directory if not exists
-	if err := os.MkdirAll(storesDir, 0755); err != nil {
-		m.logger.Error("Failed to create stores directory: %v", err)
-		return apperrors.NewInternalError("Failed to create stores directory", err)
-	}
+// Metrics returns the traffic and health metrics of the manager's clients
+func (m *Manager) Metrics() *Metrics {
+	return m.metrics
+}
 
-	// Find all .db files in stores directory
-	files, err := filepath.Glob(filepath.Join(storesDir, "*_store.db"))
+// ListStores returns the names of the devices that have a session store
+func (m *Manager) ListStores() ([]string, error) {
+	deviceNames, err := m.store.List(context.Background())
 	if err != nil {
-		m.logger.Error("Failed to glob store files: %v", err)
-		return apperrors.NewInternalError("Failed to glob store files", err)
+		m.logger.Error("Failed to list session stores: %v", err)
+		return nil, err
 	}
 
-	m.logger.WithField("count", len(files)).Info("Found store files")
+	return deviceNames, nil
+}
 
-	// Load each device
-	for _, file := range files {
-		deviceName := strings.TrimSuffix(filepath.Base(file), "_store.db")
+// CreateClient creates a new WhatsApp client. The lease claim, the device
+// lookup and opening the session happen without holding m.mu, so a slow
+// database or proxy does not block the other devices.
+func (m *Manager) CreateClient(ctx context.Context, deviceName string) (domain.WhatsAppClientInterface, error) {
+	m.logger.WithField("device", deviceName).Info("Creating client")
 
-		m.logger.WithField("device", deviceName).Info("Loading device")
+	// Reserve the name, or wait for the request that reserved it
+	for {
+		m.mu.Lock()
+		if client, exists := m.clients[deviceName]; exists {
+			m.mu.Unlock()
+			m.logger.WithField("device", deviceName).Warn("Client already exists")
+			return client, nil
+		}
+		if m.closed {
+			m.mu.Unlock()
+			return nil, apperrors.New(apperrors.ErrorTypeConnection, "WhatsApp clients are shutting down")
+		}
+		pending, creating := m.creating[deviceName]
+		if !creating {
+			m.creating[deviceName] = make(chan struct{})
+			m.mu.Unlock()
+			break
+		}
+		m.mu.Unlock()
 
-		if _, err := m.CreateClient(ctx, deviceName); err != nil {
-			m.logger.WithField("device", deviceName).Warn("Failed to load device: %v", err)
-			continue
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
 
-	m.logger.Success("Existing devices loaded")
-	return nil
-}
+	client, err := m.openClient(ctx, deviceName)
 
-// CreateClient creates a new WhatsApp client
-func (m *Manager) CreateClient(ctx context.Context, deviceName string) (domain.WhatsAppClientInterface, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	done := m.creating[deviceName]
+	delete(m.creating, deviceName)
+	closed := m.closed
+	if err == nil && !closed {
+		m.clients[deviceName] = client
+	}
+	m.mu.Unlock()
+	close(done)
 
-	m.logger.WithField("device", deviceName).Info("Creating client")
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		// DisconnectAll ran while the client was being created
+		client.Disconnect(context.Background())
+		m.store.Release(deviceName)
+		m.releaseLease(deviceName)
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "WhatsApp clients are shutting down")
+	}
+
+	m.logger.WithField("device", deviceName).Success("Client created")
+	return client, nil
+}
 
-	// Check if client already exists
-	if _, exists := m.clients[deviceName]; exists {
-		m.logger.WithField("device", deviceName).Warn("Client already exists")
-		return m.clients[deviceName], nil
+// openClient claims the device's lease and opens its client. The caller has
+// reserved the name in m.creating.
+func (m *Manager) openClient(ctx context.Context, deviceName string) (*Client, error) {
+	// Only the replica holding the device's lease may open its session
+	if m.leases != nil {
+		if err := m.leases.Claim(ctx, deviceName); err != nil {
+			m.logger.WithField("device", deviceName).Warn("Device lease not acquired: %v", err)
+			return nil, err
+		}
 	}
 
-	// Create client config
-	clientConfig := ClientConfig{
+	// Create new client; it outlives the request that created it
+	client, err := NewClient(context.Background(), m.clientConfig(ctx, deviceName))
+	if err != nil {
+		m.logger.WithField("device", deviceName).Error("Failed to create client: %v", err)
+		m.releaseLease(deviceName)
+		return nil, err
+	}
+	return client, nil
+}
+
+// lockSettled locks m.mu once no client is being created for any of the
+// devices, so that their session stores are not in use by a pending create
+func (m *Manager) lockSettled(deviceNames ...string) {
+	for {
+		m.mu.Lock()
+		var pending chan struct{}
+		for _, deviceName := range deviceNames {
+			if ch, creating := m.creating[deviceName]; creating {
+				pending = ch
+				break
+			}
+		}
+		if pending == nil {
+			return
+		}
+		m.mu.Unlock()
+		<-pending
+	}
+}
+
+// clientConfig returns the configuration for a supervised client, with the
+// settings of its device record when there is one
+func (m *Manager) clientConfig(ctx context.Context, deviceName string) ClientConfig {
+	cfg := ClientConfig{
 		DeviceName:     deviceName,
-		StoresDir:      m.config.WhatsApp.StoresDir,
+		Store:          m.store,
 		EventHandler:   m.eventHandler,
 		MaxConcurrency: m.config.WhatsApp.MaxConcurrency,
 		LogLevel:       "ERROR",
+		Supervisor:     m.supervisor,
+		Metrics:        m.metrics,
 	}
 
-	// Create new client
-	client, err := NewClient(ctx, clientConfig)
+	if m.devices == nil {
+		return cfg
+	}
+	device, err := m.devices.FindByName(ctx, deviceName)
 	if err != nil {
-		m.logger.WithField("device", deviceName).Error("Failed to create client: %v", err)
-		return nil, err
+		if apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			m.logger.WithField("device", deviceName).Warn("Failed to load device settings: %v", err)
+		}
+		return cfg
 	}
 
-	m.clients[deviceName] = client
-
-	m.logger.WithField("device", deviceName).Success("Client created")
-	return client, nil
+	cfg.PresencePolicy = device.PresencePolicy
+	cfg.PresenceSubscriptions = device.PresenceSubscriptions
+	cfg.ProxyURL = device.ProxyURL
+	return cfg
 }
 
 // GetClient retrieves a client by device name
@@ -116,32 +211,122 @@ func (m *Manager) GetClient(deviceName string) (domain.WhatsAppClientInterface,
 
 // RemoveClient removes a client and cleans up resources
 func (m *Manager) RemoveClient(ctx context.Context, deviceName string) error {
-	m.mu.Lock()
+	m.lockSettled(deviceName)
 	defer m.mu.Unlock()
 
 	m.logger.WithField("device", deviceName).Info("Removing client")
 
+	client, exists := m.clients[deviceName]
+	if exists {
+		// Disconnect client
+		if err := client.Disconnect(ctx); err != nil {
+			m.logger.WithField("device", deviceName).Warn("Error disconnecting client: %v", err)
+		}
+
+		// Remove from map
+		delete(m.clients, deviceName)
+		m.supervisor.Forget(deviceName)
+		m.metrics.forget(deviceName)
+	} else {
+		deviceNames, err := m.store.List(ctx)
+		if err != nil {
+			return err
+		}
+		if !containsName(deviceNames, deviceName) {
+			return apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+		}
+	}
+
+	// Delete the session store, loaded or not, so the name can be paired afresh
+	if err := m.store.Remove(ctx, deviceName); err != nil {
+		m.logger.WithField("device", deviceName).Warn("Failed to delete session store: %v", err)
+	}
+	m.releaseLease(deviceName)
+
+	m.logger.WithField("device", deviceName).Success("Client removed")
+	return nil
+}
+
+// UnloadClient disconnects a client and drops it from the manager, keeping its
+// session store so that it can be exported or loaded again
+func (m *Manager) UnloadClient(ctx context.Context, deviceName string) error {
+	m.lockSettled(deviceName)
+	defer m.mu.Unlock()
+
 	client, exists := m.clients[deviceName]
 	if !exists {
 		return apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
 	}
 
-	// Disconnect client
+	m.unload(ctx, deviceName, client)
+	m.releaseLease(deviceName)
+
+	m.logger.WithField("device", deviceName).Success("Client unloaded")
+	return nil
+}
+
+// RenameClient moves a device's session store to a new name. A loaded client
+// is unloaded first; the caller creates it again under the new name.
+func (m *Manager) RenameClient(ctx context.Context, from, to string) error {
+	m.lockSettled(from, to)
+	defer m.mu.Unlock()
+
+	log := m.logger.WithFields(map[string]interface{}{"device": from, "name": to})
+	log.Info("Renaming client")
+
+	if _, exists := m.clients[to]; exists {
+		return apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("Device '%s' is already loaded", to))
+	}
+
+	if client, exists := m.clients[from]; exists {
+		m.unload(ctx, from, client)
+	} else if m.leases != nil {
+		// The session must not be in use on another replica while it moves
+		if err := m.leases.Claim(ctx, from); err != nil {
+			return err
+		}
+	}
+	defer m.releaseLease(from)
+
+	if err := m.store.Rename(ctx, from, to); err != nil {
+		log.Error("Failed to rename session store: %v", err)
+		return err
+	}
+
+	log.Success("Client renamed")
+	return nil
+}
+
+// unload disconnects a client and drops it, releasing its session store.
+// Callers hold m.mu.
+func (m *Manager) unload(ctx context.Context, deviceName string, client *Client) {
 	if err := client.Disconnect(ctx); err != nil {
 		m.logger.WithField("device", deviceName).Warn("Error disconnecting client: %v", err)
 	}
-
-	// Remove from map
 	delete(m.clients, deviceName)
+	m.supervisor.Forget(deviceName)
+	m.metrics.forget(deviceName)
 
-	// Optionally delete store file
-	storeFile := filepath.Join(m.config.WhatsApp.StoresDir, deviceName+"_store.db")
-	if err := os.Remove(storeFile); err != nil {
-		m.logger.WithField("device", deviceName).Warn("Failed to delete store file: %v", err)
+	if err := m.store.Release(deviceName); err != nil {
+		m.logger.WithField("device", deviceName).Warn("Failed to release session store: %v", err)
 	}
+}
 
-	m.logger.WithField("device", deviceName).Success("Client removed")
-	return nil
+// releaseLease gives up the lease of a device once its client is gone
+func (m *Manager) releaseLease(deviceName string) {
+	if m.leases != nil {
+		m.leases.Release(deviceName)
+	}
+}
+
+// containsName reports whether name is in names
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
 }
 
 // ListClients returns a list of all device names
@@ -157,12 +342,45 @@ func (m *Manager) ListClients() []string {
 	return deviceNames
 }
 
+// Drain waits until every client has processed the messages it received and
+// finished its sends in progress, or until ctx is done. Clients stay connected
+// meanwhile, so that replies sent while processing still go out.
+func (m *Manager) Drain(ctx context.Context) error {
+	m.mu.RLock()
+	clients := make([]*Client, 0, len(m.clients))
+	for _, client := range m.clients {
+		clients = append(clients, client)
+	}
+	m.mu.RUnlock()
+
+	m.logger.Info("Draining clients")
+
+	var pending []string
+	for _, client := range clients {
+		if err := client.Drain(ctx); err != nil {
+			pending = append(pending, client.GetDeviceName())
+		}
+	}
+
+	if len(pending) > 0 {
+		return apperrors.New(apperrors.ErrorTypeInternal,
+			fmt.Sprintf("Timed out waiting for pending work of: %s", strings.Join(pending, ", ")))
+	}
+
+	m.logger.Success("All clients drained")
+	return nil
+}
+
 // DisconnectAll disconnects all clients
 func (m *Manager) DisconnectAll(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.logger.Info("Disconnecting all clients")
+	m.closed = true
+
+	// Intentional disconnects must not be reconnected
+	m.supervisor.Stop()
 
 	var errors []string
 	for deviceName, client := range m.clients {
@@ -198,6 +416,51 @@ func (m *Manager) GetAllConnectionInfo() []domain.ConnectionInfo {
 	return info
 }
 
+// GetConnectionHistory returns the recorded connection transitions of a device, oldest first
+func (m *Manager) GetConnectionHistory(deviceName string) []domain.ConnectionTransition {
+	return m.supervisor.History(deviceName)
+}
+
+// RegisterTransitionHandler registers a function called with every connection state transition.
+// It must not block; slow work belongs on another goroutine.
+func (m *Manager) RegisterTransitionHandler(handler func(domain.ConnectionTransition)) {
+	m.supervisor.OnTransition(handler)
+}
+
+// resetClient replaces a logged out client with a fresh one on an empty store,
+// ready to be paired again
+func (m *Manager) resetClient(ctx context.Context, old *Client) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deviceName := old.deviceName
+	log := m.logger.WithField("device", deviceName)
+
+	// Removed or already replaced in the meantime
+	if current, exists := m.clients[deviceName]; !exists || current != old {
+		return nil
+	}
+
+	log.Info("Resetting logged out device")
+
+	if err := old.Disconnect(ctx); err != nil {
+		log.Warn("Error disconnecting client: %v", err)
+	}
+	if err := m.store.Remove(ctx, deviceName); err != nil {
+		log.Warn("Failed to delete session store: %v", err)
+	}
+
+	client, err := NewClient(context.Background(), m.clientConfig(ctx, deviceName))
+	if err != nil {
+		delete(m.clients, deviceName)
+		return err
+	}
+	m.clients[deviceName] = client
+
+	log.Success("Device reset, waiting to be paired again")
+	return nil
+}
+
 // GetClientCount returns the number of clients
 func (m *Manager) GetClientCount() int {
 	m.mu.RLock()
@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/config"
+)
+
+// slowDevices is a device registry whose lookups wait until released
+type slowDevices struct {
+	ports.DeviceRepository
+
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (d *slowDevices) FindByName(ctx context.Context, name string) (*domain.Device, error) {
+	d.entered <- struct{}{}
+	<-d.release
+	return &domain.Device{Name: name}, nil
+}
+
+// newTestManager creates a manager over a SQLite store in a temporary directory
+func newTestManager(t *testing.T, devices ports.DeviceRepository) *Manager {
+	t.Helper()
+
+	t.Setenv("SERVER_PORT", "3000")
+	t.Setenv("JWT_SECRET", "test")
+	t.Setenv("MONGO_DB", "test")
+	t.Setenv("WHATSAPP_STORE_BACKEND", "sqlite")
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+
+	store, err := sessionstore.New(context.Background(), sessionstore.Config{StoresDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("open session store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	return NewManager(NewEventHandler(nil), store, devices)
+}
+
+func TestCreateClientDoesNotBlockOtherDevices(t *testing.T) {
+	devices := &slowDevices{entered: make(chan struct{}, 2), release: make(chan struct{})}
+	manager := newTestManager(t, devices)
+
+	type result struct {
+		client domain.WhatsAppClientInterface
+		err    error
+	}
+	results := make(chan result, 2)
+	create := func() {
+		client, err := manager.CreateClient(context.Background(), "officer")
+		results <- result{client, err}
+	}
+
+	go create()
+	<-devices.entered
+
+	// Lookups of other devices answer while the device registry is slow
+	answered := make(chan struct{})
+	go func() {
+		manager.GetClient("other")
+		manager.ListClients()
+		close(answered)
+	}()
+	select {
+	case <-answered:
+	case <-time.After(time.Second):
+		t.Fatal("GetClient blocked while a client was being created")
+	}
+
+	// A second create of the same device waits for the first
+	go create()
+	select {
+	case <-devices.entered:
+		t.Fatal("second CreateClient opened the device again")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(devices.release)
+
+	first, second := <-results, <-results
+	if first.err != nil || second.err != nil {
+		t.Fatalf("CreateClient errors: %v, %v", first.err, second.err)
+	}
+	if first.client != second.client {
+		t.Error("concurrent CreateClient calls returned different clients")
+	}
+	if count := manager.GetClientCount(); count != 1 {
+		t.Errorf("GetClientCount() = %d, want 1", count)
+	}
+}
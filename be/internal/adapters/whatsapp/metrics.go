@@ -0,0 +1,303 @@
+package whatsapp
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/metrics"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+)
+
+// messageTypeOther labels received messages that are not text or media, such as contacts or locations
+const messageTypeOther domain.MessageType = "other"
+
+// Metrics collects the traffic and health of the manager's clients and the
+// latency of the message processors. Counters live until the device is
+// removed or unloaded; gauges are read from the clients when scraped.
+type Metrics struct {
+	manager  *Manager
+	registry *metrics.Registry
+
+	sent               *metrics.Counter   // device, type
+	received           *metrics.Counter   // device, type
+	sendFailures       *metrics.Counter   // device, error_type
+	reconnects         *metrics.Counter   // device
+	processing         *metrics.Histogram // device, processor
+	processingFailures *metrics.Counter   // device, processor
+
+	mu           sync.Mutex
+	lastSent     map[string]time.Time
+	lastReceived map[string]time.Time
+}
+
+// newMetrics creates the metrics of the manager's clients
+func newMetrics(manager *Manager) *Metrics {
+	registry := metrics.NewRegistry()
+
+	m := &Metrics{
+		manager:  manager,
+		registry: registry,
+
+		sent: registry.NewCounter("whatsapp_messages_sent_total",
+			"Messages sent, by device and message type.", "device", "type"),
+		received: registry.NewCounter("whatsapp_messages_received_total",
+			"Messages received from others, by device and message type.", "device", "type"),
+		sendFailures: registry.NewCounter("whatsapp_send_failures_total",
+			"Messages that could not be sent, by device and error type.", "device", "error_type"),
+		reconnects: registry.NewCounter("whatsapp_reconnect_attempts_total",
+			"Reconnect attempts by the connection supervisor, by device.", "device"),
+		processing: registry.NewHistogram("whatsapp_message_processing_seconds",
+			"Time a message processor took for a received message, by device and processor.",
+			metrics.DefaultBuckets, "device", "processor"),
+		processingFailures: registry.NewCounter("whatsapp_message_processing_failures_total",
+			"Received messages a processor failed on, by device and processor.", "device", "processor"),
+
+		lastSent:     make(map[string]time.Time),
+		lastReceived: make(map[string]time.Time),
+	}
+
+	registry.NewGaugeFunc("whatsapp_device_connected",
+		"Whether the device is connected (1) or not (0).", []string{"device"}, m.collectConnected)
+	registry.NewGaugeFunc("whatsapp_device_queue_depth",
+		"Received messages waiting for a processing slot, by device.", []string{"device"}, m.collectQueueDepth)
+	registry.NewGaugeFunc("whatsapp_device_processing_messages",
+		"Received messages being processed, by device.", []string{"device"}, m.collectProcessing)
+	registry.NewGaugeFunc("whatsapp_device_last_message_age_seconds",
+		"Seconds since the device last sent or received a message, by device and direction.",
+		[]string{"device", "direction"}, m.collectLastMessageAge)
+
+	return m
+}
+
+// messageSent records a sent message of the given type
+func (m *Metrics) messageSent(deviceName string, messageType domain.MessageType) {
+	m.sent.Inc(deviceName, string(messageType))
+
+	m.mu.Lock()
+	m.lastSent[deviceName] = time.Now()
+	m.mu.Unlock()
+}
+
+// sendFailed records a message that could not be sent
+func (m *Metrics) sendFailed(deviceName string, err error) {
+	m.sendFailures.Inc(deviceName, string(apperrors.GetAppError(err).Type))
+}
+
+// messageReceived records a message received from someone else
+func (m *Metrics) messageReceived(deviceName string, messageType domain.MessageType) {
+	m.received.Inc(deviceName, string(messageType))
+
+	m.mu.Lock()
+	m.lastReceived[deviceName] = time.Now()
+	m.mu.Unlock()
+}
+
+// reconnectAttempted records a reconnect attempt by the supervisor
+func (m *Metrics) reconnectAttempted(deviceName string) {
+	m.reconnects.Inc(deviceName)
+}
+
+// ObserveProcessing records how long a message processor took for a message
+func (m *Metrics) ObserveProcessing(deviceName, processor string, duration time.Duration, err error) {
+	m.processing.Observe(duration.Seconds(), deviceName, processor)
+	if err != nil {
+		m.processingFailures.Inc(deviceName, processor)
+	}
+}
+
+// forget drops the metrics of a device that is no longer loaded here
+func (m *Metrics) forget(deviceName string) {
+	m.sent.Delete(deviceName)
+	m.received.Delete(deviceName)
+	m.sendFailures.Delete(deviceName)
+	m.reconnects.Delete(deviceName)
+	m.processing.Delete(deviceName)
+	m.processingFailures.Delete(deviceName)
+
+	m.mu.Lock()
+	delete(m.lastSent, deviceName)
+	delete(m.lastReceived, deviceName)
+	m.mu.Unlock()
+}
+
+// DeviceMetrics returns the metrics of a loaded device
+func (m *Metrics) DeviceMetrics(deviceName string) (*domain.DeviceMetrics, bool) {
+	m.manager.mu.RLock()
+	client, exists := m.manager.clients[deviceName]
+	m.manager.mu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	return m.deviceMetrics(client), true
+}
+
+// AllDeviceMetrics returns the metrics of every loaded device, sorted by name
+func (m *Metrics) AllDeviceMetrics() []domain.DeviceMetrics {
+	clients := m.clients()
+
+	all := make([]domain.DeviceMetrics, 0, len(clients))
+	for _, client := range clients {
+		all = append(all, *m.deviceMetrics(client))
+	}
+	return all
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	return m.registry.WritePrometheus(w)
+}
+
+// deviceMetrics gathers the metrics of a client
+func (m *Metrics) deviceMetrics(client *Client) *domain.DeviceMetrics {
+	deviceName := client.deviceName
+
+	dm := &domain.DeviceMetrics{
+		DeviceName:       deviceName,
+		Status:           client.GetConnectionStatus(),
+		Connected:        client.IsConnected(),
+		MessagesSent:     make(map[domain.MessageType]int64),
+		MessagesReceived: make(map[domain.MessageType]int64),
+		SendFailures:     make(map[string]int64),
+		QueueDepth:       client.queueDepth(),
+		Processing:       client.processing(),
+	}
+
+	for _, sample := range m.sent.Samples() {
+		if sample.Labels[0] == deviceName {
+			dm.MessagesSent[domain.MessageType(sample.Labels[1])] = int64(sample.Value)
+		}
+	}
+	for _, sample := range m.received.Samples() {
+		if sample.Labels[0] == deviceName {
+			dm.MessagesReceived[domain.MessageType(sample.Labels[1])] = int64(sample.Value)
+		}
+	}
+	for _, sample := range m.sendFailures.Samples() {
+		if sample.Labels[0] == deviceName {
+			dm.SendFailures[sample.Labels[1]] = int64(sample.Value)
+		}
+	}
+	for _, sample := range m.reconnects.Samples() {
+		if sample.Labels[0] == deviceName {
+			dm.Reconnects = int64(sample.Value)
+		}
+	}
+
+	failures := make(map[string]int64)
+	for _, sample := range m.processingFailures.Samples() {
+		if sample.Labels[0] == deviceName {
+			failures[sample.Labels[1]] = int64(sample.Value)
+		}
+	}
+	for _, value := range m.processing.Values() {
+		if value.Labels[0] != deviceName {
+			continue
+		}
+		dm.Processors = append(dm.Processors, domain.ProcessorMetrics{
+			Processor:      value.Labels[1],
+			Processed:      int64(value.Count),
+			Failed:         failures[value.Labels[1]],
+			AverageLatency: time.Duration(value.Mean() * float64(time.Second)),
+			MaxLatency:     time.Duration(value.Max * float64(time.Second)),
+		})
+	}
+
+	m.mu.Lock()
+	if at, ok := m.lastSent[deviceName]; ok {
+		dm.LastSentAt = &at
+	}
+	if at, ok := m.lastReceived[deviceName]; ok {
+		dm.LastReceivedAt = &at
+	}
+	m.mu.Unlock()
+
+	return dm
+}
+
+// clients returns the manager's clients sorted by device name
+func (m *Metrics) clients() []*Client {
+	m.manager.mu.RLock()
+	clients := make([]*Client, 0, len(m.manager.clients))
+	for _, client := range m.manager.clients {
+		clients = append(clients, client)
+	}
+	m.manager.mu.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].deviceName < clients[j].deviceName
+	})
+	return clients
+}
+
+func (m *Metrics) collectConnected() []metrics.Sample {
+	var samples []metrics.Sample
+	for _, client := range m.clients() {
+		connected := 0.0
+		if client.IsConnected() {
+			connected = 1
+		}
+		samples = append(samples, metrics.Sample{Labels: []string{client.deviceName}, Value: connected})
+	}
+	return samples
+}
+
+func (m *Metrics) collectQueueDepth() []metrics.Sample {
+	var samples []metrics.Sample
+	for _, client := range m.clients() {
+		samples = append(samples, metrics.Sample{Labels: []string{client.deviceName}, Value: float64(client.queueDepth())})
+	}
+	return samples
+}
+
+func (m *Metrics) collectProcessing() []metrics.Sample {
+	var samples []metrics.Sample
+	for _, client := range m.clients() {
+		samples = append(samples, metrics.Sample{Labels: []string{client.deviceName}, Value: float64(client.processing())})
+	}
+	return samples
+}
+
+func (m *Metrics) collectLastMessageAge() []metrics.Sample {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var samples []metrics.Sample
+	for deviceName, at := range m.lastSent {
+		samples = append(samples, metrics.Sample{Labels: []string{deviceName, "sent"}, Value: now.Sub(at).Seconds()})
+	}
+	for deviceName, at := range m.lastReceived {
+		samples = append(samples, metrics.Sample{Labels: []string{deviceName, "received"}, Value: now.Sub(at).Seconds()})
+	}
+	return samples
+}
+
+// receivedMessageType returns the metrics type of a received message, or ""
+// for messages that carry no content of their own, such as reactions, edits
+// and deletions
+func receivedMessageType(msg *waProto.Message) domain.MessageType {
+	switch {
+	case msg == nil:
+		return ""
+	case msg.GetConversation() != "" || msg.GetExtendedTextMessage() != nil:
+		return domain.MessageTypeText
+	case msg.GetImageMessage() != nil:
+		return domain.MessageTypeImage
+	case msg.GetVideoMessage() != nil:
+		return domain.MessageTypeVideo
+	case msg.GetAudioMessage() != nil:
+		return domain.MessageTypeAudio
+	case msg.GetDocumentMessage() != nil:
+		return domain.MessageTypeFile
+	case msg.GetProtocolMessage() != nil, msg.GetReactionMessage() != nil,
+		msg.GetSenderKeyDistributionMessage() != nil:
+		return ""
+	}
+	return messageTypeOther
+}
@@ -0,0 +1,130 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+const (
+	// numberCacheTTL is how long a registered number is trusted without checking again
+	numberCacheTTL = 24 * time.Hour
+
+	// unregisteredNumberCacheTTL is shorter, since a number may sign up at any time
+	unregisteredNumberCacheTTL = time.Hour
+
+	// numberQueryBatch is the number of phone numbers checked per request
+	numberQueryBatch = 100
+)
+
+// numberCache remembers which phone numbers are on WhatsApp, so that sending
+// to the same number repeatedly checks it once
+type numberCache struct {
+	mu      sync.Mutex
+	entries map[string]numberCacheEntry
+}
+
+type numberCacheEntry struct {
+	check     domain.NumberCheck
+	expiresAt time.Time
+}
+
+func newNumberCache() *numberCache {
+	return &numberCache{entries: make(map[string]numberCacheEntry)}
+}
+
+// get returns the cached check of a number
+func (nc *numberCache) get(phone string) (domain.NumberCheck, bool) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	entry, ok := nc.entries[phone]
+	if !ok {
+		return domain.NumberCheck{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(nc.entries, phone)
+		return domain.NumberCheck{}, false
+	}
+	return entry.check, true
+}
+
+// put caches the check of a number
+func (nc *numberCache) put(check domain.NumberCheck) {
+	ttl := numberCacheTTL
+	if !check.Registered {
+		ttl = unregisteredNumberCacheTTL
+	}
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.entries[check.Phone] = numberCacheEntry{check: check, expiresAt: time.Now().Add(ttl)}
+}
+
+// IsOnWhatsApp reports which phone numbers, in international digits, have a
+// WhatsApp account, with the canonical JID to message them at. Results are in
+// the order of phones; numbers WhatsApp did not answer for are not registered.
+func (c *Client) IsOnWhatsApp(ctx context.Context, phones []string) ([]domain.NumberCheck, error) {
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	results := make(map[string]domain.NumberCheck, len(phones))
+	query := make([]string, 0, len(phones))
+	for _, phone := range phones {
+		if _, seen := results[phone]; seen {
+			continue
+		}
+		if check, ok := c.numbers.get(phone); ok {
+			results[phone] = check
+			continue
+		}
+		results[phone] = domain.NumberCheck{Phone: phone}
+		query = append(query, "+"+phone)
+	}
+
+	for start := 0; start < len(query); start += numberQueryBatch {
+		end := start + numberQueryBatch
+		if end > len(query) {
+			end = len(query)
+		}
+
+		responses, err := c.client.IsOnWhatsApp(query[start:end])
+		if err != nil {
+			c.logger.Error("Failed to check phone numbers: %v", err)
+			return nil, apperrors.NewWhatsAppError("Failed to check phone numbers", err)
+		}
+
+		for _, response := range responses {
+			check := domain.NumberCheck{
+				Phone:      strings.TrimPrefix(response.Query, "+"),
+				Registered: response.IsIn,
+			}
+			if response.IsIn {
+				check.JID = response.JID.ToNonAD().String()
+			}
+			if response.VerifiedName != nil && response.VerifiedName.Details != nil {
+				check.BusinessName = response.VerifiedName.Details.GetVerifiedName()
+			}
+			results[check.Phone] = check
+		}
+	}
+	for _, queried := range query {
+		c.numbers.put(results[strings.TrimPrefix(queried, "+")])
+	}
+
+	checks := make([]domain.NumberCheck, 0, len(phones))
+	for _, phone := range phones {
+		checks = append(checks, results[phone])
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"numbers": len(phones),
+		"queried": len(query),
+	}).Debug("Phone numbers checked")
+	return checks, nil
+}
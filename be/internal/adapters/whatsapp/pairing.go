@@ -0,0 +1,191 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow"
+)
+
+// firstCodeTimeout is how long starting a pairing waits for the first QR code
+const firstCodeTimeout = 30 * time.Second
+
+// StartPairing opens the login connection and returns the pairing session
+// once its first QR code arrived. While a session waits for a scan, it is
+// returned instead of starting another.
+func (c *Client) StartPairing(ctx context.Context) (*domain.PairingSession, error) {
+	c.qrMu.Lock()
+	defer c.qrMu.Unlock()
+
+	return c.startPairing(ctx)
+}
+
+// startPairing starts a pairing session; the caller holds qrMu
+func (c *Client) startPairing(ctx context.Context) (*domain.PairingSession, error) {
+	if c.client.Store.ID != nil {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, "Device already logged in")
+	}
+	if session, ok := c.GetPairing(); ok && session.IsActive() {
+		return session, nil
+	}
+
+	c.logger.Info("Starting pairing")
+
+	// The channel must be requested before connecting and follows every code
+	// WhatsApp issues on this connection
+	qrChan, err := c.client.GetQRChannel(c.renewConnection())
+	if err != nil {
+		return nil, apperrors.NewWhatsAppError("Failed to prepare pairing", err)
+	}
+
+	c.updatePairing(func(session *domain.PairingSession) {
+		*session = domain.PairingSession{
+			DeviceName: c.deviceName,
+			Status:     domain.PairingWaiting,
+			StartedAt:  time.Now(),
+		}
+	})
+	go c.followPairing(qrChan)
+
+	if err := c.client.Connect(); err != nil {
+		c.logger.Error("Failed to connect for pairing: %v", err)
+		c.endPairing(domain.PairingFailed, err.Error())
+		return nil, apperrors.NewConnectionError("Failed to connect for pairing", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, firstCodeTimeout)
+	defer cancel()
+
+	session, err := c.WaitPairing(waitCtx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	switch session.Status {
+	case domain.PairingWaiting:
+		if session.Sequence == 0 {
+			return nil, apperrors.NewWhatsAppError("Timeout waiting for QR code", nil)
+		}
+	case domain.PairingTimeout, domain.PairingFailed:
+		return nil, apperrors.NewWhatsAppError(fmt.Sprintf("Pairing ended before a QR code was issued: %s", session.Status), nil)
+	}
+
+	return session, nil
+}
+
+// GetPairing returns the latest pairing session of the device
+func (c *Client) GetPairing() (*domain.PairingSession, bool) {
+	c.pairingMu.Lock()
+	defer c.pairingMu.Unlock()
+
+	if c.pairing == nil {
+		return nil, false
+	}
+	session := *c.pairing
+	return &session, true
+}
+
+// WaitPairing waits until the pairing session has a code newer than the
+// after-th or has ended, and returns it. When ctx is done first it returns the
+// session as it is.
+func (c *Client) WaitPairing(ctx context.Context, after int) (*domain.PairingSession, error) {
+	for {
+		c.pairingMu.Lock()
+		if c.pairing == nil {
+			c.pairingMu.Unlock()
+			return nil, apperrors.NewNotFoundError("Pairing session")
+		}
+		session := *c.pairing
+		changed := c.pairingChanged
+		c.pairingMu.Unlock()
+
+		if session.Sequence > after || !session.IsActive() {
+			return &session, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return &session, nil
+		}
+	}
+}
+
+// followPairing records the codes and the outcome of a pairing until the QR
+// channel closes
+func (c *Client) followPairing(qrChan <-chan whatsmeow.QRChannelItem) {
+	for item := range qrChan {
+		switch item.Event {
+		case whatsmeow.QRChannelEventCode:
+			sequence := 0
+			c.updatePairing(func(session *domain.PairingSession) {
+				session.Code = item.Code
+				session.Sequence++
+				session.ExpiresAt = time.Now().Add(item.Timeout)
+				sequence = session.Sequence
+			})
+			c.logger.WithField("sequence", sequence).Info("QR code issued")
+
+			if c.eventHandler != nil {
+				c.eventHandler.OnQRCode(c.deviceName, item.Code)
+			}
+
+		case whatsmeow.QRChannelSuccess.Event:
+			c.endPairing(domain.PairingSuccess, "")
+
+		case whatsmeow.QRChannelTimeout.Event:
+			c.logger.Warn("Pairing timed out without a scan")
+			c.endPairing(domain.PairingTimeout, "")
+
+		case whatsmeow.QRChannelEventError:
+			c.endPairing(domain.PairingFailed, item.Error.Error())
+
+		default:
+			c.logger.WithField("event", item.Event).Warn("Pairing failed")
+			c.endPairing(domain.PairingFailed, item.Event)
+		}
+	}
+
+	// The channel also closes without an outcome when the client is shut down
+	c.endPairing(domain.PairingFailed, "pairing cancelled")
+}
+
+// endPairing ends the pairing session if it is still active
+func (c *Client) endPairing(status domain.PairingStatus, reason string) {
+	c.updatePairing(func(session *domain.PairingSession) {
+		if !session.IsActive() {
+			return
+		}
+
+		now := time.Now()
+		session.Status = status
+		session.Code = ""
+		session.EndedAt = &now
+		session.Error = reason
+		if status == domain.PairingSuccess {
+			session.JID = c.GetJID()
+		}
+	})
+}
+
+// updatePairing changes the pairing session and wakes up its waiters
+func (c *Client) updatePairing(update func(session *domain.PairingSession)) {
+	c.pairingMu.Lock()
+	defer c.pairingMu.Unlock()
+
+	if c.pairing == nil {
+		c.pairing = &domain.PairingSession{DeviceName: c.deviceName}
+	}
+	update(c.pairing)
+
+	if c.pairingChanged != nil {
+		close(c.pairingChanged)
+	}
+	c.pairingChanged = make(chan struct{})
+}
@@ -0,0 +1,186 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// SetPresence marks the device available or unavailable to its contacts
+func (c *Client) SetPresence(ctx context.Context, available bool) error {
+	if !c.IsConnected() {
+		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	state := types.PresenceUnavailable
+	if available {
+		state = types.PresenceAvailable
+	}
+
+	err := c.client.SendPresence(state)
+	if errors.Is(err, whatsmeow.ErrNoPushName) {
+		return apperrors.NewValidationError("Set a display name for the device before changing its presence")
+	}
+	if err != nil {
+		c.logger.Error("Failed to send presence: %v", err)
+		return apperrors.NewWhatsAppError("Failed to set presence", err)
+	}
+
+	c.logger.WithField("presence", state).Info("Presence set")
+	return nil
+}
+
+// SetPresencePolicy changes when the device shows as online. It takes effect
+// with the next message or, for online and offline, right away when connected.
+func (c *Client) SetPresencePolicy(policy domain.PresencePolicy) {
+	c.presenceMu.Lock()
+	c.presencePolicy = policy
+	c.presenceMu.Unlock()
+
+	if c.IsConnected() {
+		go c.applyPresencePolicy()
+	}
+}
+
+// SubscribePresence follows the online status of a contact. Updates arrive
+// as presence events, and the subscription is renewed on every reconnect.
+func (c *Client) SubscribePresence(ctx context.Context, jid string) error {
+	target, err := parseJID(jid)
+	if err != nil {
+		return apperrors.NewValidationError(fmt.Sprintf("Invalid JID: %s", jid))
+	}
+	if target.Server != types.DefaultUserServer {
+		return apperrors.NewValidationError("Presence can only be followed for individual contacts")
+	}
+	target = target.ToNonAD()
+
+	c.presenceMu.Lock()
+	if _, exists := c.presences[target.String()]; !exists {
+		c.presences[target.String()] = domain.ContactPresence{JID: target.String()}
+	}
+	c.presenceMu.Unlock()
+
+	if !c.IsConnected() {
+		// Subscribed once connected
+		return nil
+	}
+
+	if err := c.client.SubscribePresence(target); err != nil {
+		c.logger.WithField("jid", target.String()).Error("Failed to subscribe to presence: %v", err)
+		return apperrors.NewWhatsAppError("Failed to subscribe to presence", err)
+	}
+
+	c.logger.WithField("jid", target.String()).Info("Subscribed to presence")
+	return nil
+}
+
+// UnsubscribePresence stops following a contact. WhatsApp has no way to end
+// a subscription, so updates stop arriving with the next reconnect.
+func (c *Client) UnsubscribePresence(jid string) {
+	c.presenceMu.Lock()
+	delete(c.presences, jid)
+	c.presenceMu.Unlock()
+}
+
+// GetContactPresence returns the last known presence of a followed contact
+func (c *Client) GetContactPresence(jid string) (*domain.ContactPresence, bool) {
+	c.presenceMu.RLock()
+	defer c.presenceMu.RUnlock()
+
+	presence, exists := c.presences[jid]
+	if !exists {
+		return nil, false
+	}
+	return &presence, true
+}
+
+// handlePresence records a presence update of a followed contact
+func (c *Client) handlePresence(evt *events.Presence) {
+	presence := domain.ContactPresence{
+		JID:       evt.From.ToNonAD().String(),
+		Available: !evt.Unavailable,
+		UpdatedAt: time.Now(),
+	}
+	if !evt.LastSeen.IsZero() {
+		lastSeen := evt.LastSeen
+		presence.LastSeen = &lastSeen
+	}
+
+	c.presenceMu.Lock()
+	previous, followed := c.presences[presence.JID]
+	if followed {
+		if presence.LastSeen == nil {
+			presence.LastSeen = previous.LastSeen
+		}
+		c.presences[presence.JID] = presence
+	}
+	c.presenceMu.Unlock()
+
+	if !followed {
+		return
+	}
+
+	if c.eventHandler != nil {
+		c.eventHandler.OnPresence(c.deviceName, presence)
+	}
+}
+
+// restorePresence applies the presence policy and renews presence
+// subscriptions after connecting
+func (c *Client) restorePresence() {
+	c.applyPresencePolicy()
+
+	c.presenceMu.RLock()
+	jids := make([]string, 0, len(c.presences))
+	for jid := range c.presences {
+		jids = append(jids, jid)
+	}
+	c.presenceMu.RUnlock()
+
+	for _, jid := range jids {
+		if err := c.SubscribePresence(c.connection(), jid); err != nil {
+			c.logger.WithField("jid", jid).Warn("Failed to renew presence subscription: %v", err)
+		}
+	}
+}
+
+// applyPresencePolicy sends the presence the policy asks for on connect
+func (c *Client) applyPresencePolicy() {
+	var available bool
+	switch c.getPresencePolicy() {
+	case domain.PresenceOnline:
+		available = true
+	case domain.PresenceOffline:
+		available = false
+	default:
+		return
+	}
+
+	if err := c.SetPresence(c.connection(), available); err != nil {
+		c.logger.Warn("Failed to apply presence policy: %v", err)
+	}
+}
+
+// presenceBeforeSend marks the device available before sending a message if the policy asks for it
+func (c *Client) presenceBeforeSend() {
+	switch c.getPresencePolicy() {
+	case "", domain.PresenceOnSend:
+		if err := c.client.SendPresence(types.PresenceAvailable); err != nil {
+			c.logger.Debug("Failed to send presence before message: %v", err)
+		}
+	}
+}
+
+// getPresencePolicy returns the current presence policy
+func (c *Client) getPresencePolicy() domain.PresencePolicy {
+	c.presenceMu.RLock()
+	defer c.presenceMu.RUnlock()
+	return c.presencePolicy
+}
@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// GetProfile retrieves the push name, about text and picture ID of the device
+func (c *Client) GetProfile(ctx context.Context) (*domain.DeviceProfile, error) {
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+	if c.client.Store.ID == nil {
+		return nil, apperrors.NewValidationError("Device is not paired")
+	}
+
+	own := c.client.Store.ID.ToNonAD()
+	profile := &domain.DeviceProfile{
+		DeviceName: c.deviceName,
+		JID:        own.String(),
+		PushName:   c.client.Store.PushName,
+	}
+
+	users, err := c.client.GetUserInfo([]types.JID{own})
+	if err != nil {
+		c.logger.Error("Failed to get own user info: %v", err)
+		return nil, apperrors.NewWhatsAppError("Failed to retrieve profile", err)
+	}
+	if info, ok := users[own]; ok {
+		profile.About = info.Status
+		profile.PictureID = info.PictureID
+	}
+
+	return profile, nil
+}
+
+// SetPushName changes the display name of the device
+func (c *Client) SetPushName(ctx context.Context, name string) error {
+	c.logger.WithField("name", name).Info("Setting push name")
+
+	if !c.IsConnected() {
+		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	if err := c.client.SendAppState(ctx, appstate.BuildSettingPushName(name)); err != nil {
+		c.logger.Error("Failed to set push name: %v", err)
+		return apperrors.NewWhatsAppError("Failed to set display name", err)
+	}
+
+	// The app state update is not echoed back, so keep the store in step
+	c.client.Store.PushName = name
+	if err := c.client.Store.Save(ctx); err != nil {
+		c.logger.Warn("Failed to save push name: %v", err)
+	}
+
+	c.logger.Success("Push name set")
+	return nil
+}
+
+// SetAbout changes the about text of the device
+func (c *Client) SetAbout(ctx context.Context, about string) error {
+	c.logger.Info("Setting about text")
+
+	if !c.IsConnected() {
+		return apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	if err := c.client.SetStatusMessage(about); err != nil {
+		c.logger.Error("Failed to set about text: %v", err)
+		return apperrors.NewWhatsAppError("Failed to set about text", err)
+	}
+
+	c.logger.Success("About text set")
+	return nil
+}
+
+// SetProfilePicture changes the profile picture of the device and returns the new picture ID
+func (c *Client) SetProfilePicture(ctx context.Context, jpeg []byte) (string, error) {
+	c.logger.WithField("size", len(jpeg)).Info("Setting profile picture")
+
+	if !c.IsConnected() {
+		return "", apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	// An empty target sets the picture of the device itself
+	pictureID, err := c.client.SetGroupPhoto(types.EmptyJID, jpeg)
+	if errors.Is(err, whatsmeow.ErrInvalidImageFormat) {
+		return "", apperrors.NewValidationError("WhatsApp rejected the image; use a square JPEG of at most 640x640")
+	}
+	if err != nil {
+		c.logger.Error("Failed to set profile picture: %v", err)
+		return "", apperrors.NewWhatsAppError("Failed to set profile picture", err)
+	}
+
+	c.logger.WithField("picture_id", pictureID).Success("Profile picture set")
+	return pictureID, nil
+}
+
+// GetProfilePicture retrieves the profile picture of a user or group. It
+// returns nil if the picture is still the one with existingID.
+func (c *Client) GetProfilePicture(ctx context.Context, jid string, preview bool, existingID string) (*domain.ProfilePicture, error) {
+	if !c.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection, "Client not connected")
+	}
+
+	target, err := parseJID(jid)
+	if err != nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid JID: %s", jid))
+	}
+
+	info, err := c.client.GetProfilePictureInfo(target, &whatsmeow.GetProfilePictureParams{
+		Preview:    preview,
+		ExistingID: existingID,
+	})
+	switch {
+	case errors.Is(err, whatsmeow.ErrProfilePictureNotSet):
+		return nil, apperrors.NewNotFoundError("Profile picture")
+	case errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized):
+		return nil, apperrors.NewForbiddenError("Profile picture is hidden from this device")
+	case err != nil:
+		c.logger.WithField("jid", jid).Error("Failed to get profile picture: %v", err)
+		return nil, apperrors.NewWhatsAppError("Failed to retrieve profile picture", err)
+	case info == nil:
+		return nil, nil
+	}
+
+	return &domain.ProfilePicture{
+		JID:       target.String(),
+		ID:        info.ID,
+		URL:       info.URL,
+		Type:      info.Type,
+		FetchedAt: time.Now(),
+	}, nil
+}
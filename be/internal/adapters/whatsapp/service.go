@@ -19,9 +19,11 @@ type Service struct {
 	connectUC      *whatsapp.ConnectUseCase
 	disconnectUC   *whatsapp.DisconnectUseCase
 	getQRUC        *whatsapp.GetQRCodeUseCase
+	pairPhoneUC    *whatsapp.PairPhoneUseCase
 	sendMessageUC  *whatsapp.SendMessageUseCase
 	listContactsUC *whatsapp.ListContactsUseCase
 	listGroupsUC   *whatsapp.ListGroupsUseCase
+	manageGroupsUC *whatsapp.ManageGroupsUseCase
 }
 
 // NewService creates a new WhatsApp service
@@ -32,9 +34,11 @@ func NewService(manager domain.WhatsAppManagerInterface) ports.WhatsAppService {
 		connectUC:      whatsapp.NewConnectUseCase(manager),
 		disconnectUC:   whatsapp.NewDisconnectUseCase(manager),
 		getQRUC:        whatsapp.NewGetQRCodeUseCase(manager),
+		pairPhoneUC:    whatsapp.NewPairPhoneUseCase(manager),
 		sendMessageUC:  whatsapp.NewSendMessageUseCase(manager),
 		listContactsUC: whatsapp.NewListContactsUseCase(manager),
 		listGroupsUC:   whatsapp.NewListGroupsUseCase(manager),
+		manageGroupsUC: whatsapp.NewManageGroupsUseCase(manager),
 	}
 }
 
@@ -53,6 +57,11 @@ func (s *Service) GetQRCode(ctx context.Context, deviceName string) (*domain.QRC
 	return s.getQRUC.Execute(ctx, deviceName)
 }
 
+// PairPhone requests a link code for pairing a device by phone number
+func (s *Service) PairPhone(ctx context.Context, deviceName, phone string) (*domain.PairingCodeResponse, error) {
+	return s.pairPhoneUC.Execute(ctx, deviceName, phone)
+}
+
 // IsDeviceConnected checks if a device is connected
 func (s *Service) IsDeviceConnected(deviceName string) bool {
 	client, exists := s.manager.GetClient(deviceName)
@@ -82,6 +91,11 @@ func (s *Service) GetAllConnectionInfo() []domain.ConnectionInfo {
 	return s.manager.GetAllConnectionInfo()
 }
 
+// GetConnectionHistory returns the recorded connection transitions of a device
+func (s *Service) GetConnectionHistory(deviceName string) []domain.ConnectionTransition {
+	return s.manager.GetConnectionHistory(deviceName)
+}
+
 // SendMessage sends a message via WhatsApp
 func (s *Service) SendMessage(ctx context.Context, params domain.SendMessageParams) error {
 	return s.sendMessageUC.Execute(ctx, params)
@@ -109,9 +123,49 @@ func (s *Service) ListContacts(ctx context.Context, deviceName string) ([]domain
 	return s.listContactsUC.Execute(ctx, deviceName)
 }
 
-// ListGroups retrieves groups from a WhatsApp device
-func (s *Service) ListGroups(ctx context.Context, deviceName string) ([]domain.WhatsAppGroup, error) {
-	return s.listGroupsUC.Execute(ctx, deviceName)
+// ListGroups retrieves a page of the groups of a WhatsApp device matching the filter
+func (s *Service) ListGroups(ctx context.Context, deviceName string, filter domain.GroupFilter, skip, limit int) ([]domain.WhatsAppGroup, int, error) {
+	return s.listGroupsUC.Execute(ctx, deviceName, filter, skip, limit)
+}
+
+// CreateGroup creates a group on a WhatsApp device
+func (s *Service) CreateGroup(ctx context.Context, deviceName, name string, participants []string) (*domain.WhatsAppGroup, error) {
+	return s.manageGroupsUC.Create(ctx, deviceName, name, participants)
+}
+
+// GetGroup retrieves a group of a WhatsApp device
+func (s *Service) GetGroup(ctx context.Context, deviceName, groupJID string) (*domain.WhatsAppGroup, error) {
+	return s.manageGroupsUC.Get(ctx, deviceName, groupJID)
+}
+
+// UpdateGroup changes the name, description and modes of a group
+func (s *Service) UpdateGroup(ctx context.Context, deviceName, groupJID string, update domain.GroupUpdate) (*domain.WhatsAppGroup, error) {
+	return s.manageGroupsUC.Update(ctx, deviceName, groupJID, update)
+}
+
+// UpdateGroupParticipants adds, removes, promotes or demotes group members
+func (s *Service) UpdateGroupParticipants(ctx context.Context, deviceName, groupJID string, participants []string, action domain.GroupParticipantAction) ([]domain.GroupParticipantResult, error) {
+	return s.manageGroupsUC.UpdateParticipants(ctx, deviceName, groupJID, participants, action)
+}
+
+// SetGroupPhoto changes the picture of a group
+func (s *Service) SetGroupPhoto(ctx context.Context, deviceName, groupJID string, photo []byte) (string, error) {
+	return s.manageGroupsUC.SetPhoto(ctx, deviceName, groupJID, photo)
+}
+
+// GetGroupInviteLink returns, or resets and returns, the invite link of a group
+func (s *Service) GetGroupInviteLink(ctx context.Context, deviceName, groupJID string, reset bool) (string, error) {
+	return s.manageGroupsUC.InviteLink(ctx, deviceName, groupJID, reset)
+}
+
+// JoinGroup joins a group by invite link
+func (s *Service) JoinGroup(ctx context.Context, deviceName, link string) (string, error) {
+	return s.manageGroupsUC.Join(ctx, deviceName, link)
+}
+
+// LeaveGroup leaves a group
+func (s *Service) LeaveGroup(ctx context.Context, deviceName, groupJID string) error {
+	return s.manageGroupsUC.Leave(ctx, deviceName, groupJID)
 }
 
 // CreateDevice creates a new device
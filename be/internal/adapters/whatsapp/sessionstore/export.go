@@ -0,0 +1,236 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lib/pq"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// Store images are plain SQLite store files holding a single paired device,
+// whichever backend they come from or go to. The whatsmeow tables are keyed by
+// the device JID in an our_jid or jid column; tables without one, such as the
+// shared LID map, are not part of an image and are rebuilt by whatsmeow.
+
+// inspectImageFile opens a SQLite store file, bringing it to the current
+// schema, and returns the JID of its paired device
+func inspectImageFile(ctx context.Context, path string) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", apperrors.NewNotFoundError("Session store file")
+	}
+
+	container, err := sqlstore.New(ctx, "sqlite3", sqliteDSN(path), waLog.Noop)
+	if err != nil {
+		return "", apperrors.NewValidationError(fmt.Sprintf("Not a valid session store: %v", err))
+	}
+	defer container.Close()
+
+	device, err := container.GetFirstDevice(ctx)
+	if err != nil {
+		return "", apperrors.NewValidationError(fmt.Sprintf("Not a valid session store: %v", err))
+	}
+	if device == nil || device.ID == nil {
+		return "", apperrors.NewValidationError("Session store holds no paired device")
+	}
+	return device.ID.String(), nil
+}
+
+// snapshotSQLite returns a consistent copy of a SQLite file that may be in use
+func snapshotSQLite(ctx context.Context, path string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "session-export-")
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to create temporary directory", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", sqliteDSN(path))
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to open SQLite store", err)
+	}
+	defer db.Close()
+
+	snapshot := filepath.Join(dir, "store.db")
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", snapshot); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to copy SQLite store", err)
+	}
+
+	image, err := os.ReadFile(snapshot)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to read store copy", err)
+	}
+	return image, nil
+}
+
+// Export copies the rows of the device bound to the name into a new SQLite store file
+func (b *postgresBackend) Export(ctx context.Context, deviceName string) ([]byte, error) {
+	var jid string
+	err := b.db.QueryRowContext(ctx,
+		`SELECT jid FROM whatsapp_device_names WHERE device_name = $1`, deviceName).Scan(&jid)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, apperrors.NewDatabaseError("Failed to find device name", err)
+	}
+	if jid == "" {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Paired session of device '%s'", deviceName))
+	}
+
+	dir, err := os.MkdirTemp("", "session-export-")
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to create temporary directory", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store.db")
+
+	// Creating the container lays out the current schema
+	container, err := sqlstore.New(ctx, "sqlite3", sqliteDSN(path), waLog.Noop)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to create SQLite store", err)
+	}
+	container.Close()
+
+	target, err := sql.Open("sqlite3", sqliteDSN(path))
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to open SQLite store", err)
+	}
+	defer target.Close()
+
+	tables, err := sqliteTables(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := target.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to begin export", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		if err := exportTable(ctx, b.db, tx, table, jid); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to commit export", err)
+	}
+	target.Close()
+
+	if _, err := inspectImageFile(ctx, path); err != nil {
+		return nil, err
+	}
+
+	image, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to read exported store", err)
+	}
+	return image, nil
+}
+
+// Import copies the device of the image into the shared database and binds it to the name
+func (b *postgresBackend) Import(ctx context.Context, deviceName string, image []byte) error {
+	file, err := os.CreateTemp("", "session-import-*.db")
+	if err != nil {
+		return apperrors.NewInternalError("Failed to create temporary file", err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.Write(image)
+	file.Close()
+	if err != nil {
+		return apperrors.NewInternalError("Failed to write temporary file", err)
+	}
+
+	if _, err := inspectImageFile(ctx, path); err != nil {
+		return err
+	}
+
+	skipped, err := b.importSQLite(ctx, deviceName, path)
+	if err != nil {
+		return err
+	}
+	if skipped != "" {
+		return apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("Session not imported: %s", skipped))
+	}
+	return nil
+}
+
+// exportTable copies the rows of one device from a Postgres table into the same SQLite table
+func exportTable(ctx context.Context, source *sql.DB, tx *sql.Tx, table, jid string) error {
+	sourceTypes, err := postgresColumnTypes(ctx, source, table)
+	if err != nil {
+		return err
+	}
+
+	deviceColumn := ""
+	for _, candidate := range []string{"our_jid", "jid"} {
+		if _, ok := sourceTypes[candidate]; ok {
+			deviceColumn = candidate
+			break
+		}
+	}
+	if deviceColumn == "" {
+		return nil
+	}
+
+	probe, err := tx.QueryContext(ctx, "SELECT * FROM "+pq.QuoteIdentifier(table)+" LIMIT 0")
+	if err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to describe %s", table), err)
+	}
+	targetColumns, err := probe.Columns()
+	probe.Close()
+	if err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to describe %s", table), err)
+	}
+
+	var columns, names, placeholders []string
+	for _, column := range targetColumns {
+		if _, ok := sourceTypes[column]; !ok {
+			continue
+		}
+		columns = append(columns, column)
+		names = append(names, pq.QuoteIdentifier(column))
+		placeholders = append(placeholders, "?")
+	}
+
+	rows, err := source.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1",
+		strings.Join(names, ", "), pq.QuoteIdentifier(table), pq.QuoteIdentifier(deviceColumn)), jid)
+	if err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+	}
+	defer rows.Close()
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		pq.QuoteIdentifier(table), strings.Join(names, ", "), strings.Join(placeholders, ", "))
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+		}
+
+		args := make([]interface{}, len(columns))
+		for i, column := range columns {
+			args[i] = convertValue(values[i], sourceTypes[column])
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+			return apperrors.NewDatabaseError(fmt.Sprintf("Failed to write %s", table), err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+	}
+	return nil
+}
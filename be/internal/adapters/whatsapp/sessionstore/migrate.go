@@ -0,0 +1,284 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// MigratedSuffix is appended to SQLite store files once they are imported
+const MigratedSuffix = ".migrated"
+
+// MigrationReport lists what happened to each SQLite store
+type MigrationReport struct {
+	Migrated []string          // Imported devices
+	Skipped  map[string]string // Devices left alone, with the reason
+	Failed   map[string]string // Devices that could not be imported, with the error
+}
+
+// MigrateSQLite imports the paired devices of the SQLite store files in dir
+// into a Postgres backend. Each device is copied table by table in a single
+// transaction and bound to its name; the file is then renamed with
+// MigratedSuffix. Names already bound in Postgres are skipped, so a device
+// paired again after switching backends is never overwritten. A failed device
+// does not stop the others.
+func MigrateSQLite(ctx context.Context, dir string, target Backend) (*MigrationReport, error) {
+	backend, ok := target.(*postgresBackend)
+	if !ok {
+		return nil, apperrors.NewValidationError("SQLite stores can only be migrated into the postgres session store")
+	}
+
+	log := logger.New("SessionStoreMigration").WithField("dir", dir)
+
+	deviceNames, err := listSQLiteStores(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MigrationReport{
+		Migrated: []string{},
+		Skipped:  make(map[string]string),
+		Failed:   make(map[string]string),
+	}
+	if len(deviceNames) == 0 {
+		return report, nil
+	}
+
+	log.WithField("stores", len(deviceNames)).Info("Migrating SQLite session stores to Postgres")
+
+	for _, deviceName := range deviceNames {
+		path := filepath.Join(dir, deviceName+FileSuffix)
+
+		skipped, err := backend.importSQLite(ctx, deviceName, path)
+		switch {
+		case err != nil:
+			log.WithField("device", deviceName).Error("Failed to migrate session store: %v", err)
+			report.Failed[deviceName] = err.Error()
+			continue
+		case skipped != "":
+			log.WithField("device", deviceName).Warn("Session store not migrated: %s", skipped)
+			report.Skipped[deviceName] = skipped
+			continue
+		}
+
+		if err := os.Rename(path, path+MigratedSuffix); err != nil {
+			log.WithField("device", deviceName).Warn("Migrated session store could not be renamed: %v", err)
+		}
+		report.Migrated = append(report.Migrated, deviceName)
+	}
+
+	log.WithFields(map[string]interface{}{
+		"migrated": len(report.Migrated),
+		"skipped":  len(report.Skipped),
+		"failed":   len(report.Failed),
+	}).Success("SQLite session stores migrated")
+
+	return report, nil
+}
+
+// importSQLite copies the device of a SQLite store file. It returns a reason
+// when the file was skipped.
+func (b *postgresBackend) importSQLite(ctx context.Context, deviceName, path string) (string, error) {
+	var bound string
+	err := b.db.QueryRowContext(ctx,
+		`SELECT jid FROM whatsapp_device_names WHERE device_name = $1`, deviceName).Scan(&bound)
+	if err != nil && err != sql.ErrNoRows {
+		return "", apperrors.NewDatabaseError("Failed to find device name", err)
+	}
+	if bound != "" {
+		return fmt.Sprintf("device name is already bound to %s", bound), nil
+	}
+
+	// Opening through sqlstore brings the file to the schema version of the target
+	source, err := sqlstore.New(ctx, "sqlite3", sqliteDSN(path), waLog.Noop)
+	if err != nil {
+		return "", apperrors.NewDatabaseError("Failed to open SQLite store", err)
+	}
+	device, err := source.GetFirstDevice(ctx)
+	source.Close()
+	if err != nil {
+		return "", apperrors.NewDatabaseError("Failed to get device from SQLite store", err)
+	}
+	if device == nil || device.ID == nil {
+		return "device was never paired", nil
+	}
+
+	db, err := sql.Open("sqlite3", sqliteDSN(path))
+	if err != nil {
+		return "", apperrors.NewDatabaseError("Failed to open SQLite store", err)
+	}
+	defer db.Close()
+
+	tables, err := sqliteTables(ctx, db)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", apperrors.NewDatabaseError("Failed to begin migration", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		if err := copyTable(ctx, db, tx, table); err != nil {
+			return "", err
+		}
+	}
+
+	if err := bindDeviceName(ctx, tx, deviceName, *device.ID); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", apperrors.NewDatabaseError("Failed to commit migration", err)
+	}
+	return "", nil
+}
+
+// sqliteTables returns the whatsmeow data tables of a store, devices first so
+// that the rows referencing them can be inserted
+func sqliteTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT name FROM sqlite_master WHERE type = 'table' AND name LIKE 'whatsmeow%' AND name != 'whatsmeow_version'`)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to list SQLite tables", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, apperrors.NewDatabaseError("Failed to list SQLite tables", err)
+		}
+		tables = append(tables, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to list SQLite tables", err)
+	}
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i] == "whatsmeow_device" && tables[j] != "whatsmeow_device"
+	})
+	return tables, nil
+}
+
+// copyTable inserts every row of a SQLite table into the same Postgres table.
+// Columns the target does not have are dropped, and values are converted to
+// the target column types where SQLite stores them differently.
+func copyTable(ctx context.Context, source *sql.DB, tx *sql.Tx, table string) error {
+	targetTypes, err := postgresColumnTypes(ctx, tx, table)
+	if err != nil {
+		return err
+	}
+	if len(targetTypes) == 0 {
+		return nil
+	}
+
+	rows, err := source.QueryContext(ctx, "SELECT * FROM "+pq.QuoteIdentifier(table))
+	if err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+	}
+
+	var kept []int
+	var names, placeholders []string
+	for i, column := range columns {
+		if _, ok := targetTypes[column]; !ok {
+			continue
+		}
+		kept = append(kept, i)
+		names = append(names, pq.QuoteIdentifier(column))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(placeholders)+1))
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+		pq.QuoteIdentifier(table), strings.Join(names, ", "), strings.Join(placeholders, ", "))
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+		}
+
+		args := make([]interface{}, 0, len(kept))
+		for _, i := range kept {
+			args = append(args, convertValue(values[i], targetTypes[columns[i]]))
+		}
+
+		if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+			return apperrors.NewDatabaseError(fmt.Sprintf("Failed to write %s", table), err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.NewDatabaseError(fmt.Sprintf("Failed to read %s", table), err)
+	}
+	return nil
+}
+
+// postgresColumnTypes returns the data types of a Postgres table's columns
+func postgresColumnTypes(ctx context.Context, db queryer, table string) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1`, table)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError(fmt.Sprintf("Failed to describe %s", table), err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return nil, apperrors.NewDatabaseError(fmt.Sprintf("Failed to describe %s", table), err)
+		}
+		columns[name] = dataType
+	}
+	return columns, rows.Err()
+}
+
+// convertValue adapts a value read from SQLite to a Postgres column type.
+// SQLite keeps booleans as integers and may return text as bytes.
+func convertValue(value interface{}, dataType string) interface{} {
+	switch dataType {
+	case "boolean":
+		switch v := value.(type) {
+		case int64:
+			return v != 0
+		case []byte:
+			return string(v) == "1" || strings.EqualFold(string(v), "true")
+		case string:
+			return v == "1" || strings.EqualFold(v, "true")
+		}
+	case "text", "character varying", "uuid":
+		if v, ok := value.([]byte); ok {
+			return string(v)
+		}
+	case "bytea":
+		if v, ok := value.(string); ok {
+			return []byte(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,220 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// deviceNamesSchema maps device names to the whatsmeow devices of the shared
+// database. The JID is empty until the device is paired.
+const deviceNamesSchema = `
+	CREATE TABLE IF NOT EXISTS whatsapp_device_names (
+		device_name TEXT PRIMARY KEY,
+		jid         TEXT NOT NULL DEFAULT '',
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+
+// postgresBackend keeps every device in one shared Postgres database
+type postgresBackend struct {
+	db        *sql.DB
+	container *sqlstore.Container
+	location  string
+}
+
+// newPostgresBackend connects to the database and brings its schema up to date
+func newPostgresBackend(ctx context.Context, dsn string, log waLog.Logger) (*postgresBackend, error) {
+	if dsn == "" {
+		return nil, apperrors.NewValidationError("A Postgres connection string is required for the postgres session store")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to open Postgres session store", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, apperrors.NewDatabaseError("Failed to connect to Postgres session store", err)
+	}
+
+	container := sqlstore.NewWithDB(db, "postgres", log)
+	if err := container.Upgrade(ctx); err != nil {
+		db.Close()
+		return nil, apperrors.NewDatabaseError("Failed to upgrade Postgres session store", err)
+	}
+	if _, err := db.ExecContext(ctx, deviceNamesSchema); err != nil {
+		db.Close()
+		return nil, apperrors.NewDatabaseError("Failed to create device names table", err)
+	}
+
+	return &postgresBackend{
+		db:        db,
+		container: container,
+		location:  redactDSN(dsn),
+	}, nil
+}
+
+// Open returns the device bound to the name, or a new unpaired device
+func (b *postgresBackend) Open(ctx context.Context, deviceName string) (*store.Device, error) {
+	if _, err := b.db.ExecContext(ctx,
+		`INSERT INTO whatsapp_device_names (device_name) VALUES ($1) ON CONFLICT (device_name) DO NOTHING`,
+		deviceName); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to register device name", err)
+	}
+
+	device, err := b.boundDevice(ctx, deviceName)
+	if err != nil {
+		return nil, err
+	}
+	if device == nil {
+		device = b.container.NewDevice()
+	}
+	return device, nil
+}
+
+// Bind records the JID the device was paired as
+func (b *postgresBackend) Bind(ctx context.Context, deviceName string, jid types.JID) error {
+	return bindDeviceName(ctx, b.db, deviceName, jid)
+}
+
+// Release does nothing: the database is shared by all devices
+func (b *postgresBackend) Release(deviceName string) error {
+	return nil
+}
+
+// Remove deletes the device bound to the name and the name itself
+func (b *postgresBackend) Remove(ctx context.Context, deviceName string) error {
+	device, err := b.boundDevice(ctx, deviceName)
+	if err != nil {
+		return err
+	}
+	if device != nil {
+		if err := b.container.DeleteDevice(ctx, device); err != nil {
+			return apperrors.NewDatabaseError("Failed to delete device store", err)
+		}
+	}
+
+	if _, err := b.db.ExecContext(ctx,
+		`DELETE FROM whatsapp_device_names WHERE device_name = $1`, deviceName); err != nil {
+		return apperrors.NewDatabaseError("Failed to delete device name", err)
+	}
+	return nil
+}
+
+// Rename moves the name's binding to the new name
+func (b *postgresBackend) Rename(ctx context.Context, from, to string) error {
+	var exists bool
+	if err := b.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM whatsapp_device_names WHERE device_name = $1)`, to).Scan(&exists); err != nil {
+		return apperrors.NewDatabaseError("Failed to find device name", err)
+	}
+	if exists {
+		return apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' already has a session store", to))
+	}
+
+	if _, err := b.db.ExecContext(ctx,
+		`UPDATE whatsapp_device_names SET device_name = $2, updated_at = now() WHERE device_name = $1`,
+		from, to); err != nil {
+		return apperrors.NewDatabaseError("Failed to rename device name", err)
+	}
+	return nil
+}
+
+// List returns the registered device names
+func (b *postgresBackend) List(ctx context.Context) ([]string, error) {
+	rows, err := b.db.QueryContext(ctx, `SELECT device_name FROM whatsapp_device_names ORDER BY device_name`)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to list device names", err)
+	}
+	defer rows.Close()
+
+	deviceNames := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, apperrors.NewDatabaseError("Failed to read device name", err)
+		}
+		deviceNames = append(deviceNames, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to list device names", err)
+	}
+	return deviceNames, nil
+}
+
+// Location returns the database address and the device name
+func (b *postgresBackend) Location(deviceName string) string {
+	return b.location + "#" + deviceName
+}
+
+// Close closes the database
+func (b *postgresBackend) Close() error {
+	return b.container.Close()
+}
+
+// boundDevice returns the device bound to the name, or nil if the name is not
+// bound or the device was deleted, e.g. by logging out
+func (b *postgresBackend) boundDevice(ctx context.Context, deviceName string) (*store.Device, error) {
+	var jid string
+	err := b.db.QueryRowContext(ctx,
+		`SELECT jid FROM whatsapp_device_names WHERE device_name = $1`, deviceName).Scan(&jid)
+	if err == sql.ErrNoRows || (err == nil && jid == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to find device name", err)
+	}
+
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Invalid JID bound to device name", err)
+	}
+
+	device, err := b.container.GetDevice(ctx, parsed)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to get device from store", err)
+	}
+	return device, nil
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// bindDeviceName records the JID of a device name
+func bindDeviceName(ctx context.Context, db execer, deviceName string, jid types.JID) error {
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO whatsapp_device_names (device_name, jid) VALUES ($1, $2)
+		ON CONFLICT (device_name) DO UPDATE SET jid = excluded.jid, updated_at = now()`,
+		deviceName, jid.String()); err != nil {
+		return apperrors.NewDatabaseError("Failed to bind device name", err)
+	}
+	return nil
+}
+
+// redactDSN returns the address of a Postgres URL without credentials or parameters
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return "postgres"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,104 @@
+// Package sessionstore keeps the whatsmeow device stores of named devices,
+// either in one SQLite file per device or in a single shared Postgres database.
+package sessionstore
+
+import (
+	"context"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// Supported backends
+const (
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// FileSuffix is appended to the device name to form its SQLite store file name
+const FileSuffix = "_store.db"
+
+// Backend keeps the whatsmeow device stores of named devices
+type Backend interface {
+	// Open returns the device store of a device, or a new unpaired one if it has none
+	Open(ctx context.Context, deviceName string) (*store.Device, error)
+
+	// Bind records the JID a device was paired as, so that Open finds it again
+	Bind(ctx context.Context, deviceName string, jid types.JID) error
+
+	// Release frees what was held open for a device by Open
+	Release(deviceName string) error
+
+	// Remove releases a device and deletes its store
+	Remove(ctx context.Context, deviceName string) error
+
+	// Rename moves a device's store to a new name. The device must not be open
+	// and the new name must not have a store.
+	Rename(ctx context.Context, from, to string) error
+
+	// List returns the names of the devices that have a store
+	List(ctx context.Context) ([]string, error)
+
+	// Location describes where a device's store is kept, without credentials
+	Location(deviceName string) string
+
+	// Export returns a SQLite store image holding only the device's paired session
+	Export(ctx context.Context, deviceName string) ([]byte, error)
+
+	// Import loads a SQLite store image produced by Export as the device's
+	// store. The device must not be open and must not have a paired session.
+	Import(ctx context.Context, deviceName string, image []byte) error
+
+	// Close releases every device and the backend itself
+	Close() error
+}
+
+// Config selects and configures a backend
+type Config struct {
+	Backend   string // BackendSQLite (default) or BackendPostgres
+	StoresDir string // Directory of the SQLite store files
+	DSN       string // Postgres connection string
+
+	// MigrateSQLite imports the SQLite stores found in StoresDir when the
+	// backend is Postgres. Imported files are renamed, so this is safe to
+	// leave enabled.
+	MigrateSQLite bool
+
+	LogLevel string // whatsmeow database log level, default ERROR
+}
+
+// New opens the configured backend
+func New(ctx context.Context, config Config) (Backend, error) {
+	if config.LogLevel == "" {
+		config.LogLevel = "ERROR"
+	}
+	if config.StoresDir == "" {
+		config.StoresDir = "./stores"
+	}
+
+	switch config.Backend {
+	case "", BackendSQLite:
+		return newSQLiteBackend(config.StoresDir, config.LogLevel)
+
+	case BackendPostgres:
+		backend, err := newPostgresBackend(ctx, config.DSN, waLog.Stdout("DB-postgres", config.LogLevel, true))
+		if err != nil {
+			return nil, err
+		}
+
+		if config.MigrateSQLite {
+			if _, err := MigrateSQLite(ctx, config.StoresDir, backend); err != nil {
+				backend.Close()
+				return nil, err
+			}
+		}
+		return backend, nil
+
+	default:
+		return nil, fmt.Errorf("unknown session store backend %q", config.Backend)
+	}
+}
@@ -0,0 +1,214 @@
+package sessionstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// sqliteBackend keeps every device in its own <name>_store.db file
+type sqliteBackend struct {
+	dir      string
+	logLevel string
+
+	mu         sync.Mutex
+	containers map[string]*sqlstore.Container
+}
+
+// newSQLiteBackend creates a backend over the store files in dir
+func newSQLiteBackend(dir, logLevel string) (*sqliteBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, apperrors.NewInternalError("Failed to create stores directory", err)
+	}
+
+	return &sqliteBackend{
+		dir:        dir,
+		logLevel:   logLevel,
+		containers: make(map[string]*sqlstore.Container),
+	}, nil
+}
+
+// Open returns the first device in the device's file, creating the file if needed
+func (b *sqliteBackend) Open(ctx context.Context, deviceName string) (*store.Device, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	container, ok := b.containers[deviceName]
+	if !ok {
+		var err error
+		container, err = sqlstore.New(ctx, "sqlite3", sqliteDSN(b.path(deviceName)),
+			waLog.Stdout("DB-"+deviceName, b.logLevel, true))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("Failed to create SQLite store", err)
+		}
+		b.containers[deviceName] = container
+	}
+
+	device, err := container.GetFirstDevice(ctx)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to get device from store", err)
+	}
+	if device == nil {
+		device = container.NewDevice()
+	}
+	return device, nil
+}
+
+// Bind does nothing: a file only ever holds one device
+func (b *sqliteBackend) Bind(ctx context.Context, deviceName string, jid types.JID) error {
+	return nil
+}
+
+// Release closes the device's file
+func (b *sqliteBackend) Release(deviceName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.release(deviceName)
+}
+
+// release closes the device's file. Callers hold b.mu.
+func (b *sqliteBackend) release(deviceName string) error {
+	container, ok := b.containers[deviceName]
+	if !ok {
+		return nil
+	}
+	delete(b.containers, deviceName)
+	return container.Close()
+}
+
+// Remove closes and deletes the device's file
+func (b *sqliteBackend) Remove(ctx context.Context, deviceName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.release(deviceName); err != nil {
+		return apperrors.NewDatabaseError("Failed to close SQLite store", err)
+	}
+
+	if err := os.Remove(b.path(deviceName)); err != nil && !os.IsNotExist(err) {
+		return apperrors.NewInternalError("Failed to delete store file", err)
+	}
+	return nil
+}
+
+// Rename renames the device's file
+func (b *sqliteBackend) Rename(ctx context.Context, from, to string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, open := b.containers[from]; open {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Session store is in use")
+	}
+	if _, err := os.Stat(b.path(to)); err == nil {
+		return apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' already has a session store", to))
+	}
+
+	// A device that was never opened has no file to move
+	if err := os.Rename(b.path(from), b.path(to)); err != nil && !os.IsNotExist(err) {
+		return apperrors.NewInternalError("Failed to rename store file", err)
+	}
+	return nil
+}
+
+// List returns the names of the store files in the directory
+func (b *sqliteBackend) List(ctx context.Context) ([]string, error) {
+	return listSQLiteStores(b.dir)
+}
+
+// Location returns the path of the device's file
+func (b *sqliteBackend) Location(deviceName string) string {
+	return b.path(deviceName)
+}
+
+// Export returns a consistent copy of the device's file, taken while it may be in use
+func (b *sqliteBackend) Export(ctx context.Context, deviceName string) ([]byte, error) {
+	path := b.path(deviceName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Session store of device '%s'", deviceName))
+	}
+
+	if _, err := inspectImageFile(ctx, path); err != nil {
+		return nil, err
+	}
+	return snapshotSQLite(ctx, path)
+}
+
+// Import writes the image as the device's file
+func (b *sqliteBackend) Import(ctx context.Context, deviceName string, image []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, open := b.containers[deviceName]; open {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Session store is in use")
+	}
+
+	path := b.path(deviceName)
+	if _, err := inspectImageFile(ctx, path); err == nil {
+		return apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' already has a paired session", deviceName))
+	}
+
+	// Write next to the target so that the rename is atomic
+	staged := path + ".restore"
+	if err := os.WriteFile(staged, image, 0600); err != nil {
+		return apperrors.NewInternalError("Failed to write store file", err)
+	}
+	defer os.Remove(staged)
+
+	if _, err := inspectImageFile(ctx, staged); err != nil {
+		return err
+	}
+	if err := os.Rename(staged, path); err != nil {
+		return apperrors.NewInternalError("Failed to replace store file", err)
+	}
+	return nil
+}
+
+// Close closes every open file
+func (b *sqliteBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var firstErr error
+	for deviceName := range b.containers {
+		if err := b.release(deviceName); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// path returns the path of a device's store file
+func (b *sqliteBackend) path(deviceName string) string {
+	return filepath.Join(b.dir, deviceName+FileSuffix)
+}
+
+// sqliteDSN returns the connection string of a store file
+func sqliteDSN(path string) string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on", path)
+}
+
+// listSQLiteStores returns the device names of the store files in dir
+func listSQLiteStores(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileSuffix))
+	if err != nil {
+		return nil, apperrors.NewInternalError("Failed to glob store files", err)
+	}
+
+	deviceNames := make([]string, 0, len(files))
+	for _, file := range files {
+		deviceNames = append(deviceNames, strings.TrimSuffix(filepath.Base(file), FileSuffix))
+	}
+	return deviceNames, nil
+}
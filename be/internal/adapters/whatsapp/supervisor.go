@@ -0,0 +1,337 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// maxConnectionHistory is the number of transitions kept per device
+	maxConnectionHistory = 50
+
+	// connectConfirmTimeout is how long a reconnect waits for the Connected event
+	connectConfirmTimeout = 30 * time.Second
+)
+
+// SupervisorConfig controls reconnect backoff
+type SupervisorConfig struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64 // Fraction of the delay added or removed at random, e.g. 0.2
+}
+
+// Supervisor keeps paired devices connected. It reconnects dropped
+// connections with exponential backoff and jitter, resets devices that were
+// logged out from the phone and records every connection state transition.
+type Supervisor struct {
+	manager      *Manager
+	eventHandler domain.WhatsAppEventHandler
+	config       SupervisorConfig
+	logger       *logger.Logger
+
+	mu        sync.Mutex
+	devices   map[string]*supervisedDevice
+	rand      *rand.Rand
+	listeners []func(domain.ConnectionTransition)
+}
+
+// supervisedDevice is the connection state of a single device
+type supervisedDevice struct {
+	status   domain.ConnectionStatus
+	attempts int
+	cancel   context.CancelFunc // Cancels the pending reconnect, if any
+	loop     int                // Identifies the current reconnect loop
+	history  []domain.ConnectionTransition
+}
+
+// NewSupervisor creates a new connection supervisor for the manager's clients
+func NewSupervisor(manager *Manager, eventHandler domain.WhatsAppEventHandler, config SupervisorConfig) *Supervisor {
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = 2 * time.Second
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	if config.Jitter < 0 || config.Jitter > 1 {
+		config.Jitter = 0.2
+	}
+
+	return &Supervisor{
+		manager:      manager,
+		eventHandler: eventHandler,
+		config:       config,
+		logger:       logger.New("ConnectionSupervisor"),
+		devices:      make(map[string]*supervisedDevice),
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// OnTransition registers a function called with every recorded transition.
+// It is called with the supervisor locked and must not block.
+func (s *Supervisor) OnTransition(listener func(domain.ConnectionTransition)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// Status returns the last recorded status of a device, or an empty status if none was recorded
+func (s *Supervisor) Status(deviceName string) domain.ConnectionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if device, ok := s.devices[deviceName]; ok {
+		return device.status
+	}
+	return ""
+}
+
+// History returns the recorded transitions of a device, oldest first
+func (s *Supervisor) History(deviceName string) []domain.ConnectionTransition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	device, ok := s.devices[deviceName]
+	if !ok {
+		return []domain.ConnectionTransition{}
+	}
+
+	history := make([]domain.ConnectionTransition, len(device.history))
+	copy(history, device.history)
+	return history
+}
+
+// Forget stops supervising a device and drops its history
+func (s *Supervisor) Forget(deviceName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if device, ok := s.devices[deviceName]; ok && device.cancel != nil {
+		device.cancel()
+	}
+	delete(s.devices, deviceName)
+}
+
+// Stop cancels all pending reconnects and records connected devices as
+// disconnected, since intentional disconnects raise no events
+func (s *Supervisor) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for deviceName, device := range s.devices {
+		if device.cancel != nil {
+			device.cancel()
+			device.cancel = nil
+		}
+		if device.status == domain.StatusConnected {
+			s.record(deviceName, device, domain.StatusDisconnected, "shutting down", 0)
+		}
+	}
+}
+
+// handleConnected records a successful connection and resets the backoff
+func (s *Supervisor) handleConnected(client *Client) {
+	s.mu.Lock()
+	device := s.device(client.deviceName)
+	if device.cancel != nil {
+		device.cancel()
+		device.cancel = nil
+	}
+	device.attempts = 0
+	s.record(client.deviceName, device, domain.StatusConnected, "connected", 0)
+	s.mu.Unlock()
+}
+
+// handleDisconnected records a dropped connection, reports it and schedules a reconnect if retry is set
+func (s *Supervisor) handleDisconnected(client *Client, status domain.ConnectionStatus, reason string, retry bool) {
+	s.mu.Lock()
+	device := s.device(client.deviceName)
+	s.record(client.deviceName, device, status, reason, device.attempts)
+	s.mu.Unlock()
+
+	s.notifyDisconnected(client.deviceName, reason)
+
+	if retry {
+		s.scheduleReconnect(client, 0)
+	}
+}
+
+// handleTemporaryBan waits out a temporary ban before reconnecting
+func (s *Supervisor) handleTemporaryBan(client *Client, reason string, expire time.Duration) {
+	s.mu.Lock()
+	device := s.device(client.deviceName)
+	s.record(client.deviceName, device, domain.StatusBanned, reason, device.attempts)
+	s.mu.Unlock()
+
+	s.notifyDisconnected(client.deviceName, reason)
+
+	if expire > 0 {
+		s.scheduleReconnect(client, expire)
+	}
+}
+
+// handleLoggedOut marks the device as needing re-pairing and replaces its store
+// with an empty one, so that it can be paired again
+func (s *Supervisor) handleLoggedOut(client *Client, reason string) {
+	s.mu.Lock()
+	device := s.device(client.deviceName)
+	if device.cancel != nil {
+		device.cancel()
+		device.cancel = nil
+	}
+	device.attempts = 0
+	s.record(client.deviceName, device, domain.StatusLoggedOut, reason, 0)
+	s.mu.Unlock()
+
+	s.notifyDisconnected(client.deviceName, reason)
+
+	// The event is dispatched from the client's own goroutine, which the reset disconnects
+	go func() {
+		if err := s.manager.resetClient(context.Background(), client); err != nil {
+			s.logger.WithField("device", client.deviceName).Error("Failed to reset logged out device: %v", err)
+		}
+	}()
+}
+
+// scheduleReconnect starts a reconnect loop for the client unless one is already running.
+// A positive delay replaces the backoff before the first attempt.
+func (s *Supervisor) scheduleReconnect(client *Client, delay time.Duration) {
+	s.mu.Lock()
+	device := s.device(client.deviceName)
+	if device.cancel != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(client.connection())
+	device.cancel = cancel
+	device.loop++
+	loop := device.loop
+	s.mu.Unlock()
+
+	go s.reconnect(ctx, client, loop, delay)
+}
+
+// reconnect retries the connection until it is established, the client is
+// closed or the reconnect is cancelled
+func (s *Supervisor) reconnect(ctx context.Context, client *Client, loop int, delay time.Duration) {
+	defer func() {
+		s.mu.Lock()
+		if device, ok := s.devices[client.deviceName]; ok && device.loop == loop && device.cancel != nil {
+			device.cancel()
+			device.cancel = nil
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		s.mu.Lock()
+		device := s.device(client.deviceName)
+		device.attempts++
+		attempt := device.attempts
+		wait := delay
+		if wait <= 0 {
+			wait = s.backoff(attempt)
+		}
+		delay = 0
+		s.record(client.deviceName, device, domain.StatusReconnecting,
+			fmt.Sprintf("reconnecting in %s", wait.Round(time.Second)), attempt)
+		s.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+
+		if client.client.IsConnected() && client.client.IsLoggedIn() {
+			return
+		}
+
+		s.mu.Lock()
+		s.record(client.deviceName, s.device(client.deviceName), domain.StatusConnecting,
+			fmt.Sprintf("reconnect attempt %d", attempt), attempt)
+		s.mu.Unlock()
+		s.manager.metrics.reconnectAttempted(client.deviceName)
+
+		if err := client.client.Connect(); err != nil {
+			s.logger.WithFields(map[string]interface{}{
+				"device":  client.deviceName,
+				"attempt": attempt,
+			}).Warn("Reconnect failed: %v", err)
+			continue
+		}
+
+		// Connect only opens the websocket; the Connected event confirms the
+		// login and cancels ctx. Without it the next attempt follows.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(connectConfirmTimeout):
+			client.client.Disconnect()
+		}
+	}
+}
+
+// backoff returns the delay before the given attempt: exponential, capped and jittered
+func (s *Supervisor) backoff(attempt int) time.Duration {
+	delay := float64(s.config.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if delay > float64(s.config.MaxBackoff) {
+		delay = float64(s.config.MaxBackoff)
+	}
+
+	delay *= 1 + s.config.Jitter*(2*s.rand.Float64()-1)
+	return time.Duration(delay)
+}
+
+// notifyDisconnected surfaces a disconnect and its reason to the event handler
+func (s *Supervisor) notifyDisconnected(deviceName, reason string) {
+	if s.eventHandler != nil {
+		s.eventHandler.OnDisconnected(deviceName, reason)
+	}
+}
+
+// device returns the state of a device, creating it if needed. Callers hold s.mu.
+func (s *Supervisor) device(deviceName string) *supervisedDevice {
+	device, ok := s.devices[deviceName]
+	if !ok {
+		device = &supervisedDevice{status: domain.StatusDisconnected}
+		s.devices[deviceName] = device
+	}
+	return device
+}
+
+// record appends a transition to the device's history. Callers hold s.mu.
+func (s *Supervisor) record(deviceName string, device *supervisedDevice, to domain.ConnectionStatus, reason string, attempt int) {
+	transition := domain.ConnectionTransition{
+		DeviceName: deviceName,
+		From:       device.status,
+		To:         to,
+		Reason:     reason,
+		Attempt:    attempt,
+		Timestamp:  time.Now(),
+	}
+
+	device.status = to
+	device.history = append(device.history, transition)
+	if len(device.history) > maxConnectionHistory {
+		device.history = device.history[len(device.history)-maxConnectionHistory:]
+	}
+
+	for _, listener := range s.listeners {
+		listener(transition)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"device":  deviceName,
+		"from":    transition.From,
+		"to":      transition.To,
+		"attempt": attempt,
+	}).Info("Connection state changed: %s", reason)
+}
@@ -3,17 +3,27 @@ package app
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/repositories"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/apikey"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/message"
+	waUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/whatsapp"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast"
+	broadcastDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	broadcastRepo "github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/repository"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse"
 	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
 	qrRepo "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/repository"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule"
+	scheduleDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+	scheduleRepo "github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/repository"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/config"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -29,18 +39,34 @@ type Container struct {
 	MongoDB *mongo.Database
 
 	// Repositories
-	DeviceRepository ports.DeviceRepository
-	QRRepository     qrDomain.QuickResponseRepository
-	APIKeyRepository domain.APIKeyRepository
+	DeviceRepository  ports.DeviceRepository
+	SessionRepository ports.WhatsAppSessionRepository
+	QRRepository      qrDomain.QuickResponseRepository
+	QRMigrator        qrDomain.SchemaMigrator
+	APIKeyRepository  domain.APIKeyRepository
+	LeaseRepository   ports.LeaseRepository // Nil unless device leases are enabled
+	ContactRepository ports.ContactRepository
+
+	DigestScheduleRepository qrDomain.DigestScheduleRepository
+	OfficerRepository        qrDomain.OfficerRepository
+	ReminderRepository       qrDomain.ReminderRepository
+
+	CampaignRepository broadcastDomain.CampaignRepository
+	SegmentRepository  broadcastDomain.SegmentRepository
+
+	ScheduleRepository scheduleDomain.ScheduleRepository
 
 	// Message Processing
 	MessageRegistry domain.MessageProcessorRegistry
 	QRProcessor     domain.MessageProcessor
 
 	// WhatsApp
+	SessionStore         sessionstore.Backend
 	WhatsAppEventHandler *whatsapp.EventHandler
 	WhatsAppManager      domain.WhatsAppManagerInterface
 	WhatsAppService      ports.WhatsAppService
+	LeaseKeeper          *whatsapp.LeaseKeeper // Nil unless device leases are enabled
+	Metrics              domain.DeviceMetricsReader
 
 	// Use Cases - Device
 	CreateDeviceUC *device.CreateDeviceUseCase
@@ -49,8 +75,35 @@ type Container struct {
 	UpdateDeviceUC *device.UpdateDeviceUseCase
 	DeleteDeviceUC *device.DeleteDeviceUseCase
 
+	SyncDeviceStateUC  *device.SyncDeviceStateUseCase
+	ReconcileDevicesUC *device.ReconcileDevicesUseCase
+	AuthorizeDeviceUC  *device.AuthorizeDeviceUseCase
+	BackupDeviceUC     *device.BackupDeviceUseCase
+	RestoreDeviceUC    *device.RestoreDeviceUseCase
+
+	// Use Cases - WhatsApp sessions
+	RecordSessionUC *waUsecase.RecordSessionUseCase
+	SessionStatusUC *waUsecase.SessionStatusUseCase
+	QRPairingUC     *waUsecase.QRPairingUseCase
+
+	// Use Cases - WhatsApp profiles
+	GetProfileUC      *waUsecase.GetProfileUseCase
+	UpdateProfileUC   *waUsecase.UpdateProfileUseCase
+	SetProfilePhotoUC *waUsecase.SetProfilePhotoUseCase
+	ProfilePictureUC  *waUsecase.ProfilePictureUseCase
+
+	// Use Cases - WhatsApp presence
+	FollowPresenceUC *waUsecase.FollowPresenceUseCase
+	GetPresenceUC    *waUsecase.GetPresenceUseCase
+
+	// Use Cases - WhatsApp contacts
+	SyncContactsUC     *waUsecase.SyncContactsUseCase
+	ContactDirectoryUC *waUsecase.ContactDirectoryUseCase
+	CheckNumbersUC     *waUsecase.CheckNumbersUseCase
+
 	// Use Cases - Message
 	ProcessMessageUC *message.ProcessMessageUseCase
+	ImportChatUC     *message.ImportChatUseCase
 
 	// Use Cases - API Key
 	GenerateAPIKeyUC *apikey.GenerateKeyUseCase
@@ -59,6 +112,25 @@ type Container struct {
 	UpdateAPIKeyUC   *apikey.UpdateKeyUseCase
 	ValidateAPIKeyUC *apikey.ValidateKeyUseCase
 
+	// Quick Response digests and reminders
+	DigestService   *quickresponse.DigestService
+	DigestScheduler *quickresponse.DigestScheduler
+	ReminderService *quickresponse.ReminderService
+	ReminderJob     *quickresponse.ReminderJob
+
+	// Broadcast campaigns
+	CampaignService *broadcast.CampaignService
+	CampaignRunner  *broadcast.CampaignRunner
+
+	// Scheduled and recurring messages
+	ScheduleService *schedule.ScheduleService
+	ScheduleRunner  *schedule.ScheduleRunner
+
+	// Streams is cancelled by EndStreams; long-lived responses such as event
+	// streams and QR long-polls end with it
+	Streams    context.Context
+	endStreams context.CancelFunc
+
 	logger *logger.Logger
 }
 
@@ -77,6 +149,7 @@ func NewContainer(ctx context.Context) (*Container, error) {
 		Config: cfg,
 		logger: log,
 	}
+	container.Streams, container.endStreams = context.WithCancel(context.Background())
 
 	// Initialize components in order
 	if err := container.initDatabase(ctx); err != nil {
@@ -99,6 +172,14 @@ func NewContainer(ctx context.Context) (*Container, error) {
 		return nil, err
 	}
 
+	if err := container.initDevices(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := container.initSchedulers(); err != nil {
+		return nil, err
+	}
+
 	log.Success("Application container initialized")
 	return container, nil
 }
@@ -132,8 +213,12 @@ func (c *Container) initRepositories() error {
 	// Device repository
 	c.DeviceRepository = repositories.NewDeviceMongoRepository(c.MongoDB)
 
+	// WhatsApp session and connection history repository
+	c.SessionRepository = repositories.NewWhatsAppSessionMongoRepository(c.MongoDB, c.Config.WhatsApp.ConnectionHistoryRetention)
+
 	// Quick Response repository
 	c.QRRepository = qrRepo.NewMongoRepository(c.MongoDB)
+	c.QRMigrator = qrRepo.NewSchemaV2Migrator(c.MongoDB)
 
 	// API Key repository
 	apiKeyRepo, err := repositories.NewAPIKeyMongoRepository(c.MongoDB, c.logger)
@@ -142,6 +227,28 @@ func (c *Container) initRepositories() error {
 	}
 	c.APIKeyRepository = apiKeyRepo
 
+	// Digest schedule repository
+	c.DigestScheduleRepository = qrRepo.NewDigestScheduleMongoRepository(c.MongoDB)
+
+	// Officer roster and reminder repositories
+	c.OfficerRepository = qrRepo.NewOfficerMongoRepository(c.MongoDB)
+	c.ReminderRepository = qrRepo.NewReminderMongoRepository(c.MongoDB)
+
+	// Contact directories of the devices
+	c.ContactRepository = repositories.NewContactMongoRepository(c.MongoDB)
+
+	// Broadcast campaigns and saved contact segments
+	c.CampaignRepository = broadcastRepo.NewCampaignMongoRepository(c.MongoDB)
+	c.SegmentRepository = broadcastRepo.NewSegmentMongoRepository(c.MongoDB)
+
+	// Scheduled messages and their runs
+	c.ScheduleRepository = scheduleRepo.NewScheduleMongoRepository(c.MongoDB)
+
+	// Device leases, shared by the replicas
+	if c.Config.WhatsApp.LeasesEnabled {
+		c.LeaseRepository = repositories.NewLeaseMongoRepository(c.MongoDB)
+	}
+
 	c.logger.Success("Repositories initialized")
 	return nil
 }
@@ -165,22 +272,43 @@ func (c *Container) initMessageProcessing() error {
 func (c *Container) initWhatsApp(ctx context.Context) error {
 	c.logger.Info("Initializing WhatsApp components")
 
+	// Open the session store, importing SQLite stores when switching to Postgres
+	store, err := sessionstore.New(ctx, sessionstore.Config{
+		Backend:       c.Config.WhatsApp.StoreBackend,
+		StoresDir:     c.Config.WhatsApp.StoresDir,
+		DSN:           c.Config.WhatsApp.StoreDSN,
+		MigrateSQLite: c.Config.WhatsApp.StoreMigrateSQLite,
+		LogLevel:      "ERROR",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open session store: %w", err)
+	}
+	c.SessionStore = store
+
 	// Create event handler with message registry
 	c.WhatsAppEventHandler = whatsapp.NewEventHandler(c.MessageRegistry)
 
-	// Create WhatsApp manager
-	c.WhatsAppManager = whatsapp.NewManager(c.WhatsAppEventHandler)
-
-	// Load existing devices
-	if err := c.WhatsAppManager.LoadExistingDevices(ctx); err != nil {
-		c.logger.Warn("Failed to load existing devices: %v", err)
-		// Not a fatal error, continue
+	// Create WhatsApp manager; clients are created from the device registry in initDevices
+	manager := whatsapp.NewManager(c.WhatsAppEventHandler, c.SessionStore, c.DeviceRepository)
+	c.WhatsAppManager = manager
+
+	// Device traffic and health, including how long each message processor takes
+	c.Metrics = manager.Metrics()
+	c.MessageRegistry.SetObserver(manager.Metrics())
+
+	// With leases, the manager only creates clients for devices this replica holds
+	if c.LeaseRepository != nil {
+		c.LeaseKeeper = whatsapp.NewLeaseKeeper(c.LeaseRepository, c.DeviceRepository, manager, whatsapp.LeaseConfig{
+			ReplicaID: c.Config.WhatsApp.ReplicaID,
+			Address:   c.Config.WhatsApp.ReplicaAddress,
+			TTL:       c.Config.WhatsApp.LeaseTTL,
+		})
 	}
 
 	// Create WhatsApp service
 	c.WhatsAppService = whatsapp.NewService(c.WhatsAppManager)
 
-	c.logger.WithField("devices", c.WhatsAppManager.GetClientCount()).Success("WhatsApp components initialized")
+	c.logger.Success("WhatsApp components initialized")
 	return nil
 }
 
@@ -189,14 +317,41 @@ func (c *Container) initUseCases() error {
 	c.logger.Info("Initializing use cases")
 
 	// Device use cases
-	c.CreateDeviceUC = device.NewCreateDeviceUseCase(c.DeviceRepository)
+	c.CreateDeviceUC = device.NewCreateDeviceUseCase(c.DeviceRepository, c.WhatsAppManager)
 	c.GetDeviceUC = device.NewGetDeviceUseCase(c.DeviceRepository)
 	c.ListDevicesUC = device.NewListDevicesUseCase(c.DeviceRepository)
-	c.UpdateDeviceUC = device.NewUpdateDeviceUseCase(c.DeviceRepository)
+	c.UpdateDeviceUC = device.NewUpdateDeviceUseCase(c.DeviceRepository, c.WhatsAppManager)
 	c.DeleteDeviceUC = device.NewDeleteDeviceUseCase(c.DeviceRepository, c.WhatsAppManager)
+	c.SyncDeviceStateUC = device.NewSyncDeviceStateUseCase(c.DeviceRepository)
+	c.ReconcileDevicesUC = device.NewReconcileDevicesUseCase(c.DeviceRepository, c.WhatsAppManager)
+	c.AuthorizeDeviceUC = device.NewAuthorizeDeviceUseCase(c.DeviceRepository)
+	c.BackupDeviceUC = device.NewBackupDeviceUseCase(c.DeviceRepository, c.WhatsAppManager, c.SessionStore)
+	c.RestoreDeviceUC = device.NewRestoreDeviceUseCase(c.DeviceRepository, c.WhatsAppManager, c.SessionStore)
+
+	// WhatsApp session use cases
+	c.RecordSessionUC = waUsecase.NewRecordSessionUseCase(c.SessionRepository, c.WhatsAppManager, c.SessionStore.Location)
+	c.SessionStatusUC = waUsecase.NewSessionStatusUseCase(c.SessionRepository, c.WhatsAppManager)
+	c.QRPairingUC = waUsecase.NewQRPairingUseCase(c.WhatsAppManager)
+
+	// WhatsApp profile use cases
+	c.GetProfileUC = waUsecase.NewGetProfileUseCase(c.WhatsAppManager)
+	c.UpdateProfileUC = waUsecase.NewUpdateProfileUseCase(c.WhatsAppManager)
+	c.SetProfilePhotoUC = waUsecase.NewSetProfilePhotoUseCase(c.WhatsAppManager)
+	c.ProfilePictureUC = waUsecase.NewProfilePictureUseCase(c.WhatsAppManager,
+		filepath.Join(c.Config.WhatsApp.UploadsDir, "profile_pictures"), c.Config.WhatsApp.ProfilePictureTTL)
+
+	// WhatsApp presence use cases
+	c.FollowPresenceUC = waUsecase.NewFollowPresenceUseCase(c.DeviceRepository, c.WhatsAppManager)
+	c.GetPresenceUC = waUsecase.NewGetPresenceUseCase(c.WhatsAppManager)
+
+	// WhatsApp contact use cases
+	c.SyncContactsUC = waUsecase.NewSyncContactsUseCase(c.ContactRepository, c.WhatsAppManager)
+	c.ContactDirectoryUC = waUsecase.NewContactDirectoryUseCase(c.ContactRepository)
+	c.CheckNumbersUC = waUsecase.NewCheckNumbersUseCase(c.WhatsAppManager)
 
 	// Message use cases
 	c.ProcessMessageUC = message.NewProcessMessageUseCase(c.MessageRegistry)
+	c.ImportChatUC = message.NewImportChatUseCase(c.MessageRegistry)
 
 	// API Key use cases
 	c.GenerateAPIKeyUC = apikey.NewGenerateKeyUseCase(c.APIKeyRepository, c.logger)
@@ -209,17 +364,147 @@ func (c *Container) initUseCases() error {
 	return nil
 }
 
+// initDevices keeps device records in step with their WhatsApp sessions and
+// loads the clients of registered devices
+func (c *Container) initDevices(ctx context.Context) error {
+	c.logger.Info("Initializing devices")
+
+	// Connect and logout events update the device records
+	c.SyncDeviceStateUC.Start(context.Background())
+	c.WhatsAppEventHandler.RegisterDeviceEventHandler(c.SyncDeviceStateUC.Handle)
+
+	// Connection state transitions update the sessions and their history
+	c.RecordSessionUC.Start(context.Background())
+	c.WhatsAppManager.RegisterTransitionHandler(c.RecordSessionUC.Handle)
+
+	// Contact stores and contact changes update the contact directories
+	c.SyncContactsUC.Start(context.Background())
+	c.WhatsAppEventHandler.RegisterConnectionHandler(c.SyncContactsUC.HandleConnection)
+	c.WhatsAppEventHandler.RegisterContactSyncHandler(c.SyncContactsUC.HandleSynced)
+	c.WhatsAppEventHandler.RegisterContactHandler(c.SyncContactsUC.HandleUpdate)
+
+	// Renew held leases and take over the devices of replicas that went away
+	if c.LeaseKeeper != nil {
+		defer c.LeaseKeeper.Start()
+	}
+
+	report, err := c.ReconcileDevicesUC.Execute(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to reconcile devices: %v", err)
+		// Not a fatal error, continue
+		return nil
+	}
+
+	if len(report.OrphanedStores) > 0 {
+		c.logger.WithField("stores", report.OrphanedStores).Warn("Session stores without a device record were not loaded")
+	}
+	if len(report.OrphanedRecords) > 0 {
+		c.logger.WithField("devices", report.OrphanedRecords).Warn("Devices lost their session store and must be paired again")
+	}
+	if len(report.HeldElsewhere) > 0 {
+		c.logger.WithField("devices", len(report.HeldElsewhere)).Info("Devices are running on other replicas")
+	}
+
+	c.logger.WithField("devices", len(report.Loaded)).Success("Devices initialized")
+	return nil
+}
+
+// initSchedulers initializes and starts background schedulers
+func (c *Container) initSchedulers() error {
+	c.logger.Info("Initializing schedulers")
+
+	// Quick Response digests
+	c.DigestService = quickresponse.NewDigestService(c.QRRepository, c.OfficerRepository, c.WhatsAppService)
+	c.DigestScheduler = quickresponse.NewDigestScheduler(c.DigestScheduleRepository, c.DigestService, time.Minute)
+	c.DigestScheduler.Start(context.Background())
+
+	// Missing-report reminders
+	c.ReminderService = quickresponse.NewReminderService(c.QRRepository, c.OfficerRepository, c.ReminderRepository, c.WhatsAppService)
+	c.ReminderJob = quickresponse.NewReminderJob(c.ReminderService, time.Minute)
+	c.ReminderJob.Start(context.Background())
+
+	// Broadcast campaigns, continuing those running before a restart
+	c.CampaignRunner = broadcast.NewCampaignRunner(c.CampaignRepository, c.WhatsAppService, time.Minute)
+	c.CampaignService = broadcast.NewCampaignService(c.CampaignRepository, c.SegmentRepository, c.ContactRepository, c.WhatsAppService, c.CampaignRunner)
+	c.CampaignRunner.Start(context.Background())
+
+	// Scheduled messages, sending those that fell due during a restart
+	c.ScheduleService = schedule.NewScheduleService(c.ScheduleRepository, c.Config.WhatsApp.ScheduleTimezone)
+	c.ScheduleRunner = schedule.NewScheduleRunner(c.ScheduleRepository, c.WhatsAppService, 30*time.Second)
+	c.ScheduleRunner.Start(context.Background())
+
+	c.logger.Success("Schedulers initialized")
+	return nil
+}
+
+// EndStreams ends event streams and QR long-polls. They last until the client
+// leaves, so the HTTP server calls this when it shuts down rather than wait.
+func (c *Container) EndStreams() {
+	c.endStreams()
+}
+
 // Shutdown performs graceful shutdown of all components
 func (c *Container) Shutdown(ctx context.Context) error {
 	c.logger.Info("Shutting down application")
+	c.endStreams()
 
-	// Disconnect all WhatsApp clients
+	// Stop schedulers before disconnecting the clients they post through
+	if c.DigestScheduler != nil {
+		c.DigestScheduler.Stop()
+	}
+	if c.ReminderJob != nil {
+		c.ReminderJob.Stop()
+	}
+	if c.CampaignRunner != nil {
+		c.CampaignRunner.Stop()
+	}
+	if c.ScheduleRunner != nil {
+		c.ScheduleRunner.Stop()
+	}
+
+	// Stop taking over devices while the clients go down
+	if c.LeaseKeeper != nil {
+		c.LeaseKeeper.Stop()
+	}
+
+	// Finish processing received messages and sends in progress, then
+	// disconnect all WhatsApp clients
 	if c.WhatsAppManager != nil {
+		if err := c.WhatsAppManager.Drain(ctx); err != nil {
+			c.logger.Warn("Shutting down with pending work: %v", err)
+		}
 		if err := c.WhatsAppManager.DisconnectAll(ctx); err != nil {
 			c.logger.Warn("Error disconnecting WhatsApp clients: %v", err)
 		}
 	}
 
+	// Hand the devices to the other replicas once their sessions are closed here
+	if c.LeaseKeeper != nil {
+		c.LeaseKeeper.ReleaseAll()
+	}
+
+	// Persist the transitions recorded while disconnecting
+	if c.RecordSessionUC != nil {
+		c.RecordSessionUC.Stop()
+	}
+
+	// Persist the contact changes received before disconnecting
+	if c.SyncContactsUC != nil {
+		c.SyncContactsUC.Stop()
+	}
+
+	// Apply the device events received before disconnecting
+	if c.SyncDeviceStateUC != nil {
+		c.SyncDeviceStateUC.Stop()
+	}
+
+	// Close the session store once no client uses it
+	if c.SessionStore != nil {
+		if err := c.SessionStore.Close(); err != nil {
+			c.logger.Warn("Error closing session store: %v", err)
+		}
+	}
+
 	// Disconnect MongoDB
 	if c.MongoDB != nil {
 		if err := c.MongoDB.Client().Disconnect(ctx); err != nil {
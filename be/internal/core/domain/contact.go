@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Contact is an entry of a device's contact directory: the names WhatsApp
+// knows a person by, with the tags and notes kept by the users of the device
+type Contact struct {
+	ID           string
+	DeviceName   string
+	JID          string
+	Phone        string // International number without the plus; empty for contacts known only by LID
+	FullName     string // Name in the device owner's address book
+	FirstName    string
+	PushName     string // Name the contact set for themselves
+	BusinessName string // Verified name of a business account
+
+	Tags  []string
+	Notes string
+
+	SyncedAt  time.Time // Last change from WhatsApp
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DisplayName returns the name to show for the contact: the address book
+// name when saved, then the name they chose, then the phone number
+func (c *Contact) DisplayName() string {
+	switch {
+	case c.FullName != "":
+		return c.FullName
+	case c.FirstName != "":
+		return c.FirstName
+	case c.BusinessName != "":
+		return c.BusinessName
+	case c.PushName != "":
+		return c.PushName
+	case c.Phone != "":
+		return "+" + c.Phone
+	}
+	return c.JID
+}
+
+// ContactFilter represents filters for querying a device's contacts
+type ContactFilter struct {
+	DeviceName string
+	Search     string // Case-insensitive part of a name or the phone number
+	Tag        string
+}
+
+// ContactAnnotations holds the tags and notes to set on a contact; nil fields are left as they are
+type ContactAnnotations struct {
+	Tags  *[]string
+	Notes *string
+}
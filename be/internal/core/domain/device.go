@@ -10,8 +10,30 @@ type Device struct {
 	Description string
 	Status      DeviceStatus
 	JID         string // WhatsApp JID when connected
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+
+	Organization string // Organisation the device is shared with; set from the creator's or by an admin
+
+	PresencePolicy        PresencePolicy // Empty means PresenceOnSend
+	PresenceSubscriptions []string       // JIDs whose online status the device follows
+
+	ProxyURL string // HTTP(S) or SOCKS5 proxy for the websocket and media; empty connects directly
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Caller identifies who is acting on a device
+type Caller struct {
+	Username     string
+	Organization string // Empty when the caller belongs to no organisation
+}
+
+// OwnedBy reports whether the device belongs to the caller or is shared with the caller's organisation
+func (d *Device) OwnedBy(caller Caller) bool {
+	if caller.Username != "" && d.Owner == caller.Username {
+		return true
+	}
+	return d.Organization != "" && d.Organization == caller.Organization
 }
 
 // DeviceStatus represents the status of a device
@@ -21,24 +43,98 @@ const (
 	DeviceStatusActive   DeviceStatus = "active"
 	DeviceStatusInactive DeviceStatus = "inactive"
 	DeviceStatusDeleted  DeviceStatus = "deleted"
+
+	// DeviceStatusLoggedOut marks a device that was unlinked from the phone and must be paired again
+	DeviceStatusLoggedOut DeviceStatus = "logged_out"
+
+	// DeviceStatusTransferred marks a device whose session was handed to another server; it is not loaded here
+	DeviceStatusTransferred DeviceStatus = "transferred"
+)
+
+// PresencePolicy decides when a device shows as online to its contacts
+type PresencePolicy string
+
+const (
+	// PresenceOnSend marks the device available before each message it sends
+	PresenceOnSend PresencePolicy = "on_send"
+
+	// PresenceOnline marks the device available whenever it connects
+	PresenceOnline PresencePolicy = "online"
+
+	// PresenceOffline keeps the device unavailable, so that the phone it is linked to
+	// keeps receiving notifications. WhatsApp sends no contact presence to it.
+	PresenceOffline PresencePolicy = "offline"
 )
 
+// IsValid reports whether the policy is known; the empty policy is valid
+func (p PresencePolicy) IsValid() bool {
+	switch p {
+	case "", PresenceOnSend, PresenceOnline, PresenceOffline:
+		return true
+	}
+	return false
+}
+
+// SessionArchiveVersion is the version of the session archive format
+const SessionArchiveVersion = 1
+
+// SessionArchive is the content of an encrypted device session backup
+type SessionArchive struct {
+	Version    int       `json:"version"`
+	DeviceName string    `json:"device_name"`
+	JID        string    `json:"jid"`
+	Transfer   bool      `json:"transfer"` // The source stopped using the session when the archive was made
+	CreatedAt  time.Time `json:"created_at"`
+	Store      []byte    `json:"store"` // SQLite image of the whatsmeow session store
+}
+
 // CreateDeviceRequest represents a request to create a device
 type CreateDeviceRequest struct {
-	Name        string
-	Owner       string
-	Description string
+	Name         string
+	Owner        string
+	Organization string // The creator's organisation, if any
+	Description  string
+	ProxyURL     string
 }
 
 // UpdateDeviceRequest represents a request to update a device
 type UpdateDeviceRequest struct {
-	Name        *string
-	Description *string
-	Status      *DeviceStatus
+	Name           *string
+	Description    *string
+	Status         *DeviceStatus
+	PresencePolicy *PresencePolicy
+	ProxyURL       *string // Empty removes the proxy
 }
 
 // DeviceFilter represents filters for querying devices
 type DeviceFilter struct {
-	Owner  string
-	Status DeviceStatus
+	Owner        string
+	Status       DeviceStatus
+	AccessibleTo *Caller // When set, only devices the caller owns or shares through their organisation
+}
+
+// DeviceReconciliation reports how the device registry and the session stores on disk were matched up
+type DeviceReconciliation struct {
+	Loaded          []string // Devices whose client was created from their store
+	Failed          []string // Devices whose client could not be created
+	HeldElsewhere   []string // Devices whose lease another replica holds; loaded there
+	OrphanedStores  []string // Session stores without a device record; left on disk, not loaded
+	OrphanedRecords []string // Paired device records without a session store; marked logged out
+}
+
+// DeviceLease records which replica runs the client of a device. A replica
+// renews its leases before they expire; a lease that expired may be taken over
+// by any replica.
+type DeviceLease struct {
+	DeviceName string
+	Holder     string // Replica ID
+	Address    string // Base URL other replicas forward the device's requests to; may be empty
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// IsLive reports whether the lease has not expired at now
+func (l *DeviceLease) IsLive(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
 }
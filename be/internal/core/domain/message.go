@@ -4,16 +4,18 @@ import "time"
 
 // IncomingMessage represents a received WhatsApp message
 type IncomingMessage struct {
-	ID           string
-	DeviceName   string
-	From         string
-	FromName     string
-	Content      string
-	Timestamp    time.Time
-	IsGroup      bool
-	IsProcessed  bool
-	ProcessedAt  *time.Time
-	ProcessError string
+	ID              string
+	DeviceName      string
+	From            string
+	FromName        string
+	Content         string
+	QuotedMessageID string // ID of the message this one replies to, if any
+	Timestamp       time.Time
+	IsGroup         bool
+	Imported        bool // Backfilled from a chat export; Timestamp is the original send time
+	IsProcessed     bool
+	ProcessedAt     *time.Time
+	ProcessError    string
 }
 
 // MessageProcessor defines the contract for processing incoming messages
@@ -31,6 +33,19 @@ type MessageProcessor interface {
 	Priority() int
 }
 
+// MessagePreviewer is implemented by processors that can show what they would
+// store for a message without storing it
+type MessagePreviewer interface {
+	// Preview returns the record the processor would save, or nil if it would skip the message
+	Preview(message IncomingMessage) (interface{}, error)
+}
+
+// MessagePreview is the result of previewing a message with a single processor
+type MessagePreview struct {
+	Processor string
+	Result    interface{}
+}
+
 // MessageProcessorRegistry manages message processors
 type MessageProcessorRegistry interface {
 	// Register registers a message processor
@@ -39,6 +54,18 @@ type MessageProcessorRegistry interface {
 	// Process processes a message through all applicable processors
 	Process(message IncomingMessage) error
 
+	// Preview runs the message through all applicable processors that support previews, without saving
+	Preview(message IncomingMessage) ([]MessagePreview, error)
+
 	// GetProcessors returns all registered processors
 	GetProcessors() []MessageProcessor
+
+	// SetObserver sets the observer told about every processor run
+	SetObserver(observer MessageProcessingObserver)
+}
+
+// MessageProcessingObserver is told how long each processor took for a
+// message and whether it failed
+type MessageProcessingObserver interface {
+	ObserveProcessing(deviceName, processor string, duration time.Duration, err error)
 }
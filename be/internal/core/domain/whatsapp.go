@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -13,8 +14,38 @@ const (
 	StatusConnecting   ConnectionStatus = "connecting"
 	StatusConnected    ConnectionStatus = "connected"
 	StatusFailed       ConnectionStatus = "failed"
+	StatusReconnecting ConnectionStatus = "reconnecting"
+	StatusLoggedOut    ConnectionStatus = "logged_out" // Unlinked from the phone; the device must be paired again
+	StatusBanned       ConnectionStatus = "banned"     // Temporarily banned by WhatsApp
 )
 
+// ConnectionTransition records a change in a device's connection state
+type ConnectionTransition struct {
+	DeviceName string
+	From       ConnectionStatus
+	To         ConnectionStatus
+	Reason     string
+	Attempt    int // Reconnect attempt, 0 outside of reconnects
+	Timestamp  time.Time
+}
+
+// UptimeReport summarizes how long a device was connected over a period
+type UptimeReport struct {
+	DeviceName  string
+	From        time.Time
+	To          time.Time
+	Connected   time.Duration
+	Ratio       float64 // Share of the period spent connected, from 0 to 1
+	Transitions []ConnectionTransition
+}
+
+// SessionStatus combines a device's persisted session with its live connection state
+type SessionStatus struct {
+	Session *WhatsAppSession // Nil until the device's first connection state change
+	Live    ConnectionInfo
+	Uptime  []UptimeReport // Last 24 hours and last 7 days
+}
+
 // ReceiverType represents the type of message receiver
 type ReceiverType string
 
@@ -42,6 +73,7 @@ type WhatsAppSession struct {
 	QRCode           string
 	LastConnected    *time.Time
 	LastDisconnected *time.Time
+	HeartbeatAt      *time.Time // Last time the device was seen connected
 	StoreDBPath      string
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
@@ -51,11 +83,35 @@ type WhatsAppSession struct {
 type WhatsAppContact struct {
 	JID          string
 	Name         string
+	FullName     string // Name in the device owner's address book
+	FirstName    string
+	PushName     string // Name the contact set for themselves
 	BusinessName string
 	IsGroup      bool
 	IsBroadcast  bool
 }
 
+// ContactUpdate is a change to the names of a contact reported by WhatsApp;
+// nil fields are unchanged
+type ContactUpdate struct {
+	JID          string
+	FullName     *string
+	FirstName    *string
+	PushName     *string
+	BusinessName *string
+	Timestamp    time.Time
+}
+
+// NumberCheck reports whether a phone number is on WhatsApp
+type NumberCheck struct {
+	Input        string // Number as given
+	Phone        string // International digits; empty when the number is invalid
+	JID          string // Canonical JID when registered
+	Registered   bool
+	BusinessName string // Verified name of a business account
+	Error        string // Why the number could not be checked
+}
+
 // WhatsAppGroup represents a WhatsApp group
 type WhatsAppGroup struct {
 	JID           string
@@ -63,24 +119,58 @@ type WhatsAppGroup struct {
 	Topic         string
 	OwnerJID      string
 	Participants  []string
+	Admins        []string // Participants that are admins or the owner
+	IsAdmin       bool     // Whether the device is an admin of the group
 	IsAnnounce    bool
 	IsLocked      bool
 	IsEphemeral   bool
 	CreatedAt     time.Time
 }
 
+// GroupFilter narrows a listing of groups
+type GroupFilter struct {
+	Search    string // Case-insensitive part of the group name
+	AdminOnly bool   // Only groups the device is an admin of
+}
+
+// GroupParticipantAction is a change to the members of a group
+type GroupParticipantAction string
+
+const (
+	GroupParticipantAdd     GroupParticipantAction = "add"
+	GroupParticipantRemove  GroupParticipantAction = "remove"
+	GroupParticipantPromote GroupParticipantAction = "promote"
+	GroupParticipantDemote  GroupParticipantAction = "demote"
+)
+
+// GroupParticipantResult is the outcome of a participant change for one person
+type GroupParticipantResult struct {
+	JID       string
+	Success   bool
+	ErrorCode int // WhatsApp's code when the change failed, e.g. 403 when privacy settings only allow an invite
+}
+
+// GroupUpdate holds the group settings to change; nil fields are left as they are
+type GroupUpdate struct {
+	Name        *string
+	Description *string
+	Announce    *bool // Only admins may send messages
+	Locked      *bool // Only admins may change the group info
+}
+
 // WhatsAppMessage represents a message to be sent or received
 type WhatsAppMessage struct {
-	ID           string
-	From         string
-	To           string
-	Type         MessageType
-	Content      string
-	MediaURL     string
-	Caption      string
-	Timestamp    time.Time
-	IsFromMe     bool
-	ReceiverType ReceiverType
+	ID              string
+	From            string
+	To              string
+	Type            MessageType
+	Content         string
+	MediaURL        string
+	Caption         string
+	QuotedMessageID string
+	Timestamp       time.Time
+	IsFromMe        bool
+	ReceiverType    ReceiverType
 }
 
 // SendMessageParams represents parameters for sending a message
@@ -91,6 +181,7 @@ type SendMessageParams struct {
 	ReceiverType ReceiverType
 	MessageType  MessageType
 	MediaPath    string
+	FileData     []byte // File content; takes precedence over MediaPath
 	FileName     string
 	Caption      string
 	Typing       bool
@@ -104,6 +195,44 @@ type QRCodeResponse struct {
 	Timeout    int // seconds
 }
 
+// PairingStatus is the state of a QR pairing session
+type PairingStatus string
+
+const (
+	PairingWaiting PairingStatus = "waiting" // A code is shown and waits to be scanned
+	PairingSuccess PairingStatus = "success"
+	PairingTimeout PairingStatus = "timeout" // Every code expired without being scanned
+	PairingFailed  PairingStatus = "failed"
+)
+
+// PairingSession follows the QR codes of one pairing attempt. WhatsApp issues
+// a handful of codes that replace each other until one is scanned or all have
+// expired.
+type PairingSession struct {
+	DeviceName string
+	Status     PairingStatus
+	Code       string    // Current QR code; empty once the session has ended
+	Sequence   int       // Number of codes issued so far; increases with every refresh
+	ExpiresAt  time.Time // When the current code is replaced by the next
+	StartedAt  time.Time
+	EndedAt    *time.Time
+	JID        string // Paired JID on success
+	Error      string // Why the session failed
+}
+
+// IsActive reports whether the session still waits for a scan
+func (s *PairingSession) IsActive() bool {
+	return s.Status == PairingWaiting
+}
+
+// PairingCodeResponse represents a phone-number link code for device pairing
+type PairingCodeResponse struct {
+	DeviceName string
+	Phone      string
+	Code       string // Link code shown as XXXX-XXXX, entered on the phone
+	ExpiresAt  time.Time
+}
+
 // ConnectionInfo represents connection information
 type ConnectionInfo struct {
 	DeviceName   string
@@ -113,6 +242,33 @@ type ConnectionInfo struct {
 	LastPing     *time.Time
 }
 
+// DeviceProfile represents how a device appears to the people it messages
+type DeviceProfile struct {
+	DeviceName string
+	JID        string
+	PushName   string // Display name shown to contacts that haven't saved the number
+	About      string
+	PictureID  string // Empty when no profile picture is set
+}
+
+// ProfilePicture represents the profile picture of a user or group
+type ProfilePicture struct {
+	JID       string
+	ID        string
+	URL       string
+	Type      string // "image" for full size, "preview" for a thumbnail
+	LocalPath string // Cached copy of the image, set by the profile picture use case
+	FetchedAt time.Time
+}
+
+// ContactPresence represents the last known online status of a contact
+type ContactPresence struct {
+	JID       string
+	Available bool
+	LastSeen  *time.Time // Nil when the contact hides it or no update arrived yet
+	UpdatedAt time.Time  // Zero until the first update arrives
+}
+
 // DeviceInfo represents device information from WhatsApp
 type DeviceInfo struct {
 	Platform    string
@@ -129,6 +285,13 @@ type WhatsAppClientInterface interface {
 	IsConnected() bool
 	GetConnectionStatus() ConnectionStatus
 	GetQRCode(ctx context.Context) (*QRCodeResponse, error)
+	StartPairing(ctx context.Context) (*PairingSession, error)           // Starts a QR pairing session, or joins the active one
+	GetPairing() (*PairingSession, bool)                                 // Latest pairing session, active or ended
+	WaitPairing(ctx context.Context, after int) (*PairingSession, error) // Waits for a code newer than after or the end of the session
+	PairPhone(ctx context.Context, phone string) (string, error)
+
+	// Network
+	SetProxy(proxyURL string) error // Applies to media at once and to the websocket from the next connect
 
 	// Device Information
 	GetJID() string
@@ -142,9 +305,34 @@ type WhatsAppClientInterface interface {
 	// Contacts & Groups
 	GetContacts(ctx context.Context) ([]WhatsAppContact, error)
 	GetGroups(ctx context.Context) ([]WhatsAppGroup, error)
+	IsOnWhatsApp(ctx context.Context, phones []string) ([]NumberCheck, error) // Phones in international digits; results in the same order
+
+	// Group Administration
+	CreateGroup(ctx context.Context, name string, participants []string) (*WhatsAppGroup, error)
+	GetGroup(ctx context.Context, groupJID string) (*WhatsAppGroup, error)
+	UpdateGroupParticipants(ctx context.Context, groupJID string, participants []string, action GroupParticipantAction) ([]GroupParticipantResult, error)
+	SetGroupName(ctx context.Context, groupJID, name string) error
+	SetGroupDescription(ctx context.Context, groupJID, description string) error
+	SetGroupPhoto(ctx context.Context, groupJID string, jpeg []byte) (string, error)
+	SetGroupAnnounce(ctx context.Context, groupJID string, announce bool) error // Only admins may send messages
+	SetGroupLocked(ctx context.Context, groupJID string, locked bool) error     // Only admins may change the group info
+	GetGroupInviteLink(ctx context.Context, groupJID string, reset bool) (string, error)
+	JoinGroupWithLink(ctx context.Context, link string) (string, error) // Returns the JID of the joined group
+	LeaveGroup(ctx context.Context, groupJID string) error
+
+	// Profile
+	GetProfile(ctx context.Context) (*DeviceProfile, error)
+	SetPushName(ctx context.Context, name string) error
+	SetAbout(ctx context.Context, about string) error
+	SetProfilePicture(ctx context.Context, jpeg []byte) (string, error)
+	GetProfilePicture(ctx context.Context, jid string, preview bool, existingID string) (*ProfilePicture, error) // Nil when the picture is still existingID
 
 	// Status
 	SetPresence(ctx context.Context, available bool) error
+	SetPresencePolicy(policy PresencePolicy)
+	SubscribePresence(ctx context.Context, jid string) error
+	UnsubscribePresence(jid string)
+	GetContactPresence(jid string) (*ContactPresence, bool) // False when the device does not follow the contact
 	SendTyping(ctx context.Context, to string, typing bool) error
 }
 
@@ -154,11 +342,17 @@ type WhatsAppManagerInterface interface {
 	CreateClient(ctx context.Context, deviceName string) (WhatsAppClientInterface, error)
 	GetClient(deviceName string) (WhatsAppClientInterface, bool)
 	RemoveClient(ctx context.Context, deviceName string) error
+	UnloadClient(ctx context.Context, deviceName string) error // Disconnects and drops a client, keeping its session store
+	RenameClient(ctx context.Context, from, to string) error   // Unloads a client and moves its session store to a new name
 	ListClients() []string
+	ListStores() ([]string, error)
 
 	// Bulk Operations
+	Drain(ctx context.Context) error // Waits for pending message processing and sends
 	DisconnectAll(ctx context.Context) error
 	GetAllConnectionInfo() []ConnectionInfo
+	GetConnectionHistory(deviceName string) []ConnectionTransition
+	RegisterTransitionHandler(handler func(ConnectionTransition))
 }
 
 // WhatsAppEventHandler defines the contract for handling WhatsApp events
@@ -166,6 +360,80 @@ type WhatsAppEventHandler interface {
 	OnConnected(deviceName, jid string)
 	OnDisconnected(deviceName string, reason string)
 	OnQRCode(deviceName, qrCode string)
+	OnPairSuccess(deviceName, jid string)
+	OnPairError(deviceName string, err error)
+	OnLoggedOut(deviceName string, reason string)
 	OnMessage(deviceName string, message WhatsAppMessage)
+	OnPresence(deviceName string, presence ContactPresence)
+	OnContactUpdated(deviceName string, update ContactUpdate)
+	OnContactsSynced(deviceName string) // The device's contact list was synced in full from the phone
 	OnError(deviceName string, err error)
 }
+
+// DeviceEventType identifies a device lifecycle event
+type DeviceEventType string
+
+const (
+	DeviceEventConnected    DeviceEventType = "connected"
+	DeviceEventDisconnected DeviceEventType = "disconnected"
+	DeviceEventQRCode       DeviceEventType = "qr_code"
+	DeviceEventPairSuccess  DeviceEventType = "pair_success"
+	DeviceEventPairError    DeviceEventType = "pair_error"
+	DeviceEventLoggedOut    DeviceEventType = "logged_out"
+	DeviceEventPresence     DeviceEventType = "presence"
+	DeviceEventError        DeviceEventType = "error"
+)
+
+// DeviceEvent is a lifecycle event published on a device's event stream
+type DeviceEvent struct {
+	DeviceName string
+	Type       DeviceEventType
+	JID        string     // Set for connected, pair_success and presence events
+	Data       string     // QR code, disconnect or logout reason, error message, or "available"/"unavailable"
+	LastSeen   *time.Time // Set for presence events when the contact shares it
+	Timestamp  time.Time
+}
+
+// DeviceEventStream lets callers follow the lifecycle events of a device
+type DeviceEventStream interface {
+	// Subscribe returns the device's events and a function that ends the subscription
+	Subscribe(deviceName string) (<-chan DeviceEvent, func())
+}
+
+// DeviceMetrics is the traffic and health of a loaded device since this
+// replica started
+type DeviceMetrics struct {
+	DeviceName       string
+	Status           ConnectionStatus
+	Connected        bool
+	MessagesSent     map[MessageType]int64
+	MessagesReceived map[MessageType]int64
+	SendFailures     map[string]int64 // By error type
+	Reconnects       int64            // Reconnect attempts by the connection supervisor
+	LastSentAt       *time.Time
+	LastReceivedAt   *time.Time
+	QueueDepth       int // Received messages waiting for a processing slot
+	Processing       int // Received messages being processed
+	Processors       []ProcessorMetrics
+}
+
+// ProcessorMetrics is how a message processor performed for a device
+type ProcessorMetrics struct {
+	Processor      string
+	Processed      int64
+	Failed         int64
+	AverageLatency time.Duration
+	MaxLatency     time.Duration
+}
+
+// DeviceMetricsReader exposes the metrics of the loaded devices
+type DeviceMetricsReader interface {
+	// DeviceMetrics returns the metrics of a loaded device
+	DeviceMetrics(deviceName string) (*DeviceMetrics, bool)
+
+	// AllDeviceMetrics returns the metrics of every loaded device
+	AllDeviceMetrics() []DeviceMetrics
+
+	// WritePrometheus writes all metrics in the Prometheus text exposition format
+	WritePrometheus(w io.Writer) error
+}
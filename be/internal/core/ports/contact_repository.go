@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+)
+
+// ContactRepository defines the contract for the contact directories of devices
+type ContactRepository interface {
+	// SaveSynced creates or updates contacts with the phone and names from
+	// WhatsApp, keeping their tags and notes
+	SaveSynced(ctx context.Context, contacts []*domain.Contact) error
+
+	// FindByJID retrieves a contact of a device
+	FindByJID(ctx context.Context, deviceName, jid string) (*domain.Contact, error)
+
+	// FindAll retrieves the contacts matching the filter, sorted by display name
+	FindAll(ctx context.Context, filter *domain.ContactFilter, skip, limit int) ([]*domain.Contact, error)
+
+	// Count counts the contacts matching the filter
+	Count(ctx context.Context, filter *domain.ContactFilter) (int64, error)
+
+	// Annotate sets the tags and/or notes of a contact and returns it
+	Annotate(ctx context.Context, deviceName, jid string, annotations domain.ContactAnnotations) (*domain.Contact, error)
+
+	// Tags lists the distinct tags used in a device's contacts
+	Tags(ctx context.Context, deviceName string) ([]string, error)
+}
@@ -34,4 +34,8 @@ type DeviceRepository interface {
 
 	// UpdateStatus updates the status of a device
 	UpdateStatus(ctx context.Context, id string, status domain.DeviceStatus) error
+
+	// RenameReferences moves the records kept under a device's name, such as
+	// its contacts, schedules and campaigns, to its new name
+	RenameReferences(ctx context.Context, from, to string) error
 }
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+)
+
+// LeaseRepository defines the contract for device lease persistence. Replicas
+// sharing one registry use it to agree on which of them runs each device.
+type LeaseRepository interface {
+	// Acquire claims the lease of a device for holder until ttl from now. It
+	// succeeds when the lease is free, expired or already held by holder, and
+	// otherwise returns the live lease of the other holder with acquired false.
+	Acquire(ctx context.Context, deviceName, holder, address string, ttl time.Duration) (lease *domain.DeviceLease, acquired bool, err error)
+
+	// Renew extends a lease held by holder; it returns a not found error if the lease was lost
+	Renew(ctx context.Context, deviceName, holder string, ttl time.Duration) error
+
+	// Release gives up a lease held by holder; releasing a lease held by another replica does nothing
+	Release(ctx context.Context, deviceName, holder string) error
+
+	// Find retrieves the lease of a device, live or expired
+	Find(ctx context.Context, deviceName string) (*domain.DeviceLease, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// SessionStore defines the contract for moving a device's WhatsApp session in and out of storage
+type SessionStore interface {
+	// Export returns a SQLite image holding only the device's paired session
+	Export(ctx context.Context, deviceName string) ([]byte, error)
+
+	// Import loads an image produced by Export as the session of a device that is not loaded
+	Import(ctx context.Context, deviceName string, image []byte) error
+}
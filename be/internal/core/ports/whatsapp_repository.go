@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 )
@@ -25,6 +26,22 @@ type WhatsAppSessionRepository interface {
 
 	// UpdateJID updates the JID of a session
 	UpdateJID(ctx context.Context, deviceName string, jid string) error
+
+	// RecordTransition appends a connection state transition to the device's history
+	RecordTransition(ctx context.Context, transition domain.ConnectionTransition) error
+
+	// FindTransitions retrieves the transitions of a device within [from, to), oldest first
+	FindTransitions(ctx context.Context, deviceName string, from, to time.Time) ([]domain.ConnectionTransition, error)
+
+	// FindLastTransitionBefore retrieves the latest transition of a device before t, or nil if there is none
+	FindLastTransitionBefore(ctx context.Context, deviceName string, t time.Time) (*domain.ConnectionTransition, error)
+
+	// Heartbeat records that the devices, persisted as connected, were still connected at t
+	Heartbeat(ctx context.Context, deviceNames []string, t time.Time) error
+
+	// FindStaleConnected retrieves the sessions persisted as connected whose
+	// last heartbeat is before t, e.g. left behind by a process that crashed
+	FindStaleConnected(ctx context.Context, before time.Time) ([]*domain.WhatsAppSession, error)
 }
 
 // WhatsAppMessageRepository defines the contract for message persistence
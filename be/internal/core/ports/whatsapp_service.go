@@ -12,9 +12,11 @@ type WhatsAppService interface {
 	ConnectDevice(ctx context.Context, deviceName string) error
 	DisconnectDevice(ctx context.Context, deviceName string) error
 	GetQRCode(ctx context.Context, deviceName string) (*domain.QRCodeResponse, error)
+	PairPhone(ctx context.Context, deviceName, phone string) (*domain.PairingCodeResponse, error)
 	IsDeviceConnected(deviceName string) bool
 	GetConnectionInfo(deviceName string) (*domain.ConnectionInfo, error)
 	GetAllConnectionInfo() []domain.ConnectionInfo
+	GetConnectionHistory(deviceName string) []domain.ConnectionTransition
 
 	// Messaging
 	SendMessage(ctx context.Context, params domain.SendMessageParams) error
@@ -23,7 +25,17 @@ type WhatsAppService interface {
 
 	// Contacts & Groups
 	ListContacts(ctx context.Context, deviceName string) ([]domain.WhatsAppContact, error)
-	ListGroups(ctx context.Context, deviceName string) ([]domain.WhatsAppGroup, error)
+	ListGroups(ctx context.Context, deviceName string, filter domain.GroupFilter, skip, limit int) ([]domain.WhatsAppGroup, int, error)
+
+	// Group Administration
+	CreateGroup(ctx context.Context, deviceName, name string, participants []string) (*domain.WhatsAppGroup, error)
+	GetGroup(ctx context.Context, deviceName, groupJID string) (*domain.WhatsAppGroup, error)
+	UpdateGroup(ctx context.Context, deviceName, groupJID string, update domain.GroupUpdate) (*domain.WhatsAppGroup, error)
+	UpdateGroupParticipants(ctx context.Context, deviceName, groupJID string, participants []string, action domain.GroupParticipantAction) ([]domain.GroupParticipantResult, error)
+	SetGroupPhoto(ctx context.Context, deviceName, groupJID string, photo []byte) (string, error)
+	GetGroupInviteLink(ctx context.Context, deviceName, groupJID string, reset bool) (string, error)
+	JoinGroup(ctx context.Context, deviceName, link string) (string, error)
+	LeaveGroup(ctx context.Context, deviceName, groupJID string) error
 
 	// Device Management
 	CreateDevice(ctx context.Context, deviceName string) error
@@ -29,14 +29,14 @@ func NewGenerateKeyUseCase(repo domain.APIKeyRepository, log *logger.Logger) *Ge
 func (uc *GenerateKeyUseCase) Execute(ctx context.Context, owner string, req *domain.CreateAPIKeyRequest) (*domain.APIKey, error) {
 	// Validate owner
 	if owner == "" {
-		return nil, errors.New(errors.ErrTypeValidation, "owner is required")
+		return nil, errors.New(errors.ErrorTypeValidation, "owner is required")
 	}
 
 	// Generate a secure random API key
 	key, err := generateSecureKey(64) // 64 bytes = 128 hex characters
 	if err != nil {
-		uc.logger.Error("Failed to generate API key", err)
-		return nil, errors.Wrap(err, errors.ErrTypeInternal, "failed to generate API key")
+		uc.logger.Error("Failed to generate API key: %v", err)
+		return nil, errors.Wrap(err, errors.ErrorTypeInternal, "failed to generate API key")
 	}
 
 	// Calculate expiration time
@@ -74,11 +74,11 @@ func (uc *GenerateKeyUseCase) Execute(ctx context.Context, owner string, req *do
 		return nil, err
 	}
 
-	uc.logger.Info("API key generated successfully", logger.Fields{
+	uc.logger.WithFields(map[string]interface{}{
 		"id":    apiKey.ID,
 		"name":  apiKey.Name,
 		"owner": owner,
-	})
+	}).Info("API key generated successfully")
 
 	return apiKey, nil
 }
@@ -34,7 +34,7 @@ type ListKeysResponse struct {
 func (uc *ListKeysUseCase) Execute(ctx context.Context, owner string, limit, offset int) (*ListKeysResponse, error) {
 	// Validate owner
 	if owner == "" {
-		return nil, errors.New(errors.ErrTypeValidation, "owner is required")
+		return nil, errors.New(errors.ErrorTypeValidation, "owner is required")
 	}
 
 	// Set default limit
@@ -48,13 +48,13 @@ func (uc *ListKeysUseCase) Execute(ctx context.Context, owner string, limit, off
 		return nil, err
 	}
 
-	uc.logger.Info("Retrieved API keys", logger.Fields{
+	uc.logger.WithFields(map[string]interface{}{
 		"owner":  owner,
 		"count":  len(keys),
 		"total":  total,
 		"limit":  limit,
 		"offset": offset,
-	})
+	}).Info("Retrieved API keys")
 
 	// Mask the API keys in the response (show only last 8 characters)
 	for _, key := range keys {
@@ -27,10 +27,10 @@ func NewRevokeKeyUseCase(repo domain.APIKeyRepository, log *logger.Logger) *Revo
 func (uc *RevokeKeyUseCase) Execute(ctx context.Context, keyID string, owner string) error {
 	// Validate inputs
 	if keyID == "" {
-		return errors.New(errors.ErrTypeValidation, "key ID is required")
+		return errors.New(errors.ErrorTypeValidation, "key ID is required")
 	}
 	if owner == "" {
-		return errors.New(errors.ErrTypeValidation, "owner is required")
+		return errors.New(errors.ErrorTypeValidation, "owner is required")
 	}
 
 	// Retrieve the API key
@@ -41,12 +41,12 @@ func (uc *RevokeKeyUseCase) Execute(ctx context.Context, keyID string, owner str
 
 	// Verify ownership
 	if apiKey.Owner != owner {
-		uc.logger.Warn("Unauthorized attempt to revoke API key", logger.Fields{
-			"key_id": keyID,
-			"owner":  owner,
+		uc.logger.WithFields(map[string]interface{}{
+			"key_id":       keyID,
+			"owner":        owner,
 			"actual_owner": apiKey.Owner,
-		})
-		return errors.New(errors.ErrTypeUnauthorized, "you are not authorized to revoke this API key")
+		}).Warn("Unauthorized attempt to revoke API key")
+		return errors.New(errors.ErrorTypeUnauthorized, "you are not authorized to revoke this API key")
 	}
 
 	// Delete the API key
@@ -54,11 +54,11 @@ func (uc *RevokeKeyUseCase) Execute(ctx context.Context, keyID string, owner str
 		return err
 	}
 
-	uc.logger.Info("API key revoked successfully", logger.Fields{
+	uc.logger.WithFields(map[string]interface{}{
 		"id":    keyID,
 		"name":  apiKey.Name,
 		"owner": owner,
-	})
+	}).Info("API key revoked successfully")
 
 	return nil
 }
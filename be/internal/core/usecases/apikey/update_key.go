@@ -26,10 +26,10 @@ func NewUpdateKeyUseCase(repo domain.APIKeyRepository, log *logger.Logger) *Upda
 func (uc *UpdateKeyUseCase) Execute(ctx context.Context, keyID string, owner string, req *domain.UpdateAPIKeyRequest) (*domain.APIKey, error) {
 	// Validate inputs
 	if keyID == "" {
-		return nil, errors.New(errors.ErrTypeValidation, "key ID is required")
+		return nil, errors.New(errors.ErrorTypeValidation, "key ID is required")
 	}
 	if owner == "" {
-		return nil, errors.New(errors.ErrTypeValidation, "owner is required")
+		return nil, errors.New(errors.ErrorTypeValidation, "owner is required")
 	}
 
 	// Retrieve the existing API key
@@ -40,12 +40,12 @@ func (uc *UpdateKeyUseCase) Execute(ctx context.Context, keyID string, owner str
 
 	// Verify ownership
 	if apiKey.Owner != owner {
-		uc.logger.Warn("Unauthorized attempt to update API key", logger.Fields{
+		uc.logger.WithFields(map[string]interface{}{
 			"key_id":       keyID,
 			"owner":        owner,
 			"actual_owner": apiKey.Owner,
-		})
-		return nil, errors.New(errors.ErrTypeUnauthorized, "you are not authorized to update this API key")
+		}).Warn("Unauthorized attempt to update API key")
+		return nil, errors.New(errors.ErrorTypeUnauthorized, "you are not authorized to update this API key")
 	}
 
 	// Apply updates
@@ -74,10 +74,10 @@ func (uc *UpdateKeyUseCase) Execute(ctx context.Context, keyID string, owner str
 		return nil, err
 	}
 
-	uc.logger.Info("API key updated successfully", logger.Fields{
+	uc.logger.WithFields(map[string]interface{}{
 		"id":    keyID,
 		"owner": owner,
-	})
+	}).Info("API key updated successfully")
 
 	return apiKey, nil
 }
@@ -86,12 +86,12 @@ func (uc *UpdateKeyUseCase) Execute(ctx context.Context, keyID string, owner str
 func (uc *UpdateKeyUseCase) validateStatusTransition(current, new domain.APIKeyStatus) error {
 	// Cannot change from expired status
 	if current == domain.APIKeyStatusExpired {
-		return errors.New(errors.ErrTypeValidation, "cannot modify expired API key")
+		return errors.New(errors.ErrorTypeValidation, "cannot modify expired API key")
 	}
 
 	// Cannot change to expired status manually (only system can do this)
 	if new == domain.APIKeyStatusExpired {
-		return errors.New(errors.ErrTypeValidation, "cannot manually set key to expired status")
+		return errors.New(errors.ErrorTypeValidation, "cannot manually set key to expired status")
 	}
 
 	return nil
@@ -26,7 +26,7 @@ func NewValidateKeyUseCase(repo domain.APIKeyRepository, log *logger.Logger) *Va
 func (uc *ValidateKeyUseCase) Execute(ctx context.Context, key string) (*domain.APIKey, error) {
 	// Validate input
 	if key == "" {
-		return nil, errors.New(errors.ErrTypeValidation, "API key is required")
+		return nil, errors.New(errors.ErrorTypeValidation, "API key is required")
 	}
 
 	// Retrieve API key from repository
@@ -35,23 +35,23 @@ func (uc *ValidateKeyUseCase) Execute(ctx context.Context, key string) (*domain.
 		// Don't expose that the key doesn't exist for security reasons
 		if errors.IsNotFound(err) {
 			uc.logger.Warn("Invalid API key attempt")
-			return nil, errors.New(errors.ErrTypeUnauthorized, "invalid API key")
+			return nil, errors.New(errors.ErrorTypeUnauthorized, "invalid API key")
 		}
 		return nil, err
 	}
 
 	// Check if the key is active
 	if !apiKey.IsActive() {
-		uc.logger.Warn("Attempt to use inactive API key", logger.Fields{
+		uc.logger.WithFields(map[string]interface{}{
 			"key_id": apiKey.ID,
 			"status": apiKey.Status,
-		})
+		}).Warn("Attempt to use inactive API key")
 
 		if apiKey.IsExpired() {
-			return nil, errors.New(errors.ErrTypeUnauthorized, "API key has expired")
+			return nil, errors.New(errors.ErrorTypeUnauthorized, "API key has expired")
 		}
 
-		return nil, errors.New(errors.ErrTypeUnauthorized, "API key is not active")
+		return nil, errors.New(errors.ErrorTypeUnauthorized, "API key is not active")
 	}
 
 	// Update last used timestamp asynchronously (don't block request)
@@ -59,16 +59,16 @@ func (uc *ValidateKeyUseCase) Execute(ctx context.Context, key string) (*domain.
 		// Use a new context for background operation
 		bgCtx := context.Background()
 		if err := uc.repo.UpdateLastUsed(bgCtx, key); err != nil {
-			uc.logger.Error("Failed to update last used timestamp", err, logger.Fields{
+			uc.logger.WithFields(map[string]interface{}{
 				"key_id": apiKey.ID,
-			})
+			}).Error("Failed to update last used timestamp: %v", err)
 		}
 	}()
 
-	uc.logger.Debug("API key validated successfully", logger.Fields{
+	uc.logger.WithFields(map[string]interface{}{
 		"key_id": apiKey.ID,
 		"owner":  apiKey.Owner,
-	})
+	}).Debug("API key validated successfully")
 
 	return apiKey, nil
 }
@@ -83,12 +83,12 @@ func (uc *ValidateKeyUseCase) ValidateWithPermission(ctx context.Context, key, r
 
 	// Check permission
 	if !apiKey.HasPermission(resource, action) {
-		uc.logger.Warn("API key lacks required permission", logger.Fields{
+		uc.logger.WithFields(map[string]interface{}{
 			"key_id":   apiKey.ID,
 			"resource": resource,
 			"action":   action,
-		})
-		return nil, errors.New(errors.ErrTypeUnauthorized, "insufficient permissions for this operation")
+		}).Warn("API key lacks required permission")
+		return nil, errors.New(errors.ErrorTypeUnauthorized, "insufficient permissions for this operation")
 	}
 
 	return apiKey, nil
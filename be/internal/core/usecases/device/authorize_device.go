@@ -0,0 +1,58 @@
+package device
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// AuthorizeDeviceUseCase checks that a caller may act on a registered device
+type AuthorizeDeviceUseCase struct {
+	deviceRepo ports.DeviceRepository
+	logger     *logger.Logger
+}
+
+// NewAuthorizeDeviceUseCase creates a new AuthorizeDeviceUseCase
+func NewAuthorizeDeviceUseCase(deviceRepo ports.DeviceRepository) *AuthorizeDeviceUseCase {
+	return &AuthorizeDeviceUseCase{
+		deviceRepo: deviceRepo,
+		logger:     logger.New("AuthorizeDeviceUseCase"),
+	}
+}
+
+// Execute returns the device with the given name if the caller or the
+// caller's organisation owns it. Unregistered and deleted devices are not found.
+func (uc *AuthorizeDeviceUseCase) Execute(ctx context.Context, deviceName string, caller domain.Caller) (*domain.Device, error) {
+	if caller.Username == "" {
+		return nil, apperrors.NewUnauthorizedError("user not authenticated")
+	}
+	if deviceName == "" {
+		return nil, apperrors.NewValidationError("Device name is required")
+	}
+
+	device, err := uc.deviceRepo.FindByName(ctx, deviceName)
+	if err != nil {
+		return nil, err
+	}
+	if device.Status == domain.DeviceStatusDeleted {
+		return nil, apperrors.NewNotFoundError("Device")
+	}
+
+	if !device.OwnedBy(caller) {
+		uc.logger.WithFields(map[string]interface{}{
+			"device": deviceName,
+			"caller": caller.Username,
+		}).Warn("Device access denied")
+		return nil, apperrors.NewForbiddenError(fmt.Sprintf("You do not have access to device '%s'", deviceName))
+	}
+	if device.Status == domain.DeviceStatusTransferred {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' was transferred to another server", deviceName))
+	}
+
+	return device, nil
+}
@@ -0,0 +1,148 @@
+package device
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/secretbox"
+)
+
+// BackupDeviceUseCase handles exporting a device's WhatsApp session as an encrypted archive
+type BackupDeviceUseCase struct {
+	deviceRepo  ports.DeviceRepository
+	whatsappMgr domain.WhatsAppManagerInterface
+	sessions    ports.SessionStore
+	logger      *logger.Logger
+}
+
+// NewBackupDeviceUseCase creates a new BackupDeviceUseCase
+func NewBackupDeviceUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domain.WhatsAppManagerInterface, sessions ports.SessionStore) *BackupDeviceUseCase {
+	return &BackupDeviceUseCase{
+		deviceRepo:  deviceRepo,
+		whatsappMgr: whatsappMgr,
+		sessions:    sessions,
+		logger:      logger.New("BackupDeviceUseCase"),
+	}
+}
+
+// Execute returns the archive and its sealed bytes for a paired device owned by the caller.
+//
+// A plain backup leaves the device running; restoring it elsewhere must then be
+// forced, since the session may still be in use here. A transfer backup stops
+// the device first and marks it transferred, so that it is never loaded here
+// again and the archive can be restored elsewhere safely.
+func (uc *BackupDeviceUseCase) Execute(ctx context.Context, id string, caller domain.Caller, passphrase string, transfer bool) (*domain.SessionArchive, []byte, error) {
+	if len(passphrase) < secretbox.MinPassphraseLength {
+		return nil, nil, apperrors.NewValidationError(secretbox.ErrPassphraseTooShort.Error())
+	}
+
+	device, err := findOwnedDevice(ctx, uc.deviceRepo, id, caller)
+	if err != nil {
+		return nil, nil, err
+	}
+	if device.Status == domain.DeviceStatusTransferred {
+		return nil, nil, apperrors.New(apperrors.ErrorTypeConflict, "Device session was already transferred")
+	}
+	if device.JID == "" {
+		return nil, nil, apperrors.NewValidationError("Device is not paired")
+	}
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device":   device.Name,
+		"transfer": transfer,
+	})
+	log.Info("Backing up device session")
+
+	if transfer {
+		// Nothing may change the session once it has been copied
+		if err := uc.whatsappMgr.UnloadClient(ctx, device.Name); err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			return nil, nil, err
+		}
+	}
+
+	archive, sealed, err := uc.seal(ctx, device, passphrase, transfer)
+	if err == nil && transfer {
+		err = uc.deviceRepo.UpdateStatus(ctx, device.ID, domain.DeviceStatusTransferred)
+	}
+	if err != nil {
+		log.Error("Failed to back up device session: %v", err)
+		if transfer {
+			uc.reload(ctx, device.Name)
+		}
+		return nil, nil, err
+	}
+
+	log.Success("Device session backed up")
+	return archive, sealed, nil
+}
+
+// seal exports the device's session and encrypts it
+func (uc *BackupDeviceUseCase) seal(ctx context.Context, device *domain.Device, passphrase string, transfer bool) (*domain.SessionArchive, []byte, error) {
+	image, err := uc.sessions.Export(ctx, device.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive := &domain.SessionArchive{
+		Version:    domain.SessionArchiveVersion,
+		DeviceName: device.Name,
+		JID:        device.JID,
+		Transfer:   transfer,
+		CreatedAt:  time.Now(),
+		Store:      image,
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return nil, nil, apperrors.NewInternalError("Failed to encode session archive", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, apperrors.NewInternalError("Failed to compress session archive", err)
+	}
+
+	sealed, err := secretbox.Seal(buf.Bytes(), passphrase)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("Failed to encrypt session archive", err)
+	}
+	return archive, sealed, nil
+}
+
+// reload loads and connects a device again after a failed transfer
+func (uc *BackupDeviceUseCase) reload(ctx context.Context, deviceName string) {
+	client, err := uc.whatsappMgr.CreateClient(ctx, deviceName)
+	if err != nil {
+		uc.logger.WithField("device", deviceName).Error("Failed to reload device: %v", err)
+		return
+	}
+	if err := client.Connect(ctx); err != nil {
+		uc.logger.WithField("device", deviceName).Warn("Failed to reconnect device: %v", err)
+	}
+}
+
+// findOwnedDevice returns a device that exists, is not deleted and belongs to the caller
+func findOwnedDevice(ctx context.Context, deviceRepo ports.DeviceRepository, id string, caller domain.Caller) (*domain.Device, error) {
+	if caller.Username == "" {
+		return nil, apperrors.NewUnauthorizedError("user not authenticated")
+	}
+
+	device, err := deviceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if device.Status == domain.DeviceStatusDeleted {
+		return nil, apperrors.NewNotFoundError("Device")
+	}
+	if !device.OwnedBy(caller) {
+		return nil, apperrors.NewForbiddenError(fmt.Sprintf("You do not have access to device '%s'", device.Name))
+	}
+	return device, nil
+}
@@ -13,19 +13,21 @@ import (
 
 // CreateDeviceUseCase handles device creation logic
 type CreateDeviceUseCase struct {
-	deviceRepo ports.DeviceRepository
-	logger     *logger.Logger
+	deviceRepo  ports.DeviceRepository
+	whatsappMgr domain.WhatsAppManagerInterface
+	logger      *logger.Logger
 }
 
 // NewCreateDeviceUseCase creates a new CreateDeviceUseCase
-func NewCreateDeviceUseCase(deviceRepo ports.DeviceRepository) *CreateDeviceUseCase {
+func NewCreateDeviceUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domain.WhatsAppManagerInterface) *CreateDeviceUseCase {
 	return &CreateDeviceUseCase{
-		deviceRepo: deviceRepo,
-		logger:     logger.New("CreateDeviceUseCase"),
+		deviceRepo:  deviceRepo,
+		whatsappMgr: whatsappMgr,
+		logger:      logger.New("CreateDeviceUseCase"),
 	}
 }
 
-// Execute creates a new device
+// Execute creates a new device and its WhatsApp client, ready to be paired
 func (uc *CreateDeviceUseCase) Execute(ctx context.Context, req domain.CreateDeviceRequest) (*domain.Device, error) {
 	uc.logger.WithField("name", req.Name).Info("Creating device")
 
@@ -39,6 +41,12 @@ func (uc *CreateDeviceUseCase) Execute(ctx context.Context, req domain.CreateDev
 		return nil, apperrors.NewValidationError("Owner is required")
 	}
 
+	if req.ProxyURL != "" {
+		if err := validator.ValidateProxyURL(req.ProxyURL); err != nil {
+			return nil, apperrors.NewValidationError(err.Error())
+		}
+	}
+
 	// Check if device already exists
 	existing, err := uc.deviceRepo.FindByName(ctx, req.Name)
 	if err != nil && !apperrors.IsAppError(err) {
@@ -50,17 +58,43 @@ func (uc *CreateDeviceUseCase) Execute(ctx context.Context, req domain.CreateDev
 
 	// Create device entity
 	device := &domain.Device{
-		Name:        req.Name,
-		Owner:       req.Owner,
-		Description: req.Description,
-		Status:      domain.DeviceStatusActive,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		Name:         req.Name,
+		Owner:        req.Owner,
+		Organization: req.Organization,
+		Description:  req.Description,
+		Status:       domain.DeviceStatusActive,
+		ProxyURL:     req.ProxyURL,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	// Create the WhatsApp client first, so that a record never exists without one
+	if uc.whatsappMgr != nil {
+		client, err := uc.whatsappMgr.CreateClient(ctx, device.Name)
+		if err != nil {
+			uc.logger.Error("Failed to create WhatsApp client: %v", err)
+			return nil, err
+		}
+
+		// The client was built before the record existed to take the proxy from
+		if device.ProxyURL != "" {
+			if err := client.SetProxy(device.ProxyURL); err != nil {
+				if removeErr := uc.whatsappMgr.RemoveClient(ctx, device.Name); removeErr != nil {
+					uc.logger.Warn("Failed to remove WhatsApp client: %v", removeErr)
+				}
+				return nil, err
+			}
+		}
 	}
 
 	// Save to repository
 	if err := uc.deviceRepo.Create(ctx, device); err != nil {
 		uc.logger.Error("Failed to create device: %v", err)
+		if uc.whatsappMgr != nil {
+			if removeErr := uc.whatsappMgr.RemoveClient(ctx, device.Name); removeErr != nil {
+				uc.logger.Warn("Failed to remove WhatsApp client: %v", removeErr)
+			}
+		}
 		return nil, err
 	}
 
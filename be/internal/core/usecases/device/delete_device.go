@@ -24,12 +24,12 @@ func NewDeleteDeviceUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domai
 	}
 }
 
-// Execute deletes a device
-func (uc *DeleteDeviceUseCase) Execute(ctx context.Context, id string) error {
+// Execute deletes a device owned by the caller
+func (uc *DeleteDeviceUseCase) Execute(ctx context.Context, id string, caller domain.Caller) error {
 	uc.logger.WithField("id", id).Info("Deleting device")
 
 	// Get device to find its name
-	device, err := uc.deviceRepo.FindByID(ctx, id)
+	device, err := findOwnedDevice(ctx, uc.deviceRepo, id, caller)
 	if err != nil {
 		uc.logger.Error("Failed to find device: %v", err)
 		return err
@@ -22,11 +22,11 @@ func NewGetDeviceUseCase(deviceRepo ports.DeviceRepository) *GetDeviceUseCase {
 	}
 }
 
-// Execute retrieves a device by ID
-func (uc *GetDeviceUseCase) Execute(ctx context.Context, id string) (*domain.Device, error) {
+// Execute retrieves a device by ID if the caller or the caller's organisation owns it
+func (uc *GetDeviceUseCase) Execute(ctx context.Context, id string, caller domain.Caller) (*domain.Device, error) {
 	uc.logger.WithField("id", id).Info("Getting device")
 
-	device, err := uc.deviceRepo.FindByID(ctx, id)
+	device, err := findOwnedDevice(ctx, uc.deviceRepo, id, caller)
 	if err != nil {
 		uc.logger.Error("Failed to get device: %v", err)
 		return nil, err
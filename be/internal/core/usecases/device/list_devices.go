@@ -5,6 +5,7 @@ import (
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
 )
 
@@ -22,9 +23,20 @@ func NewListDevicesUseCase(deviceRepo ports.DeviceRepository) *ListDevicesUseCas
 	}
 }
 
-// Execute lists devices with pagination and optional filters
-func (uc *ListDevicesUseCase) Execute(ctx context.Context, filter *domain.DeviceFilter, skip, limit int) ([]*domain.Device, int64, error) {
-	uc.logger.Info("Listing devices")
+// Execute lists the devices the caller owns or shares through their
+// organisation, with pagination and optional filters
+func (uc *ListDevicesUseCase) Execute(ctx context.Context, caller domain.Caller, filter *domain.DeviceFilter, skip, limit int) ([]*domain.Device, int64, error) {
+	if caller.Username == "" {
+		return nil, 0, apperrors.NewUnauthorizedError("user not authenticated")
+	}
+	uc.logger.WithField("caller", caller.Username).Info("Listing devices")
+
+	scoped := domain.DeviceFilter{}
+	if filter != nil {
+		scoped = *filter
+	}
+	scoped.AccessibleTo = &caller
+	filter = &scoped
 
 	// Get total count
 	total, err := uc.deviceRepo.Count(ctx, filter)
@@ -0,0 +1,121 @@
+package device
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// ReconcileDevicesUseCase matches the device registry against the session
+// stores on disk and creates the WhatsApp clients of registered devices
+type ReconcileDevicesUseCase struct {
+	deviceRepo  ports.DeviceRepository
+	whatsappMgr domain.WhatsAppManagerInterface
+	logger      *logger.Logger
+}
+
+// NewReconcileDevicesUseCase creates a new ReconcileDevicesUseCase
+func NewReconcileDevicesUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domain.WhatsAppManagerInterface) *ReconcileDevicesUseCase {
+	return &ReconcileDevicesUseCase{
+		deviceRepo:  deviceRepo,
+		whatsappMgr: whatsappMgr,
+		logger:      logger.New("ReconcileDevicesUseCase"),
+	}
+}
+
+// Execute creates a client for every device that is not deleted or transferred. Stores
+// without a record, including those of deleted devices, are reported and not
+// loaded. Records that still carry a JID but have lost their store are marked
+// logged out, since their session is gone. Running it again only loads what is
+// missing. Devices whose lease another replica holds are left to it.
+func (uc *ReconcileDevicesUseCase) Execute(ctx context.Context) (*domain.DeviceReconciliation, error) {
+	uc.logger.Info("Reconciling devices with session stores")
+
+	devices, err := uc.deviceRepo.FindAll(ctx, nil, 0, 0)
+	if err != nil {
+		uc.logger.Error("Failed to list devices: %v", err)
+		return nil, err
+	}
+
+	storeNames, err := uc.whatsappMgr.ListStores()
+	if err != nil {
+		return nil, err
+	}
+	stores := make(map[string]bool, len(storeNames))
+	for _, name := range storeNames {
+		stores[name] = true
+	}
+
+	report := &domain.DeviceReconciliation{
+		Loaded:          []string{},
+		Failed:          []string{},
+		HeldElsewhere:   []string{},
+		OrphanedStores:  []string{},
+		OrphanedRecords: []string{},
+	}
+
+	registered := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		if device.Status == domain.DeviceStatusDeleted {
+			continue
+		}
+		registered[device.Name] = true
+		if device.Status == domain.DeviceStatusTransferred {
+			// The session now runs on another server
+			continue
+		}
+
+		log := uc.logger.WithField("device", device.Name)
+
+		if !stores[device.Name] && device.JID != "" {
+			log.Warn("Device record has no session store, marking it logged out")
+			report.OrphanedRecords = append(report.OrphanedRecords, device.Name)
+
+			if err := uc.deviceRepo.UpdateJID(ctx, device.ID, ""); err != nil {
+				log.Error("Failed to clear device JID: %v", err)
+			}
+			if err := uc.deviceRepo.UpdateStatus(ctx, device.ID, domain.DeviceStatusLoggedOut); err != nil {
+				log.Error("Failed to update device status: %v", err)
+			}
+		}
+
+		if _, err := uc.whatsappMgr.CreateClient(ctx, device.Name); err != nil {
+			if apperrors.GetAppError(err).Type == apperrors.ErrorTypeConflict {
+				// Another replica holds the device's lease
+				report.HeldElsewhere = append(report.HeldElsewhere, device.Name)
+				continue
+			}
+			log.Warn("Failed to load device: %v", err)
+			report.Failed = append(report.Failed, device.Name)
+			continue
+		}
+		report.Loaded = append(report.Loaded, device.Name)
+	}
+
+	for _, name := range storeNames {
+		if !registered[name] {
+			uc.logger.WithField("device", name).Warn("Session store has no device record, not loading it")
+			report.OrphanedStores = append(report.OrphanedStores, name)
+		}
+	}
+
+	sort.Strings(report.Loaded)
+	sort.Strings(report.Failed)
+	sort.Strings(report.HeldElsewhere)
+	sort.Strings(report.OrphanedStores)
+	sort.Strings(report.OrphanedRecords)
+
+	uc.logger.WithFields(map[string]interface{}{
+		"loaded":           len(report.Loaded),
+		"failed":           len(report.Failed),
+		"held_elsewhere":   len(report.HeldElsewhere),
+		"orphaned_stores":  len(report.OrphanedStores),
+		"orphaned_records": len(report.OrphanedRecords),
+	}).Success("Devices reconciled")
+
+	return report, nil
+}
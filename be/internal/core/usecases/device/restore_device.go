@@ -0,0 +1,170 @@
+package device
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/secretbox"
+)
+
+// RestoreDeviceUseCase handles loading an encrypted session archive into a device
+type RestoreDeviceUseCase struct {
+	deviceRepo  ports.DeviceRepository
+	whatsappMgr domain.WhatsAppManagerInterface
+	sessions    ports.SessionStore
+	logger      *logger.Logger
+}
+
+// NewRestoreDeviceUseCase creates a new RestoreDeviceUseCase
+func NewRestoreDeviceUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domain.WhatsAppManagerInterface, sessions ports.SessionStore) *RestoreDeviceUseCase {
+	return &RestoreDeviceUseCase{
+		deviceRepo:  deviceRepo,
+		whatsappMgr: whatsappMgr,
+		sessions:    sessions,
+		logger:      logger.New("RestoreDeviceUseCase"),
+	}
+}
+
+// Execute restores the session in a sealed archive into a device owned by the
+// caller and connects it.
+//
+// The device must not have a paired session of its own, unless it was
+// transferred away, and no other device here may hold the archived session.
+// Archives of plain backups are refused unless force is set, confirming that
+// the server they came from no longer uses the session.
+func (uc *RestoreDeviceUseCase) Execute(ctx context.Context, id string, caller domain.Caller, sealed []byte, passphrase string, force bool) (*domain.Device, error) {
+	device, err := findOwnedDevice(ctx, uc.deviceRepo, id, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := openArchive(sealed, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device": device.Name,
+		"source": archive.DeviceName,
+		"jid":    archive.JID,
+	})
+
+	if !archive.Transfer && !force {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			"Archive is a plain backup whose session may still be active on its source; "+
+				"make a transfer backup, or restore with force once the source no longer uses it")
+	}
+
+	if device.Status != domain.DeviceStatusTransferred && device.JID != "" {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' already has a paired session; delete it or log it out first", device.Name))
+	}
+	if client, exists := uc.whatsappMgr.GetClient(device.Name); exists && client.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' is connected", device.Name))
+	}
+	if err := uc.ensureSessionUnused(ctx, device, archive.JID); err != nil {
+		return nil, err
+	}
+
+	log.Info("Restoring device session")
+
+	// Drop the device's unpaired session; the archive replaces it
+	if err := uc.whatsappMgr.RemoveClient(ctx, device.Name); err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+		return nil, err
+	}
+
+	if err := uc.sessions.Import(ctx, device.Name, archive.Store); err != nil {
+		log.Error("Failed to import session: %v", err)
+
+		// Leave the device ready to be paired instead
+		if _, createErr := uc.whatsappMgr.CreateClient(ctx, device.Name); createErr != nil {
+			log.Error("Failed to recreate client: %v", createErr)
+		}
+		return nil, err
+	}
+
+	if err := uc.deviceRepo.UpdateJID(ctx, device.ID, archive.JID); err != nil {
+		return nil, err
+	}
+	if err := uc.deviceRepo.UpdateStatus(ctx, device.ID, domain.DeviceStatusActive); err != nil {
+		return nil, err
+	}
+
+	client, err := uc.whatsappMgr.CreateClient(ctx, device.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Connect(ctx); err != nil {
+		log.Warn("Restored session did not connect yet: %v", err)
+	}
+
+	log.Success("Device session restored")
+	return uc.deviceRepo.FindByID(ctx, device.ID)
+}
+
+// ensureSessionUnused checks that no other device here holds the session
+func (uc *RestoreDeviceUseCase) ensureSessionUnused(ctx context.Context, target *domain.Device, jid string) error {
+	devices, err := uc.deviceRepo.FindAll(ctx, nil, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range devices {
+		if other.ID == target.ID || other.JID != jid {
+			continue
+		}
+		if other.Status == domain.DeviceStatusDeleted || other.Status == domain.DeviceStatusTransferred {
+			continue
+		}
+		return apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Session is already in use by device '%s'", other.Name))
+	}
+	return nil
+}
+
+// maxUnpackedArchiveSize limits a session archive once decompressed, so that
+// a small upload cannot expand without bound
+const maxUnpackedArchiveSize = 256 << 20
+
+// openArchive decrypts and decodes a sealed session archive
+func openArchive(sealed []byte, passphrase string) (*domain.SessionArchive, error) {
+	plaintext, err := secretbox.Open(sealed, passphrase)
+	if err != nil {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Failed to open session archive: %v", err))
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return nil, apperrors.NewValidationError("Session archive is corrupted")
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(io.LimitReader(zr, maxUnpackedArchiveSize+1))
+	if err != nil {
+		return nil, apperrors.NewValidationError("Session archive is corrupted")
+	}
+	if len(data) > maxUnpackedArchiveSize {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Session archive unpacks to more than %d MB", maxUnpackedArchiveSize>>20))
+	}
+
+	var archive domain.SessionArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, apperrors.NewValidationError("Session archive is corrupted")
+	}
+	if archive.Version != domain.SessionArchiveVersion {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Unsupported session archive version %d", archive.Version))
+	}
+	if archive.JID == "" || len(archive.Store) == 0 {
+		return nil, apperrors.NewValidationError("Session archive holds no session")
+	}
+	return &archive, nil
+}
@@ -0,0 +1,163 @@
+package device
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// syncQueueSize is the number of events waiting to be applied; further
+	// events are dropped until the queue drains
+	syncQueueSize = 256
+
+	// syncTimeout bounds the repository updates made for a single event
+	syncTimeout = 10 * time.Second
+)
+
+// SyncDeviceStateUseCase keeps device records in step with their WhatsApp
+// sessions. Events are queued and applied in order on a background goroutine,
+// so that the WhatsApp client's event goroutine never waits for the database.
+type SyncDeviceStateUseCase struct {
+	deviceRepo ports.DeviceRepository
+	logger     *logger.Logger
+
+	queue  chan domain.DeviceEvent
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSyncDeviceStateUseCase creates a new SyncDeviceStateUseCase
+func NewSyncDeviceStateUseCase(deviceRepo ports.DeviceRepository) *SyncDeviceStateUseCase {
+	return &SyncDeviceStateUseCase{
+		deviceRepo: deviceRepo,
+		logger:     logger.New("SyncDeviceStateUseCase"),
+		queue:      make(chan domain.DeviceEvent, syncQueueSize),
+	}
+}
+
+// Start begins applying queued events in the background
+func (uc *SyncDeviceStateUseCase) Start(ctx context.Context) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.cancel != nil {
+		return
+	}
+
+	ctx, uc.cancel = context.WithCancel(ctx)
+	uc.done = make(chan struct{})
+
+	go uc.loop(ctx, uc.done)
+	uc.logger.Info("Device state sync started")
+}
+
+// Stop applies the events already queued and stops the sync
+func (uc *SyncDeviceStateUseCase) Stop() {
+	uc.mu.Lock()
+	cancel, done := uc.cancel, uc.done
+	uc.cancel = nil
+	uc.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	uc.logger.Info("Device state sync stopped")
+}
+
+// Handle queues the events that change a device record, without blocking
+func (uc *SyncDeviceStateUseCase) Handle(event domain.DeviceEvent) {
+	switch event.Type {
+	case domain.DeviceEventConnected, domain.DeviceEventPairSuccess, domain.DeviceEventLoggedOut:
+	default:
+		return
+	}
+
+	select {
+	case uc.queue <- event:
+	default:
+		uc.logger.WithField("device", event.DeviceName).Warn("Device state queue full, %s event dropped", event.Type)
+	}
+}
+
+// loop applies events until ctx is cancelled, then drains the queue
+func (uc *SyncDeviceStateUseCase) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case event := <-uc.queue:
+			uc.Execute(event)
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-uc.queue:
+					uc.Execute(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Execute applies a device lifecycle event to the device record. Connecting or
+// pairing stores the JID and activates the device; being logged out clears the
+// JID and marks the device as logged out. Other events leave the record as is.
+func (uc *SyncDeviceStateUseCase) Execute(event domain.DeviceEvent) {
+	switch event.Type {
+	case domain.DeviceEventConnected, domain.DeviceEventPairSuccess:
+		uc.apply(event, event.JID, domain.DeviceStatusActive)
+	case domain.DeviceEventLoggedOut:
+		uc.apply(event, "", domain.DeviceStatusLoggedOut)
+	}
+}
+
+// apply updates the JID and status of the event's device where they differ
+func (uc *SyncDeviceStateUseCase) apply(event domain.DeviceEvent, jid string, status domain.DeviceStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device": event.DeviceName,
+		"event":  event.Type,
+	})
+
+	device, err := uc.deviceRepo.FindByName(ctx, event.DeviceName)
+	if err != nil {
+		log.Warn("No device record for WhatsApp session: %v", err)
+		return
+	}
+
+	// A deleted record must not be revived by its session's last events
+	if device.Status == domain.DeviceStatusDeleted {
+		return
+	}
+
+	if device.JID != jid {
+		if err := uc.deviceRepo.UpdateJID(ctx, device.ID, jid); err != nil {
+			log.Error("Failed to update device JID: %v", err)
+			return
+		}
+	}
+
+	if device.Status != status {
+		if err := uc.deviceRepo.UpdateStatus(ctx, device.ID, status); err != nil {
+			log.Error("Failed to update device status: %v", err)
+			return
+		}
+	}
+
+	log.WithFields(map[string]interface{}{
+		"jid":    jid,
+		"status": status,
+	}).Debug("Device record synced")
+}
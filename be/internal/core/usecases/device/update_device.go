@@ -12,47 +12,37 @@ import (
 
 // UpdateDeviceUseCase handles device update logic
 type UpdateDeviceUseCase struct {
-	deviceRepo ports.DeviceRepository
-	logger     *logger.Logger
+	deviceRepo  ports.DeviceRepository
+	whatsappMgr domain.WhatsAppManagerInterface
+	logger      *logger.Logger
 }
 
 // NewUpdateDeviceUseCase creates a new UpdateDeviceUseCase
-func NewUpdateDeviceUseCase(deviceRepo ports.DeviceRepository) *UpdateDeviceUseCase {
+func NewUpdateDeviceUseCase(deviceRepo ports.DeviceRepository, whatsappMgr domain.WhatsAppManagerInterface) *UpdateDeviceUseCase {
 	return &UpdateDeviceUseCase{
-		deviceRepo: deviceRepo,
-		logger:     logger.New("UpdateDeviceUseCase"),
+		deviceRepo:  deviceRepo,
+		whatsappMgr: whatsappMgr,
+		logger:      logger.New("UpdateDeviceUseCase"),
 	}
 }
 
-// Execute updates a device
-func (uc *UpdateDeviceUseCase) Execute(ctx context.Context, id string, req domain.UpdateDeviceRequest) (*domain.Device, error) {
+// Execute updates a device owned by the caller. Renaming moves its session
+// store and the records kept under its name; a loaded client comes back under
+// the new name.
+func (uc *UpdateDeviceUseCase) Execute(ctx context.Context, id string, caller domain.Caller, req domain.UpdateDeviceRequest) (*domain.Device, error) {
 	uc.logger.WithField("id", id).Info("Updating device")
 
-	// Get existing device
-	device, err := uc.deviceRepo.FindByID(ctx, id)
+	device, err := findOwnedDevice(ctx, uc.deviceRepo, id, caller)
 	if err != nil {
 		uc.logger.Error("Failed to find device: %v", err)
 		return nil, err
 	}
 
-	// Update fields if provided
-	if req.Name != nil {
-		// Validate device name
-		if !validator.ValidateDeviceName(*req.Name) {
-			return nil, apperrors.NewValidationError("Invalid device name: must be alphanumeric, dash, or underscore only (3-50 characters)")
+	previousName := device.Name
+	if req.Name != nil && *req.Name != device.Name {
+		if err := uc.checkName(ctx, device, *req.Name); err != nil {
+			return nil, err
 		}
-
-		// Check if name is taken by another device
-		if *req.Name != device.Name {
-			existing, err := uc.deviceRepo.FindByName(ctx, *req.Name)
-			if err != nil && !apperrors.IsAppError(err) {
-				return nil, err
-			}
-			if existing != nil && existing.ID != device.ID {
-				return nil, apperrors.New(apperrors.ErrorTypeConflict, "Device with this name already exists")
-			}
-		}
-
 		device.Name = *req.Name
 	}
 
@@ -60,16 +50,51 @@ func (uc *UpdateDeviceUseCase) Execute(ctx context.Context, id string, req domai
 		device.Description = *req.Description
 	}
 
-	if req.Status != nil {
+	if req.Status != nil && *req.Status != device.Status {
+		// Only restoring a session archive brings a transferred device back
+		if device.Status == domain.DeviceStatusTransferred {
+			return nil, apperrors.New(apperrors.ErrorTypeConflict, "Device session was transferred; restore a session archive to use it here again")
+		}
 		device.Status = *req.Status
 	}
 
-	// Save updated device
-	if err := uc.deviceRepo.Update(ctx, device); err != nil {
+	if req.PresencePolicy != nil {
+		if !req.PresencePolicy.IsValid() {
+			return nil, apperrors.NewValidationError("Presence policy must be on_send, online or offline")
+		}
+		device.PresencePolicy = *req.PresencePolicy
+	}
+
+	proxyChanged := req.ProxyURL != nil && *req.ProxyURL != device.ProxyURL
+	if proxyChanged {
+		if *req.ProxyURL != "" {
+			if err := validator.ValidateProxyURL(*req.ProxyURL); err != nil {
+				return nil, apperrors.NewValidationError(err.Error())
+			}
+		}
+		device.ProxyURL = *req.ProxyURL
+	}
+
+	renamed := device.Name != previousName
+	if renamed {
+		if err := uc.rename(ctx, device, previousName); err != nil {
+			return nil, err
+		}
+	} else if err := uc.deviceRepo.Update(ctx, device); err != nil {
 		uc.logger.Error("Failed to update device: %v", err)
 		return nil, err
 	}
 
+	// Loaded clients follow the new settings right away
+	if req.PresencePolicy != nil {
+		if client, exists := uc.whatsappMgr.GetClient(device.Name); exists {
+			client.SetPresencePolicy(device.PresencePolicy)
+		}
+	}
+	if proxyChanged && !renamed {
+		uc.reloadClient(ctx, device)
+	}
+
 	uc.logger.WithFields(map[string]interface{}{
 		"id":   device.ID,
 		"name": device.Name,
@@ -77,3 +102,100 @@ func (uc *UpdateDeviceUseCase) Execute(ctx context.Context, id string, req domai
 
 	return device, nil
 }
+
+// checkName validates a new name for a device
+func (uc *UpdateDeviceUseCase) checkName(ctx context.Context, device *domain.Device, name string) error {
+	if !validator.ValidateDeviceName(name) {
+		return apperrors.NewValidationError("Invalid device name: must be alphanumeric, dash, or underscore only (3-50 characters)")
+	}
+	if device.Status == domain.DeviceStatusTransferred {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Device session was transferred; restore a session archive before renaming it")
+	}
+
+	existing, err := uc.deviceRepo.FindByName(ctx, name)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.ID != device.ID {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Device with this name already exists")
+	}
+	return nil
+}
+
+// rename saves a device under its new name, moving its session store and the
+// records kept under the old name. A client loaded under the old name is
+// created again under the new one.
+func (uc *UpdateDeviceUseCase) rename(ctx context.Context, device *domain.Device, previousName string) error {
+	log := uc.logger.WithFields(map[string]interface{}{"device": previousName, "name": device.Name})
+
+	var reconnect, loaded bool
+	if client, exists := uc.whatsappMgr.GetClient(previousName); exists {
+		loaded = true
+		reconnect = client.IsConnected() || client.GetJID() != ""
+	}
+
+	if err := uc.whatsappMgr.RenameClient(ctx, previousName, device.Name); err != nil {
+		log.Error("Failed to rename session store: %v", err)
+		return err
+	}
+
+	if err := uc.deviceRepo.Update(ctx, device); err != nil {
+		log.Error("Failed to update device: %v", err)
+		if err := uc.whatsappMgr.RenameClient(ctx, device.Name, previousName); err != nil {
+			log.Error("Failed to move session store back: %v", err)
+		}
+		return err
+	}
+
+	if err := uc.deviceRepo.RenameReferences(ctx, previousName, device.Name); err != nil {
+		// The device already works under its new name; only records such as
+		// old schedules stay under the previous one
+		log.Error("Failed to move records to the new device name: %v", err)
+	}
+
+	if !loaded {
+		return nil
+	}
+	client, err := uc.whatsappMgr.CreateClient(ctx, device.Name)
+	if err != nil {
+		log.Error("Failed to load renamed client: %v", err)
+		return nil
+	}
+	if reconnect {
+		if err := client.Connect(ctx); err != nil {
+			log.Warn("Failed to reconnect renamed client: %v", err)
+		}
+	}
+	return nil
+}
+
+// reloadClient rebuilds a loaded client so that its connection goes through
+// the device's current proxy, reconnecting it if it was paired
+func (uc *UpdateDeviceUseCase) reloadClient(ctx context.Context, device *domain.Device) {
+	log := uc.logger.WithField("device", device.Name)
+
+	client, exists := uc.whatsappMgr.GetClient(device.Name)
+	if !exists {
+		return
+	}
+	reconnect := client.IsConnected() || client.GetJID() != ""
+
+	if err := uc.whatsappMgr.UnloadClient(ctx, device.Name); err != nil {
+		log.Error("Failed to unload client: %v", err)
+		return
+	}
+
+	client, err := uc.whatsappMgr.CreateClient(ctx, device.Name)
+	if err != nil {
+		log.Error("Failed to reload client: %v", err)
+		return
+	}
+	if reconnect {
+		if err := client.Connect(ctx); err != nil {
+			log.Warn("Failed to reconnect client through new proxy: %v", err)
+			return
+		}
+	}
+
+	log.Info("Client reloaded with new proxy")
+}
@@ -0,0 +1,196 @@
+package message
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/chatexport"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/validator"
+)
+
+// maxImportPreviews limits the previews returned by a dry run
+const maxImportPreviews = 500
+
+// ImportChatInput describes an exported chat to import
+type ImportChatInput struct {
+	Data       []byte         // Contents of the exported .txt or .zip
+	DeviceName string         // Device the chat belongs to, stored on imported records
+	ChatJID    string         // Optional JID of the exported chat
+	Location   *time.Location // Time zone of the timestamps in the export
+	DryRun     bool
+}
+
+// ImportedMessage is a message from the export and what the processors made of it
+type ImportedMessage struct {
+	Line      int
+	MessageID string
+	Timestamp time.Time
+	Sender    string
+	Previews  []domain.MessagePreview
+}
+
+// ImportError records a message that failed to import
+type ImportError struct {
+	Line      int
+	Timestamp time.Time
+	Sender    string
+	Error     string
+}
+
+// ImportChatResult summarizes an import run
+type ImportChatResult struct {
+	DryRun    bool
+	Total     int // Messages with a sender in the export
+	Matched   int // Messages at least one processor can handle
+	Saveable  int // Dry run only: messages that would be saved
+	Processed int // Messages processed without error
+	Failed    int
+	Previews  []ImportedMessage // Dry run only, capped at maxImportPreviews
+	Errors    []ImportError
+}
+
+// ImportChatUseCase replays an exported WhatsApp chat through the message processors
+type ImportChatUseCase struct {
+	registry domain.MessageProcessorRegistry
+	logger   *logger.Logger
+}
+
+// NewImportChatUseCase creates a new ImportChatUseCase
+func NewImportChatUseCase(registry domain.MessageProcessorRegistry) *ImportChatUseCase {
+	return &ImportChatUseCase{
+		registry: registry,
+		logger:   logger.New("ImportChatUseCase"),
+	}
+}
+
+// Execute parses the export and processes its messages in import mode, with
+// their original timestamps and senders
+func (uc *ImportChatUseCase) Execute(ctx context.Context, input ImportChatInput) (*ImportChatResult, error) {
+	if len(input.Data) == 0 {
+		return nil, apperrors.NewValidationError("Chat export is empty")
+	}
+	if input.ChatJID != "" && !validator.ValidateWhatsAppJID(input.ChatJID) {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("Invalid WhatsApp JID: %s", input.ChatJID))
+	}
+
+	messages, err := chatexport.ReadFile(input.Data, input.Location)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid chat export").WithDetails("reason", err.Error())
+	}
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device":   input.DeviceName,
+		"chat":     input.ChatJID,
+		"messages": len(messages),
+		"dry_run":  input.DryRun,
+	})
+	log.Info("Importing chat export")
+
+	processors := uc.registry.GetProcessors()
+	result := &ImportChatResult{DryRun: input.DryRun}
+
+	for _, exported := range messages {
+		if err := ctx.Err(); err != nil {
+			return nil, apperrors.NewInternalError("Chat import cancelled", err)
+		}
+
+		result.Total++
+		if exported.Content == "" {
+			continue
+		}
+
+		message := domain.IncomingMessage{
+			ID:         importMessageID(input.ChatJID, exported),
+			DeviceName: input.DeviceName,
+			From:       exported.SenderJID(),
+			FromName:   exported.Sender,
+			Content:    exported.Content,
+			Timestamp:  exported.Timestamp,
+			IsGroup:    strings.HasSuffix(input.ChatJID, "@g.us"),
+			Imported:   true,
+		}
+
+		if !canProcess(processors, message) {
+			continue
+		}
+		result.Matched++
+
+		if input.DryRun {
+			previews, err := uc.registry.Preview(message)
+			if err != nil {
+				result.addError(exported, err)
+				continue
+			}
+			result.Processed++
+			if len(previews) == 0 {
+				continue
+			}
+
+			result.Saveable++
+			if len(result.Previews) < maxImportPreviews {
+				result.Previews = append(result.Previews, ImportedMessage{
+					Line:      exported.Line,
+					MessageID: message.ID,
+					Timestamp: exported.Timestamp,
+					Sender:    exported.Sender,
+					Previews:  previews,
+				})
+			}
+			continue
+		}
+
+		if err := uc.registry.Process(message); err != nil {
+			result.addError(exported, err)
+			continue
+		}
+		result.Processed++
+	}
+
+	log.WithFields(map[string]interface{}{
+		"matched":   result.Matched,
+		"processed": result.Processed,
+		"failed":    result.Failed,
+	}).Success("Chat export imported")
+
+	return result, nil
+}
+
+// addError records a failed message
+func (r *ImportChatResult) addError(exported chatexport.Message, err error) {
+	r.Failed++
+	r.Errors = append(r.Errors, ImportError{
+		Line:      exported.Line,
+		Timestamp: exported.Timestamp,
+		Sender:    exported.Sender,
+		Error:     err.Error(),
+	})
+}
+
+// canProcess reports whether any processor can handle the message
+func canProcess(processors []domain.MessageProcessor, message domain.IncomingMessage) bool {
+	for _, processor := range processors {
+		if processor.CanProcess(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// importMessageID derives a stable ID for an exported message, which has no
+// WhatsApp message ID, so that importing the same export twice is detected
+func importMessageID(chatJID string, message chatexport.Message) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		chatJID,
+		message.Timestamp.UTC().Format(time.RFC3339),
+		message.Sender,
+		message.Content,
+	}, "\x00")))
+	return "import-" + hex.EncodeToString(sum[:10])
+}
@@ -3,6 +3,7 @@ package message
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
@@ -12,6 +13,7 @@ import (
 // ProcessorRegistry manages and executes message processors
 type ProcessorRegistry struct {
 	processors []domain.MessageProcessor
+	observer   domain.MessageProcessingObserver
 	mu         sync.RWMutex
 	logger     *logger.Logger
 }
@@ -62,7 +64,13 @@ func (r *ProcessorRegistry) Process(message domain.IncomingMessage) error {
 
 		r.logger.WithField("processor", processor.Name()).Info("Processing with processor")
 
-		if err := processor.Process(message); err != nil {
+		started := time.Now()
+		err := processor.Process(message)
+		if r.observer != nil && !message.Imported {
+			// Backfilled messages are not live traffic
+			r.observer.ObserveProcessing(message.DeviceName, processor.Name(), time.Since(started), err)
+		}
+		if err != nil {
 			r.logger.WithFields(map[string]interface{}{
 				"processor": processor.Name(),
 				"error":     err.Error(),
@@ -87,6 +95,42 @@ func (r *ProcessorRegistry) Process(message domain.IncomingMessage) error {
 	return nil
 }
 
+// Preview runs the message through all applicable processors that support previews, without saving
+func (r *ProcessorRegistry) Preview(message domain.IncomingMessage) ([]domain.MessagePreview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var previews []domain.MessagePreview
+	for _, processor := range r.processors {
+		previewer, ok := processor.(domain.MessagePreviewer)
+		if !ok || !processor.CanProcess(message) {
+			continue
+		}
+
+		result, err := previewer.Preview(message)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+
+		previews = append(previews, domain.MessagePreview{
+			Processor: processor.Name(),
+			Result:    result,
+		})
+	}
+
+	return previews, nil
+}
+
+// SetObserver sets the observer told about every processor run
+func (r *ProcessorRegistry) SetObserver(observer domain.MessageProcessingObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+}
+
 // GetProcessors returns all registered processors
 func (r *ProcessorRegistry) GetProcessors() []domain.MessageProcessor {
 	r.mu.RLock()
@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// maxNumbersPerCheck bounds the phone numbers checked in one request
+const maxNumbersPerCheck = 500
+
+// CheckNumbersUseCase handles checking which phone numbers are on WhatsApp
+type CheckNumbersUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewCheckNumbersUseCase creates a new CheckNumbersUseCase
+func NewCheckNumbersUseCase(manager domain.WhatsAppManagerInterface) *CheckNumbersUseCase {
+	return &CheckNumbersUseCase{
+		manager: manager,
+		logger:  logger.New("CheckNumbersUseCase"),
+	}
+}
+
+// Execute checks phone numbers in international or national format and
+// returns a result per number, in order. Invalid numbers are reported in
+// their result rather than failing the whole check.
+func (uc *CheckNumbersUseCase) Execute(ctx context.Context, deviceName string, numbers []string) ([]domain.NumberCheck, error) {
+	if len(numbers) == 0 {
+		return nil, apperrors.NewValidationError("At least one phone number is required")
+	}
+	if len(numbers) > maxNumbersPerCheck {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("At most %d phone numbers can be checked at once", maxNumbersPerCheck))
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.NumberCheck, len(numbers))
+	phones := make([]string, 0, len(numbers))
+	for i, number := range numbers {
+		results[i].Input = number
+
+		phone, err := normalizePhone(number)
+		if err != nil {
+			results[i].Error = apperrors.GetAppError(err).Message
+			continue
+		}
+		results[i].Phone = phone
+		phones = append(phones, phone)
+	}
+
+	if len(phones) > 0 {
+		checks, err := client.IsOnWhatsApp(ctx, phones)
+		if err != nil {
+			return nil, err
+		}
+
+		byPhone := make(map[string]domain.NumberCheck, len(checks))
+		for _, check := range checks {
+			byPhone[check.Phone] = check
+		}
+		for i := range results {
+			if check, ok := byPhone[results[i].Phone]; ok && results[i].Error == "" {
+				results[i].JID = check.JID
+				results[i].Registered = check.Registered
+				results[i].BusinessName = check.BusinessName
+			}
+		}
+	}
+
+	registered := 0
+	for _, result := range results {
+		if result.Registered {
+			registered++
+		}
+	}
+	uc.logger.WithFields(map[string]interface{}{
+		"device":     deviceName,
+		"numbers":    len(numbers),
+		"registered": registered,
+	}).Info("Phone numbers checked")
+
+	return results, nil
+}
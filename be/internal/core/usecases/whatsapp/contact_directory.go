@@ -0,0 +1,126 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// Limits on the annotations of a contact
+const (
+	maxContactTags      = 20
+	maxContactTagLength = 50
+	maxContactNotes     = 2000
+)
+
+// ContactDirectoryUseCase handles reading a device's contact directory and
+// tagging and annotating its contacts
+type ContactDirectoryUseCase struct {
+	contacts ports.ContactRepository
+	logger   *logger.Logger
+}
+
+// NewContactDirectoryUseCase creates a new ContactDirectoryUseCase
+func NewContactDirectoryUseCase(contacts ports.ContactRepository) *ContactDirectoryUseCase {
+	return &ContactDirectoryUseCase{
+		contacts: contacts,
+		logger:   logger.New("ContactDirectoryUseCase"),
+	}
+}
+
+// List lists the contacts of a device matching the filter, sorted by display
+// name, with the number of matching contacts
+func (uc *ContactDirectoryUseCase) List(ctx context.Context, filter *domain.ContactFilter, skip, limit int) ([]*domain.Contact, int64, error) {
+	total, err := uc.contacts.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	contacts, err := uc.contacts.FindAll(ctx, filter, skip, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contacts, total, nil
+}
+
+// Get retrieves a contact of a device by JID or phone number
+func (uc *ContactDirectoryUseCase) Get(ctx context.Context, deviceName, jid string) (*domain.Contact, error) {
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return nil, err
+	}
+	return uc.contacts.FindByJID(ctx, deviceName, jid)
+}
+
+// Annotate sets the tags and/or notes of a contact. Tags are trimmed and
+// de-duplicated, ignoring case.
+func (uc *ContactDirectoryUseCase) Annotate(ctx context.Context, deviceName, jid string, annotations domain.ContactAnnotations) (*domain.Contact, error) {
+	if annotations.Tags == nil && annotations.Notes == nil {
+		return nil, apperrors.NewValidationError("Nothing to update; set tags or notes")
+	}
+
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	if annotations.Tags != nil {
+		tags, err := normalizeContactTags(*annotations.Tags)
+		if err != nil {
+			return nil, err
+		}
+		annotations.Tags = &tags
+	}
+	if annotations.Notes != nil {
+		notes := strings.TrimSpace(*annotations.Notes)
+		if utf8.RuneCountInString(notes) > maxContactNotes {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Notes must be at most %d characters", maxContactNotes))
+		}
+		annotations.Notes = &notes
+	}
+
+	contact, err := uc.contacts.Annotate(ctx, deviceName, jid, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    jid,
+	}).Success("Contact updated")
+	return contact, nil
+}
+
+// Tags lists the tags used in a device's contacts
+func (uc *ContactDirectoryUseCase) Tags(ctx context.Context, deviceName string) ([]string, error) {
+	return uc.contacts.Tags(ctx, deviceName)
+}
+
+// normalizeContactTags trims tags, drops empty ones and duplicates, and checks their number and length
+func normalizeContactTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxContactTagLength {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Tags must be at most %d characters", maxContactTagLength))
+		}
+		seen[strings.ToLower(tag)] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxContactTags {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("A contact can have at most %d tags", maxContactTags))
+	}
+	return normalized, nil
+}
@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"context"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// FollowPresenceUseCase handles choosing the contacts whose online status a device follows
+type FollowPresenceUseCase struct {
+	deviceRepo ports.DeviceRepository
+	manager    domain.WhatsAppManagerInterface
+	logger     *logger.Logger
+}
+
+// NewFollowPresenceUseCase creates a new FollowPresenceUseCase
+func NewFollowPresenceUseCase(deviceRepo ports.DeviceRepository, manager domain.WhatsAppManagerInterface) *FollowPresenceUseCase {
+	return &FollowPresenceUseCase{
+		deviceRepo: deviceRepo,
+		manager:    manager,
+		logger:     logger.New("FollowPresenceUseCase"),
+	}
+}
+
+// Follow subscribes a device to the presence of a contact, given as a JID or
+// phone number. The subscription is kept with the device and renewed whenever
+// it connects.
+func (uc *FollowPresenceUseCase) Follow(ctx context.Context, deviceName, jid string) (string, error) {
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return "", err
+	}
+	if err := client.SubscribePresence(ctx, jid); err != nil {
+		return "", err
+	}
+
+	device, err := uc.deviceRepo.FindByName(ctx, deviceName)
+	if err != nil {
+		return "", err
+	}
+	if !containsString(device.PresenceSubscriptions, jid) {
+		device.PresenceSubscriptions = append(device.PresenceSubscriptions, jid)
+		if err := uc.deviceRepo.Update(ctx, device); err != nil {
+			return "", err
+		}
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    jid,
+	}).Success("Following contact presence")
+	return jid, nil
+}
+
+// Unfollow stops a device from following the presence of a contact
+func (uc *FollowPresenceUseCase) Unfollow(ctx context.Context, deviceName, jid string) error {
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return err
+	}
+
+	device, err := uc.deviceRepo.FindByName(ctx, deviceName)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := make([]string, 0, len(device.PresenceSubscriptions))
+	for _, followed := range device.PresenceSubscriptions {
+		if followed != jid {
+			subscriptions = append(subscriptions, followed)
+		}
+	}
+	if len(subscriptions) != len(device.PresenceSubscriptions) {
+		device.PresenceSubscriptions = subscriptions
+		if err := uc.deviceRepo.Update(ctx, device); err != nil {
+			return err
+		}
+	}
+
+	if client, exists := uc.manager.GetClient(deviceName); exists {
+		client.UnsubscribePresence(jid)
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    jid,
+	}).Info("Stopped following contact presence")
+	return nil
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// GetPresenceUseCase handles reading the online status of a followed contact
+type GetPresenceUseCase struct {
+	manager domain.WhatsAppManagerInterface
+}
+
+// NewGetPresenceUseCase creates a new GetPresenceUseCase
+func NewGetPresenceUseCase(manager domain.WhatsAppManagerInterface) *GetPresenceUseCase {
+	return &GetPresenceUseCase{manager: manager}
+}
+
+// Execute returns the last known presence of a contact the device follows
+func (uc *GetPresenceUseCase) Execute(ctx context.Context, deviceName, jid string) (*domain.ContactPresence, error) {
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+	}
+
+	presence, followed := client.GetContactPresence(jid)
+	if !followed {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Presence subscription for '%s'; follow the contact first", jid))
+	}
+	return presence, nil
+}
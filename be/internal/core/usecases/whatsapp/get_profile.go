@@ -0,0 +1,52 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// GetProfileUseCase handles reading how a device appears to its contacts
+type GetProfileUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewGetProfileUseCase creates a new GetProfileUseCase
+func NewGetProfileUseCase(manager domain.WhatsAppManagerInterface) *GetProfileUseCase {
+	return &GetProfileUseCase{
+		manager: manager,
+		logger:  logger.New("GetProfileUseCase"),
+	}
+}
+
+// Execute retrieves the display name, about text and picture ID of a device
+func (uc *GetProfileUseCase) Execute(ctx context.Context, deviceName string) (*domain.DeviceProfile, error) {
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := client.GetProfile(ctx)
+	if err != nil {
+		uc.logger.WithField("device", deviceName).Error("Failed to get profile: %v", err)
+		return nil, err
+	}
+	return profile, nil
+}
+
+// connectedClient returns the client of a device that is connected
+func connectedClient(manager domain.WhatsAppManagerInterface, deviceName string) (domain.WhatsAppClientInterface, error) {
+	client, exists := manager.GetClient(deviceName)
+	if !exists {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+	}
+	if !client.IsConnected() {
+		return nil, apperrors.New(apperrors.ErrorTypeConnection,
+			fmt.Sprintf("Device '%s' is not connected", deviceName))
+	}
+	return client, nil
+}
@@ -35,6 +35,10 @@ func (uc *GetQRCodeUseCase) Execute(ctx context.Context, deviceName string) (*do
 		client, err = uc.manager.CreateClient(ctx, deviceName)
 		if err != nil {
 			uc.logger.WithField("device", deviceName).Error("Failed to create client: %v", err)
+			// Keep the type of errors such as the lease conflict
+			if apperrors.IsAppError(err) {
+				return nil, err
+			}
 			return nil, apperrors.NewInternalError("Failed to create WhatsApp client", err)
 		}
 	}
@@ -3,6 +3,8 @@ package whatsapp
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
@@ -23,19 +25,20 @@ func NewListGroupsUseCase(manager domain.WhatsAppManagerInterface) *ListGroupsUs
 	}
 }
 
-// Execute retrieves groups from a WhatsApp device
-func (uc *ListGroupsUseCase) Execute(ctx context.Context, deviceName string) ([]domain.WhatsAppGroup, error) {
+// Execute retrieves the groups of a WhatsApp device matching the filter,
+// sorted by name, with the number of matching groups
+func (uc *ListGroupsUseCase) Execute(ctx context.Context, deviceName string, filter domain.GroupFilter, skip, limit int) ([]domain.WhatsAppGroup, int, error) {
 	uc.logger.WithField("device", deviceName).Info("Listing groups")
 
 	// Get client
 	client, exists := uc.manager.GetClient(deviceName)
 	if !exists {
-		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+		return nil, 0, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
 	}
 
 	// Check if connected
 	if !client.IsConnected() {
-		return nil, apperrors.New(apperrors.ErrorTypeConnection,
+		return nil, 0, apperrors.New(apperrors.ErrorTypeConnection,
 			fmt.Sprintf("Device '%s' is not connected", deviceName))
 	}
 
@@ -43,13 +46,44 @@ func (uc *ListGroupsUseCase) Execute(ctx context.Context, deviceName string) ([]
 	groups, err := client.GetGroups(ctx)
 	if err != nil {
 		uc.logger.WithField("device", deviceName).Error("Failed to get groups: %v", err)
-		return nil, apperrors.NewWhatsAppError("Failed to retrieve groups", err)
+		return nil, 0, apperrors.NewWhatsAppError("Failed to retrieve groups", err)
+	}
+
+	// Filter and sort
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+	matched := groups[:0]
+	for _, group := range groups {
+		if filter.AdminOnly && !group.IsAdmin {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(group.Name), search) {
+			continue
+		}
+		matched = append(matched, group)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := strings.ToLower(matched[i].Name), strings.ToLower(matched[j].Name)
+		if a != b {
+			return a < b
+		}
+		return matched[i].JID < matched[j].JID
+	})
+
+	// Paginate
+	total := len(matched)
+	if skip > total {
+		skip = total
+	}
+	end := total
+	if limit > 0 && skip+limit < total {
+		end = skip + limit
 	}
 
 	uc.logger.WithFields(map[string]interface{}{
 		"device": deviceName,
-		"count":  len(groups),
+		"count":  end - skip,
+		"total":  total,
 	}).Success("Groups retrieved")
 
-	return groups, nil
+	return matched[skip:end], total, nil
 }
@@ -0,0 +1,280 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// Limits enforced by the WhatsApp apps
+const (
+	maxGroupNameLength        = 25
+	maxGroupDescriptionLength = 2048
+)
+
+// ManageGroupsUseCase handles administering the groups of a device
+type ManageGroupsUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewManageGroupsUseCase creates a new ManageGroupsUseCase
+func NewManageGroupsUseCase(manager domain.WhatsAppManagerInterface) *ManageGroupsUseCase {
+	return &ManageGroupsUseCase{
+		manager: manager,
+		logger:  logger.New("ManageGroupsUseCase"),
+	}
+}
+
+// Create creates a group with the given members; members may be JIDs or phone
+// numbers in international format
+func (uc *ManageGroupsUseCase) Create(ctx context.Context, deviceName, name string, participants []string) (*domain.WhatsAppGroup, error) {
+	name, err := validateGroupName(name)
+	if err != nil {
+		return nil, err
+	}
+	jids, err := normalizeParticipants(participants)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := client.CreateGroup(ctx, name, jids)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"group":  group.JID,
+	}).Success("Group created")
+	return group, nil
+}
+
+// Get retrieves a group the device is a member of
+func (uc *ManageGroupsUseCase) Get(ctx context.Context, deviceName, groupJID string) (*domain.WhatsAppGroup, error) {
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetGroup(ctx, groupJID)
+}
+
+// UpdateParticipants adds, removes, promotes or demotes members of a group
+func (uc *ManageGroupsUseCase) UpdateParticipants(ctx context.Context, deviceName, groupJID string, participants []string, action domain.GroupParticipantAction) ([]domain.GroupParticipantResult, error) {
+	switch action {
+	case domain.GroupParticipantAdd, domain.GroupParticipantRemove, domain.GroupParticipantPromote, domain.GroupParticipantDemote:
+	default:
+		return nil, apperrors.NewValidationError("action must be add, remove, promote or demote")
+	}
+
+	jids, err := normalizeParticipants(participants)
+	if err != nil {
+		return nil, err
+	}
+	if len(jids) == 0 {
+		return nil, apperrors.NewValidationError("At least one participant is required")
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := client.UpdateGroupParticipants(ctx, groupJID, jids, action)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"group":  groupJID,
+		"action": action,
+		"failed": failed,
+	}).Success("Group participants updated")
+
+	return results, nil
+}
+
+// Update changes the name, description, announce and locked modes of a
+// group and returns the group
+func (uc *ManageGroupsUseCase) Update(ctx context.Context, deviceName, groupJID string, update domain.GroupUpdate) (*domain.WhatsAppGroup, error) {
+	if update.Name == nil && update.Description == nil && update.Announce == nil && update.Locked == nil {
+		return nil, apperrors.NewValidationError("Nothing to update; set name, description, announce or locked")
+	}
+
+	var name, description string
+	if update.Name != nil {
+		var err error
+		if name, err = validateGroupName(*update.Name); err != nil {
+			return nil, err
+		}
+	}
+	if update.Description != nil {
+		description = strings.TrimSpace(*update.Description)
+		if utf8.RuneCountInString(description) > maxGroupDescriptionLength {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Group description must be at most %d characters", maxGroupDescriptionLength))
+		}
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"group":  groupJID,
+	})
+	log.Info("Updating group")
+
+	if update.Name != nil {
+		if err := client.SetGroupName(ctx, groupJID, name); err != nil {
+			return nil, err
+		}
+	}
+	if update.Description != nil {
+		if err := client.SetGroupDescription(ctx, groupJID, description); err != nil {
+			return nil, err
+		}
+	}
+	if update.Announce != nil {
+		if err := client.SetGroupAnnounce(ctx, groupJID, *update.Announce); err != nil {
+			return nil, err
+		}
+	}
+	if update.Locked != nil {
+		if err := client.SetGroupLocked(ctx, groupJID, *update.Locked); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Success("Group updated")
+	return client.GetGroup(ctx, groupJID)
+}
+
+// SetPhoto sets a JPEG or PNG image as the group picture and returns the new
+// picture ID. The image is cropped and scaled like a profile picture.
+func (uc *ManageGroupsUseCase) SetPhoto(ctx context.Context, deviceName, groupJID string, data []byte) (string, error) {
+	photo, err := prepareProfilePhoto(data, "Group photo")
+	if err != nil {
+		return "", err
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return "", err
+	}
+
+	pictureID, err := client.SetGroupPhoto(ctx, groupJID, photo)
+	if err != nil {
+		return "", err
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device":     deviceName,
+		"group":      groupJID,
+		"picture_id": pictureID,
+	}).Success("Group photo set")
+	return pictureID, nil
+}
+
+// InviteLink returns the invite link of a group; reset revokes the current
+// link and creates a new one
+func (uc *ManageGroupsUseCase) InviteLink(ctx context.Context, deviceName, groupJID string, reset bool) (string, error) {
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return "", err
+	}
+
+	link, err := client.GetGroupInviteLink(ctx, groupJID, reset)
+	if err != nil {
+		return "", err
+	}
+
+	if reset {
+		uc.logger.WithFields(map[string]interface{}{
+			"device": deviceName,
+			"group":  groupJID,
+		}).Success("Group invite link reset")
+	}
+	return link, nil
+}
+
+// Join joins a group by its invite link or invite code and returns the group's JID
+func (uc *ManageGroupsUseCase) Join(ctx context.Context, deviceName, link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return "", apperrors.NewValidationError("Invite link is required")
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return "", err
+	}
+
+	return client.JoinGroupWithLink(ctx, link)
+}
+
+// Leave leaves a group
+func (uc *ManageGroupsUseCase) Leave(ctx context.Context, deviceName, groupJID string) error {
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return err
+	}
+
+	if err := client.LeaveGroup(ctx, groupJID); err != nil {
+		return err
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"group":  groupJID,
+	}).Success("Left group")
+	return nil
+}
+
+// validateGroupName trims a group name and checks its length
+func validateGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperrors.NewValidationError("Group name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > maxGroupNameLength {
+		return "", apperrors.NewValidationError(fmt.Sprintf("Group name must be at most %d characters", maxGroupNameLength))
+	}
+	return name, nil
+}
+
+// normalizeParticipants converts group members given as JIDs or phone
+// numbers to JIDs, dropping duplicates
+func normalizeParticipants(participants []string) ([]string, error) {
+	seen := make(map[string]bool, len(participants))
+	jids := make([]string, 0, len(participants))
+	for _, participant := range participants {
+		jid, err := normalizeContactJID(participant)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[jid] {
+			seen[jid] = true
+			jids = append(jids, jid)
+		}
+	}
+	return jids, nil
+}
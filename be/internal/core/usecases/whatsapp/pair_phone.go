@@ -0,0 +1,75 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// pairingCodeTTL is how long the login connection stays open for a link code
+const pairingCodeTTL = 160 * time.Second
+
+// PairPhoneUseCase handles pairing a device by phone-number link code
+type PairPhoneUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewPairPhoneUseCase creates a new PairPhoneUseCase
+func NewPairPhoneUseCase(manager domain.WhatsAppManagerInterface) *PairPhoneUseCase {
+	return &PairPhoneUseCase{
+		manager: manager,
+		logger:  logger.New("PairPhoneUseCase"),
+	}
+}
+
+// Execute requests a link code that pairs the device with the given phone number.
+// Pairing completes when the code is entered on the phone, which is reported on
+// the device event stream.
+func (uc *PairPhoneUseCase) Execute(ctx context.Context, deviceName, phone string) (*domain.PairingCodeResponse, error) {
+	uc.logger.WithField("device", deviceName).Info("Requesting pairing code")
+
+	phone, err := normalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get client
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		// Create new client if not exists
+		client, err = uc.manager.CreateClient(ctx, deviceName)
+		if err != nil {
+			uc.logger.WithField("device", deviceName).Error("Failed to create client: %v", err)
+			// Keep the type of errors such as the lease conflict
+			if apperrors.IsAppError(err) {
+				return nil, err
+			}
+			return nil, apperrors.NewInternalError("Failed to create WhatsApp client", err)
+		}
+	}
+
+	// Check if already paired
+	if client.GetJID() != "" {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' is already paired", deviceName))
+	}
+
+	code, err := client.PairPhone(ctx, phone)
+	if err != nil {
+		uc.logger.WithField("device", deviceName).Error("Failed to request pairing code: %v", err)
+		return nil, err
+	}
+
+	uc.logger.WithField("device", deviceName).Success("Pairing code generated")
+	return &domain.PairingCodeResponse{
+		DeviceName: deviceName,
+		Phone:      phone,
+		Code:       code,
+		ExpiresAt:  time.Now().Add(pairingCodeTTL),
+	}, nil
+}
@@ -0,0 +1,225 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// maxProfilePictureSize caps the download of a profile picture
+const maxProfilePictureSize = 5 << 20
+
+// ProfilePictureUseCase handles fetching profile pictures of contacts and
+// groups and keeping a local copy of them.
+//
+// Copies are kept per device, since a picture hidden from one device may be
+// visible to another, under <cacheDir>/<device>/<jid>_<type>_<id>.jpg. A copy
+// younger than ttl is served without asking WhatsApp; an older one is checked
+// against its picture ID and downloaded again only when the picture changed.
+type ProfilePictureUseCase struct {
+	manager  domain.WhatsAppManagerInterface
+	cacheDir string
+	ttl      time.Duration
+	http     *http.Client
+	logger   *logger.Logger
+}
+
+// NewProfilePictureUseCase creates a new ProfilePictureUseCase
+func NewProfilePictureUseCase(manager domain.WhatsAppManagerInterface, cacheDir string, ttl time.Duration) *ProfilePictureUseCase {
+	return &ProfilePictureUseCase{
+		manager:  manager,
+		cacheDir: cacheDir,
+		ttl:      ttl,
+		http:     &http.Client{Timeout: 30 * time.Second},
+		logger:   logger.New("ProfilePictureUseCase"),
+	}
+}
+
+// Execute returns the profile picture of a user or group with its cached copy.
+// The JID may also be given as a phone number in international format. With
+// refresh, WhatsApp is asked whether the picture changed however recent the
+// cached copy is.
+func (uc *ProfilePictureUseCase) Execute(ctx context.Context, deviceName, jid string, preview, refresh bool) (*domain.ProfilePicture, error) {
+	jid, err := normalizeContactJID(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	pictureType := "image"
+	if preview {
+		pictureType = "preview"
+	}
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"jid":    jid,
+		"type":   pictureType,
+	})
+
+	dir := filepath.Join(uc.cacheDir, fileSafe(deviceName))
+	prefix := fmt.Sprintf("%s_%s_", fileSafe(jid), pictureType)
+	cached := uc.findCached(dir, prefix, jid, pictureType)
+
+	if cached != nil && !refresh && time.Since(cached.FetchedAt) < uc.ttl {
+		return cached, nil
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		if cached != nil {
+			log.Warn("Serving stale profile picture: %v", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	existingID := ""
+	if cached != nil {
+		existingID = cached.ID
+	}
+
+	picture, err := client.GetProfilePicture(ctx, jid, preview, existingID)
+	if err != nil {
+		if apperrors.GetAppError(err).Type == apperrors.ErrorTypeNotFound {
+			// The picture was removed
+			uc.removeCached(dir, prefix, "")
+		}
+		return nil, err
+	}
+
+	if picture == nil {
+		// Unchanged; start the cached copy's ttl again
+		now := time.Now()
+		if err := os.Chtimes(cached.LocalPath, now, now); err != nil {
+			log.Warn("Failed to touch cached profile picture: %v", err)
+		}
+		cached.FetchedAt = now
+		return cached, nil
+	}
+
+	path := filepath.Join(dir, prefix+fileSafe(picture.ID)+".jpg")
+	if err := uc.download(ctx, picture.URL, path); err != nil {
+		log.Error("Failed to download profile picture: %v", err)
+		return nil, err
+	}
+	uc.removeCached(dir, prefix, path)
+
+	picture.LocalPath = path
+	log.WithField("picture_id", picture.ID).Success("Profile picture cached")
+	return picture, nil
+}
+
+// findCached returns the cached copy with the given file name prefix, if any
+func (uc *ProfilePictureUseCase) findCached(dir, prefix, jid, pictureType string) *domain.ProfilePicture {
+	matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*.jpg"))
+	if len(matches) == 0 {
+		return nil
+	}
+
+	path := matches[len(matches)-1]
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	return &domain.ProfilePicture{
+		JID:       jid,
+		ID:        strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".jpg"),
+		Type:      pictureType,
+		LocalPath: path,
+		FetchedAt: info.ModTime(),
+	}
+}
+
+// removeCached deletes the cached copies with the given prefix except keep
+func (uc *ProfilePictureUseCase) removeCached(dir, prefix, keep string) {
+	matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*.jpg"))
+	for _, path := range matches {
+		if path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			uc.logger.Warn("Failed to remove cached profile picture %s: %v", path, err)
+		}
+	}
+}
+
+// download saves the image at url to path
+func (uc *ProfilePictureUseCase) download(ctx context.Context, url, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to create download request", err)
+	}
+
+	resp, err := uc.http.Do(req)
+	if err != nil {
+		return apperrors.NewWhatsAppError("Failed to download profile picture", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apperrors.NewWhatsAppError("Failed to download profile picture",
+			fmt.Errorf("unexpected status %s", resp.Status))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return apperrors.NewInternalError("Failed to create profile picture directory", err)
+	}
+
+	// Write next to the target so that readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return apperrors.NewInternalError("Failed to create profile picture file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, io.LimitReader(resp.Body, maxProfilePictureSize))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return apperrors.NewWhatsAppError("Failed to download profile picture", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return apperrors.NewInternalError("Failed to save profile picture", err)
+	}
+	return nil
+}
+
+// normalizeContactJID accepts a JID or a phone number in international or national format
+func normalizeContactJID(jid string) (string, error) {
+	jid = strings.TrimSpace(jid)
+	if strings.Contains(jid, "@") {
+		return jid, nil
+	}
+
+	phone, err := normalizePhone(jid)
+	if err != nil {
+		return "", err
+	}
+	return phone + "@s.whatsapp.net", nil
+}
+
+// fileSafe replaces characters that may not appear in a file name
+func fileSafe(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '@' || r == '.' || r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+}
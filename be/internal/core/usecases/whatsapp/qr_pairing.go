@@ -0,0 +1,75 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// QRPairingUseCase handles pairing a device by scanning QR codes
+type QRPairingUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewQRPairingUseCase creates a new QRPairingUseCase
+func NewQRPairingUseCase(manager domain.WhatsAppManagerInterface) *QRPairingUseCase {
+	return &QRPairingUseCase{
+		manager: manager,
+		logger:  logger.New("QRPairingUseCase"),
+	}
+}
+
+// Start returns the pairing session of a device that is not paired yet,
+// starting one unless a session is already waiting for a scan
+func (uc *QRPairingUseCase) Start(ctx context.Context, deviceName string) (*domain.PairingSession, error) {
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		var err error
+		client, err = uc.manager.CreateClient(ctx, deviceName)
+		if err != nil {
+			uc.logger.WithField("device", deviceName).Error("Failed to create client: %v", err)
+			return nil, err
+		}
+	}
+
+	if client.GetJID() != "" {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict,
+			fmt.Sprintf("Device '%s' is already paired", deviceName))
+	}
+
+	session, err := client.StartPairing(ctx)
+	if err != nil {
+		uc.logger.WithField("device", deviceName).Error("Failed to start pairing: %v", err)
+		return nil, err
+	}
+	return session, nil
+}
+
+// Get returns the latest pairing session of a device, active or ended
+func (uc *QRPairingUseCase) Get(deviceName string) (*domain.PairingSession, error) {
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+	}
+
+	session, ok := client.GetPairing()
+	if !ok {
+		return nil, apperrors.NewNotFoundError("Pairing session")
+	}
+	return session, nil
+}
+
+// Wait returns the pairing session once it has a code newer than the after-th
+// or has ended, or as it is when ctx is done
+func (uc *QRPairingUseCase) Wait(ctx context.Context, deviceName string, after int) (*domain.PairingSession, error) {
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+	}
+
+	return client.WaitPairing(ctx, after)
+}
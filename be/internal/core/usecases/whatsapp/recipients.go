@@ -0,0 +1,67 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/phone"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/validator"
+)
+
+// normalizePhone converts a phone number in international or national
+// format to its international digits, e.g. "0812-3456-7890" and
+// "+62 812-3456-7890" both become "6281234567890"
+func normalizePhone(number string) (string, error) {
+	normalized, err := phone.Normalize(number)
+	if err != nil {
+		return "", apperrors.NewValidationError(fmt.Sprintf("Invalid phone number %q: must be in international format, e.g. +62812..., or a national number", strings.TrimSpace(number)))
+	}
+	return normalized, nil
+}
+
+// resolveRecipient returns the JID to send a message to. Groups may be given
+// by ID without the @g.us server. People may be given by JID or by phone
+// number in international or national format; phone numbers are checked
+// with WhatsApp and resolved to the JID WhatsApp knows them by.
+func resolveRecipient(ctx context.Context, client domain.WhatsAppClientInterface, to string, receiverType domain.ReceiverType) (string, error) {
+	to = strings.TrimSpace(to)
+
+	user, server, isJID := strings.Cut(to, "@")
+	if receiverType == domain.ReceiverGroup || server == "g.us" {
+		if !isJID {
+			to += "@g.us"
+		}
+		if !validator.ValidateWhatsAppJID(to) {
+			return "", apperrors.NewValidationError(fmt.Sprintf("Invalid group JID: %s", to))
+		}
+		return to, nil
+	}
+
+	switch {
+	case !isJID:
+		user = to
+	case server == "lid":
+		// Hidden users are only known by their LID
+		return to, nil
+	case server != "s.whatsapp.net":
+		return "", apperrors.NewValidationError(fmt.Sprintf("Invalid WhatsApp JID: %s", to))
+	}
+
+	number, err := normalizePhone(user)
+	if err != nil {
+		return "", err
+	}
+
+	checks, err := client.IsOnWhatsApp(ctx, []string{number})
+	if err != nil {
+		return "", err
+	}
+	if len(checks) == 0 || !checks[0].Registered {
+		return "", apperrors.NewValidationError(fmt.Sprintf("+%s is not registered on WhatsApp", number)).
+			WithDetails("phone", number)
+	}
+	return checks[0].JID, nil
+}
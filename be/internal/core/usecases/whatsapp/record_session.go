@@ -0,0 +1,244 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// sessionQueueSize is the number of transitions waiting to be persisted;
+	// further transitions are dropped until the queue drains
+	sessionQueueSize = 256
+
+	// sessionWriteTimeout bounds the repository writes for a single transition
+	sessionWriteTimeout = 10 * time.Second
+
+	// sessionHeartbeatInterval is how often connected devices are marked as
+	// still connected
+	sessionHeartbeatInterval = time.Minute
+
+	// sessionStaleAfter is how long without a heartbeat before a session
+	// persisted as connected is taken to have been left behind by a process
+	// that stopped without recording the disconnect
+	sessionStaleAfter = 3 * sessionHeartbeatInterval
+)
+
+// RecordSessionUseCase persists connection state transitions: it appends
+// them to the device's connection history and keeps its session up to date.
+// Transitions are queued and written in order on a background goroutine, so
+// that the connection code recording them never waits for the database.
+//
+// A process that crashes records no disconnect, so connected devices get a
+// heartbeat. A connected run that was never closed is closed at its last
+// heartbeat, when the device's next run starts or once the heartbeat is stale.
+type RecordSessionUseCase struct {
+	sessions ports.WhatsAppSessionRepository
+	manager  domain.WhatsAppManagerInterface
+	location func(deviceName string) string
+	logger   *logger.Logger
+
+	queue  chan domain.ConnectionTransition
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRecordSessionUseCase creates a new RecordSessionUseCase. location
+// describes where the session store of a device is kept.
+func NewRecordSessionUseCase(sessions ports.WhatsAppSessionRepository, manager domain.WhatsAppManagerInterface, location func(deviceName string) string) *RecordSessionUseCase {
+	return &RecordSessionUseCase{
+		sessions: sessions,
+		manager:  manager,
+		location: location,
+		logger:   logger.New("RecordSessionUseCase"),
+		queue:    make(chan domain.ConnectionTransition, sessionQueueSize),
+	}
+}
+
+// Start begins persisting queued transitions in the background
+func (uc *RecordSessionUseCase) Start(ctx context.Context) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.cancel != nil {
+		return
+	}
+
+	ctx, uc.cancel = context.WithCancel(ctx)
+	uc.done = make(chan struct{})
+
+	go uc.loop(ctx, uc.done)
+	uc.logger.Info("Session recorder started")
+}
+
+// Stop persists the transitions already queued and stops the recorder
+func (uc *RecordSessionUseCase) Stop() {
+	uc.mu.Lock()
+	cancel, done := uc.cancel, uc.done
+	uc.cancel = nil
+	uc.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	uc.logger.Info("Session recorder stopped")
+}
+
+// Handle queues a transition without blocking
+func (uc *RecordSessionUseCase) Handle(transition domain.ConnectionTransition) {
+	select {
+	case uc.queue <- transition:
+	default:
+		uc.logger.WithField("device", transition.DeviceName).Warn("Session queue full, connection transition dropped")
+	}
+}
+
+// loop persists transitions and heartbeats until ctx is cancelled, then
+// drains the queue
+func (uc *RecordSessionUseCase) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	// Close the runs of a process that stopped before this one started
+	uc.heartbeat()
+
+	ticker := time.NewTicker(sessionHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case transition := <-uc.queue:
+			uc.Execute(transition)
+		case <-ticker.C:
+			uc.heartbeat()
+		case <-ctx.Done():
+			for {
+				select {
+				case transition := <-uc.queue:
+					uc.Execute(transition)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// heartbeat marks the devices connected here as still connected, and closes
+// the connected runs whose heartbeat is stale
+func (uc *RecordSessionUseCase) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionWriteTimeout)
+	defer cancel()
+
+	now := time.Now()
+	var connected []string
+	for _, deviceName := range uc.manager.ListClients() {
+		if client, exists := uc.manager.GetClient(deviceName); exists && client.IsConnected() {
+			connected = append(connected, deviceName)
+		}
+	}
+	if err := uc.sessions.Heartbeat(ctx, connected, now); err != nil {
+		uc.logger.Error("Failed to record session heartbeat: %v", err)
+	}
+
+	stale, err := uc.sessions.FindStaleConnected(ctx, now.Add(-sessionStaleAfter))
+	if err != nil {
+		uc.logger.Error("Failed to find stale sessions: %v", err)
+		return
+	}
+	for _, session := range stale {
+		uc.Execute(lostConnection(session, now))
+	}
+}
+
+// lostConnection returns the transition that closes a connected run left
+// open, at the last time the device was seen connected, and no later than t
+func lostConnection(session *domain.WhatsAppSession, t time.Time) domain.ConnectionTransition {
+	lastSeen := t
+	switch {
+	case session.HeartbeatAt != nil:
+		lastSeen = *session.HeartbeatAt
+	case session.LastConnected != nil:
+		lastSeen = *session.LastConnected
+	}
+	if lastSeen.After(t) {
+		lastSeen = t
+	}
+
+	return domain.ConnectionTransition{
+		DeviceName: session.DeviceName,
+		From:       domain.StatusConnected,
+		To:         domain.StatusDisconnected,
+		Reason:     "no disconnect was recorded; closed at the last heartbeat",
+		Timestamp:  lastSeen,
+	}
+}
+
+// Execute persists a single transition and applies it to the device's session
+func (uc *RecordSessionUseCase) Execute(transition domain.ConnectionTransition) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionWriteTimeout)
+	defer cancel()
+
+	log := uc.logger.WithFields(map[string]interface{}{
+		"device": transition.DeviceName,
+		"to":     transition.To,
+	})
+
+	session, err := uc.sessions.FindByDeviceName(ctx, transition.DeviceName)
+	if err != nil {
+		if appErr := apperrors.GetAppError(err); appErr.Type != apperrors.ErrorTypeNotFound {
+			log.Error("Failed to load session: %v", err)
+			if err := uc.sessions.RecordTransition(ctx, transition); err != nil {
+				log.Error("Failed to record connection transition: %v", err)
+			}
+			return
+		}
+		session = &domain.WhatsAppSession{
+			DeviceName:  transition.DeviceName,
+			StoreDBPath: uc.location(transition.DeviceName),
+		}
+	}
+
+	// The last connected run ended without a transition, e.g. the process
+	// crashed or the device moved here from a replica that did
+	if session.Status == domain.StatusConnected && transition.From != domain.StatusConnected {
+		lost := lostConnection(session, transition.Timestamp)
+		log.Warn("Closing connected run left open since %s", lost.Timestamp.Format(time.RFC3339))
+		if err := uc.sessions.RecordTransition(ctx, lost); err != nil {
+			log.Error("Failed to record connection transition: %v", err)
+		}
+		session.LastDisconnected = &lost.Timestamp
+	}
+
+	if err := uc.sessions.RecordTransition(ctx, transition); err != nil {
+		log.Error("Failed to record connection transition: %v", err)
+	}
+
+	timestamp := transition.Timestamp
+	switch {
+	case transition.To == domain.StatusConnected:
+		session.LastConnected = &timestamp
+		session.HeartbeatAt = &timestamp
+		if client, exists := uc.manager.GetClient(transition.DeviceName); exists {
+			session.JID = client.GetJID()
+		}
+	case transition.From == domain.StatusConnected:
+		session.LastDisconnected = &timestamp
+	}
+	if transition.To == domain.StatusLoggedOut {
+		session.JID = ""
+	}
+	session.Status = transition.To
+
+	if err := uc.sessions.Save(ctx, session); err != nil {
+		log.Error("Failed to save session: %v", err)
+	}
+}
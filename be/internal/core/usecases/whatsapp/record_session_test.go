@@ -0,0 +1,124 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// memorySessions keeps one device's session and history in memory
+type memorySessions struct {
+	ports.WhatsAppSessionRepository
+
+	session     *domain.WhatsAppSession
+	transitions []domain.ConnectionTransition
+}
+
+func (m *memorySessions) FindByDeviceName(ctx context.Context, deviceName string) (*domain.WhatsAppSession, error) {
+	if m.session == nil {
+		return nil, apperrors.NewNotFoundError("Session")
+	}
+	copied := *m.session
+	return &copied, nil
+}
+
+func (m *memorySessions) Save(ctx context.Context, session *domain.WhatsAppSession) error {
+	m.session = session
+	return nil
+}
+
+func (m *memorySessions) RecordTransition(ctx context.Context, transition domain.ConnectionTransition) error {
+	m.transitions = append(m.transitions, transition)
+	return nil
+}
+
+func (m *memorySessions) FindLastTransitionBefore(ctx context.Context, deviceName string, t time.Time) (*domain.ConnectionTransition, error) {
+	var last *domain.ConnectionTransition
+	for i, transition := range m.transitions {
+		if transition.Timestamp.Before(t) {
+			last = &m.transitions[i]
+		}
+	}
+	return last, nil
+}
+
+func (m *memorySessions) FindTransitions(ctx context.Context, deviceName string, from, to time.Time) ([]domain.ConnectionTransition, error) {
+	var found []domain.ConnectionTransition
+	for _, transition := range m.transitions {
+		if !transition.Timestamp.Before(from) && transition.Timestamp.Before(to) {
+			found = append(found, transition)
+		}
+	}
+	return found, nil
+}
+
+// noClients is a manager without loaded clients
+type noClients struct {
+	domain.WhatsAppManagerInterface
+}
+
+func (noClients) GetClient(deviceName string) (domain.WhatsAppClientInterface, bool) {
+	return nil, false
+}
+
+func TestRecordSessionClosesRunLeftOpen(t *testing.T) {
+	start := time.Now().Add(-2 * time.Hour)
+	crashed := start.Add(30 * time.Minute)
+	restarted := start.Add(time.Hour)
+
+	sessions := &memorySessions{}
+	recorder := NewRecordSessionUseCase(sessions, noClients{}, func(string) string { return "" })
+
+	recorder.Execute(domain.ConnectionTransition{
+		DeviceName: "officer", From: domain.StatusConnecting, To: domain.StatusConnected, Timestamp: start,
+	})
+	sessions.session.HeartbeatAt = &crashed
+
+	// The process crashed; the next one connects the device without having
+	// recorded a disconnect
+	recorder.Execute(domain.ConnectionTransition{
+		DeviceName: "officer", From: domain.StatusConnecting, To: domain.StatusConnected, Timestamp: restarted,
+	})
+
+	if len(sessions.transitions) != 3 {
+		t.Fatalf("recorded %d transitions, want 3: %+v", len(sessions.transitions), sessions.transitions)
+	}
+	lost := sessions.transitions[1]
+	if lost.From != domain.StatusConnected || lost.To != domain.StatusDisconnected || !lost.Timestamp.Equal(crashed) {
+		t.Errorf("closing transition = %+v, want connected to disconnected at %v", lost, crashed)
+	}
+
+	status := NewSessionStatusUseCase(sessions, noClients{})
+	report, err := status.Uptime(context.Background(), "officer", start, restarted)
+	if err != nil {
+		t.Fatalf("Uptime: %v", err)
+	}
+	if report.Connected != 30*time.Minute {
+		t.Errorf("Connected = %v, want 30m", report.Connected)
+	}
+}
+
+func TestUptimeEndsStaleRunAtHeartbeat(t *testing.T) {
+	now := time.Now()
+	connected := now.Add(-time.Hour)
+	heartbeat := now.Add(-40 * time.Minute)
+
+	sessions := &memorySessions{
+		session: &domain.WhatsAppSession{DeviceName: "officer", Status: domain.StatusConnected, HeartbeatAt: &heartbeat},
+		transitions: []domain.ConnectionTransition{
+			{DeviceName: "officer", From: domain.StatusConnecting, To: domain.StatusConnected, Timestamp: connected},
+		},
+	}
+
+	report, err := NewSessionStatusUseCase(sessions, noClients{}).Uptime(context.Background(), "officer", now.Add(-2*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Uptime: %v", err)
+	}
+	if report.Connected != 20*time.Minute {
+		t.Errorf("Connected = %v, want 20m", report.Connected)
+	}
+}
@@ -3,11 +3,12 @@ package whatsapp
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
-	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/validator"
 )
 
 // SendMessageUseCase handles message sending logic
@@ -32,11 +33,6 @@ func (uc *SendMessageUseCase) Execute(ctx context.Context, params domain.SendMes
 		"type":   params.MessageType,
 	}).Info("Sending message")
 
-	// Validate JID
-	if !validator.ValidateWhatsAppJID(params.To) {
-		return apperrors.NewValidationError(fmt.Sprintf("Invalid WhatsApp JID: %s", params.To))
-	}
-
 	// Get client
 	client, exists := uc.manager.GetClient(params.DeviceName)
 	if !exists {
@@ -49,6 +45,13 @@ func (uc *SendMessageUseCase) Execute(ctx context.Context, params domain.SendMes
 			fmt.Sprintf("Device '%s' is not connected", params.DeviceName))
 	}
 
+	// Resolve phone numbers to the JID WhatsApp knows them by
+	to, err := resolveRecipient(ctx, client, params.To, params.ReceiverType)
+	if err != nil {
+		return err
+	}
+	params.To = to
+
 	// Send typing indicator if enabled
 	if params.Typing {
 		if err := client.SendTyping(ctx, params.To, true); err != nil {
@@ -57,10 +60,16 @@ func (uc *SendMessageUseCase) Execute(ctx context.Context, params domain.SendMes
 		defer func() {
 			_ = client.SendTyping(ctx, params.To, false)
 		}()
+
+		// Keep typing for a moment before sending, like MessageService does
+		select {
+		case <-time.After(typingDelay()):
+		case <-ctx.Done():
+			return apperrors.NewWhatsAppError("Failed to send message", ctx.Err())
+		}
 	}
 
 	// Send message based on type
-	var err error
 	switch params.MessageType {
 	case domain.MessageTypeText:
 		err = client.SendTextMessage(ctx, params.To, params.Message, params.ReceiverType)
@@ -87,3 +96,9 @@ func (uc *SendMessageUseCase) Execute(ctx context.Context, params domain.SendMes
 
 	return nil
 }
+
+// typingDelay returns how long to show "typing..." before a message: 0.7 to
+// 1.7 seconds, so that messages sent in a row are not evenly spaced
+func typingDelay() time.Duration {
+	return time.Duration(rand.Intn(1000)+700) * time.Millisecond
+}
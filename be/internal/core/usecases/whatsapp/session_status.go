@@ -0,0 +1,133 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// uptimeWindows are the periods reported with a device's status
+var uptimeWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour}
+
+// SessionStatusUseCase reports a device's persisted session, live connection
+// state and uptime
+type SessionStatusUseCase struct {
+	sessions ports.WhatsAppSessionRepository
+	manager  domain.WhatsAppManagerInterface
+	logger   *logger.Logger
+}
+
+// NewSessionStatusUseCase creates a new SessionStatusUseCase
+func NewSessionStatusUseCase(sessions ports.WhatsAppSessionRepository, manager domain.WhatsAppManagerInterface) *SessionStatusUseCase {
+	return &SessionStatusUseCase{
+		sessions: sessions,
+		manager:  manager,
+		logger:   logger.New("SessionStatusUseCase"),
+	}
+}
+
+// Execute returns the session, live status and uptime over the last day and week of a device
+func (uc *SessionStatusUseCase) Execute(ctx context.Context, deviceName string) (*domain.SessionStatus, error) {
+	client, exists := uc.manager.GetClient(deviceName)
+	if !exists {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("Device '%s'", deviceName))
+	}
+
+	status := &domain.SessionStatus{
+		Live: domain.ConnectionInfo{
+			DeviceName:  deviceName,
+			Status:      client.GetConnectionStatus(),
+			JID:         client.GetJID(),
+			IsConnected: client.IsConnected(),
+		},
+		Uptime: make([]domain.UptimeReport, 0, len(uptimeWindows)),
+	}
+
+	session, err := uc.sessions.FindByDeviceName(ctx, deviceName)
+	if err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+		return nil, err
+	}
+	status.Session = session
+
+	now := time.Now()
+	for _, window := range uptimeWindows {
+		report, err := uc.Uptime(ctx, deviceName, now.Add(-window), now)
+		if err != nil {
+			return nil, err
+		}
+		report.Transitions = nil
+		status.Uptime = append(status.Uptime, *report)
+	}
+
+	return status, nil
+}
+
+// Uptime computes how long a device was connected within [from, to) from its
+// connection history. The state at from is taken from the last transition
+// before it; a device without earlier history counts as disconnected.
+func (uc *SessionStatusUseCase) Uptime(ctx context.Context, deviceName string, from, to time.Time) (*domain.UptimeReport, error) {
+	if !from.Before(to) {
+		return nil, apperrors.NewValidationError("from must be before to")
+	}
+
+	previous, err := uc.sessions.FindLastTransitionBefore(ctx, deviceName, from)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := uc.sessions.FindTransitions(ctx, deviceName, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	connected := previous != nil && previous.To == domain.StatusConnected
+	since := from
+
+	var total time.Duration
+	for _, transition := range transitions {
+		if connected {
+			total += transition.Timestamp.Sub(since)
+		}
+		connected = transition.To == domain.StatusConnected
+		since = transition.Timestamp
+	}
+
+	// The period may end in the future, e.g. "today"
+	end := to
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	if connected {
+		// A run whose heartbeat is stale ended at the last heartbeat, even if
+		// its closing transition is not recorded yet
+		openEnd := end
+		session, err := uc.sessions.FindByDeviceName(ctx, deviceName)
+		if err != nil && apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			return nil, err
+		}
+		if session != nil && session.HeartbeatAt != nil && openEnd.Sub(*session.HeartbeatAt) > sessionStaleAfter {
+			openEnd = *session.HeartbeatAt
+		}
+		if openEnd.After(since) {
+			total += openEnd.Sub(since)
+		}
+	}
+
+	report := &domain.UptimeReport{
+		DeviceName:  deviceName,
+		From:        from,
+		To:          to,
+		Connected:   total,
+		Transitions: transitions,
+	}
+	if end.After(from) {
+		report.Ratio = float64(total) / float64(end.Sub(from))
+	}
+
+	return report, nil
+}
@@ -0,0 +1,111 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // Register the PNG decoder
+	"strings"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// profilePhotoSize is the largest side WhatsApp accepts for profile pictures
+const profilePhotoSize = 640
+
+// SetProfilePhotoUseCase handles changing the profile picture of a device
+type SetProfilePhotoUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewSetProfilePhotoUseCase creates a new SetProfilePhotoUseCase
+func NewSetProfilePhotoUseCase(manager domain.WhatsAppManagerInterface) *SetProfilePhotoUseCase {
+	return &SetProfilePhotoUseCase{
+		manager: manager,
+		logger:  logger.New("SetProfilePhotoUseCase"),
+	}
+}
+
+// Execute sets a JPEG or PNG image as the profile picture of a connected device
+// and returns the new picture ID. The image is cropped to a centred square and
+// scaled down to what WhatsApp accepts.
+func (uc *SetProfilePhotoUseCase) Execute(ctx context.Context, deviceName string, data []byte) (string, error) {
+	photo, err := prepareProfilePhoto(data, "Profile picture")
+	if err != nil {
+		return "", err
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return "", err
+	}
+
+	log := uc.logger.WithField("device", deviceName)
+	log.Info("Setting profile picture")
+
+	pictureID, err := client.SetProfilePicture(ctx, photo)
+	if err != nil {
+		return "", err
+	}
+
+	log.WithField("picture_id", pictureID).Success("Profile picture set")
+	return pictureID, nil
+}
+
+// prepareProfilePhoto crops an image to a square of at most profilePhotoSize
+// pixels and encodes it as JPEG; subject names the picture in errors
+func prepareProfilePhoto(data []byte, subject string) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewValidationError(subject + " must be a JPEG or PNG image")
+	}
+
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	if side < 192 {
+		return nil, apperrors.NewValidationError(subject + " must be at least 192x192 pixels")
+	}
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	size := side
+	if size > profilePhotoSize {
+		size = profilePhotoSize
+	}
+
+	// Average the source pixels that fall into each target pixel
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := crop.Min.Y + y*side/size
+		y1 := crop.Min.Y + (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0 := crop.Min.X + x*side/size
+			x1 := crop.Min.X + (x+1)*side/size
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, _ := src.At(sx, sy).RGBA()
+					r, g, b, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: 0xffff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, apperrors.NewInternalError("Failed to encode "+strings.ToLower(subject), err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,252 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// contactQueueSize is the number of contact changes waiting to be
+	// persisted; further changes are dropped until the queue drains
+	contactQueueSize = 1024
+
+	// contactWriteTimeout bounds the repository writes for a single change or full sync
+	contactWriteTimeout = time.Minute
+)
+
+// contactJob is a queued contact change, or a full sync when update is nil
+type contactJob struct {
+	deviceName string
+	update     *domain.ContactUpdate
+}
+
+// SyncContactsUseCase keeps the contact directories of devices in step with
+// WhatsApp. A device's contact store is copied in full the first time it
+// connects and whenever the phone syncs its contact list again; changes to
+// single contacts in between are applied as they arrive. Like the session
+// recorder, changes are queued and written on a background goroutine.
+type SyncContactsUseCase struct {
+	contacts ports.ContactRepository
+	manager  domain.WhatsAppManagerInterface
+	logger   *logger.Logger
+
+	queue  chan contactJob
+	mu     sync.Mutex
+	synced map[string]bool // Devices copied in full since starting
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSyncContactsUseCase creates a new SyncContactsUseCase
+func NewSyncContactsUseCase(contacts ports.ContactRepository, manager domain.WhatsAppManagerInterface) *SyncContactsUseCase {
+	return &SyncContactsUseCase{
+		contacts: contacts,
+		manager:  manager,
+		logger:   logger.New("SyncContactsUseCase"),
+		queue:    make(chan contactJob, contactQueueSize),
+		synced:   make(map[string]bool),
+	}
+}
+
+// Start begins persisting queued changes in the background
+func (uc *SyncContactsUseCase) Start(ctx context.Context) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.cancel != nil {
+		return
+	}
+
+	ctx, uc.cancel = context.WithCancel(ctx)
+	uc.done = make(chan struct{})
+
+	go uc.loop(ctx, uc.done)
+	uc.logger.Info("Contact sync started")
+}
+
+// Stop persists the changes already queued and stops the sync
+func (uc *SyncContactsUseCase) Stop() {
+	uc.mu.Lock()
+	cancel, done := uc.cancel, uc.done
+	uc.cancel = nil
+	uc.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	uc.logger.Info("Contact sync stopped")
+}
+
+// HandleConnection queues a full sync the first time a device connects
+func (uc *SyncContactsUseCase) HandleConnection(deviceName string, connected bool) {
+	if !connected {
+		return
+	}
+
+	uc.mu.Lock()
+	first := !uc.synced[deviceName]
+	uc.synced[deviceName] = true
+	uc.mu.Unlock()
+
+	if first {
+		uc.enqueue(contactJob{deviceName: deviceName})
+	}
+}
+
+// HandleSynced queues a full sync after the phone synced the contact list
+func (uc *SyncContactsUseCase) HandleSynced(deviceName string) {
+	uc.enqueue(contactJob{deviceName: deviceName})
+}
+
+// HandleUpdate queues a change to a single contact
+func (uc *SyncContactsUseCase) HandleUpdate(deviceName string, update domain.ContactUpdate) {
+	uc.enqueue(contactJob{deviceName: deviceName, update: &update})
+}
+
+// enqueue queues a job without blocking
+func (uc *SyncContactsUseCase) enqueue(job contactJob) {
+	select {
+	case uc.queue <- job:
+	default:
+		uc.logger.WithField("device", job.deviceName).Warn("Contact queue full, contact change dropped")
+	}
+}
+
+// loop persists queued changes until ctx is cancelled, then drains the queue
+func (uc *SyncContactsUseCase) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case job := <-uc.queue:
+			uc.run(job)
+		case <-ctx.Done():
+			for {
+				select {
+				case job := <-uc.queue:
+					uc.run(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// run persists a queued job
+func (uc *SyncContactsUseCase) run(job contactJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), contactWriteTimeout)
+	defer cancel()
+
+	if job.update == nil {
+		if _, err := uc.Execute(ctx, job.deviceName); err != nil {
+			uc.logger.WithField("device", job.deviceName).Warn("Failed to sync contacts: %v", err)
+		}
+		return
+	}
+
+	if err := uc.apply(ctx, job.deviceName, *job.update); err != nil {
+		uc.logger.WithFields(map[string]interface{}{
+			"device": job.deviceName,
+			"jid":    job.update.JID,
+		}).Error("Failed to update contact: %v", err)
+	}
+}
+
+// Execute copies the contact store of a connected device into its directory
+// and returns the number of contacts synced
+func (uc *SyncContactsUseCase) Execute(ctx context.Context, deviceName string) (int, error) {
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return 0, err
+	}
+
+	found, err := client.GetContacts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	contacts := make([]*domain.Contact, 0, len(found))
+	for _, contact := range found {
+		if contact.IsGroup || contact.IsBroadcast {
+			continue
+		}
+		contacts = append(contacts, &domain.Contact{
+			DeviceName:   deviceName,
+			JID:          contact.JID,
+			Phone:        contactPhone(contact.JID),
+			FullName:     contact.FullName,
+			FirstName:    contact.FirstName,
+			PushName:     contact.PushName,
+			BusinessName: contact.BusinessName,
+			SyncedAt:     now,
+		})
+	}
+
+	if err := uc.contacts.SaveSynced(ctx, contacts); err != nil {
+		return 0, err
+	}
+
+	uc.logger.WithFields(map[string]interface{}{
+		"device": deviceName,
+		"count":  len(contacts),
+	}).Success("Contacts synced")
+	return len(contacts), nil
+}
+
+// apply saves a change to a single contact, creating the contact when new
+func (uc *SyncContactsUseCase) apply(ctx context.Context, deviceName string, update domain.ContactUpdate) error {
+	contact, err := uc.contacts.FindByJID(ctx, deviceName, update.JID)
+	if err != nil {
+		if apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			return err
+		}
+		contact = &domain.Contact{
+			DeviceName: deviceName,
+			JID:        update.JID,
+			Phone:      contactPhone(update.JID),
+		}
+	}
+
+	if update.FullName != nil {
+		contact.FullName = *update.FullName
+	}
+	if update.FirstName != nil {
+		contact.FirstName = *update.FirstName
+	}
+	if update.PushName != nil {
+		contact.PushName = *update.PushName
+	}
+	if update.BusinessName != nil {
+		contact.BusinessName = *update.BusinessName
+	}
+	contact.SyncedAt = update.Timestamp
+	if contact.SyncedAt.IsZero() {
+		contact.SyncedAt = time.Now()
+	}
+
+	return uc.contacts.SaveSynced(ctx, []*domain.Contact{contact})
+}
+
+// contactPhone returns the phone number of a contact's JID, or "" when the
+// contact is only known by LID
+func contactPhone(jid string) string {
+	user, server, found := strings.Cut(jid, "@")
+	if !found || server != "s.whatsapp.net" {
+		return ""
+	}
+	// Strip the device part, if any
+	user, _, _ = strings.Cut(user, ":")
+	return user
+}
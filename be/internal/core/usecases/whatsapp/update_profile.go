@@ -0,0 +1,84 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// Limits enforced by the WhatsApp apps
+const (
+	maxPushNameLength = 25
+	maxAboutLength    = 139
+)
+
+// ProfileUpdate holds the profile fields to change; nil fields are left as they are
+type ProfileUpdate struct {
+	PushName *string
+	About    *string
+}
+
+// UpdateProfileUseCase handles changing the display name and about text of a device
+type UpdateProfileUseCase struct {
+	manager domain.WhatsAppManagerInterface
+	logger  *logger.Logger
+}
+
+// NewUpdateProfileUseCase creates a new UpdateProfileUseCase
+func NewUpdateProfileUseCase(manager domain.WhatsAppManagerInterface) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{
+		manager: manager,
+		logger:  logger.New("UpdateProfileUseCase"),
+	}
+}
+
+// Execute applies the update to a connected device and returns its profile
+func (uc *UpdateProfileUseCase) Execute(ctx context.Context, deviceName string, update ProfileUpdate) (*domain.DeviceProfile, error) {
+	if update.PushName == nil && update.About == nil {
+		return nil, apperrors.NewValidationError("Nothing to update; set push_name or about")
+	}
+
+	var pushName, about string
+	if update.PushName != nil {
+		pushName = strings.TrimSpace(*update.PushName)
+		if pushName == "" {
+			return nil, apperrors.NewValidationError("Display name cannot be empty")
+		}
+		if utf8.RuneCountInString(pushName) > maxPushNameLength {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("Display name must be at most %d characters", maxPushNameLength))
+		}
+	}
+	if update.About != nil {
+		about = strings.TrimSpace(*update.About)
+		if utf8.RuneCountInString(about) > maxAboutLength {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("About text must be at most %d characters", maxAboutLength))
+		}
+	}
+
+	client, err := connectedClient(uc.manager, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	log := uc.logger.WithField("device", deviceName)
+	log.Info("Updating profile")
+
+	if update.PushName != nil {
+		if err := client.SetPushName(ctx, pushName); err != nil {
+			return nil, err
+		}
+	}
+	if update.About != nil {
+		if err := client.SetAbout(ctx, about); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Success("Profile updated")
+	return client.GetProfile(ctx)
+}
@@ -0,0 +1,287 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// Pacing used when a campaign does not set its own
+const (
+	DefaultIntervalSeconds = 10
+	DefaultJitterSeconds   = 5
+)
+
+// CampaignService manages broadcast campaigns: their recipients and their
+// lifecycle from draft to completion. Sending is left to the CampaignRunner.
+type CampaignService struct {
+	campaigns domain.CampaignRepository
+	segments  domain.SegmentRepository
+	contacts  ports.ContactRepository
+	whatsapp  ports.WhatsAppService
+	runner    *CampaignRunner
+	logger    *logger.Logger
+}
+
+// NewCampaignService creates a new CampaignService
+func NewCampaignService(
+	campaigns domain.CampaignRepository,
+	segments domain.SegmentRepository,
+	contacts ports.ContactRepository,
+	whatsapp ports.WhatsAppService,
+	runner *CampaignRunner,
+) *CampaignService {
+	return &CampaignService{
+		campaigns: campaigns,
+		segments:  segments,
+		contacts:  contacts,
+		whatsapp:  whatsapp,
+		runner:    runner,
+		logger:    logger.New("CampaignService"),
+	}
+}
+
+// Create saves a new draft campaign
+func (s *CampaignService) Create(campaign *domain.Campaign) error {
+	campaign.Status = domain.CampaignDraft
+	if err := campaign.Validate(); err != nil {
+		return apperrors.NewValidationError(err.Error())
+	}
+	return s.campaigns.Create(campaign)
+}
+
+// Get retrieves a campaign of a device with its recipient counts
+func (s *CampaignService) Get(deviceName, id string) (*domain.Campaign, domain.CampaignStats, error) {
+	campaign, err := s.find(deviceName, id)
+	if err != nil {
+		return nil, domain.CampaignStats{}, err
+	}
+
+	stats, err := s.campaigns.Stats([]string{campaign.ID})
+	if err != nil {
+		return nil, domain.CampaignStats{}, err
+	}
+	return campaign, stats[campaign.ID], nil
+}
+
+// List lists the campaigns of a device, newest first, with their recipient
+// counts and the number of matching campaigns
+func (s *CampaignService) List(deviceName string, status domain.CampaignStatus, skip, limit int) ([]*domain.Campaign, map[string]domain.CampaignStats, int64, error) {
+	total, err := s.campaigns.CountByDevice(deviceName, status)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	campaigns, err := s.campaigns.FindByDevice(deviceName, status, skip, limit)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	ids := make([]string, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+	stats, err := s.campaigns.Stats(ids)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return campaigns, stats, total, nil
+}
+
+// AddRecipients adds the recipients of the given sources to a campaign
+func (s *CampaignService) AddRecipients(ctx context.Context, deviceName, id string, sources RecipientSources) (*AddResult, error) {
+	if sources.Empty() {
+		return nil, apperrors.NewValidationError("Give numbers, groups, contact_tags or segment_ids")
+	}
+
+	campaign, err := s.findEditable(deviceName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	list := newRecipientList()
+	if err := s.collect(ctx, campaign, sources, list); err != nil {
+		if apperrors.IsAppError(err) {
+			return nil, err
+		}
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	return s.save(campaign, list)
+}
+
+// ImportCSV adds the recipients listed in a CSV file to a campaign
+func (s *CampaignService) ImportCSV(deviceName, id string, r io.Reader) (*AddResult, error) {
+	campaign, err := s.findEditable(deviceName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	list := newRecipientList()
+	if err := parseCSV(r, list); err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	return s.save(campaign, list)
+}
+
+// Recipients lists the recipients of a campaign in sending order
+func (s *CampaignService) Recipients(deviceName, id string, status domain.RecipientStatus, skip, limit int) ([]*domain.Recipient, domain.CampaignStats, error) {
+	campaign, stats, err := s.Get(deviceName, id)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	recipients, err := s.campaigns.FindRecipients(campaign.ID, status, skip, limit)
+	if err != nil {
+		return nil, stats, err
+	}
+	return recipients, stats, nil
+}
+
+// Start starts sending a draft campaign
+func (s *CampaignService) Start(deviceName, id string) (*domain.Campaign, error) {
+	campaign, stats, err := s.Get(deviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Pending == 0 {
+		return nil, apperrors.NewValidationError("Campaign has no recipients")
+	}
+
+	campaign, err = s.campaigns.Transition(campaign.ID, []domain.CampaignStatus{domain.CampaignDraft}, domain.CampaignRunning, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.runner.Launch(campaign)
+	s.logger.WithFields(map[string]interface{}{
+		"campaign":   campaign.Name,
+		"device":     campaign.DeviceName,
+		"recipients": stats.Pending,
+	}).Info("Campaign started")
+	return campaign, nil
+}
+
+// Pause stops sending a running campaign after the message in progress
+func (s *CampaignService) Pause(deviceName, id, username string) (*domain.Campaign, error) {
+	if _, err := s.find(deviceName, id); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaigns.Transition(id, []domain.CampaignStatus{domain.CampaignRunning}, domain.CampaignPaused, reason("Paused", username))
+	if err != nil {
+		return nil, err
+	}
+
+	s.runner.Halt(campaign.ID)
+	return campaign, nil
+}
+
+// Resume continues sending a paused campaign
+func (s *CampaignService) Resume(deviceName, id string) (*domain.Campaign, error) {
+	if _, err := s.find(deviceName, id); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaigns.Transition(id, []domain.CampaignStatus{domain.CampaignPaused}, domain.CampaignRunning, "")
+	if err != nil {
+		return nil, err
+	}
+
+	s.runner.Launch(campaign)
+	return campaign, nil
+}
+
+// Cancel stops a campaign for good; its pending recipients are skipped
+func (s *CampaignService) Cancel(deviceName, id, username string) (*domain.Campaign, error) {
+	if _, err := s.find(deviceName, id); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaigns.Transition(id,
+		[]domain.CampaignStatus{domain.CampaignDraft, domain.CampaignRunning, domain.CampaignPaused},
+		domain.CampaignCancelled, reason("Cancelled", username))
+	if err != nil {
+		return nil, err
+	}
+
+	s.runner.Halt(campaign.ID)
+	if err := s.campaigns.SkipPending(campaign.ID); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// Delete removes a campaign that is not running, with its recipients
+func (s *CampaignService) Delete(deviceName, id string) error {
+	campaign, err := s.find(deviceName, id)
+	if err != nil {
+		return err
+	}
+	if campaign.Status == domain.CampaignRunning {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Pause or cancel the campaign before deleting it")
+	}
+	return s.campaigns.Delete(campaign.ID)
+}
+
+// WaitingForDevice reports whether a running campaign cannot send because its device is offline
+func (s *CampaignService) WaitingForDevice(campaign *domain.Campaign) bool {
+	return campaign.Status == domain.CampaignRunning && !s.whatsapp.IsDeviceConnected(campaign.DeviceName)
+}
+
+// find retrieves a campaign of a device; campaigns of other devices are not found
+func (s *CampaignService) find(deviceName, id string) (*domain.Campaign, error) {
+	campaign, err := s.campaigns.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.DeviceName != deviceName {
+		return nil, apperrors.NewNotFoundError("Campaign")
+	}
+	return campaign, nil
+}
+
+// findEditable retrieves a campaign of a device that recipients can still be added to
+func (s *CampaignService) findEditable(deviceName, id string) (*domain.Campaign, error) {
+	campaign, err := s.find(deviceName, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Finished() {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("Campaign is %s", campaign.Status))
+	}
+	return campaign, nil
+}
+
+// save adds the collected recipients to a campaign
+func (s *CampaignService) save(campaign *domain.Campaign, list *recipientList) (*AddResult, error) {
+	added, err := s.campaigns.AddRecipients(campaign.ID, list.recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	list.result.Added = added
+	list.result.Duplicates += len(list.recipients) - added
+
+	s.logger.WithFields(map[string]interface{}{
+		"campaign": campaign.Name,
+		"added":    added,
+		"rejected": len(list.result.Rejected),
+	}).Info("Campaign recipients added")
+	return list.result, nil
+}
+
+// reason describes who changed a campaign's status
+func reason(action, username string) string {
+	if username == "" {
+		return action
+	}
+	return action + " by " + username
+}
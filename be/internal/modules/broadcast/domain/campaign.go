@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits on a campaign's settings
+const (
+	MaxTemplateLength  = 4096
+	MaxIntervalSeconds = 3600
+	MaxJitterSeconds   = 3600
+)
+
+// CampaignStatus represents where a campaign is in its lifecycle
+type CampaignStatus string
+
+const (
+	CampaignDraft     CampaignStatus = "draft"     // Collecting recipients, nothing sent yet
+	CampaignRunning   CampaignStatus = "running"   // Sending to pending recipients
+	CampaignPaused    CampaignStatus = "paused"    // Stopped by a user until resumed
+	CampaignCompleted CampaignStatus = "completed" // Every recipient was sent to or failed
+	CampaignCancelled CampaignStatus = "cancelled" // Stopped for good; pending recipients were skipped
+)
+
+// Campaign represents a message broadcast from a device to a list of recipients
+type Campaign struct {
+	ID              string
+	DeviceName      string // Device the messages are sent from
+	Name            string
+	Template        string // Message text; {variable} is replaced per recipient
+	Typing          bool   // Show "typing..." before each message
+	IntervalSeconds int    // Pause between two messages...
+	JitterSeconds   int    // ...plus a random 0 to JitterSeconds more
+	Status          CampaignStatus
+	StatusReason    string // Who paused or cancelled the campaign
+	CreatedBy       string
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Validate checks that the campaign can be sent
+func (c *Campaign) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(c.Template) == "" {
+		return fmt.Errorf("template is required")
+	}
+	if utf8.RuneCountInString(c.Template) > MaxTemplateLength {
+		return fmt.Errorf("template must be at most %d characters", MaxTemplateLength)
+	}
+	if c.IntervalSeconds < 1 || c.IntervalSeconds > MaxIntervalSeconds {
+		return fmt.Errorf("interval_seconds must be between 1 and %d", MaxIntervalSeconds)
+	}
+	if c.JitterSeconds < 0 || c.JitterSeconds > MaxJitterSeconds {
+		return fmt.Errorf("jitter_seconds must be between 0 and %d", MaxJitterSeconds)
+	}
+	return nil
+}
+
+// Delay returns the pause before the next message, given a random number in [0, 1)
+func (c *Campaign) Delay(random float64) time.Duration {
+	jitter := time.Duration(random * float64(c.JitterSeconds) * float64(time.Second))
+	return time.Duration(c.IntervalSeconds)*time.Second + jitter
+}
+
+// Finished reports whether the campaign can no longer send
+func (c *Campaign) Finished() bool {
+	return c.Status == CampaignCompleted || c.Status == CampaignCancelled
+}
+
+// templateVariable matches {variable} placeholders
+var templateVariable = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// TemplateVariables lists the variables a template uses, lowercased, in order of first use
+func TemplateVariables(template string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range templateVariable.FindAllStringSubmatch(template, -1) {
+		name := strings.ToLower(match[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// RenderTemplate replaces the {variable} placeholders of a template with the
+// recipient's variables. Variable names are not case sensitive. It fails when
+// a variable has no value, rather than sending a message with a gap in it.
+func RenderTemplate(template string, variables map[string]string) (string, error) {
+	var missing []string
+	rendered := templateVariable.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := strings.ToLower(placeholder[1 : len(placeholder)-1])
+		value, ok := variables[name]
+		if !ok || value == "" {
+			missing = append(missing, "{"+name+"}")
+			return placeholder
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for %s", strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
+
+// RecipientStatus represents the delivery state of a campaign recipient
+type RecipientStatus string
+
+const (
+	RecipientPending RecipientStatus = "pending"
+	RecipientSent    RecipientStatus = "sent"
+	RecipientFailed  RecipientStatus = "failed"
+	RecipientSkipped RecipientStatus = "skipped" // The campaign was cancelled first
+)
+
+// Recipient represents a single recipient of a campaign
+type Recipient struct {
+	ID         string
+	CampaignID string
+	Position   int    // Recipients are sent to in order of position
+	To         string // Phone number in international digits, or a JID
+	IsGroup    bool
+	Name       string
+	Variables  map[string]string // Lowercase variable names; includes name and phone when known
+	Status     RecipientStatus
+	Message    string // Text sent, once rendered
+	Error      string // Why the last attempt failed
+	Attempts   int
+	SentAt     *time.Time
+	UpdatedAt  time.Time
+}
+
+// CampaignStats counts the recipients of a campaign by status
+type CampaignStats struct {
+	Total   int64
+	Pending int64
+	Sent    int64
+	Failed  int64
+	Skipped int64
+}
+
+// CampaignRepository defines the contract for campaign and recipient persistence
+type CampaignRepository interface {
+	// Create saves a new campaign
+	Create(campaign *Campaign) error
+
+	// FindByID retrieves a campaign by ID
+	FindByID(id string) (*Campaign, error)
+
+	// FindByDevice retrieves the campaigns of a device, newest first; an empty status matches all
+	FindByDevice(deviceName string, status CampaignStatus, skip, limit int) ([]*Campaign, error)
+
+	// CountByDevice counts the campaigns of a device; an empty status matches all
+	CountByDevice(deviceName string, status CampaignStatus) (int64, error)
+
+	// FindByStatus retrieves all campaigns with the given status
+	FindByStatus(status CampaignStatus) ([]*Campaign, error)
+
+	// Transition moves a campaign to a new status if it is in one of the from
+	// statuses, and returns it; otherwise it returns a conflict error
+	Transition(id string, from []CampaignStatus, to CampaignStatus, reason string) (*Campaign, error)
+
+	// Delete removes a campaign and its recipients
+	Delete(id string) error
+
+	// AddRecipients appends recipients to a campaign, skipping those it
+	// already has, and returns the number added
+	AddRecipients(campaignID string, recipients []*Recipient) (int, error)
+
+	// NextPending retrieves the pending recipient with the lowest position, or nil when there is none
+	NextPending(campaignID string) (*Recipient, error)
+
+	// UpdateRecipient saves the delivery state of a recipient
+	UpdateRecipient(recipient *Recipient) error
+
+	// SkipPending marks the pending recipients of a campaign as skipped
+	SkipPending(campaignID string) error
+
+	// FindRecipients retrieves the recipients of a campaign in order; an empty status matches all
+	FindRecipients(campaignID string, status RecipientStatus, skip, limit int) ([]*Recipient, error)
+
+	// Stats counts the recipients of campaigns by status
+	Stats(campaignIDs []string) (map[string]CampaignStats, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	variables := map[string]string{"name": "Budi", "unit": "UPT Madiun", "empty": ""}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no placeholders", template: "Selamat pagi", want: "Selamat pagi"},
+		{name: "single", template: "Halo {name}", want: "Halo Budi"},
+		{name: "repeated", template: "{name}, {name}!", want: "Budi, Budi!"},
+		{name: "case insensitive", template: "Halo {Name} dari {UNIT}", want: "Halo Budi dari UPT Madiun"},
+		{name: "not a placeholder", template: "Kode {a-b} { name } {}", want: "Kode {a-b} { name } {}"},
+		{name: "unknown variable", template: "Halo {name}, lokasi {location}", wantErr: true},
+		{name: "empty value", template: "Halo {empty}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.template, variables)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("RenderTemplate(%q) = %q, want error", tt.template, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTemplate(%q) unexpected error: %v", tt.template, err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	got := TemplateVariables("Halo {Name} dari {unit}, {NAME} {a-b}")
+	want := []string{"name", "unit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TemplateVariables() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Segment is a saved selection of a device's contacts, by tag and/or search,
+// that campaigns can be sent to. Its members are looked up when it is used,
+// so contacts tagged later are included.
+type Segment struct {
+	ID         string
+	DeviceName string
+	Name       string
+	Tag        string // Contacts with this tag
+	Search     string // Contacts whose name or phone number contains this
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks that the segment selects contacts
+func (s *Segment) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(s.Tag) == "" && strings.TrimSpace(s.Search) == "" {
+		return fmt.Errorf("tag or search is required")
+	}
+	return nil
+}
+
+// SegmentRepository defines the contract for Segment persistence
+type SegmentRepository interface {
+	// Create saves a new segment
+	Create(segment *Segment) error
+
+	// FindByID retrieves a segment by ID
+	FindByID(id string) (*Segment, error)
+
+	// FindByDevice retrieves the segments of a device, sorted by name
+	FindByDevice(deviceName string) ([]*Segment, error)
+
+	// Update updates an existing segment
+	Update(segment *Segment) error
+
+	// Delete removes a segment
+	Delete(id string) error
+}
@@ -0,0 +1,336 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/phone"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/validator"
+)
+
+const (
+	// maxRecipientsPerAdd bounds the recipients added to a campaign in one request
+	maxRecipientsPerAdd = 10000
+
+	// contactPageSize is the number of directory contacts read at a time
+	contactPageSize = 500
+)
+
+// csvAddressColumns are the CSV headers recognised as the recipient's phone
+// number or JID, in order of preference
+var csvAddressColumns = []string{"phone", "to", "number", "jid", "nomor", "no_hp"}
+
+// RecipientInput is a recipient given by phone number or JID
+type RecipientInput struct {
+	To        string
+	Name      string
+	Variables map[string]string
+}
+
+// RecipientSources selects the recipients to add to a campaign
+type RecipientSources struct {
+	Numbers     []RecipientInput // People or groups given directly
+	Groups      []string         // Group JIDs, with or without @g.us
+	ContactTags []string         // Directory contacts with any of these tags
+	SegmentIDs  []string         // Saved segments of the campaign's device
+}
+
+// Empty reports whether no source was given
+func (s *RecipientSources) Empty() bool {
+	return len(s.Numbers) == 0 && len(s.Groups) == 0 && len(s.ContactTags) == 0 && len(s.SegmentIDs) == 0
+}
+
+// Rejection is a recipient that could not be added
+type Rejection struct {
+	Row    int // CSV row, counting the header as row 1; 0 for other sources
+	Input  string
+	Reason string
+}
+
+// AddResult summarizes adding recipients to a campaign
+type AddResult struct {
+	Added      int // New recipients
+	Duplicates int // Recipients the campaign already had, or that were listed twice
+	Rejected   []Rejection
+}
+
+// recipientList collects recipients, dropping repeats of the same address
+type recipientList struct {
+	recipients []*domain.Recipient
+	seen       map[string]bool
+	result     *AddResult
+}
+
+func newRecipientList() *recipientList {
+	return &recipientList{
+		seen:   make(map[string]bool),
+		result: &AddResult{Rejected: []Rejection{}},
+	}
+}
+
+// add normalises a recipient's address and adds it, or records why it cannot be added
+func (l *recipientList) add(row int, input RecipientInput, group bool) error {
+	address, isGroup, err := recipientAddress(input.To, group)
+	if err != nil {
+		l.result.Rejected = append(l.result.Rejected, Rejection{Row: row, Input: input.To, Reason: err.Error()})
+		return nil
+	}
+	if l.seen[address] {
+		l.result.Duplicates++
+		return nil
+	}
+	if len(l.recipients) >= maxRecipientsPerAdd {
+		return fmt.Errorf("at most %d recipients can be added at once", maxRecipientsPerAdd)
+	}
+	l.seen[address] = true
+
+	variables := make(map[string]string, len(input.Variables)+2)
+	for name, value := range input.Variables {
+		if name = variableName(name); name != "" {
+			variables[name] = strings.TrimSpace(value)
+		}
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = variables["name"]
+	}
+	if name == "" {
+		name = variables["nama"]
+	}
+	if name != "" && variables["name"] == "" {
+		variables["name"] = name
+	}
+	if !isGroup && !strings.Contains(address, "@") && variables["phone"] == "" {
+		variables["phone"] = address
+	}
+
+	l.recipients = append(l.recipients, &domain.Recipient{
+		To:        address,
+		IsGroup:   isGroup,
+		Name:      name,
+		Variables: variables,
+	})
+	return nil
+}
+
+// collect adds the recipients of every source
+func (s *CampaignService) collect(ctx context.Context, campaign *domain.Campaign, sources RecipientSources, list *recipientList) error {
+	for _, input := range sources.Numbers {
+		if err := list.add(0, input, false); err != nil {
+			return err
+		}
+	}
+
+	if len(sources.Groups) > 0 {
+		names := s.groupNames(ctx, campaign.DeviceName)
+		for _, jid := range sources.Groups {
+			address, _, _ := recipientAddress(jid, true)
+			if err := list.add(0, RecipientInput{To: jid, Name: names[address]}, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tag := range sources.ContactTags {
+		if err := s.collectContacts(ctx, &coreDomain.ContactFilter{DeviceName: campaign.DeviceName, Tag: tag}, list); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range sources.SegmentIDs {
+		segment, err := s.segments.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if segment.DeviceName != campaign.DeviceName {
+			return apperrors.NewNotFoundError("Segment")
+		}
+		filter := &coreDomain.ContactFilter{DeviceName: campaign.DeviceName, Tag: segment.Tag, Search: segment.Search}
+		if err := s.collectContacts(ctx, filter, list); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectContacts adds the directory contacts matching a filter
+func (s *CampaignService) collectContacts(ctx context.Context, filter *coreDomain.ContactFilter, list *recipientList) error {
+	for skip := 0; ; skip += contactPageSize {
+		contacts, err := s.contacts.FindAll(ctx, filter, skip, contactPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, contact := range contacts {
+			to := contact.Phone
+			if to == "" {
+				to = contact.JID
+			}
+			variables := map[string]string{
+				"first_name":    contact.FirstName,
+				"full_name":     contact.FullName,
+				"business_name": contact.BusinessName,
+			}
+			if err := list.add(0, RecipientInput{To: to, Name: contact.DisplayName(), Variables: variables}, false); err != nil {
+				return err
+			}
+		}
+
+		if len(contacts) < contactPageSize {
+			return nil
+		}
+	}
+}
+
+// groupNames maps the JIDs of the device's groups to their names, so that
+// {name} can be used for groups too. Groups are still added when the device
+// cannot list them.
+func (s *CampaignService) groupNames(ctx context.Context, deviceName string) map[string]string {
+	names := make(map[string]string)
+
+	groups, _, err := s.whatsapp.ListGroups(ctx, deviceName, coreDomain.GroupFilter{}, 0, maxRecipientsPerAdd)
+	if err != nil {
+		s.logger.WithField("device", deviceName).Warn("Failed to list groups for their names: %v", err)
+		return names
+	}
+	for _, group := range groups {
+		names[group.JID] = group.Name
+	}
+	return names
+}
+
+// parseCSV reads recipients from a CSV file with a header row. The phone
+// number or JID is taken from the first of the columns phone, to, number,
+// jid, nomor or no_hp; every column, including name, becomes a template
+// variable named after its header. Both comma and semicolon separated files
+// are accepted, as spreadsheet programs export either.
+func parseCSV(r io.Reader, list *recipientList) error {
+	data, err := io.ReadAll(io.LimitReader(r, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	if header, _, _ := strings.Cut(text, "\n"); strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return fmt.Errorf("CSV is empty")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = variableName(name)
+	}
+	address := -1
+	for _, candidate := range csvAddressColumns {
+		for i, name := range columns {
+			if name == candidate {
+				address = i
+				break
+			}
+		}
+		if address >= 0 {
+			break
+		}
+	}
+	if address < 0 {
+		return fmt.Errorf("CSV needs a column named %s", strings.Join(csvAddressColumns, ", "))
+	}
+
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			list.result.Rejected = append(list.result.Rejected, Rejection{Row: row, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("invalid CSV: %w", err)
+		}
+		if address >= len(record) || strings.TrimSpace(record[address]) == "" {
+			if strings.TrimSpace(strings.Join(record, "")) != "" {
+				list.result.Rejected = append(list.result.Rejected, Rejection{Row: row, Reason: "no phone number"})
+			}
+			continue
+		}
+
+		variables := make(map[string]string, len(record))
+		for i, value := range record {
+			if i < len(columns) && columns[i] != "" && i != address {
+				variables[columns[i]] = value
+			}
+		}
+		if err := list.add(row, RecipientInput{To: record[address], Variables: variables}, false); err != nil {
+			return err
+		}
+	}
+}
+
+// recipientAddress normalises a recipient to the address stored with the
+// campaign: international digits for phone numbers, or a JID for groups and
+// hidden users. Numbers are checked with WhatsApp when the message is sent.
+func recipientAddress(to string, group bool) (string, bool, error) {
+	to = strings.ToLower(strings.TrimSpace(to))
+	if to == "" {
+		return "", false, fmt.Errorf("no phone number")
+	}
+
+	user, server, isJID := strings.Cut(to, "@")
+	if group || server == "g.us" {
+		if !isJID {
+			to += "@g.us"
+		}
+		if !validator.ValidateWhatsAppJID(to) {
+			return "", false, fmt.Errorf("invalid group JID")
+		}
+		return to, true, nil
+	}
+
+	switch {
+	case !isJID:
+		user = to
+	case server == "lid":
+		return to, false, nil
+	case server != "s.whatsapp.net":
+		return "", false, fmt.Errorf("invalid WhatsApp JID")
+	default:
+		// Strip the device part, if any
+		user, _, _ = strings.Cut(user, ":")
+	}
+
+	number, err := phone.Normalize(user)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid phone number")
+	}
+	return number, false, nil
+}
+
+// variableName converts a CSV header or variable name to the lowercase name
+// used in templates, e.g. "First Name" becomes first_name
+func variableName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(ch rune) bool {
+		return !(ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_')
+	}), "_")
+}
@@ -0,0 +1,617 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CampaignMongoRepository implements CampaignRepository using MongoDB
+type CampaignMongoRepository struct {
+	campaigns  *mongo.Collection
+	recipients *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoCampaign represents the MongoDB campaign document
+type mongoCampaign struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceName      string             `bson:"device_name"`
+	Name            string             `bson:"name"`
+	Template        string             `bson:"template"`
+	Typing          bool               `bson:"typing"`
+	IntervalSeconds int                `bson:"interval_seconds"`
+	JitterSeconds   int                `bson:"jitter_seconds"`
+	Status          string             `bson:"status"`
+	StatusReason    string             `bson:"status_reason,omitempty"`
+	CreatedBy       string             `bson:"created_by"`
+	StartedAt       int64              `bson:"started_at,omitempty"`
+	FinishedAt      int64              `bson:"finished_at,omitempty"`
+	CreatedAt       int64              `bson:"created_at"`
+	UpdatedAt       int64              `bson:"updated_at"`
+}
+
+// mongoRecipient represents the MongoDB recipient document
+type mongoRecipient struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	CampaignID string             `bson:"campaign_id"`
+	Position   int                `bson:"position"`
+	To         string             `bson:"to"`
+	IsGroup    bool               `bson:"is_group"`
+	Name       string             `bson:"name,omitempty"`
+	Variables  map[string]string  `bson:"variables,omitempty"`
+	Status     string             `bson:"status"`
+	Message    string             `bson:"message,omitempty"`
+	Error      string             `bson:"error,omitempty"`
+	Attempts   int                `bson:"attempts"`
+	SentAt     int64              `bson:"sent_at,omitempty"`
+	UpdatedAt  int64              `bson:"updated_at"`
+}
+
+// NewCampaignMongoRepository creates a new MongoDB repository for campaigns
+func NewCampaignMongoRepository(db *mongo.Database) domain.CampaignRepository {
+	campaigns := db.Collection("broadcast_campaigns")
+	recipients := db.Collection("broadcast_recipients")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on device and creation time, for listing
+	_, _ = campaigns.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "device_name", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	// Index on status, for resuming running campaigns
+	_, _ = campaigns.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}},
+	})
+
+	// Index on campaign and address (unique); a campaign sends to everyone once
+	_, _ = recipients.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "campaign_id", Value: 1}, {Key: "to", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Index on campaign, status and position, for the next pending recipient
+	_, _ = recipients.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "status", Value: 1}, {Key: "position", Value: 1}},
+	})
+
+	return &CampaignMongoRepository{
+		campaigns:  campaigns,
+		recipients: recipients,
+		logger:     logger.New("CampaignRepository"),
+	}
+}
+
+// Create saves a new campaign
+func (r *CampaignMongoRepository) Create(campaign *domain.Campaign) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	result, err := r.campaigns.InsertOne(ctx, toMongoCampaign(campaign))
+	if err != nil {
+		r.logger.Error("Failed to insert campaign: %v", err)
+		return apperrors.NewDatabaseError("Failed to save campaign", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		campaign.ID = oid.Hex()
+	}
+
+	r.logger.WithField("id", campaign.ID).Success("Campaign saved")
+	return nil
+}
+
+// FindByID retrieves a campaign by ID
+func (r *CampaignMongoRepository) FindByID(id string) (*domain.Campaign, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoCampaign
+	err = r.campaigns.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Campaign")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find campaign: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve campaign", err)
+	}
+
+	return toDomainCampaign(&doc), nil
+}
+
+// FindByDevice retrieves the campaigns of a device, newest first; an empty status matches all
+func (r *CampaignMongoRepository) FindByDevice(deviceName string, status domain.CampaignStatus, skip, limit int) ([]*domain.Campaign, error) {
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	return r.find(deviceFilter(deviceName, status), opts)
+}
+
+// CountByDevice counts the campaigns of a device; an empty status matches all
+func (r *CampaignMongoRepository) CountByDevice(deviceName string, status domain.CampaignStatus) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := r.campaigns.CountDocuments(ctx, deviceFilter(deviceName, status))
+	if err != nil {
+		r.logger.Error("Failed to count campaigns: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to count campaigns", err)
+	}
+
+	return count, nil
+}
+
+// FindByStatus retrieves all campaigns with the given status
+func (r *CampaignMongoRepository) FindByStatus(status domain.CampaignStatus) ([]*domain.Campaign, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	return r.find(bson.M{"status": string(status)}, opts)
+}
+
+// Transition moves a campaign to a new status if it is in one of the from
+// statuses, and returns it; otherwise it returns a conflict error
+func (r *CampaignMongoRepository) Transition(id string, from []domain.CampaignStatus, to domain.CampaignStatus, reason string) (*domain.Campaign, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	statuses := make(bson.A, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	now := time.Now().Unix()
+	update := bson.M{
+		"$set": bson.M{
+			"status":        string(to),
+			"status_reason": reason,
+			"updated_at":    now,
+		},
+	}
+	switch to {
+	case domain.CampaignRunning:
+		// Keep the time the campaign first started
+		update["$min"] = bson.M{"started_at": now}
+	case domain.CampaignCompleted, domain.CampaignCancelled:
+		update["$set"].(bson.M)["finished_at"] = now
+	}
+
+	var doc mongoCampaign
+	err = r.campaigns.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "status": bson.M{"$in": statuses}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		current, err := r.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("Campaign is %s", current.Status))
+	}
+	if err != nil {
+		r.logger.Error("Failed to update campaign status: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to update campaign", err)
+	}
+
+	r.logger.WithFields(map[string]interface{}{
+		"id":     id,
+		"status": to,
+	}).Info("Campaign status changed")
+	return toDomainCampaign(&doc), nil
+}
+
+// Delete removes a campaign and its recipients
+func (r *CampaignMongoRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	result, err := r.campaigns.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("Failed to delete campaign: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete campaign", err)
+	}
+	if result.DeletedCount == 0 {
+		return apperrors.NewNotFoundError("Campaign")
+	}
+
+	if _, err := r.recipients.DeleteMany(ctx, bson.M{"campaign_id": id}); err != nil {
+		r.logger.Error("Failed to delete campaign recipients: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete campaign recipients", err)
+	}
+
+	r.logger.WithField("id", id).Success("Campaign deleted")
+	return nil
+}
+
+// AddRecipients appends recipients to a campaign, skipping those it already
+// has, and returns the number added
+func (r *CampaignMongoRepository) AddRecipients(campaignID string, recipients []*domain.Recipient) (int, error) {
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Continue after the last recipient added so far
+	position := 0
+	var last mongoRecipient
+	err := r.recipients.FindOne(ctx,
+		bson.M{"campaign_id": campaignID},
+		options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}}),
+	).Decode(&last)
+	if err != nil && err != mongo.ErrNoDocuments {
+		r.logger.Error("Failed to find last recipient: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to add recipients", err)
+	}
+	if err == nil {
+		position = last.Position + 1
+	}
+
+	now := time.Now().Unix()
+	models := make([]mongo.WriteModel, 0, len(recipients))
+	for _, recipient := range recipients {
+		recipient.CampaignID = campaignID
+		recipient.Position = position
+		recipient.Status = domain.RecipientPending
+		position++
+
+		doc := toMongoRecipient(recipient)
+		doc.UpdatedAt = now
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"campaign_id": campaignID, "to": recipient.To}).
+			SetUpdate(bson.M{"$setOnInsert": doc}).
+			SetUpsert(true))
+	}
+
+	result, err := r.recipients.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		r.logger.Error("Failed to add recipients: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to add recipients", err)
+	}
+
+	return int(result.UpsertedCount), nil
+}
+
+// NextPending retrieves the pending recipient with the lowest position, or nil when there is none
+func (r *CampaignMongoRepository) NextPending(campaignID string) (*domain.Recipient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var doc mongoRecipient
+	err := r.recipients.FindOne(ctx,
+		bson.M{"campaign_id": campaignID, "status": string(domain.RecipientPending)},
+		options.FindOne().SetSort(bson.D{{Key: "position", Value: 1}}),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to find pending recipient: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve recipient", err)
+	}
+
+	return toDomainRecipient(&doc), nil
+}
+
+// UpdateRecipient saves the delivery state of a recipient
+func (r *CampaignMongoRepository) UpdateRecipient(recipient *domain.Recipient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(recipient.ID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	recipient.UpdatedAt = time.Now()
+	doc := toMongoRecipient(recipient)
+
+	_, err = r.recipients.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set": bson.M{
+			"status":     doc.Status,
+			"message":    doc.Message,
+			"error":      doc.Error,
+			"attempts":   doc.Attempts,
+			"sent_at":    doc.SentAt,
+			"updated_at": recipient.UpdatedAt.Unix(),
+		},
+	})
+	if err != nil {
+		r.logger.Error("Failed to update recipient: %v", err)
+		return apperrors.NewDatabaseError("Failed to update recipient", err)
+	}
+
+	return nil
+}
+
+// SkipPending marks the pending recipients of a campaign as skipped
+func (r *CampaignMongoRepository) SkipPending(campaignID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.recipients.UpdateMany(ctx,
+		bson.M{"campaign_id": campaignID, "status": string(domain.RecipientPending)},
+		bson.M{"$set": bson.M{
+			"status":     string(domain.RecipientSkipped),
+			"updated_at": time.Now().Unix(),
+		}},
+	)
+	if err != nil {
+		r.logger.Error("Failed to skip pending recipients: %v", err)
+		return apperrors.NewDatabaseError("Failed to update recipients", err)
+	}
+
+	return nil
+}
+
+// FindRecipients retrieves the recipients of a campaign in order; an empty status matches all
+func (r *CampaignMongoRepository) FindRecipients(campaignID string, status domain.RecipientStatus, skip, limit int) ([]*domain.Recipient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"campaign_id": campaignID}
+	if status != "" {
+		filter["status"] = string(status)
+	}
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "position", Value: 1}})
+
+	cursor, err := r.recipients.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find recipients: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve recipients", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.Recipient, 0)
+	for cursor.Next(ctx) {
+		var doc mongoRecipient
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainRecipient(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate recipients", err)
+	}
+
+	return results, nil
+}
+
+// Stats counts the recipients of campaigns by status
+func (r *CampaignMongoRepository) Stats(campaignIDs []string) (map[string]domain.CampaignStats, error) {
+	stats := make(map[string]domain.CampaignStats, len(campaignIDs))
+	if len(campaignIDs) == 0 {
+		return stats, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := r.recipients.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"campaign_id": bson.M{"$in": campaignIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"campaign_id": "$campaign_id", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		r.logger.Error("Failed to count recipients: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to count recipients", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				CampaignID string `bson:"campaign_id"`
+				Status     string `bson:"status"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+
+		s := stats[row.ID.CampaignID]
+		s.Total += row.Count
+		switch domain.RecipientStatus(row.ID.Status) {
+		case domain.RecipientPending:
+			s.Pending += row.Count
+		case domain.RecipientSent:
+			s.Sent += row.Count
+		case domain.RecipientFailed:
+			s.Failed += row.Count
+		case domain.RecipientSkipped:
+			s.Skipped += row.Count
+		}
+		stats[row.ID.CampaignID] = s
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate recipient counts", err)
+	}
+
+	return stats, nil
+}
+
+// find retrieves campaigns matching the filter
+func (r *CampaignMongoRepository) find(filter bson.M, opts *options.FindOptions) ([]*domain.Campaign, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.campaigns.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find campaigns: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve campaigns", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.Campaign, 0)
+	for cursor.Next(ctx) {
+		var doc mongoCampaign
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainCampaign(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate campaigns", err)
+	}
+
+	return results, nil
+}
+
+// deviceFilter matches the campaigns of a device, optionally with a status
+func deviceFilter(deviceName string, status domain.CampaignStatus) bson.M {
+	filter := bson.M{"device_name": deviceName}
+	if status != "" {
+		filter["status"] = string(status)
+	}
+	return filter
+}
+
+// toMongoCampaign converts domain entity to MongoDB document
+func toMongoCampaign(campaign *domain.Campaign) *mongoCampaign {
+	doc := &mongoCampaign{
+		DeviceName:      campaign.DeviceName,
+		Name:            campaign.Name,
+		Template:        campaign.Template,
+		Typing:          campaign.Typing,
+		IntervalSeconds: campaign.IntervalSeconds,
+		JitterSeconds:   campaign.JitterSeconds,
+		Status:          string(campaign.Status),
+		StatusReason:    campaign.StatusReason,
+		CreatedBy:       campaign.CreatedBy,
+		CreatedAt:       campaign.CreatedAt.Unix(),
+		UpdatedAt:       campaign.UpdatedAt.Unix(),
+	}
+
+	if campaign.StartedAt != nil {
+		doc.StartedAt = campaign.StartedAt.Unix()
+	}
+	if campaign.FinishedAt != nil {
+		doc.FinishedAt = campaign.FinishedAt.Unix()
+	}
+
+	return doc
+}
+
+// toDomainCampaign converts MongoDB document to domain entity
+func toDomainCampaign(doc *mongoCampaign) *domain.Campaign {
+	campaign := &domain.Campaign{
+		ID:              doc.ID.Hex(),
+		DeviceName:      doc.DeviceName,
+		Name:            doc.Name,
+		Template:        doc.Template,
+		Typing:          doc.Typing,
+		IntervalSeconds: doc.IntervalSeconds,
+		JitterSeconds:   doc.JitterSeconds,
+		Status:          domain.CampaignStatus(doc.Status),
+		StatusReason:    doc.StatusReason,
+		CreatedBy:       doc.CreatedBy,
+		CreatedAt:       time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:       time.Unix(doc.UpdatedAt, 0),
+	}
+
+	if doc.StartedAt != 0 {
+		startedAt := time.Unix(doc.StartedAt, 0)
+		campaign.StartedAt = &startedAt
+	}
+	if doc.FinishedAt != 0 {
+		finishedAt := time.Unix(doc.FinishedAt, 0)
+		campaign.FinishedAt = &finishedAt
+	}
+
+	return campaign
+}
+
+// toMongoRecipient converts domain entity to MongoDB document
+func toMongoRecipient(recipient *domain.Recipient) *mongoRecipient {
+	doc := &mongoRecipient{
+		CampaignID: recipient.CampaignID,
+		Position:   recipient.Position,
+		To:         recipient.To,
+		IsGroup:    recipient.IsGroup,
+		Name:       recipient.Name,
+		Variables:  recipient.Variables,
+		Status:     string(recipient.Status),
+		Message:    recipient.Message,
+		Error:      recipient.Error,
+		Attempts:   recipient.Attempts,
+		UpdatedAt:  recipient.UpdatedAt.Unix(),
+	}
+
+	if recipient.SentAt != nil {
+		doc.SentAt = recipient.SentAt.Unix()
+	}
+
+	return doc
+}
+
+// toDomainRecipient converts MongoDB document to domain entity
+func toDomainRecipient(doc *mongoRecipient) *domain.Recipient {
+	recipient := &domain.Recipient{
+		ID:         doc.ID.Hex(),
+		CampaignID: doc.CampaignID,
+		Position:   doc.Position,
+		To:         doc.To,
+		IsGroup:    doc.IsGroup,
+		Name:       doc.Name,
+		Variables:  doc.Variables,
+		Status:     domain.RecipientStatus(doc.Status),
+		Message:    doc.Message,
+		Error:      doc.Error,
+		Attempts:   doc.Attempts,
+		UpdatedAt:  time.Unix(doc.UpdatedAt, 0),
+	}
+
+	if doc.SentAt != 0 {
+		sentAt := time.Unix(doc.SentAt, 0)
+		recipient.SentAt = &sentAt
+	}
+
+	return recipient
+}
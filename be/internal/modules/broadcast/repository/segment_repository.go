@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SegmentMongoRepository implements SegmentRepository using MongoDB
+type SegmentMongoRepository struct {
+	collection *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoSegment represents the MongoDB document structure
+type mongoSegment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceName string             `bson:"device_name"`
+	Name       string             `bson:"name"`
+	Tag        string             `bson:"tag,omitempty"`
+	Search     string             `bson:"search,omitempty"`
+	CreatedBy  string             `bson:"created_by"`
+	CreatedAt  int64              `bson:"created_at"`
+	UpdatedAt  int64              `bson:"updated_at"`
+}
+
+// NewSegmentMongoRepository creates a new MongoDB repository for segments
+func NewSegmentMongoRepository(db *mongo.Database) domain.SegmentRepository {
+	collection := db.Collection("broadcast_segments")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on device and name (unique)
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "device_name", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &SegmentMongoRepository{
+		collection: collection,
+		logger:     logger.New("SegmentRepository"),
+	}
+}
+
+// Create saves a new segment
+func (r *SegmentMongoRepository) Create(segment *domain.Segment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	segment.CreatedAt = now
+	segment.UpdatedAt = now
+
+	result, err := r.collection.InsertOne(ctx, toMongoSegment(segment))
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.New(apperrors.ErrorTypeConflict, "A segment with this name already exists")
+	}
+	if err != nil {
+		r.logger.Error("Failed to insert segment: %v", err)
+		return apperrors.NewDatabaseError("Failed to save segment", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		segment.ID = oid.Hex()
+	}
+
+	r.logger.WithField("id", segment.ID).Success("Segment saved")
+	return nil
+}
+
+// FindByID retrieves a segment by ID
+func (r *SegmentMongoRepository) FindByID(id string) (*domain.Segment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoSegment
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Segment")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find segment: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve segment", err)
+	}
+
+	return toDomainSegment(&doc), nil
+}
+
+// FindByDevice retrieves the segments of a device, sorted by name
+func (r *SegmentMongoRepository) FindByDevice(deviceName string) ([]*domain.Segment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"device_name": deviceName}, opts)
+	if err != nil {
+		r.logger.Error("Failed to find segments: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve segments", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.Segment, 0)
+	for cursor.Next(ctx) {
+		var doc mongoSegment
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainSegment(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate segments", err)
+	}
+
+	return results, nil
+}
+
+// Update updates an existing segment
+func (r *SegmentMongoRepository) Update(segment *domain.Segment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(segment.ID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	segment.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set": bson.M{
+			"name":       segment.Name,
+			"tag":        segment.Tag,
+			"search":     segment.Search,
+			"updated_at": segment.UpdatedAt.Unix(),
+		},
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return apperrors.New(apperrors.ErrorTypeConflict, "A segment with this name already exists")
+	}
+	if err != nil {
+		r.logger.Error("Failed to update segment: %v", err)
+		return apperrors.NewDatabaseError("Failed to update segment", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return apperrors.NewNotFoundError("Segment")
+	}
+
+	r.logger.WithField("id", segment.ID).Success("Segment updated")
+	return nil
+}
+
+// Delete removes a segment
+func (r *SegmentMongoRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("Failed to delete segment: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete segment", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return apperrors.NewNotFoundError("Segment")
+	}
+
+	r.logger.WithField("id", id).Success("Segment deleted")
+	return nil
+}
+
+// toMongoSegment converts domain entity to MongoDB document
+func toMongoSegment(segment *domain.Segment) *mongoSegment {
+	return &mongoSegment{
+		DeviceName: segment.DeviceName,
+		Name:       segment.Name,
+		Tag:        segment.Tag,
+		Search:     segment.Search,
+		CreatedBy:  segment.CreatedBy,
+		CreatedAt:  segment.CreatedAt.Unix(),
+		UpdatedAt:  segment.UpdatedAt.Unix(),
+	}
+}
+
+// toDomainSegment converts MongoDB document to domain entity
+func toDomainSegment(doc *mongoSegment) *domain.Segment {
+	return &domain.Segment{
+		ID:         doc.ID.Hex(),
+		DeviceName: doc.DeviceName,
+		Name:       doc.Name,
+		Tag:        doc.Tag,
+		Search:     doc.Search,
+		CreatedBy:  doc.CreatedBy,
+		CreatedAt:  time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:  time.Unix(doc.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,337 @@
+package broadcast
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/broadcast/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// campaignSendTimeout bounds how long sending a single message may take
+	campaignSendTimeout = 2 * time.Minute
+
+	// campaignMaxAttempts is how often sending to a recipient is tried before
+	// the recipient is marked failed
+	campaignMaxAttempts = 3
+)
+
+// sendOutcome says what a worker does after trying a recipient
+type sendOutcome int
+
+const (
+	sendPaced sendOutcome = iota // A message went out; wait before the next one
+	sendNext                     // Nothing was sent; go on to the next recipient now
+	sendStop                     // The device cannot send; stop until the next check
+)
+
+// CampaignRunner sends running campaigns in the background, one worker per
+// campaign. Workers send to one recipient at a time, in order, pausing
+// between messages as the campaign's pacing says. Every interval the runner
+// looks for running campaigns of devices on this replica without a worker,
+// so campaigns continue after restarts and after their device reconnects.
+type CampaignRunner struct {
+	campaigns domain.CampaignRepository
+	whatsapp  ports.WhatsAppService
+	interval  time.Duration
+	logger    *logger.Logger
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	workers map[string]*campaignWorker
+	wg      sync.WaitGroup
+}
+
+// campaignWorker is the worker sending a campaign. A halted worker stays
+// registered until it has stopped, so that no second worker sends alongside
+// the message it still has in progress.
+type campaignWorker struct {
+	cancel   context.CancelFunc
+	halted   bool // Halt was called; the worker stops after the message in progress
+	relaunch bool // Launch was called while halted; a new worker starts once it stops
+}
+
+// NewCampaignRunner creates a new CampaignRunner that checks for campaigns to continue every interval
+func NewCampaignRunner(campaigns domain.CampaignRepository, whatsapp ports.WhatsAppService, interval time.Duration) *CampaignRunner {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &CampaignRunner{
+		campaigns: campaigns,
+		whatsapp:  whatsapp,
+		interval:  interval,
+		logger:    logger.New("CampaignRunner"),
+		workers:   make(map[string]*campaignWorker),
+	}
+}
+
+// Start begins sending running campaigns in the background
+func (r *CampaignRunner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go r.loop(r.ctx, r.done)
+	r.logger.WithField("interval", r.interval.String()).Info("Campaign runner started")
+}
+
+// Stop stops the runner and waits for the messages in progress to be sent
+func (r *CampaignRunner) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.ctx = nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	r.wg.Wait()
+	r.logger.Info("Campaign runner stopped")
+}
+
+// Launch starts a worker for a running campaign unless it already has one.
+// If its worker is still stopping after Halt, a new one starts once it has.
+func (r *CampaignRunner) Launch(campaign *domain.Campaign) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ctx == nil {
+		return
+	}
+	if worker := r.workers[campaign.ID]; worker != nil {
+		if worker.halted {
+			worker.relaunch = true
+		}
+		return
+	}
+
+	r.start(campaign.ID)
+}
+
+// start starts a worker for a campaign; the caller holds r.mu
+func (r *CampaignRunner) start(campaignID string) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	worker := &campaignWorker{cancel: cancel}
+	r.workers[campaignID] = worker
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.release(campaignID, worker)
+		r.run(ctx, campaignID)
+	}()
+}
+
+// Halt stops the worker of a campaign once the message in progress is sent
+func (r *CampaignRunner) Halt(campaignID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if worker := r.workers[campaignID]; worker != nil {
+		worker.cancel()
+		worker.halted = true
+		worker.relaunch = false
+	}
+}
+
+// release forgets a worker that has stopped, and starts the next one if the
+// campaign was launched again while it was stopping
+func (r *CampaignRunner) release(campaignID string, worker *campaignWorker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	worker.cancel()
+	if r.workers[campaignID] != worker {
+		return
+	}
+	delete(r.workers, campaignID)
+
+	if worker.relaunch && r.ctx != nil {
+		r.start(campaignID)
+	}
+}
+
+func (r *CampaignRunner) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	r.launchRunning()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.launchRunning()
+		}
+	}
+}
+
+// launchRunning starts workers for the running campaigns of connected devices on this replica
+func (r *CampaignRunner) launchRunning() {
+	campaigns, err := r.campaigns.FindByStatus(domain.CampaignRunning)
+	if err != nil {
+		r.logger.Error("Failed to load running campaigns: %v", err)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		if r.whatsapp.IsDeviceConnected(campaign.DeviceName) {
+			r.Launch(campaign)
+		}
+	}
+}
+
+// run sends a campaign until it is finished, paused or its device goes offline
+func (r *CampaignRunner) run(ctx context.Context, campaignID string) {
+	for ctx.Err() == nil {
+		// Reload each time, so a pause from another request is seen
+		campaign, err := r.campaigns.FindByID(campaignID)
+		if err != nil {
+			r.logger.WithField("campaign", campaignID).Error("Failed to load campaign: %v", err)
+			return
+		}
+		if campaign.Status != domain.CampaignRunning {
+			return
+		}
+
+		log := r.logger.WithFields(map[string]interface{}{
+			"campaign": campaign.Name,
+			"device":   campaign.DeviceName,
+		})
+
+		if !r.whatsapp.IsDeviceConnected(campaign.DeviceName) {
+			log.Warn("Device is offline, campaign waits for it to reconnect")
+			return
+		}
+
+		recipient, err := r.campaigns.NextPending(campaign.ID)
+		if err != nil {
+			log.Error("Failed to load next recipient: %v", err)
+			return
+		}
+		if recipient == nil {
+			if _, err := r.campaigns.Transition(campaign.ID, []domain.CampaignStatus{domain.CampaignRunning}, domain.CampaignCompleted, ""); err != nil {
+				log.Warn("Failed to complete campaign: %v", err)
+				return
+			}
+			log.Success("Campaign completed")
+			return
+		}
+
+		switch r.send(campaign, recipient) {
+		case sendStop:
+			return
+		case sendNext:
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(campaign.Delay(rand.Float64())):
+		}
+	}
+}
+
+// send renders the campaign's message for a recipient and sends it, then
+// records the outcome. It is not cancelled by pausing, so that a message is
+// never left half sent.
+func (r *CampaignRunner) send(campaign *domain.Campaign, recipient *domain.Recipient) sendOutcome {
+	log := r.logger.WithFields(map[string]interface{}{
+		"campaign": campaign.Name,
+		"to":       recipient.To,
+	})
+
+	message, err := domain.RenderTemplate(campaign.Template, recipient.Variables)
+	if err != nil {
+		recipient.Status = domain.RecipientFailed
+		recipient.Error = "Template has " + err.Error()
+		r.saveRecipient(recipient)
+		return sendNext
+	}
+	recipient.Message = message
+
+	receiverType := coreDomain.ReceiverIndividual
+	if recipient.IsGroup {
+		receiverType = coreDomain.ReceiverGroup
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), campaignSendTimeout)
+	defer cancel()
+
+	err = r.whatsapp.SendMessage(ctx, coreDomain.SendMessageParams{
+		DeviceName:   campaign.DeviceName,
+		To:           recipient.To,
+		Message:      message,
+		ReceiverType: receiverType,
+		MessageType:  coreDomain.MessageTypeText,
+		Typing:       campaign.Typing,
+	})
+	if err == nil {
+		sentAt := time.Now()
+		recipient.Status = domain.RecipientSent
+		recipient.Error = ""
+		recipient.Attempts++
+		recipient.SentAt = &sentAt
+		r.saveRecipient(recipient)
+		return sendPaced
+	}
+
+	switch apperrors.GetAppError(err).Type {
+	case apperrors.ErrorTypeNotFound, apperrors.ErrorTypeConnection:
+		// The device went away; try this recipient again once it is back
+		log.Warn("Device cannot send, campaign waits for it: %v", err)
+		return sendStop
+	case apperrors.ErrorTypeValidation:
+		// Not a number on WhatsApp, or not a valid address; retrying will not help
+		recipient.Status = domain.RecipientFailed
+	default:
+		if recipient.Attempts+1 >= campaignMaxAttempts {
+			recipient.Status = domain.RecipientFailed
+		}
+	}
+
+	log.Warn("Failed to send campaign message: %v", err)
+	recipient.Attempts++
+	recipient.Error = errorText(err)
+	r.saveRecipient(recipient)
+	return sendPaced
+}
+
+// saveRecipient records the delivery state of a recipient
+func (r *CampaignRunner) saveRecipient(recipient *domain.Recipient) {
+	if err := r.campaigns.UpdateRecipient(recipient); err != nil {
+		r.logger.WithField("to", recipient.To).Error("Failed to save recipient: %v", err)
+	}
+}
+
+// errorText describes why sending failed, without the error type prefix
+func errorText(err error) string {
+	appErr := apperrors.GetAppError(err)
+	if appErr.Err != nil {
+		return appErr.Message + ": " + appErr.Err.Error()
+	}
+	return appErr.Message
+}
@@ -0,0 +1,326 @@
+package quickresponse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/xlsx"
+)
+
+// rosterLookback is how far back officers are considered part of the team
+// when working out who has not reported and no officer roster is configured
+const rosterLookback = 30 * 24 * time.Hour
+
+// quantityPattern matches the first number in a free-text quantity, e.g. "1.250,5 m"
+var quantityPattern = regexp.MustCompile(`\d[\d.,]*`)
+
+// thousandsPattern matches numbers using a dot as thousands separator, e.g. "1.250"
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}\.\d{3}$`)
+
+// DigestService builds Quick Response summaries and posts them to WhatsApp
+type DigestService struct {
+	repository domain.QuickResponseRepository
+	officers   domain.OfficerRepository
+	whatsapp   ports.WhatsAppService
+	logger     *logger.Logger
+}
+
+// NewDigestService creates a new DigestService
+func NewDigestService(repository domain.QuickResponseRepository, officers domain.OfficerRepository, whatsapp ports.WhatsAppService) *DigestService {
+	return &DigestService{
+		repository: repository,
+		officers:   officers,
+		whatsapp:   whatsapp,
+		logger:     logger.New("DigestService"),
+	}
+}
+
+// Build collects the reports for the schedule period ending at runAt
+func (s *DigestService) Build(schedule *domain.DigestSchedule, runAt time.Time) (*domain.Digest, error) {
+	start, end := schedule.Period(runAt)
+
+	reports, err := s.repository.FindByDateRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &domain.Digest{
+		ScheduleName: schedule.Name,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		TotalReports: len(reports),
+		Reports:      reports,
+	}
+
+	reported := make(map[string]bool)
+	totals := make(map[string]*domain.DigestDITotal)
+	for _, qr := range reports {
+		if name := domain.NormalizeName(qr.Officer.Name); name != "" {
+			reported[name] = true
+		}
+
+		di := strings.TrimSpace(qr.Activity.IrrigationDI)
+		if di == "" {
+			di = "-"
+		}
+		total, ok := totals[di]
+		if !ok {
+			total = &domain.DigestDITotal{IrrigationDI: di}
+			totals[di] = total
+		}
+		total.Reports++
+		total.AreaSize += parseQuantity(qr.Output.AreaSize)
+		total.ChannelLength += parseQuantity(qr.Output.ChannelLength)
+	}
+	digest.Officers = len(reported)
+
+	for _, total := range totals {
+		digest.DITotals = append(digest.DITotals, *total)
+	}
+	sort.Slice(digest.DITotals, func(i, j int) bool {
+		return digest.DITotals[i].IrrigationDI < digest.DITotals[j].IrrigationDI
+	})
+
+	missing, err := s.missingOfficers(schedule, reports, start)
+	if err != nil {
+		return nil, err
+	}
+	digest.MissingOfficers = missing
+
+	return digest, nil
+}
+
+// missingOfficers lists officers expected to report in the period who did not.
+// The officer roster is used when configured; otherwise officers who reported
+// within the lookback window before the period are expected to report.
+func (s *DigestService) missingOfficers(schedule *domain.DigestSchedule, reports []*domain.QuickResponse, start time.Time) ([]string, error) {
+	var missing []string
+
+	if s.officers != nil {
+		roster, err := s.officers.FindActive()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(roster) > 0 {
+			for _, officer := range roster {
+				// Weekly reporters are not expected in every daily digest
+				if schedule.Frequency == domain.DigestDaily && officer.Cadence == domain.CadenceWeekly {
+					continue
+				}
+				if !hasReportSince(officer, reports, start) {
+					missing = append(missing, officer.Name)
+				}
+			}
+			sort.Strings(missing)
+			return missing, nil
+		}
+	}
+
+	previous, err := s.repository.FindByDateRange(start.Add(-rosterLookback), start)
+	if err != nil {
+		return nil, err
+	}
+
+	reported := make(map[string]bool)
+	for _, qr := range reports {
+		reported[domain.NormalizeName(qr.Officer.Name)] = true
+	}
+
+	names := make(map[string]string)
+	for _, qr := range previous {
+		key := domain.NormalizeName(qr.Officer.Name)
+		if key != "" && !reported[key] {
+			names[key] = strings.TrimSpace(qr.Officer.Name)
+		}
+	}
+	for _, name := range names {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// Run builds the digest for a schedule and posts it to every configured group
+func (s *DigestService) Run(ctx context.Context, schedule *domain.DigestSchedule, runAt time.Time) error {
+	log := s.logger.WithFields(map[string]interface{}{
+		"schedule": schedule.Name,
+		"device":   schedule.DeviceName,
+	})
+
+	digest, err := s.Build(schedule, runAt)
+	if err != nil {
+		return err
+	}
+
+	text := FormatDigest(digest, schedule.Location())
+
+	var attachment []byte
+	if schedule.IncludeAttachment {
+		attachment, err = BuildDigestAttachment(digest, schedule.Location())
+		if err != nil {
+			return apperrors.NewInternalError("Failed to build digest attachment", err)
+		}
+	}
+
+	var failed []string
+	for _, group := range schedule.GroupJIDs {
+		if err := s.whatsapp.SendTextMessage(ctx, schedule.DeviceName, group, text, coreDomain.ReceiverGroup); err != nil {
+			log.WithField("group", group).Error("Failed to post digest: %v", err)
+			failed = append(failed, group)
+			continue
+		}
+
+		if attachment == nil {
+			continue
+		}
+
+		err := s.whatsapp.SendFileMessage(ctx, coreDomain.SendMessageParams{
+			DeviceName:   schedule.DeviceName,
+			To:           group,
+			ReceiverType: coreDomain.ReceiverGroup,
+			MessageType:  coreDomain.MessageTypeFile,
+			FileData:     attachment,
+			FileName:     digestFileName(digest, schedule.Location()),
+		})
+		if err != nil {
+			log.WithField("group", group).Error("Failed to post digest attachment: %v", err)
+			failed = append(failed, group)
+		}
+	}
+
+	if len(failed) > 0 {
+		return apperrors.New(apperrors.ErrorTypeWhatsApp,
+			fmt.Sprintf("Failed to post digest to %d of %d groups", len(failed), len(schedule.GroupJIDs))).
+			WithDetails("groups", failed)
+	}
+
+	log.WithField("reports", digest.TotalReports).Success("Digest posted")
+	return nil
+}
+
+// FormatDigest renders the digest as a WhatsApp text message
+func FormatDigest(digest *domain.Digest, loc *time.Location) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*Rekap Laporan Quick Response*\n")
+	if digest.ScheduleName != "" {
+		fmt.Fprintf(&b, "_%s_\n", digest.ScheduleName)
+	}
+	fmt.Fprintf(&b, "Periode: %s - %s\n\n",
+		digest.PeriodStart.In(loc).Format("02/01/2006 15:04"),
+		digest.PeriodEnd.In(loc).Format("02/01/2006 15:04"))
+
+	fmt.Fprintf(&b, "Jumlah laporan: %d\n", digest.TotalReports)
+	fmt.Fprintf(&b, "Jumlah petugas melapor: %d\n", digest.Officers)
+
+	if len(digest.DITotals) > 0 {
+		b.WriteString("\n*Rekap per D.I*\n")
+		for _, total := range digest.DITotals {
+			fmt.Fprintf(&b, "- %s: %d laporan", total.IrrigationDI, total.Reports)
+			if total.AreaSize > 0 {
+				fmt.Fprintf(&b, ", luas %s", formatQuantity(total.AreaSize))
+			}
+			if total.ChannelLength > 0 {
+				fmt.Fprintf(&b, ", saluran %s", formatQuantity(total.ChannelLength))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(digest.MissingOfficers) > 0 {
+		b.WriteString("\n*Belum melapor*\n")
+		for i, name := range digest.MissingOfficers {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, name)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// BuildDigestAttachment renders the digest reports as an XLSX workbook
+func BuildDigestAttachment(digest *domain.Digest, loc *time.Location) ([]byte, error) {
+	reports := [][]string{{
+		"Tanggal", "Nama", "Jabatan", "D.I Penugasan", "Metode Penugasan",
+		"Kegiatan", "D.I", "Saluran", "Ruas Bangunan", "Lokasi", "UPT PSDA WS",
+		"Luas Area", "Panjang Saluran", "Menutup Bocoran", "Angkat Sedimen",
+		"Pembersihan Sampah", "Angkat / Potong Pohon",
+	}}
+	for _, qr := range digest.Reports {
+		reports = append(reports, []string{
+			qr.CreatedAt.In(loc).Format("2006-01-02 15:04"),
+			qr.Officer.Name, qr.Officer.Position, qr.Officer.Assignment,
+			qr.Activity.Method, qr.Activity.ActivityType, qr.Activity.IrrigationDI,
+			qr.Activity.Channel, qr.Activity.BuildingRoute, qr.Activity.Location,
+			qr.Activity.WatershedUnit,
+			qr.Output.AreaSize, qr.Output.ChannelLength, qr.Output.LeaksClosed,
+			qr.Output.SedimentRemoved, qr.Output.TrashCleared, qr.Output.TreeCutRemoved,
+		})
+	}
+
+	totals := [][]string{{"D.I", "Jumlah Laporan", "Luas Area", "Panjang Saluran"}}
+	for _, total := range digest.DITotals {
+		totals = append(totals, []string{
+			total.IrrigationDI,
+			strconv.Itoa(total.Reports),
+			formatQuantity(total.AreaSize),
+			formatQuantity(total.ChannelLength),
+		})
+	}
+
+	missing := [][]string{{"Nama"}}
+	for _, name := range digest.MissingOfficers {
+		missing = append(missing, []string{name})
+	}
+
+	return xlsx.Build(
+		xlsx.Sheet{Name: "Laporan", Rows: reports},
+		xlsx.Sheet{Name: "Rekap D.I", Rows: totals},
+		xlsx.Sheet{Name: "Belum Melapor", Rows: missing},
+	)
+}
+
+// digestFileName returns the attachment file name for a digest
+func digestFileName(digest *domain.Digest, loc *time.Location) string {
+	return fmt.Sprintf("rekap-quick-response-%s.xlsx", digest.PeriodEnd.In(loc).Format("2006-01-02"))
+}
+
+// parseQuantity extracts the leading number from a free-text quantity.
+// Both "1.250,5" (Indonesian) and "1250.5" notations are accepted.
+func parseQuantity(value string) float64 {
+	number := quantityPattern.FindString(value)
+	if number == "" {
+		return 0
+	}
+
+	switch {
+	case strings.Contains(number, ","):
+		number = strings.ReplaceAll(number, ".", "")
+		number = strings.ReplaceAll(number, ",", ".")
+	case strings.Count(number, ".") > 1 || thousandsPattern.MatchString(number):
+		number = strings.ReplaceAll(number, ".", "")
+	}
+
+	number = strings.TrimRight(number, ".")
+	result, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+// formatQuantity renders a total without trailing zeros
+func formatQuantity(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
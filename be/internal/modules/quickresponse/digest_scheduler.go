@@ -0,0 +1,161 @@
+package quickresponse
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// digestSendTimeout bounds how long posting a single digest may take
+const digestSendTimeout = 5 * time.Minute
+
+// DigestScheduler periodically posts due digest schedules
+type DigestScheduler struct {
+	schedules domain.DigestScheduleRepository
+	service   *DigestService
+	interval  time.Duration
+	logger    *logger.Logger
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running map[string]bool
+	wg      sync.WaitGroup // Digests being posted
+}
+
+// NewDigestScheduler creates a new DigestScheduler that checks schedules every interval
+func NewDigestScheduler(schedules domain.DigestScheduleRepository, service *DigestService, interval time.Duration) *DigestScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &DigestScheduler{
+		schedules: schedules,
+		service:   service,
+		interval:  interval,
+		logger:    logger.New("DigestScheduler"),
+		running:   make(map[string]bool),
+	}
+}
+
+// Start begins checking schedules in the background
+func (s *DigestScheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go s.loop(ctx, s.done)
+	s.logger.WithField("interval", s.interval.String()).Info("Digest scheduler started")
+}
+
+// Stop stops the scheduler and waits for the check loop and the digests
+// being posted to finish
+func (s *DigestScheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	s.wg.Wait()
+	s.logger.Info("Digest scheduler stopped")
+}
+
+// RunNow posts a digest immediately, covering the period that ends now
+func (s *DigestScheduler) RunNow(ctx context.Context, schedule *domain.DigestSchedule) error {
+	now := time.Now()
+	if err := s.service.Run(ctx, schedule, now); err != nil {
+		return err
+	}
+	return s.schedules.MarkRun(schedule.ID, now)
+}
+
+func (s *DigestScheduler) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runDue(ctx, now)
+		}
+	}
+}
+
+// runDue posts every enabled schedule whose next run time has passed, once
+func (s *DigestScheduler) runDue(ctx context.Context, now time.Time) {
+	schedules, err := s.schedules.FindEnabled()
+	if err != nil {
+		s.logger.Error("Failed to load digest schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		// Never catch up on runs missed before the schedule was last run or created
+		since := schedule.UpdatedAt
+		if schedule.LastRunAt != nil && schedule.LastRunAt.After(since) {
+			since = *schedule.LastRunAt
+		}
+
+		// After downtime only the latest missed run is posted; the ones
+		// before it are skipped
+		runAt, due := schedule.LatestRun(since, now)
+		if !due || !s.markRunning(schedule.ID) {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(schedule *domain.DigestSchedule, runAt time.Time) {
+			defer s.wg.Done()
+			defer s.clearRunning(schedule.ID)
+
+			// Record the run first so a failing device does not trigger a retry every tick
+			if err := s.schedules.MarkRun(schedule.ID, runAt); err != nil {
+				s.logger.WithField("schedule", schedule.Name).Error("Failed to record digest run: %v", err)
+				return
+			}
+
+			sendCtx, cancel := context.WithTimeout(ctx, digestSendTimeout)
+			defer cancel()
+
+			if err := s.service.Run(sendCtx, schedule, runAt); err != nil {
+				s.logger.WithField("schedule", schedule.Name).Error("Digest run failed: %v", err)
+			}
+		}(schedule, runAt)
+	}
+}
+
+func (s *DigestScheduler) markRunning(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[id] {
+		return false
+	}
+	s.running[id] = true
+	return true
+}
+
+func (s *DigestScheduler) clearRunning(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, id)
+}
@@ -0,0 +1,33 @@
+package quickresponse
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{value: "120", want: 120},
+		{value: "120 m2", want: 120},
+		{value: "± 40 meter", want: 40},
+		{value: "12.5", want: 12.5},
+		{value: "12,5 m", want: 12.5},
+		{value: "1.250", want: 1250},
+		{value: "1.250,5 m", want: 1250.5},
+		{value: "1.250.000", want: 1250000},
+		{value: "1250.5", want: 1250.5},
+		{value: "5.", want: 5},
+		{value: "3 pohon, 2 ranting", want: 3},
+		{value: "", want: 0},
+		{value: "-", want: 0},
+		{value: "tidak ada", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseQuantity(tt.value); got != tt.want {
+				t.Errorf("parseQuantity(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
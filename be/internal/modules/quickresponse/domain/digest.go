@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DigestFrequency represents how often a digest is posted
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// DigestSchedule represents a recurring Quick Response summary posted to WhatsApp groups
+type DigestSchedule struct {
+	ID                string
+	Name              string
+	Frequency         DigestFrequency
+	Weekday           time.Weekday // Day of the week for weekly digests
+	TimeOfDay         string       // Local posting time in HH:MM format
+	Timezone          string       // IANA timezone, e.g. "Asia/Jakarta"
+	DeviceName        string       // Device used to post the digest
+	GroupJIDs         []string     // Target WhatsApp group JIDs
+	IncludeAttachment bool         // Attach the reports as an XLSX file
+	Enabled           bool
+	LastRunAt         *time.Time
+	CreatedBy         string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Location returns the schedule timezone, falling back to UTC when unset or unknown
+func (s *DigestSchedule) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate checks that the schedule can be run
+func (s *DigestSchedule) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.Frequency != DigestDaily && s.Frequency != DigestWeekly {
+		return fmt.Errorf("frequency must be 'daily' or 'weekly'")
+	}
+	if s.Weekday < time.Sunday || s.Weekday > time.Saturday {
+		return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if _, _, err := parseTimeOfDay(s.TimeOfDay); err != nil {
+		return err
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone: %s", s.Timezone)
+		}
+	}
+	if s.DeviceName == "" {
+		return fmt.Errorf("device_name is required")
+	}
+	if len(s.GroupJIDs) == 0 {
+		return fmt.Errorf("at least one group JID is required")
+	}
+	return nil
+}
+
+// NextRun returns the first scheduled posting time strictly after the given time
+func (s *DigestSchedule) NextRun(after time.Time) time.Time {
+	loc := s.Location()
+	hour, minute, _ := parseTimeOfDay(s.TimeOfDay)
+
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+
+	if s.Frequency == DigestWeekly {
+		next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// LatestRun returns the last run time after since that is not later than now.
+// It is false when no run falls in between.
+func (s *DigestSchedule) LatestRun(since, now time.Time) (time.Time, bool) {
+	runAt := s.NextRun(since)
+	if runAt.After(now) {
+		return time.Time{}, false
+	}
+	for next := s.NextRun(runAt); !next.After(now); next = s.NextRun(next) {
+		runAt = next
+	}
+	return runAt, true
+}
+
+// Period returns the reporting window that ends at the given run time
+func (s *DigestSchedule) Period(runAt time.Time) (time.Time, time.Time) {
+	if s.Frequency == DigestWeekly {
+		return runAt.AddDate(0, 0, -7), runAt
+	}
+	return runAt.AddDate(0, 0, -1), runAt
+}
+
+// parseTimeOfDay parses an HH:MM string
+func parseTimeOfDay(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("time_of_day must be in HH:MM format")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// DigestDITotal contains aggregated figures for a single irrigation area (D.I)
+type DigestDITotal struct {
+	IrrigationDI  string
+	Reports       int
+	AreaSize      float64 // Sum of Luas Area Kegiatan
+	ChannelLength float64 // Sum of Panjang Saluran
+}
+
+// Digest represents a summary of Quick Response reports for a period
+type Digest struct {
+	ScheduleName    string
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	TotalReports    int
+	Officers        int
+	DITotals        []DigestDITotal
+	MissingOfficers []string // Officers expected to report who did not
+	Reports         []*QuickResponse
+}
+
+// DigestScheduleRepository defines the contract for DigestSchedule persistence
+type DigestScheduleRepository interface {
+	// Create saves a new digest schedule
+	Create(schedule *DigestSchedule) error
+
+	// FindByID retrieves a digest schedule by ID
+	FindByID(id string) (*DigestSchedule, error)
+
+	// FindAll retrieves all digest schedules
+	FindAll() ([]*DigestSchedule, error)
+
+	// FindEnabled retrieves all enabled digest schedules
+	FindEnabled() ([]*DigestSchedule, error)
+
+	// Update updates an existing digest schedule
+	Update(schedule *DigestSchedule) error
+
+	// MarkRun records the last time a digest schedule was run
+	MarkRun(id string, runAt time.Time) error
+
+	// Delete removes a digest schedule
+	Delete(id string) error
+}
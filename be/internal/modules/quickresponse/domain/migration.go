@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MigrationStatus represents the state of a schema migration
+type MigrationStatus string
+
+const (
+	MigrationPending   MigrationStatus = "pending"
+	MigrationRunning   MigrationStatus = "running"
+	MigrationCompleted MigrationStatus = "completed"
+	MigrationFailed    MigrationStatus = "failed"
+)
+
+// MigrationOptions controls a migration run
+type MigrationOptions struct {
+	BatchSize  int  // Documents converted per batch
+	MaxBatches int  // Stop after this many batches; 0 runs until done
+	DryRun     bool // Convert without writing and without recording progress
+}
+
+// MigrationProgress is the persisted state of a migration, used to resume it
+type MigrationProgress struct {
+	Name        string
+	Status      MigrationStatus
+	LastID      string // Last processed document ID
+	Migrated    int64
+	Skipped     int64 // Documents that matched neither schema
+	Failed      int64 // Documents that could not be decoded or written
+	LastError   string
+	StartedAt   *time.Time
+	UpdatedAt   *time.Time
+	CompletedAt *time.Time
+}
+
+// MigrationSample shows how a single document would be converted
+type MigrationSample struct {
+	ID     string
+	Before map[string]interface{}
+	After  map[string]interface{}
+}
+
+// MigrationReport summarizes a migration run
+type MigrationReport struct {
+	DryRun    bool
+	Remaining int64 // Legacy documents left before this run; those the migration skipped are not counted
+	Batches   int
+	Migrated  int64
+	Skipped   int64
+	Failed    int64
+	Done      bool // No legacy documents remain after this run
+	Samples   []MigrationSample
+	Progress  *MigrationProgress
+}
+
+// SchemaMigrator converts stored Quick Response documents to the current schema
+type SchemaMigrator interface {
+	// Status returns the persisted progress and the number of legacy documents left
+	Status(ctx context.Context) (*MigrationProgress, int64, error)
+
+	// Migrate converts legacy documents in batches, resuming from the recorded progress
+	Migrate(ctx context.Context, opts MigrationOptions) (*MigrationReport, error)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportCadence represents how often an officer is expected to report
+type ReportCadence string
+
+const (
+	CadenceDaily  ReportCadence = "daily"
+	CadenceWeekly ReportCadence = "weekly"
+)
+
+// Officer represents a field officer expected to submit Quick Response reports
+type Officer struct {
+	ID         string
+	Name       string // Must match the "Nama" field used in reports
+	JID        string // Private WhatsApp JID used for reminders
+	Position   string
+	Assignment string
+	Cadence    ReportCadence
+	Weekday    time.Weekday // Reporting day for weekly cadence
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks that the officer can be tracked
+func (o *Officer) Validate() error {
+	if strings.TrimSpace(o.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if o.Cadence != CadenceDaily && o.Cadence != CadenceWeekly {
+		return fmt.Errorf("cadence must be 'daily' or 'weekly'")
+	}
+	if o.Weekday < time.Sunday || o.Weekday > time.Saturday {
+		return fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	return nil
+}
+
+// Period returns the length of the officer's reporting period
+func (o *Officer) Period() time.Duration {
+	if o.Cadence == CadenceWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Matches reports whether a report was submitted by this officer,
+// by sender JID when both are known and by name otherwise
+func (o *Officer) Matches(qr *QuickResponse) bool {
+	if o.JID != "" && qr.Sender != "" {
+		if jidUser(o.JID) == jidUser(qr.Sender) {
+			return true
+		}
+	}
+	return NormalizeName(o.Name) != "" && NormalizeName(o.Name) == NormalizeName(qr.Officer.Name)
+}
+
+// NormalizeName lowercases a name and collapses whitespace for comparisons
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// jidUser returns the user part of a JID, without device suffix or server
+func jidUser(jid string) string {
+	if i := strings.IndexAny(jid, ":@"); i >= 0 {
+		return jid[:i]
+	}
+	return jid
+}
+
+// OfficerRepository defines the contract for Officer persistence
+type OfficerRepository interface {
+	// Create saves a new officer
+	Create(officer *Officer) error
+
+	// FindByID retrieves an officer by ID
+	FindByID(id string) (*Officer, error)
+
+	// FindAll retrieves all officers
+	FindAll() ([]*Officer, error)
+
+	// FindActive retrieves all active officers
+	FindActive() ([]*Officer, error)
+
+	// Update updates an existing officer
+	Update(officer *Officer) error
+
+	// Delete removes an officer
+	Delete(id string) error
+}
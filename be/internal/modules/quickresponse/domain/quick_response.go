@@ -4,11 +4,17 @@ import "time"
 
 // QuickResponse represents a field work report from irrigation officers
 type QuickResponse struct {
-	ID        string
-	Officer   OfficerInfo
-	Activity  ActivityInfo
-	Output    OutputInfo
-	CreatedAt time.Time
+	ID         string
+	MessageID  string // WhatsApp message ID of the original report
+	DeviceName string // Device that received the report
+	Sender     string // WhatsApp JID of the reporting officer
+	Officer    OfficerInfo
+	Activity   ActivityInfo
+	Output     OutputInfo
+	Revision   int        // Current revision number (0 = original report)
+	Revisions  []Revision // Corrections applied to the report, oldest first
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // OfficerInfo contains information about the field officer
@@ -39,6 +45,21 @@ type OutputInfo struct {
 	TreeCutRemoved  string // Angkat / Potong Pohon
 }
 
+// Revision represents a correction applied to a report
+type Revision struct {
+	Number    int
+	MessageID string // WhatsApp message ID of the correction reply
+	Changes   []FieldChange
+	CreatedAt time.Time
+}
+
+// FieldChange represents a single corrected field
+type FieldChange struct {
+	Field    string // Report label, e.g. "Nama" or "Panjang Saluran"
+	OldValue string
+	NewValue string
+}
+
 // QuickResponseRepository defines the contract for QuickResponse persistence
 type QuickResponseRepository interface {
 	// Save saves a quick response report
@@ -47,6 +68,15 @@ type QuickResponseRepository interface {
 	// FindByID retrieves a quick response by ID
 	FindByID(id string) (*QuickResponse, error)
 
+	// FindByMessageID retrieves a quick response by its original WhatsApp message ID
+	FindByMessageID(messageID string) (*QuickResponse, error)
+
+	// AddRevision stores the corrected report and appends the revision to its history
+	AddRevision(qr *QuickResponse, revision Revision) error
+
+	// FindByDateRange retrieves quick responses created within [from, to), oldest first
+	FindByDateRange(from, to time.Time) ([]*QuickResponse, error)
+
 	// FindAll retrieves all quick responses with pagination
 	FindAll(skip, limit int) ([]*QuickResponse, error)
 
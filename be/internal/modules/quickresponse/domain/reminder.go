@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultReminderMessage is used when no reminder message is configured
+const DefaultReminderMessage = "Halo {name}, laporan Quick Response Anda untuk periode ini belum kami terima. Mohon segera dikirim. Terima kasih."
+
+// ReminderConfig holds the settings for missing-report reminders
+type ReminderConfig struct {
+	Enabled         bool
+	DeviceName      string // Device used to send reminders
+	CutoffTime      string // Local HH:MM after which missing reports are reminded
+	Timezone        string // IANA timezone, e.g. "Asia/Jakarta"
+	QuietHoursStart string // Local HH:MM, reminders are not sent from here...
+	QuietHoursEnd   string // ...until here; may wrap past midnight
+	MaxPerDay       int    // Maximum reminders per officer per day, failed attempts included
+	IntervalMinutes int    // Minimum minutes between reminders to the same officer
+	Message         string // Reminder text; {name} is replaced with the officer name
+	UpdatedAt       time.Time
+}
+
+// Location returns the configured timezone, falling back to UTC when unset or unknown
+func (c *ReminderConfig) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate checks that reminders can be sent with this configuration
+func (c *ReminderConfig) Validate() error {
+	if c.Enabled && c.DeviceName == "" {
+		return fmt.Errorf("device_name is required when reminders are enabled")
+	}
+	if _, _, err := parseTimeOfDay(c.CutoffTime); err != nil {
+		return fmt.Errorf("cutoff_time must be in HH:MM format")
+	}
+	if (c.QuietHoursStart == "") != (c.QuietHoursEnd == "") {
+		return fmt.Errorf("quiet_hours_start and quiet_hours_end must be set together")
+	}
+	if c.QuietHoursStart != "" {
+		if _, _, err := parseTimeOfDay(c.QuietHoursStart); err != nil {
+			return fmt.Errorf("quiet_hours_start must be in HH:MM format")
+		}
+		if _, _, err := parseTimeOfDay(c.QuietHoursEnd); err != nil {
+			return fmt.Errorf("quiet_hours_end must be in HH:MM format")
+		}
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone: %s", c.Timezone)
+		}
+	}
+	if c.MaxPerDay < 1 {
+		return fmt.Errorf("max_per_day must be at least 1")
+	}
+	if c.IntervalMinutes < 0 {
+		return fmt.Errorf("interval_minutes must not be negative")
+	}
+	return nil
+}
+
+// InQuietHours reports whether t falls within the configured quiet hours
+func (c *ReminderConfig) InQuietHours(t time.Time) bool {
+	if c.QuietHoursStart == "" || c.QuietHoursEnd == "" {
+		return false
+	}
+
+	startH, startM, err := parseTimeOfDay(c.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	endH, endM, err := parseTimeOfDay(c.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(c.Location())
+	minute := local.Hour()*60 + local.Minute()
+	start := startH*60 + startM
+	end := endH*60 + endM
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	// Quiet hours wrap past midnight, e.g. 21:00 - 06:00
+	return minute >= start || minute < end
+}
+
+// LastDeadline returns the most recent reporting deadline at or before t for the officer
+func (c *ReminderConfig) LastDeadline(officer *Officer, t time.Time) time.Time {
+	hour, minute, _ := parseTimeOfDay(c.CutoffTime)
+	local := t.In(c.Location())
+
+	deadline := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, local.Location())
+
+	if officer.Cadence == CadenceWeekly {
+		deadline = deadline.AddDate(0, 0, -((int(deadline.Weekday()) - int(officer.Weekday) + 7) % 7))
+		if deadline.After(t) {
+			deadline = deadline.AddDate(0, 0, -7)
+		}
+		return deadline
+	}
+
+	if deadline.After(t) {
+		deadline = deadline.AddDate(0, 0, -1)
+	}
+	return deadline
+}
+
+// FormatMessage renders the reminder text for an officer
+func (c *ReminderConfig) FormatMessage(officer *Officer) string {
+	message := c.Message
+	if message == "" {
+		message = DefaultReminderMessage
+	}
+	return strings.ReplaceAll(message, "{name}", officer.Name)
+}
+
+// ReminderStatus represents the delivery result of a reminder
+type ReminderStatus string
+
+const (
+	ReminderSent   ReminderStatus = "sent"
+	ReminderFailed ReminderStatus = "failed"
+)
+
+// ReminderLog records a reminder sent to an officer
+type ReminderLog struct {
+	ID          string
+	OfficerID   string
+	OfficerName string
+	JID         string
+	DeviceName  string
+	Deadline    time.Time // Deadline the reminder refers to
+	Message     string
+	Status      ReminderStatus
+	Error       string
+	CreatedAt   time.Time
+}
+
+// ReminderRepository defines the contract for reminder settings and logs
+type ReminderRepository interface {
+	// GetConfig retrieves the reminder configuration
+	GetConfig() (*ReminderConfig, error)
+
+	// SaveConfig stores the reminder configuration
+	SaveConfig(config *ReminderConfig) error
+
+	// SaveLog records a reminder attempt
+	SaveLog(log *ReminderLog) error
+
+	// FindLogs retrieves reminder logs with pagination, newest first
+	FindLogs(skip, limit int) ([]*ReminderLog, error)
+
+	// FindLogsSince retrieves the reminders sent or attempted to an officer since the given time, newest first
+	FindLogsSince(officerID string, since time.Time) ([]*ReminderLog, error)
+}
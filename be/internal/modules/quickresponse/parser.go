@@ -125,6 +125,113 @@ func (p *Parser) parseOutput(key, value string, output *domain.OutputInfo) {
 	}
 }
 
+// ParseCorrection extracts the known "label: value" lines from a correction reply.
+// Section headers are optional since every label is unique across sections.
+func (p *Parser) ParseCorrection(message string) map[string]string {
+	fields := make(map[string]string)
+
+	for _, line := range strings.Split(message, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+
+		if fieldRef(&domain.QuickResponse{}, key) == nil {
+			continue
+		}
+		fields[key] = val
+	}
+
+	return fields
+}
+
+// ApplyCorrection applies corrected fields to a report and returns what changed
+func (p *Parser) ApplyCorrection(qr *domain.QuickResponse, fields map[string]string) []domain.FieldChange {
+	changes := make([]domain.FieldChange, 0, len(fields))
+
+	for _, label := range fieldLabels {
+		newValue, ok := fields[label]
+		if !ok {
+			continue
+		}
+
+		ref := fieldRef(qr, label)
+		if *ref == newValue {
+			continue
+		}
+
+		changes = append(changes, domain.FieldChange{
+			Field:    label,
+			OldValue: *ref,
+			NewValue: newValue,
+		})
+		*ref = newValue
+	}
+
+	return changes
+}
+
+// fieldLabels lists every report label in message order
+var fieldLabels = []string{
+	"Nama",
+	"Jabatan",
+	"D.I Penugasan",
+	"Metode Penugasan",
+	"Kegiatan Quick Respons",
+	"D.I Quick Respons",
+	"Saluran Quick Respons",
+	"Ruas Bangunan Quick Respons",
+	"Desa / Kecamatan / Kabupaten Quick Respons",
+	"UPT PSDA WS",
+	"Luas Area Kegiatan",
+	"Panjang Saluran",
+	"Menutup Bocoran",
+	"Angkat Sedimen",
+	"Pembersihan Sampah",
+	"Angkat / Potong Pohon",
+}
+
+// fieldRef returns a pointer to the report field for a label, or nil if the label is unknown
+func fieldRef(qr *domain.QuickResponse, label string) *string {
+	switch label {
+	case "Nama":
+		return &qr.Officer.Name
+	case "Jabatan":
+		return &qr.Officer.Position
+	case "D.I Penugasan":
+		return &qr.Officer.Assignment
+	case "Metode Penugasan":
+		return &qr.Activity.Method
+	case "Kegiatan Quick Respons":
+		return &qr.Activity.ActivityType
+	case "D.I Quick Respons":
+		return &qr.Activity.IrrigationDI
+	case "Saluran Quick Respons":
+		return &qr.Activity.Channel
+	case "Ruas Bangunan Quick Respons":
+		return &qr.Activity.BuildingRoute
+	case "Desa / Kecamatan / Kabupaten Quick Respons":
+		return &qr.Activity.Location
+	case "UPT PSDA WS":
+		return &qr.Activity.WatershedUnit
+	case "Luas Area Kegiatan":
+		return &qr.Output.AreaSize
+	case "Panjang Saluran":
+		return &qr.Output.ChannelLength
+	case "Menutup Bocoran":
+		return &qr.Output.LeaksClosed
+	case "Angkat Sedimen":
+		return &qr.Output.SedimentRemoved
+	case "Pembersihan Sampah":
+		return &qr.Output.TrashCleared
+	case "Angkat / Potong Pohon":
+		return &qr.Output.TreeCutRemoved
+	}
+	return nil
+}
+
 // IsValid checks if a parsed QuickResponse is valid
 func (p *Parser) IsValid(qr *domain.QuickResponse) bool {
 	// At minimum, officer info must be present
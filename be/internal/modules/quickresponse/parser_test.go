@@ -0,0 +1,75 @@
+package quickresponse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+)
+
+func TestParseCorrection(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    map[string]string
+	}{
+		{
+			name:    "single field",
+			message: "Luas Area Kegiatan: 120 m2",
+			want:    map[string]string{"Luas Area Kegiatan": "120 m2"},
+		},
+		{
+			name:    "with section headers and blank lines",
+			message: "*DATA PETUGAS*\nNama : Budi Santoso\n\n*KEGIATAN*\n  Saluran Quick Respons:  Sekunder Kiri  ",
+			want:    map[string]string{"Nama": "Budi Santoso", "Saluran Quick Respons": "Sekunder Kiri"},
+		},
+		{
+			name:    "value containing a colon",
+			message: "Kegiatan Quick Respons: Pembersihan: tahap 2",
+			want:    map[string]string{"Kegiatan Quick Respons": "Pembersihan: tahap 2"},
+		},
+		{
+			name:    "empty value",
+			message: "Menutup Bocoran:",
+			want:    map[string]string{"Menutup Bocoran": ""},
+		},
+		{
+			name:    "unknown labels ignored",
+			message: "Catatan: salah ketik\nnama: Budi\nPanjang Saluran: 40 m",
+			want:    map[string]string{"Panjang Saluran": "40 m"},
+		},
+		{
+			name:    "no fields",
+			message: "mohon maaf, ada yang salah",
+			want:    map[string]string{},
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parser.ParseCorrection(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCorrection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCorrection(t *testing.T) {
+	qr := &domain.QuickResponse{}
+	qr.Officer.Name = "Budi"
+	qr.Activity.Channel = "Primer"
+
+	changes := NewParser().ApplyCorrection(qr, map[string]string{
+		"Nama":                  "Budi",
+		"Saluran Quick Respons": "Sekunder",
+	})
+
+	want := []domain.FieldChange{{Field: "Saluran Quick Respons", OldValue: "Primer", NewValue: "Sekunder"}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("ApplyCorrection() = %+v, want %+v", changes, want)
+	}
+	if qr.Activity.Channel != "Sekunder" {
+		t.Errorf("Channel = %q, want %q", qr.Activity.Channel, "Sekunder")
+	}
+}
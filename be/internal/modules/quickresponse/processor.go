@@ -1,6 +1,9 @@
 package quickresponse
 
 import (
+	"strings"
+	"time"
+
 	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
 	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
 	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
@@ -30,7 +33,12 @@ func (p *Processor) Name() string {
 
 // CanProcess checks if this processor can handle the message
 func (p *Processor) CanProcess(message domain.IncomingMessage) bool {
-	return p.parser.CanParse(message.Content)
+	if p.parser.CanParse(message.Content) {
+		return true
+	}
+
+	// Replies carrying corrected lines for an earlier report
+	return message.QuotedMessageID != "" && len(p.parser.ParseCorrection(message.Content)) > 0
 }
 
 // Process processes the Quick Response message
@@ -40,12 +48,28 @@ func (p *Processor) Process(message domain.IncomingMessage) error {
 		"from":   message.From,
 	}).Info("Processing Quick Response message")
 
-	// Parse message
-	qr := p.parser.Parse(message.Content)
+	// A reply to an earlier report is a correction of that report
+	if message.QuotedMessageID != "" {
+		original, err := p.repository.FindByMessageID(message.QuotedMessageID)
+		if err == nil {
+			return p.processCorrection(original, message)
+		}
+		if apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+			p.logger.Error("Failed to look up quoted report: %v", err)
+			return err
+		}
+		if !p.parser.CanParse(message.Content) {
+			p.logger.Warn("Message skipped: quoted message is not a known report")
+			return nil
+		}
+	}
 
-	// Validate
-	if !p.parser.IsValid(qr) {
-		p.logger.Warn("Message skipped: no valid officer data")
+	// Parse and validate message
+	qr, err := p.build(message)
+	if err != nil {
+		return err
+	}
+	if qr == nil {
 		return nil // Not an error, just skip
 	}
 
@@ -63,7 +87,105 @@ func (p *Processor) Process(message domain.IncomingMessage) error {
 	return nil
 }
 
+// Preview returns the report that would be saved for the message, or nil if it
+// would be skipped. Corrections are not previewed.
+func (p *Processor) Preview(message domain.IncomingMessage) (interface{}, error) {
+	if message.QuotedMessageID != "" {
+		return nil, nil
+	}
+
+	qr, err := p.build(message)
+	if err != nil || qr == nil {
+		return nil, err
+	}
+	return qr, nil
+}
+
+// build parses a new report from the message. It returns nil when the message
+// holds no officer data, or when an imported message was already saved.
+func (p *Processor) build(message domain.IncomingMessage) (*qrDomain.QuickResponse, error) {
+	qr := p.parser.Parse(message.Content)
+	qr.MessageID = message.ID
+	qr.DeviceName = message.DeviceName
+	qr.Sender = message.From
+
+	if !p.parser.IsValid(qr) {
+		p.logger.Warn("Message skipped: no valid officer data")
+		return nil, nil
+	}
+
+	if !message.Imported {
+		return qr, nil
+	}
+
+	// Imported reports keep their original send time and may be imported more than once
+	if !message.Timestamp.IsZero() {
+		qr.CreatedAt = message.Timestamp
+	}
+
+	_, err := p.repository.FindByMessageID(message.ID)
+	if err == nil {
+		p.logger.WithField("message_id", message.ID).Info("Message skipped: report already imported")
+		return nil, nil
+	}
+	if apperrors.GetAppError(err).Type != apperrors.ErrorTypeNotFound {
+		p.logger.Error("Failed to look up imported report: %v", err)
+		return nil, err
+	}
+
+	return qr, nil
+}
+
+// processCorrection applies the corrected lines of a reply as a new revision of the original report
+func (p *Processor) processCorrection(original *qrDomain.QuickResponse, message domain.IncomingMessage) error {
+	// Only the officer who sent the report may correct it
+	if !sameSender(original.Sender, message.From) {
+		p.logger.WithFields(map[string]interface{}{
+			"id":   original.ID,
+			"from": message.From,
+		}).Warn("Correction skipped: sender is not the author of the report")
+		return nil
+	}
+
+	changes := p.parser.ApplyCorrection(original, p.parser.ParseCorrection(message.Content))
+	if len(changes) == 0 {
+		p.logger.WithField("id", original.ID).Info("Correction skipped: no fields changed")
+		return nil
+	}
+
+	revision := qrDomain.Revision{
+		Number:    original.Revision + 1,
+		MessageID: message.ID,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+
+	if err := p.repository.AddRevision(original, revision); err != nil {
+		p.logger.Error("Failed to save Quick Response revision: %v", err)
+		return apperrors.NewDatabaseError("Failed to save Quick Response revision", err)
+	}
+
+	p.logger.WithFields(map[string]interface{}{
+		"id":       original.ID,
+		"revision": revision.Number,
+		"changes":  len(changes),
+	}).Success("Quick Response corrected")
+
+	return nil
+}
+
 // Priority returns the processor priority
 func (p *Processor) Priority() int {
 	return 100 // High priority for Quick Response messages
 }
+
+// sameSender compares two JIDs by their user part, ignoring device and server suffixes
+func sameSender(a, b string) bool {
+	user := func(jid string) string {
+		if i := strings.IndexAny(jid, ":@"); i >= 0 {
+			return jid[:i]
+		}
+		return jid
+	}
+	return a != "" && user(a) == user(b)
+}
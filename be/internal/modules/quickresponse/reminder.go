@@ -0,0 +1,269 @@
+package quickresponse
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// ReminderResult summarizes a single reminder run
+type ReminderResult struct {
+	Checked int  // Active officers checked
+	Missing int  // Officers without a report for their current period
+	Sent    int  // Reminders sent
+	Failed  int  // Reminders that could not be delivered
+	Skipped int  // Missing officers not reminded (no JID, daily limit or interval)
+	Quiet   bool // Run skipped because of quiet hours
+	Offline bool // Nobody reminded because the reminder device is not connected
+}
+
+// ReminderService sends private reminders to officers who have not reported
+type ReminderService struct {
+	reports   domain.QuickResponseRepository
+	officers  domain.OfficerRepository
+	reminders domain.ReminderRepository
+	whatsapp  ports.WhatsAppService
+	logger    *logger.Logger
+
+	mu sync.Mutex // Serializes runs so limits are not exceeded by overlapping runs
+}
+
+// NewReminderService creates a new ReminderService
+func NewReminderService(
+	reports domain.QuickResponseRepository,
+	officers domain.OfficerRepository,
+	reminders domain.ReminderRepository,
+	whatsapp ports.WhatsAppService,
+) *ReminderService {
+	return &ReminderService{
+		reports:   reports,
+		officers:  officers,
+		reminders: reminders,
+		whatsapp:  whatsapp,
+		logger:    logger.New("ReminderService"),
+	}
+}
+
+// Run reminds every active officer whose report for the current period is missing
+func (s *ReminderService) Run(ctx context.Context, now time.Time) (*ReminderResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &ReminderResult{}
+
+	config, err := s.reminders.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled {
+		return result, nil
+	}
+	if config.InQuietHours(now) {
+		result.Quiet = true
+		return result, nil
+	}
+
+	missing, checked, err := s.findMissing(config, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Checked = checked
+	result.Missing = len(missing)
+
+	// Sending would only fail; wait for the device instead of logging a
+	// failed attempt per officer every run
+	if len(missing) > 0 && !s.whatsapp.IsDeviceConnected(config.DeviceName) {
+		s.logger.WithField("device", config.DeviceName).Debug("Reminder device is not connected, reminders wait for it")
+		result.Offline = true
+		return result, nil
+	}
+
+	loc := config.Location()
+	local := now.In(loc)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	interval := time.Duration(config.IntervalMinutes) * time.Minute
+
+	for _, officer := range missing {
+		log := s.logger.WithFields(map[string]interface{}{
+			"officer": officer.Name,
+			"jid":     officer.JID,
+		})
+
+		if officer.JID == "" {
+			log.Warn("Officer has no WhatsApp JID, skipping reminder")
+			result.Skipped++
+			continue
+		}
+
+		// Failed attempts count too, so that an officer who cannot be reached
+		// is not tried again every run
+		attemptsToday, err := s.reminders.FindLogsSince(officer.ID, startOfDay)
+		if err != nil {
+			return result, err
+		}
+		if len(attemptsToday) >= config.MaxPerDay {
+			result.Skipped++
+			continue
+		}
+		if len(attemptsToday) > 0 && now.Sub(attemptsToday[0].CreatedAt) < interval {
+			result.Skipped++
+			continue
+		}
+
+		entry := &domain.ReminderLog{
+			OfficerID:   officer.ID,
+			OfficerName: officer.Name,
+			JID:         officer.JID,
+			DeviceName:  config.DeviceName,
+			Deadline:    config.LastDeadline(officer, now),
+			Message:     config.FormatMessage(officer),
+			Status:      domain.ReminderSent,
+			CreatedAt:   now,
+		}
+
+		err = s.whatsapp.SendTextMessage(ctx, config.DeviceName, officer.JID, entry.Message, coreDomain.ReceiverIndividual)
+		if err != nil {
+			log.Error("Failed to send reminder: %v", err)
+			entry.Status = domain.ReminderFailed
+			entry.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Sent++
+		}
+
+		if err := s.reminders.SaveLog(entry); err != nil {
+			log.Error("Failed to log reminder: %v", err)
+		}
+	}
+
+	if result.Sent > 0 || result.Failed > 0 {
+		s.logger.WithFields(map[string]interface{}{
+			"missing": result.Missing,
+			"sent":    result.Sent,
+			"failed":  result.Failed,
+		}).Info("Reminders processed")
+	}
+
+	return result, nil
+}
+
+// findMissing returns active officers whose deadline has passed without a report
+func (s *ReminderService) findMissing(config *domain.ReminderConfig, now time.Time) ([]*domain.Officer, int, error) {
+	officers, err := s.officers.FindActive()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(officers) == 0 {
+		return nil, 0, nil
+	}
+
+	// A weekly period plus a day of slack covers every officer's window
+	reports, err := s.reports.FindByDateRange(now.AddDate(0, 0, -8), now.Add(time.Minute))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var missing []*domain.Officer
+	for _, officer := range officers {
+		deadline := config.LastDeadline(officer, now)
+
+		// Officers added after the deadline are not expected to report yet
+		if deadline.Before(officer.CreatedAt) {
+			continue
+		}
+
+		if !hasReportSince(officer, reports, deadline.Add(-officer.Period())) {
+			missing = append(missing, officer)
+		}
+	}
+
+	return missing, len(officers), nil
+}
+
+// hasReportSince reports whether the officer submitted a report at or after since
+func hasReportSince(officer *domain.Officer, reports []*domain.QuickResponse, since time.Time) bool {
+	for _, qr := range reports {
+		if !qr.CreatedAt.Before(since) && officer.Matches(qr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderJob periodically runs the ReminderService in the background
+type ReminderJob struct {
+	service  *ReminderService
+	interval time.Duration
+	logger   *logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReminderJob creates a new ReminderJob that runs every interval
+func NewReminderJob(service *ReminderService, interval time.Duration) *ReminderJob {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &ReminderJob{
+		service:  service,
+		interval: interval,
+		logger:   logger.New("ReminderJob"),
+	}
+}
+
+// Start begins running reminders in the background
+func (j *ReminderJob) Start(ctx context.Context) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancel != nil {
+		return
+	}
+
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if _, err := j.service.Run(ctx, now); err != nil {
+					j.logger.Error("Reminder run failed: %v", err)
+				}
+			}
+		}
+	}(j.done)
+
+	j.logger.WithField("interval", j.interval.String()).Info("Reminder job started")
+}
+
+// Stop stops the job and waits for the current run to finish
+func (j *ReminderJob) Stop() {
+	j.mu.Lock()
+	cancel, done := j.cancel, j.done
+	j.cancel = nil
+	j.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	j.logger.Info("Reminder job stopped")
+}
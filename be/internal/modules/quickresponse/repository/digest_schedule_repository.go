@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DigestScheduleMongoRepository implements DigestScheduleRepository using MongoDB
+type DigestScheduleMongoRepository struct {
+	collection *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoDigestSchedule represents the MongoDB document structure
+type mongoDigestSchedule struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	Name              string             `bson:"name"`
+	Frequency         string             `bson:"frequency"`
+	Weekday           int                `bson:"weekday"`
+	TimeOfDay         string             `bson:"time_of_day"`
+	Timezone          string             `bson:"timezone"`
+	DeviceName        string             `bson:"device_name"`
+	GroupJIDs         []string           `bson:"group_jids"`
+	IncludeAttachment bool               `bson:"include_attachment"`
+	Enabled           bool               `bson:"enabled"`
+	LastRunAt         int64              `bson:"last_run_at,omitempty"`
+	CreatedBy         string             `bson:"created_by"`
+	CreatedAt         int64              `bson:"created_at"`
+	UpdatedAt         int64              `bson:"updated_at"`
+}
+
+// NewDigestScheduleMongoRepository creates a new MongoDB repository for digest schedules
+func NewDigestScheduleMongoRepository(db *mongo.Database) domain.DigestScheduleRepository {
+	collection := db.Collection("qr_digest_schedules")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "enabled", Value: 1}},
+	})
+
+	return &DigestScheduleMongoRepository{
+		collection: collection,
+		logger:     logger.New("DigestScheduleRepository"),
+	}
+}
+
+// Create saves a new digest schedule
+func (r *DigestScheduleMongoRepository) Create(schedule *domain.DigestSchedule) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	result, err := r.collection.InsertOne(ctx, toMongoDigestSchedule(schedule))
+	if err != nil {
+		r.logger.Error("Failed to insert digest schedule: %v", err)
+		return apperrors.NewDatabaseError("Failed to save digest schedule", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		schedule.ID = oid.Hex()
+	}
+
+	r.logger.WithField("id", schedule.ID).Success("Digest schedule saved")
+	return nil
+}
+
+// FindByID retrieves a digest schedule by ID
+func (r *DigestScheduleMongoRepository) FindByID(id string) (*domain.DigestSchedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoDigestSchedule
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Digest schedule")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find digest schedule: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve digest schedule", err)
+	}
+
+	return toDomainDigestSchedule(&doc), nil
+}
+
+// FindAll retrieves all digest schedules
+func (r *DigestScheduleMongoRepository) FindAll() ([]*domain.DigestSchedule, error) {
+	return r.find(bson.M{})
+}
+
+// FindEnabled retrieves all enabled digest schedules
+func (r *DigestScheduleMongoRepository) FindEnabled() ([]*domain.DigestSchedule, error) {
+	return r.find(bson.M{"enabled": true})
+}
+
+// Update updates an existing digest schedule
+func (r *DigestScheduleMongoRepository) Update(schedule *domain.DigestSchedule) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(schedule.ID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	schedule.UpdatedAt = time.Now()
+	doc := toMongoDigestSchedule(schedule)
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":               doc.Name,
+			"frequency":          doc.Frequency,
+			"weekday":            doc.Weekday,
+			"time_of_day":        doc.TimeOfDay,
+			"timezone":           doc.Timezone,
+			"device_name":        doc.DeviceName,
+			"group_jids":         doc.GroupJIDs,
+			"include_attachment": doc.IncludeAttachment,
+			"enabled":            doc.Enabled,
+			"updated_at":         doc.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		r.logger.Error("Failed to update digest schedule: %v", err)
+		return apperrors.NewDatabaseError("Failed to update digest schedule", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return apperrors.NewNotFoundError("Digest schedule")
+	}
+
+	r.logger.WithField("id", schedule.ID).Success("Digest schedule updated")
+	return nil
+}
+
+// MarkRun records the last time a digest schedule was run
+func (r *DigestScheduleMongoRepository) MarkRun(id string, runAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set": bson.M{"last_run_at": runAt.Unix()},
+	})
+	if err != nil {
+		r.logger.Error("Failed to mark digest schedule run: %v", err)
+		return apperrors.NewDatabaseError("Failed to update digest schedule", err)
+	}
+
+	return nil
+}
+
+// Delete removes a digest schedule
+func (r *DigestScheduleMongoRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("Failed to delete digest schedule: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete digest schedule", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return apperrors.NewNotFoundError("Digest schedule")
+	}
+
+	r.logger.WithField("id", id).Success("Digest schedule deleted")
+	return nil
+}
+
+// find retrieves digest schedules matching the filter, oldest first
+func (r *DigestScheduleMongoRepository) find(filter bson.M) ([]*domain.DigestSchedule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find digest schedules: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve digest schedules", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.DigestSchedule, 0)
+	for cursor.Next(ctx) {
+		var doc mongoDigestSchedule
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainDigestSchedule(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate digest schedules", err)
+	}
+
+	return results, nil
+}
+
+// toMongoDigestSchedule converts domain entity to MongoDB document
+func toMongoDigestSchedule(schedule *domain.DigestSchedule) *mongoDigestSchedule {
+	doc := &mongoDigestSchedule{
+		Name:              schedule.Name,
+		Frequency:         string(schedule.Frequency),
+		Weekday:           int(schedule.Weekday),
+		TimeOfDay:         schedule.TimeOfDay,
+		Timezone:          schedule.Timezone,
+		DeviceName:        schedule.DeviceName,
+		GroupJIDs:         schedule.GroupJIDs,
+		IncludeAttachment: schedule.IncludeAttachment,
+		Enabled:           schedule.Enabled,
+		CreatedBy:         schedule.CreatedBy,
+		CreatedAt:         schedule.CreatedAt.Unix(),
+		UpdatedAt:         schedule.UpdatedAt.Unix(),
+	}
+
+	if schedule.LastRunAt != nil {
+		doc.LastRunAt = schedule.LastRunAt.Unix()
+	}
+
+	return doc
+}
+
+// toDomainDigestSchedule converts MongoDB document to domain entity
+func toDomainDigestSchedule(doc *mongoDigestSchedule) *domain.DigestSchedule {
+	schedule := &domain.DigestSchedule{
+		ID:                doc.ID.Hex(),
+		Name:              doc.Name,
+		Frequency:         domain.DigestFrequency(doc.Frequency),
+		Weekday:           time.Weekday(doc.Weekday),
+		TimeOfDay:         doc.TimeOfDay,
+		Timezone:          doc.Timezone,
+		DeviceName:        doc.DeviceName,
+		GroupJIDs:         doc.GroupJIDs,
+		IncludeAttachment: doc.IncludeAttachment,
+		Enabled:           doc.Enabled,
+		CreatedBy:         doc.CreatedBy,
+		CreatedAt:         time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:         time.Unix(doc.UpdatedAt, 0),
+	}
+
+	if doc.LastRunAt != 0 {
+		lastRun := time.Unix(doc.LastRunAt, 0)
+		schedule.LastRunAt = &lastRun
+	}
+
+	return schedule
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// legacyQuickResponse represents documents written before schema version 2,
+// both by models.QuickResponse and by earlier versions of this repository.
+// Keys are Indonesian and timestamps are unix seconds.
+type legacyQuickResponse struct {
+	ID                     primitive.ObjectID `bson:"_id,omitempty"`
+	MessageID              string             `bson:"message_id,omitempty"`
+	DeviceName             string             `bson:"device_name,omitempty"`
+	Sender                 string             `bson:"sender,omitempty"`
+	Petugas                legacyOfficer      `bson:"petugas"`
+	IdentifikasiKegiatanQR legacyActivity     `bson:"identifikasi_kegiatan_qr"`
+	OutputKegiatanQR       legacyOutput       `bson:"output_kegiatan_qr"`
+	Revision               int                `bson:"revision,omitempty"`
+	Revisions              []legacyRevision   `bson:"revisions,omitempty"`
+	CreatedAt              int64              `bson:"created_at"`
+	UpdatedAt              int64              `bson:"updated_at,omitempty"`
+}
+
+type legacyOfficer struct {
+	Nama        string `bson:"nama"`
+	Jabatan     string `bson:"jabatan"`
+	DiPenugasan string `bson:"di_penugasan"`
+}
+
+type legacyActivity struct {
+	MetodePenugasan    string `bson:"metode_penugasan"`
+	KegiatanQR         string `bson:"kegiatan_qr"`
+	DIQR               string `bson:"di_qr"`
+	SaluranQR          string `bson:"saluran_qr"`
+	RuasBangunanQR     string `bson:"ruas_bangunan_qr"`
+	DesaKecamatanKabQR string `bson:"desa_kecamatan_kab_qr"`
+	UPTPSDAWS          string `bson:"upt_psda_ws"`
+}
+
+type legacyOutput struct {
+	LuasAreaKegiatan  string `bson:"luas_area_kegiatan"`
+	PanjangSaluran    string `bson:"panjang_saluran"`
+	MenutupBocoran    string `bson:"menutup_bocoran"`
+	AngkatSedimen     string `bson:"angkat_sedimen"`
+	PembersihanSampah string `bson:"pembersihan_sampah"`
+	AngkatPotongPohon string `bson:"angkat_potong_pohon"`
+}
+
+type legacyRevision struct {
+	Number    int                `bson:"number"`
+	MessageID string             `bson:"message_id"`
+	Changes   []mongoFieldChange `bson:"changes"`
+	CreatedAt int64              `bson:"created_at"`
+}
+
+// isLegacyDocument reports whether a raw document predates schema version 2
+func isLegacyDocument(raw bson.Raw) bool {
+	_, err := raw.LookupErr("schema_version")
+	return err != nil
+}
+
+// hasLegacyShape reports whether a raw document looks like a legacy report,
+// i.e. it has the Indonesian sections or a numeric created_at
+func hasLegacyShape(raw bson.Raw) bool {
+	if _, err := raw.LookupErr("petugas"); err == nil {
+		return true
+	}
+	if _, err := raw.LookupErr("identifikasi_kegiatan_qr"); err == nil {
+		return true
+	}
+	if value, err := raw.LookupErr("created_at"); err == nil {
+		switch value.Type {
+		case bson.TypeInt64, bson.TypeInt32, bson.TypeDouble:
+			return true
+		}
+	}
+	return false
+}
+
+// toDomainEntity converts a legacy document to a domain entity
+func (doc *legacyQuickResponse) toDomainEntity() *domain.QuickResponse {
+	revisions := make([]domain.Revision, 0, len(doc.Revisions))
+	for _, rev := range doc.Revisions {
+		changes := make([]domain.FieldChange, 0, len(rev.Changes))
+		for _, change := range rev.Changes {
+			changes = append(changes, domain.FieldChange{
+				Field:    change.Field,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			})
+		}
+		revisions = append(revisions, domain.Revision{
+			Number:    rev.Number,
+			MessageID: rev.MessageID,
+			Changes:   changes,
+			CreatedAt: time.Unix(rev.CreatedAt, 0),
+		})
+	}
+
+	qr := &domain.QuickResponse{
+		ID:         doc.ID.Hex(),
+		MessageID:  doc.MessageID,
+		DeviceName: doc.DeviceName,
+		Sender:     doc.Sender,
+		Officer: domain.OfficerInfo{
+			Name:       doc.Petugas.Nama,
+			Position:   doc.Petugas.Jabatan,
+			Assignment: doc.Petugas.DiPenugasan,
+		},
+		Activity: domain.ActivityInfo{
+			Method:        doc.IdentifikasiKegiatanQR.MetodePenugasan,
+			ActivityType:  doc.IdentifikasiKegiatanQR.KegiatanQR,
+			IrrigationDI:  doc.IdentifikasiKegiatanQR.DIQR,
+			Channel:       doc.IdentifikasiKegiatanQR.SaluranQR,
+			BuildingRoute: doc.IdentifikasiKegiatanQR.RuasBangunanQR,
+			Location:      doc.IdentifikasiKegiatanQR.DesaKecamatanKabQR,
+			WatershedUnit: doc.IdentifikasiKegiatanQR.UPTPSDAWS,
+		},
+		Output: domain.OutputInfo{
+			AreaSize:        doc.OutputKegiatanQR.LuasAreaKegiatan,
+			ChannelLength:   doc.OutputKegiatanQR.PanjangSaluran,
+			LeaksClosed:     doc.OutputKegiatanQR.MenutupBocoran,
+			SedimentRemoved: doc.OutputKegiatanQR.AngkatSedimen,
+			TrashCleared:    doc.OutputKegiatanQR.PembersihanSampah,
+			TreeCutRemoved:  doc.OutputKegiatanQR.AngkatPotongPohon,
+		},
+		Revision:  doc.Revision,
+		Revisions: revisions,
+		CreatedAt: time.Unix(doc.CreatedAt, 0),
+	}
+
+	if doc.UpdatedAt != 0 {
+		qr.UpdatedAt = time.Unix(doc.UpdatedAt, 0)
+	}
+
+	return qr
+}
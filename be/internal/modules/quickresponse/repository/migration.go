@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// schemaV2MigrationName identifies the legacy to version 2 migration in the migrations collection
+	schemaV2MigrationName = "quick_responses_v2"
+
+	defaultMigrationBatchSize = 500
+	maxMigrationBatchSize     = 5000
+	maxMigrationSamples       = 5
+)
+
+// legacyFilter matches documents written before schema version 2 that the
+// migration has not given up on
+var legacyFilter = bson.M{
+	"schema_version":    bson.M{"$exists": false},
+	"migration_skipped": bson.M{"$exists": false},
+}
+
+// SchemaV2Migrator converts legacy Quick Response documents to schema version 2
+type SchemaV2Migrator struct {
+	collection *mongo.Collection
+	migrations *mongo.Collection
+	logger     *logger.Logger
+
+	mu sync.Mutex // Prevents concurrent runs within this process
+}
+
+// mongoMigrationProgress represents the MongoDB progress document
+type mongoMigrationProgress struct {
+	ID          string              `bson:"_id"`
+	Status      string              `bson:"status"`
+	LastID      *primitive.ObjectID `bson:"last_id,omitempty"`
+	Migrated    int64               `bson:"migrated"`
+	Skipped     int64               `bson:"skipped"`
+	Failed      int64               `bson:"failed"`
+	LastError   string              `bson:"last_error,omitempty"`
+	StartedAt   *time.Time          `bson:"started_at,omitempty"`
+	UpdatedAt   *time.Time          `bson:"updated_at,omitempty"`
+	CompletedAt *time.Time          `bson:"completed_at,omitempty"`
+}
+
+// NewSchemaV2Migrator creates a new migrator for the quick_responses collection
+func NewSchemaV2Migrator(db *mongo.Database) domain.SchemaMigrator {
+	return &SchemaV2Migrator{
+		collection: db.Collection("quick_responses"),
+		migrations: db.Collection("migrations"),
+		logger:     logger.New("QuickResponseMigrator"),
+	}
+}
+
+// Status returns the persisted progress and the number of legacy documents left
+func (m *SchemaV2Migrator) Status(ctx context.Context) (*domain.MigrationProgress, int64, error) {
+	progress, err := m.loadProgress(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	remaining, err := m.collection.CountDocuments(ctx, legacyFilter)
+	if err != nil {
+		return nil, 0, apperrors.NewDatabaseError("Failed to count legacy quick responses", err)
+	}
+
+	return progress.toDomain(), remaining, nil
+}
+
+// Migrate converts legacy documents in batches, resuming from the recorded progress
+func (m *SchemaV2Migrator) Migrate(ctx context.Context, opts domain.MigrationOptions) (*domain.MigrationReport, error) {
+	if !m.mu.TryLock() {
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, "Migration is already running")
+	}
+	defer m.mu.Unlock()
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultMigrationBatchSize
+	}
+	if opts.BatchSize > maxMigrationBatchSize {
+		opts.BatchSize = maxMigrationBatchSize
+	}
+
+	remaining, err := m.collection.CountDocuments(ctx, legacyFilter)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to count legacy quick responses", err)
+	}
+
+	progress, err := m.loadProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Completed or never-run migrations start over to pick up documents written since
+	resumable := progress.Status == string(domain.MigrationRunning) || progress.Status == string(domain.MigrationFailed)
+	if opts.DryRun || !resumable {
+		now := time.Now()
+		progress = &mongoMigrationProgress{
+			ID:        schemaV2MigrationName,
+			Status:    string(domain.MigrationRunning),
+			StartedAt: &now,
+		}
+	}
+	progress.Status = string(domain.MigrationRunning)
+	progress.LastError = ""
+
+	report := &domain.MigrationReport{DryRun: opts.DryRun, Remaining: remaining}
+	log := m.logger.WithFields(map[string]interface{}{
+		"dry_run":    opts.DryRun,
+		"batch_size": opts.BatchSize,
+		"remaining":  remaining,
+	})
+	log.Info("Starting quick response schema migration")
+
+	// A dry run does not mark the documents it skips, so it leaves out those
+	// it cannot page past by ID itself
+	var unmarked []interface{}
+
+	for opts.MaxBatches == 0 || report.Batches < opts.MaxBatches {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+
+		processed, err := m.migrateBatch(ctx, progress, report, opts, &unmarked)
+		if err != nil {
+			progress.Status = string(domain.MigrationFailed)
+			progress.LastError = err.Error()
+			if !opts.DryRun {
+				_ = m.saveProgress(context.Background(), progress)
+			}
+			log.Error("Migration batch failed: %v", err)
+			return nil, apperrors.NewDatabaseError("Migration batch failed", err)
+		}
+
+		if processed == 0 {
+			report.Done = true
+			break
+		}
+		report.Batches++
+
+		if !opts.DryRun {
+			if err := m.saveProgress(ctx, progress); err != nil {
+				return nil, err
+			}
+		}
+
+		if processed < opts.BatchSize {
+			report.Done = true
+			break
+		}
+	}
+
+	if report.Done {
+		now := time.Now()
+		progress.Status = string(domain.MigrationCompleted)
+		progress.CompletedAt = &now
+		if !opts.DryRun {
+			if err := m.saveProgress(ctx, progress); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report.Progress = progress.toDomain()
+	log.WithFields(map[string]interface{}{
+		"migrated": report.Migrated,
+		"skipped":  report.Skipped,
+		"failed":   report.Failed,
+		"done":     report.Done,
+	}).Success("Quick response schema migration finished")
+
+	return report, nil
+}
+
+// migrateBatch converts the next batch of legacy documents after
+// progress.LastID, leaving out the unmarked documents a dry run skipped
+func (m *SchemaV2Migrator) migrateBatch(ctx context.Context, progress *mongoMigrationProgress, report *domain.MigrationReport, opts domain.MigrationOptions, unmarked *[]interface{}) (int, error) {
+	filter := bson.M{}
+	for key, value := range legacyFilter {
+		filter[key] = value
+	}
+	idFilter := bson.M{}
+	if progress.LastID != nil {
+		idFilter["$gt"] = *progress.LastID
+	}
+	if len(*unmarked) > 0 {
+		idFilter["$nin"] = *unmarked
+	}
+	if len(idFilter) > 0 {
+		filter["_id"] = idFilter
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(opts.BatchSize))
+
+	cursor, err := m.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	processed := 0
+	for cursor.Next(ctx) {
+		processed++
+		raw := cursor.Current
+
+		var idDoc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := bson.Unmarshal(raw, &idDoc); err != nil {
+			// Documents without an ObjectID sort apart from the others and
+			// cannot be resumed past; skip them by their own ID instead
+			rawID := raw.Lookup("_id")
+			if err := m.skip(ctx, rawID, "has no ObjectID", opts.DryRun); err != nil {
+				return processed, err
+			}
+			if opts.DryRun {
+				*unmarked = append(*unmarked, rawID)
+			}
+			progress.Skipped++
+			report.Skipped++
+			continue
+		}
+		id := idDoc.ID
+		progress.LastID = &id
+
+		if !hasLegacyShape(raw) {
+			if err := m.skip(ctx, id, "matches neither schema", opts.DryRun); err != nil {
+				return processed, err
+			}
+			progress.Skipped++
+			report.Skipped++
+			continue
+		}
+
+		var legacy legacyQuickResponse
+		if err := bson.Unmarshal(raw, &legacy); err != nil {
+			m.logger.WithField("id", id.Hex()).Warn("Failed to decode legacy document: %v", err)
+			if err := m.skip(ctx, id, "cannot be decoded: "+err.Error(), opts.DryRun); err != nil {
+				return processed, err
+			}
+			progress.Failed++
+			progress.LastError = err.Error()
+			report.Failed++
+			continue
+		}
+
+		converted := toMongoDocument(legacy.toDomainEntity())
+		converted.ID = id
+
+		if len(report.Samples) < maxMigrationSamples {
+			report.Samples = append(report.Samples, migrationSample(id, raw, converted))
+		}
+
+		if opts.DryRun {
+			progress.Migrated++
+			report.Migrated++
+			continue
+		}
+
+		// Only replace documents that are still in the legacy shape
+		replaceFilter := bson.M{"_id": id, "schema_version": bson.M{"$exists": false}}
+		if _, err := m.collection.ReplaceOne(ctx, replaceFilter, converted); err != nil {
+			m.logger.WithField("id", id.Hex()).Warn("Failed to convert document: %v", err)
+			progress.Failed++
+			progress.LastError = err.Error()
+			report.Failed++
+			continue
+		}
+
+		progress.Migrated++
+		report.Migrated++
+	}
+
+	return processed, cursor.Err()
+}
+
+// skip marks a document the migration cannot convert, so that it no longer
+// counts as remaining; converting it again would fail the same way
+func (m *SchemaV2Migrator) skip(ctx context.Context, id interface{}, reason string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+
+	_, err := m.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "schema_version": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"migration_skipped": reason}})
+	return err
+}
+
+// loadProgress reads the persisted migration progress
+func (m *SchemaV2Migrator) loadProgress(ctx context.Context) (*mongoMigrationProgress, error) {
+	var progress mongoMigrationProgress
+	err := m.migrations.FindOne(ctx, bson.M{"_id": schemaV2MigrationName}).Decode(&progress)
+	if err == mongo.ErrNoDocuments {
+		return &mongoMigrationProgress{
+			ID:     schemaV2MigrationName,
+			Status: string(domain.MigrationPending),
+		}, nil
+	}
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("Failed to load migration progress", err)
+	}
+	return &progress, nil
+}
+
+// saveProgress persists the migration progress
+func (m *SchemaV2Migrator) saveProgress(ctx context.Context, progress *mongoMigrationProgress) error {
+	now := time.Now()
+	progress.UpdatedAt = &now
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := m.migrations.ReplaceOne(ctx, bson.M{"_id": progress.ID}, progress, opts); err != nil {
+		return apperrors.NewDatabaseError("Failed to save migration progress", err)
+	}
+	return nil
+}
+
+// toDomain converts the progress document to its domain representation
+func (p *mongoMigrationProgress) toDomain() *domain.MigrationProgress {
+	progress := &domain.MigrationProgress{
+		Name:        p.ID,
+		Status:      domain.MigrationStatus(p.Status),
+		Migrated:    p.Migrated,
+		Skipped:     p.Skipped,
+		Failed:      p.Failed,
+		LastError:   p.LastError,
+		StartedAt:   p.StartedAt,
+		UpdatedAt:   p.UpdatedAt,
+		CompletedAt: p.CompletedAt,
+	}
+	if p.LastID != nil {
+		progress.LastID = p.LastID.Hex()
+	}
+	return progress
+}
+
+// migrationSample shows a document before and after conversion
+func migrationSample(id primitive.ObjectID, raw bson.Raw, converted *mongoQuickResponse) domain.MigrationSample {
+	sample := domain.MigrationSample{ID: id.Hex()}
+
+	var before bson.M
+	if err := bson.Unmarshal(raw, &before); err == nil {
+		sample.Before = before
+	}
+
+	if data, err := bson.Marshal(converted); err == nil {
+		var after bson.M
+		if err := bson.Unmarshal(data, &after); err == nil {
+			sample.After = after
+		}
+	}
+
+	return sample
+}
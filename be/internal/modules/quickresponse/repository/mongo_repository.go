@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
@@ -13,50 +14,98 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// SchemaVersion is the version written to every document stored by this repository.
+// Documents without a schema_version field use the legacy models.QuickResponse shape.
+const SchemaVersion = 2
+
+// Documents by the type of their created_at: a BSON date in version 2, unix
+// seconds (or missing) in legacy documents
+var (
+	createdAtDateFilter   = bson.M{"created_at": bson.M{"$type": "date"}}
+	createdAtLegacyFilter = bson.M{"created_at": bson.M{"$not": bson.M{"$type": "date"}}}
+)
+
 // MongoRepository implements QuickResponseRepository using MongoDB
 type MongoRepository struct {
 	collection *mongo.Collection
 	logger     *logger.Logger
 }
 
-// mongoQuickResponse represents the MongoDB document structure
+// mongoQuickResponse represents the MongoDB document structure (schema version 2)
 type mongoQuickResponse struct {
-	ID                     primitive.ObjectID `bson:"_id,omitempty"`
-	Petugas                mongoOfficer       `bson:"petugas"`
-	IdentifikasiKegiatanQR mongoActivity      `bson:"identifikasi_kegiatan_qr"`
-	OutputKegiatanQR       mongoOutput        `bson:"output_kegiatan_qr"`
-	CreatedAt              int64              `bson:"created_at"`
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	SchemaVersion int                `bson:"schema_version"`
+	MessageID     string             `bson:"message_id,omitempty"`
+	DeviceName    string             `bson:"device_name,omitempty"`
+	Sender        string             `bson:"sender,omitempty"`
+	Officer       mongoOfficer       `bson:"officer"`
+	Activity      mongoActivity      `bson:"activity"`
+	Output        mongoOutput        `bson:"output"`
+	Revision      int                `bson:"revision"`
+	Revisions     []mongoRevision    `bson:"revisions,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	UpdatedAt     *time.Time         `bson:"updated_at,omitempty"`
 }
 
 type mongoOfficer struct {
-	Nama        string `bson:"nama"`
-	Jabatan     string `bson:"jabatan"`
-	DiPenugasan string `bson:"di_penugasan"`
+	Name       string `bson:"name"`
+	Position   string `bson:"position"`
+	Assignment string `bson:"assignment"`
 }
 
 type mongoActivity struct {
-	MetodePenugasan    string `bson:"metode_penugasan"`
-	KegiatanQR         string `bson:"kegiatan_qr"`
-	DIQR               string `bson:"di_qr"`
-	SaluranQR          string `bson:"saluran_qr"`
-	RuasBangunanQR     string `bson:"ruas_bangunan_qr"`
-	DesaKecamatanKabQR string `bson:"desa_kecamatan_kab_qr"`
-	UPTPSDAWS          string `bson:"upt_psda_ws"`
+	Method        string `bson:"method"`
+	ActivityType  string `bson:"activity_type"`
+	IrrigationDI  string `bson:"irrigation_di"`
+	Channel       string `bson:"channel"`
+	BuildingRoute string `bson:"building_route"`
+	Location      string `bson:"location"`
+	WatershedUnit string `bson:"watershed_unit"`
 }
 
 type mongoOutput struct {
-	LuasAreaKegiatan  string `bson:"luas_area_kegiatan"`
-	PanjangSaluran    string `bson:"panjang_saluran"`
-	MenutupBocoran    string `bson:"menutup_bocoran"`
-	AngkatSedimen     string `bson:"angkat_sedimen"`
-	PembersihanSampah string `bson:"pembersihan_sampah"`
-	AngkatPotongPohon string `bson:"angkat_potong_pohon"`
+	AreaSize        string `bson:"area_size"`
+	ChannelLength   string `bson:"channel_length"`
+	LeaksClosed     string `bson:"leaks_closed"`
+	SedimentRemoved string `bson:"sediment_removed"`
+	TrashCleared    string `bson:"trash_cleared"`
+	TreeCutRemoved  string `bson:"tree_cut_removed"`
+}
+
+type mongoRevision struct {
+	Number    int                `bson:"number"`
+	MessageID string             `bson:"message_id"`
+	Changes   []mongoFieldChange `bson:"changes"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+type mongoFieldChange struct {
+	Field    string `bson:"field"`
+	OldValue string `bson:"old_value"`
+	NewValue string `bson:"new_value"`
 }
 
 // NewMongoRepository creates a new MongoDB repository for QuickResponse
 func NewMongoRepository(db *mongo.Database) domain.QuickResponseRepository {
+	collection := db.Collection("quick_responses")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on created_at for date range queries
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+	})
+
+	// Index on message_id for correction lookups
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "message_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+
 	return &MongoRepository{
-		collection: db.Collection("quick_responses"),
+		collection: collection,
 		logger:     logger.New("QuickResponseRepository"),
 	}
 }
@@ -67,7 +116,7 @@ func (r *MongoRepository) Save(qr *domain.QuickResponse) error {
 	defer cancel()
 
 	// Convert domain to mongo document
-	doc := r.toMongoDocument(qr)
+	doc := toMongoDocument(qr)
 
 	result, err := r.collection.InsertOne(ctx, doc)
 	if err != nil {
@@ -86,57 +135,113 @@ func (r *MongoRepository) Save(qr *domain.QuickResponse) error {
 
 // FindByID retrieves a quick response by ID
 func (r *MongoRepository) FindByID(id string) (*domain.QuickResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, apperrors.NewValidationError("Invalid ID format")
 	}
 
-	var doc mongoQuickResponse
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
-	if err == mongo.ErrNoDocuments {
-		return nil, apperrors.NewNotFoundError("Quick response")
-	}
-	if err != nil {
-		r.logger.Error("Failed to find quick response: %v", err)
-		return nil, apperrors.NewDatabaseError("Failed to retrieve quick response", err)
+	return r.findOne(bson.M{"_id": objectID})
+}
+
+// FindByMessageID retrieves a quick response by its original WhatsApp message ID
+func (r *MongoRepository) FindByMessageID(messageID string) (*domain.QuickResponse, error) {
+	if messageID == "" {
+		return nil, apperrors.NewValidationError("Message ID is required")
 	}
 
-	return r.toDomainEntity(&doc), nil
+	return r.findOne(bson.M{"message_id": messageID})
 }
 
-// FindAll retrieves all quick responses with pagination
-func (r *MongoRepository) FindAll(skip, limit int) ([]*domain.QuickResponse, error) {
+// AddRevision stores the corrected report and appends the revision to its history.
+// The whole document is rewritten, so legacy documents are upgraded on correction.
+func (r *MongoRepository) AddRevision(qr *domain.QuickResponse, revision domain.Revision) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit)).
-		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	objectID, err := primitive.ObjectIDFromHex(qr.ID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	updated := *qr
+	updated.Revision = revision.Number
+	updated.Revisions = append(append([]domain.Revision{}, qr.Revisions...), revision)
+	updated.UpdatedAt = time.Now()
+
+	doc := toMongoDocument(&updated)
+	doc.ID = objectID
 
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	// Only apply on top of the revision we read to avoid losing concurrent corrections
+	filter := bson.M{"_id": objectID, "revision": bson.M{"$in": revisionFilter(qr.Revision)}}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, doc)
 	if err != nil {
-		r.logger.Error("Failed to find quick responses: %v", err)
-		return nil, apperrors.NewDatabaseError("Failed to retrieve quick responses", err)
+		r.logger.Error("Failed to add quick response revision: %v", err)
+		return apperrors.NewDatabaseError("Failed to save quick response revision", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return apperrors.New(apperrors.ErrorTypeConflict, "Quick response was modified concurrently")
+	}
+
+	*qr = updated
+
+	r.logger.WithFields(map[string]interface{}{
+		"id":       qr.ID,
+		"revision": revision.Number,
+	}).Success("Quick response revision saved")
+	return nil
+}
+
+// FindByDateRange retrieves quick responses created within [from, to), oldest first
+func (r *MongoRepository) FindByDateRange(from, to time.Time) ([]*domain.QuickResponse, error) {
+	// Legacy documents store created_at as unix seconds, version 2 as a BSON date
+	filter := bson.M{"$or": []bson.M{
+		{"created_at": bson.M{"$gte": from, "$lt": to}},
+		{"created_at": bson.M{"$gte": from.Unix(), "$lt": to.Unix()}},
+	}}
+
+	results, err := r.find(filter, options.Find())
+	if err != nil {
+		return nil, err
+	}
+
+	// BSON orders numbers before dates, so sort mixed shapes here
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.Before(results[j].CreatedAt)
+	})
+
+	return results, nil
+}
+
+// FindAll retrieves quick responses, newest first, with pagination. BSON
+// orders all numbers before all dates, so while legacy documents remain, the
+// page is taken from each shape separately and the two are merged here.
+func (r *MongoRepository) FindAll(skip, limit int) ([]*domain.QuickResponse, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(skip + limit))
 	}
-	defer cursor.Close(ctx)
 
 	var results []*domain.QuickResponse
-	for cursor.Next(ctx) {
-		var doc mongoQuickResponse
-		if err := cursor.Decode(&doc); err != nil {
-			r.logger.Warn("Failed to decode document: %v", err)
-			continue
+	for _, filter := range []bson.M{createdAtDateFilter, createdAtLegacyFilter} {
+		found, err := r.find(filter, opts)
+		if err != nil {
+			return nil, err
 		}
-		results = append(results, r.toDomainEntity(&doc))
+		results = append(results, found...)
 	}
 
-	if err := cursor.Err(); err != nil {
-		r.logger.Error("Cursor error: %v", err)
-		return nil, apperrors.NewDatabaseError("Failed to iterate quick responses", err)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+
+	if skip >= len(results) {
+		return []*domain.QuickResponse{}, nil
+	}
+	results = results[skip:]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
 	}
 
 	r.logger.WithField("count", len(results)).Info("Quick responses retrieved")
@@ -181,61 +286,200 @@ func (r *MongoRepository) Count() (int64, error) {
 	return count, nil
 }
 
+// findOne retrieves a single quick response in either schema
+func (r *MongoRepository) findOne(filter bson.M) (*domain.QuickResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	raw, err := r.collection.FindOne(ctx, filter).Raw()
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Quick response")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find quick response: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve quick response", err)
+	}
+
+	qr, err := decodeQuickResponse(raw)
+	if err != nil {
+		r.logger.Error("Failed to decode quick response: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to decode quick response", err)
+	}
+
+	return qr, nil
+}
+
+// find retrieves quick responses in either schema
+func (r *MongoRepository) find(filter bson.M, opts *options.FindOptions) ([]*domain.QuickResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find quick responses: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve quick responses", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.QuickResponse
+	for cursor.Next(ctx) {
+		qr, err := decodeQuickResponse(cursor.Current)
+		if err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, qr)
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate quick responses", err)
+	}
+
+	return results, nil
+}
+
+// revisionFilter matches the stored revision number; documents without
+// corrections may have no revision field at all
+func revisionFilter(revision int) []interface{} {
+	if revision == 0 {
+		return []interface{}{0, nil}
+	}
+	return []interface{}{revision}
+}
+
+// decodeQuickResponse converts a raw document in either schema to a domain entity
+func decodeQuickResponse(raw bson.Raw) (*domain.QuickResponse, error) {
+	if isLegacyDocument(raw) {
+		var doc legacyQuickResponse
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		return doc.toDomainEntity(), nil
+	}
+
+	var doc mongoQuickResponse
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return toDomainEntity(&doc), nil
+}
+
 // toMongoDocument converts domain entity to MongoDB document
-func (r *MongoRepository) toMongoDocument(qr *domain.QuickResponse) *mongoQuickResponse {
-	return &mongoQuickResponse{
-		Petugas: mongoOfficer{
-			Nama:        qr.Officer.Name,
-			Jabatan:     qr.Officer.Position,
-			DiPenugasan: qr.Officer.Assignment,
+func toMongoDocument(qr *domain.QuickResponse) *mongoQuickResponse {
+	revisions := make([]mongoRevision, 0, len(qr.Revisions))
+	for _, rev := range qr.Revisions {
+		changes := make([]mongoFieldChange, 0, len(rev.Changes))
+		for _, change := range rev.Changes {
+			changes = append(changes, mongoFieldChange{
+				Field:    change.Field,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			})
+		}
+		revisions = append(revisions, mongoRevision{
+			Number:    rev.Number,
+			MessageID: rev.MessageID,
+			Changes:   changes,
+			CreatedAt: rev.CreatedAt,
+		})
+	}
+
+	doc := &mongoQuickResponse{
+		SchemaVersion: SchemaVersion,
+		MessageID:     qr.MessageID,
+		DeviceName:    qr.DeviceName,
+		Sender:        qr.Sender,
+		Officer: mongoOfficer{
+			Name:       qr.Officer.Name,
+			Position:   qr.Officer.Position,
+			Assignment: qr.Officer.Assignment,
 		},
-		IdentifikasiKegiatanQR: mongoActivity{
-			MetodePenugasan:    qr.Activity.Method,
-			KegiatanQR:         qr.Activity.ActivityType,
-			DIQR:               qr.Activity.IrrigationDI,
-			SaluranQR:          qr.Activity.Channel,
-			RuasBangunanQR:     qr.Activity.BuildingRoute,
-			DesaKecamatanKabQR: qr.Activity.Location,
-			UPTPSDAWS:          qr.Activity.WatershedUnit,
+		Activity: mongoActivity{
+			Method:        qr.Activity.Method,
+			ActivityType:  qr.Activity.ActivityType,
+			IrrigationDI:  qr.Activity.IrrigationDI,
+			Channel:       qr.Activity.Channel,
+			BuildingRoute: qr.Activity.BuildingRoute,
+			Location:      qr.Activity.Location,
+			WatershedUnit: qr.Activity.WatershedUnit,
 		},
-		OutputKegiatanQR: mongoOutput{
-			LuasAreaKegiatan:  qr.Output.AreaSize,
-			PanjangSaluran:    qr.Output.ChannelLength,
-			MenutupBocoran:    qr.Output.LeaksClosed,
-			AngkatSedimen:     qr.Output.SedimentRemoved,
-			PembersihanSampah: qr.Output.TrashCleared,
-			AngkatPotongPohon: qr.Output.TreeCutRemoved,
+		Output: mongoOutput{
+			AreaSize:        qr.Output.AreaSize,
+			ChannelLength:   qr.Output.ChannelLength,
+			LeaksClosed:     qr.Output.LeaksClosed,
+			SedimentRemoved: qr.Output.SedimentRemoved,
+			TrashCleared:    qr.Output.TrashCleared,
+			TreeCutRemoved:  qr.Output.TreeCutRemoved,
 		},
-		CreatedAt: qr.CreatedAt.Unix(),
+		Revision:  qr.Revision,
+		Revisions: revisions,
+		CreatedAt: qr.CreatedAt,
+	}
+
+	if !qr.UpdatedAt.IsZero() {
+		updatedAt := qr.UpdatedAt
+		doc.UpdatedAt = &updatedAt
 	}
+
+	return doc
 }
 
 // toDomainEntity converts MongoDB document to domain entity
-func (r *MongoRepository) toDomainEntity(doc *mongoQuickResponse) *domain.QuickResponse {
-	return &domain.QuickResponse{
-		ID: doc.ID.Hex(),
+func toDomainEntity(doc *mongoQuickResponse) *domain.QuickResponse {
+	revisions := make([]domain.Revision, 0, len(doc.Revisions))
+	for _, rev := range doc.Revisions {
+		changes := make([]domain.FieldChange, 0, len(rev.Changes))
+		for _, change := range rev.Changes {
+			changes = append(changes, domain.FieldChange{
+				Field:    change.Field,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			})
+		}
+		revisions = append(revisions, domain.Revision{
+			Number:    rev.Number,
+			MessageID: rev.MessageID,
+			Changes:   changes,
+			CreatedAt: rev.CreatedAt,
+		})
+	}
+
+	qr := &domain.QuickResponse{
+		ID:         doc.ID.Hex(),
+		MessageID:  doc.MessageID,
+		DeviceName: doc.DeviceName,
+		Sender:     doc.Sender,
 		Officer: domain.OfficerInfo{
-			Name:       doc.Petugas.Nama,
-			Position:   doc.Petugas.Jabatan,
-			Assignment: doc.Petugas.DiPenugasan,
+			Name:       doc.Officer.Name,
+			Position:   doc.Officer.Position,
+			Assignment: doc.Officer.Assignment,
 		},
 		Activity: domain.ActivityInfo{
-			Method:        doc.IdentifikasiKegiatanQR.MetodePenugasan,
-			ActivityType:  doc.IdentifikasiKegiatanQR.KegiatanQR,
-			IrrigationDI:  doc.IdentifikasiKegiatanQR.DIQR,
-			Channel:       doc.IdentifikasiKegiatanQR.SaluranQR,
-			BuildingRoute: doc.IdentifikasiKegiatanQR.RuasBangunanQR,
-			Location:      doc.IdentifikasiKegiatanQR.DesaKecamatanKabQR,
-			WatershedUnit: doc.IdentifikasiKegiatanQR.UPTPSDAWS,
+			Method:        doc.Activity.Method,
+			ActivityType:  doc.Activity.ActivityType,
+			IrrigationDI:  doc.Activity.IrrigationDI,
+			Channel:       doc.Activity.Channel,
+			BuildingRoute: doc.Activity.BuildingRoute,
+			Location:      doc.Activity.Location,
+			WatershedUnit: doc.Activity.WatershedUnit,
 		},
 		Output: domain.OutputInfo{
-			AreaSize:        doc.OutputKegiatanQR.LuasAreaKegiatan,
-			ChannelLength:   doc.OutputKegiatanQR.PanjangSaluran,
-			LeaksClosed:     doc.OutputKegiatanQR.MenutupBocoran,
-			SedimentRemoved: doc.OutputKegiatanQR.AngkatSedimen,
-			TrashCleared:    doc.OutputKegiatanQR.PembersihanSampah,
-			TreeCutRemoved:  doc.OutputKegiatanQR.AngkatPotongPohon,
+			AreaSize:        doc.Output.AreaSize,
+			ChannelLength:   doc.Output.ChannelLength,
+			LeaksClosed:     doc.Output.LeaksClosed,
+			SedimentRemoved: doc.Output.SedimentRemoved,
+			TrashCleared:    doc.Output.TrashCleared,
+			TreeCutRemoved:  doc.Output.TreeCutRemoved,
 		},
-		CreatedAt: time.Unix(doc.CreatedAt, 0),
+		Revision:  doc.Revision,
+		Revisions: revisions,
+		CreatedAt: doc.CreatedAt,
 	}
+
+	if doc.UpdatedAt != nil {
+		qr.UpdatedAt = *doc.UpdatedAt
+	}
+
+	return qr
 }
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OfficerMongoRepository implements OfficerRepository using MongoDB
+type OfficerMongoRepository struct {
+	collection *mongo.Collection
+	logger     *logger.Logger
+}
+
+// mongoOfficerRecord represents the MongoDB document structure
+type mongoOfficerRecord struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	Name       string             `bson:"name"`
+	JID        string             `bson:"jid"`
+	Position   string             `bson:"position"`
+	Assignment string             `bson:"assignment"`
+	Cadence    string             `bson:"cadence"`
+	Weekday    int                `bson:"weekday"`
+	Active     bool               `bson:"active"`
+	CreatedAt  int64              `bson:"created_at"`
+	UpdatedAt  int64              `bson:"updated_at"`
+}
+
+// NewOfficerMongoRepository creates a new MongoDB repository for officers
+func NewOfficerMongoRepository(db *mongo.Database) domain.OfficerRepository {
+	collection := db.Collection("qr_officers")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "active", Value: 1}},
+	})
+
+	return &OfficerMongoRepository{
+		collection: collection,
+		logger:     logger.New("OfficerRepository"),
+	}
+}
+
+// Create saves a new officer
+func (r *OfficerMongoRepository) Create(officer *domain.Officer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	officer.CreatedAt = now
+	officer.UpdatedAt = now
+
+	result, err := r.collection.InsertOne(ctx, toMongoOfficerRecord(officer))
+	if err != nil {
+		r.logger.Error("Failed to insert officer: %v", err)
+		return apperrors.NewDatabaseError("Failed to save officer", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		officer.ID = oid.Hex()
+	}
+
+	r.logger.WithField("id", officer.ID).Success("Officer saved")
+	return nil
+}
+
+// FindByID retrieves an officer by ID
+func (r *OfficerMongoRepository) FindByID(id string) (*domain.Officer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoOfficerRecord
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Officer")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find officer: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve officer", err)
+	}
+
+	return toDomainOfficer(&doc), nil
+}
+
+// FindAll retrieves all officers
+func (r *OfficerMongoRepository) FindAll() ([]*domain.Officer, error) {
+	return r.find(bson.M{})
+}
+
+// FindActive retrieves all active officers
+func (r *OfficerMongoRepository) FindActive() ([]*domain.Officer, error) {
+	return r.find(bson.M{"active": true})
+}
+
+// Update updates an existing officer
+func (r *OfficerMongoRepository) Update(officer *domain.Officer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(officer.ID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	officer.UpdatedAt = time.Now()
+	doc := toMongoOfficerRecord(officer)
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":       doc.Name,
+			"jid":        doc.JID,
+			"position":   doc.Position,
+			"assignment": doc.Assignment,
+			"cadence":    doc.Cadence,
+			"weekday":    doc.Weekday,
+			"active":     doc.Active,
+			"updated_at": doc.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		r.logger.Error("Failed to update officer: %v", err)
+		return apperrors.NewDatabaseError("Failed to update officer", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return apperrors.NewNotFoundError("Officer")
+	}
+
+	r.logger.WithField("id", officer.ID).Success("Officer updated")
+	return nil
+}
+
+// Delete removes an officer
+func (r *OfficerMongoRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("Failed to delete officer: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete officer", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return apperrors.NewNotFoundError("Officer")
+	}
+
+	r.logger.WithField("id", id).Success("Officer deleted")
+	return nil
+}
+
+// find retrieves officers matching the filter, sorted by name
+func (r *OfficerMongoRepository) find(filter bson.M) ([]*domain.Officer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find officers: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve officers", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.Officer, 0)
+	for cursor.Next(ctx) {
+		var doc mongoOfficerRecord
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainOfficer(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate officers", err)
+	}
+
+	return results, nil
+}
+
+// toMongoOfficerRecord converts domain entity to MongoDB document
+func toMongoOfficerRecord(officer *domain.Officer) *mongoOfficerRecord {
+	return &mongoOfficerRecord{
+		Name:       officer.Name,
+		JID:        officer.JID,
+		Position:   officer.Position,
+		Assignment: officer.Assignment,
+		Cadence:    string(officer.Cadence),
+		Weekday:    int(officer.Weekday),
+		Active:     officer.Active,
+		CreatedAt:  officer.CreatedAt.Unix(),
+		UpdatedAt:  officer.UpdatedAt.Unix(),
+	}
+}
+
+// toDomainOfficer converts MongoDB document to domain entity
+func toDomainOfficer(doc *mongoOfficerRecord) *domain.Officer {
+	return &domain.Officer{
+		ID:         doc.ID.Hex(),
+		Name:       doc.Name,
+		JID:        doc.JID,
+		Position:   doc.Position,
+		Assignment: doc.Assignment,
+		Cadence:    domain.ReportCadence(doc.Cadence),
+		Weekday:    time.Weekday(doc.Weekday),
+		Active:     doc.Active,
+		CreatedAt:  time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:  time.Unix(doc.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reminderConfigID is the ID of the single reminder settings document
+const reminderConfigID = "default"
+
+// ReminderMongoRepository implements ReminderRepository using MongoDB
+type ReminderMongoRepository struct {
+	settings *mongo.Collection
+	logs     *mongo.Collection
+	logger   *logger.Logger
+}
+
+// mongoReminderConfig represents the MongoDB settings document
+type mongoReminderConfig struct {
+	ID              string `bson:"_id"`
+	Enabled         bool   `bson:"enabled"`
+	DeviceName      string `bson:"device_name"`
+	CutoffTime      string `bson:"cutoff_time"`
+	Timezone        string `bson:"timezone"`
+	QuietHoursStart string `bson:"quiet_hours_start"`
+	QuietHoursEnd   string `bson:"quiet_hours_end"`
+	MaxPerDay       int    `bson:"max_per_day"`
+	IntervalMinutes int    `bson:"interval_minutes"`
+	Message         string `bson:"message"`
+	UpdatedAt       int64  `bson:"updated_at"`
+}
+
+// mongoReminderLog represents the MongoDB log document
+type mongoReminderLog struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	OfficerID   string             `bson:"officer_id"`
+	OfficerName string             `bson:"officer_name"`
+	JID         string             `bson:"jid"`
+	DeviceName  string             `bson:"device_name"`
+	Deadline    int64              `bson:"deadline"`
+	Message     string             `bson:"message"`
+	Status      string             `bson:"status"`
+	Error       string             `bson:"error,omitempty"`
+	CreatedAt   int64              `bson:"created_at"`
+}
+
+// NewReminderMongoRepository creates a new MongoDB repository for reminders
+func NewReminderMongoRepository(db *mongo.Database) domain.ReminderRepository {
+	logs := db.Collection("qr_reminder_logs")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, _ = logs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "officer_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	return &ReminderMongoRepository{
+		settings: db.Collection("qr_reminder_settings"),
+		logs:     logs,
+		logger:   logger.New("ReminderRepository"),
+	}
+}
+
+// GetConfig retrieves the reminder configuration, returning defaults when none is stored
+func (r *ReminderMongoRepository) GetConfig() (*domain.ReminderConfig, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var doc mongoReminderConfig
+	err := r.settings.FindOne(ctx, bson.M{"_id": reminderConfigID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return &domain.ReminderConfig{
+			CutoffTime:      "17:00",
+			Timezone:        "Asia/Jakarta",
+			QuietHoursStart: "21:00",
+			QuietHoursEnd:   "06:00",
+			MaxPerDay:       2,
+			IntervalMinutes: 120,
+		}, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to find reminder config: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve reminder config", err)
+	}
+
+	return &domain.ReminderConfig{
+		Enabled:         doc.Enabled,
+		DeviceName:      doc.DeviceName,
+		CutoffTime:      doc.CutoffTime,
+		Timezone:        doc.Timezone,
+		QuietHoursStart: doc.QuietHoursStart,
+		QuietHoursEnd:   doc.QuietHoursEnd,
+		MaxPerDay:       doc.MaxPerDay,
+		IntervalMinutes: doc.IntervalMinutes,
+		Message:         doc.Message,
+		UpdatedAt:       time.Unix(doc.UpdatedAt, 0),
+	}, nil
+}
+
+// SaveConfig stores the reminder configuration
+func (r *ReminderMongoRepository) SaveConfig(config *domain.ReminderConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	config.UpdatedAt = time.Now()
+	doc := mongoReminderConfig{
+		ID:              reminderConfigID,
+		Enabled:         config.Enabled,
+		DeviceName:      config.DeviceName,
+		CutoffTime:      config.CutoffTime,
+		Timezone:        config.Timezone,
+		QuietHoursStart: config.QuietHoursStart,
+		QuietHoursEnd:   config.QuietHoursEnd,
+		MaxPerDay:       config.MaxPerDay,
+		IntervalMinutes: config.IntervalMinutes,
+		Message:         config.Message,
+		UpdatedAt:       config.UpdatedAt.Unix(),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.settings.ReplaceOne(ctx, bson.M{"_id": reminderConfigID}, doc, opts); err != nil {
+		r.logger.Error("Failed to save reminder config: %v", err)
+		return apperrors.NewDatabaseError("Failed to save reminder config", err)
+	}
+
+	r.logger.Success("Reminder config saved")
+	return nil
+}
+
+// SaveLog records a reminder attempt
+func (r *ReminderMongoRepository) SaveLog(log *domain.ReminderLog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	doc := mongoReminderLog{
+		OfficerID:   log.OfficerID,
+		OfficerName: log.OfficerName,
+		JID:         log.JID,
+		DeviceName:  log.DeviceName,
+		Deadline:    log.Deadline.Unix(),
+		Message:     log.Message,
+		Status:      string(log.Status),
+		Error:       log.Error,
+		CreatedAt:   log.CreatedAt.Unix(),
+	}
+
+	result, err := r.logs.InsertOne(ctx, doc)
+	if err != nil {
+		r.logger.Error("Failed to insert reminder log: %v", err)
+		return apperrors.NewDatabaseError("Failed to save reminder log", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		log.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+// FindLogs retrieves reminder logs with pagination, newest first
+func (r *ReminderMongoRepository) FindLogs(skip, limit int) ([]*domain.ReminderLog, error) {
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	return r.findLogs(bson.M{}, opts)
+}
+
+// FindLogsSince retrieves the reminders sent or attempted to an officer since the given time, newest first
+func (r *ReminderMongoRepository) FindLogsSince(officerID string, since time.Time) ([]*domain.ReminderLog, error) {
+	filter := bson.M{
+		"officer_id": officerID,
+		"created_at": bson.M{"$gte": since.Unix()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	return r.findLogs(filter, opts)
+}
+
+// findLogs retrieves reminder logs matching the filter
+func (r *ReminderMongoRepository) findLogs(filter bson.M, opts *options.FindOptions) ([]*domain.ReminderLog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.logs.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find reminder logs: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve reminder logs", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.ReminderLog, 0)
+	for cursor.Next(ctx) {
+		var doc mongoReminderLog
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, &domain.ReminderLog{
+			ID:          doc.ID.Hex(),
+			OfficerID:   doc.OfficerID,
+			OfficerName: doc.OfficerName,
+			JID:         doc.JID,
+			DeviceName:  doc.DeviceName,
+			Deadline:    time.Unix(doc.Deadline, 0),
+			Message:     doc.Message,
+			Status:      domain.ReminderStatus(doc.Status),
+			Error:       doc.Error,
+			CreatedAt:   time.Unix(doc.CreatedAt, 0),
+		})
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate reminder logs", err)
+	}
+
+	return results, nil
+}
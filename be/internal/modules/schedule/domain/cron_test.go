@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "* * * * *"},
+		{expr: "0 8 * * mon-fri"},
+		{expr: "*/15 8-18/2 1,15 jan-jun *"},
+		{expr: "5/20 * * * *"},
+		{expr: "0 0 * * 7"},
+		{expr: "  @Daily  "},
+		{expr: "@hourly"},
+		{expr: "@annually"},
+		{expr: "", wantErr: true},
+		{expr: "* * * *", wantErr: true},
+		{expr: "* * * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 24 * * *", wantErr: true},
+		{expr: "* * 0 * *", wantErr: true},
+		{expr: "* * 32 * *", wantErr: true},
+		{expr: "* * * 13 *", wantErr: true},
+		{expr: "* * * * 8", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "*/x * * * *", wantErr: true},
+		{expr: "10-5 * * * *", wantErr: true},
+		{expr: "* * * foo *", wantErr: true},
+		{expr: "@reboot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if tt.wantErr && err == nil {
+				t.Errorf("ParseCron(%q) = nil error, want error", tt.expr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ParseCron(%q) unexpected error: %v", tt.expr, err)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, jakarta)
+	}
+
+	// 2024-01-25 is a Thursday
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{name: "every minute", expr: "* * * * *", after: at(2024, 1, 25, 8, 15), want: at(2024, 1, 25, 8, 16)},
+		{name: "strictly after", expr: "15 8 * * *", after: at(2024, 1, 25, 8, 15), want: at(2024, 1, 26, 8, 15)},
+		{name: "seconds truncated", expr: "* * * * *", after: at(2024, 1, 25, 8, 15).Add(30 * time.Second), want: at(2024, 1, 25, 8, 16)},
+		{name: "later today", expr: "0 17 * * *", after: at(2024, 1, 25, 8, 15), want: at(2024, 1, 25, 17, 0)},
+		{name: "step", expr: "*/15 * * * *", after: at(2024, 1, 25, 8, 16), want: at(2024, 1, 25, 8, 30)},
+		{name: "step from value", expr: "5/20 * * * *", after: at(2024, 1, 25, 8, 26), want: at(2024, 1, 25, 8, 45)},
+		{name: "weekdays skip weekend", expr: "0 8 * * mon-fri", after: at(2024, 1, 26, 9, 0), want: at(2024, 1, 29, 8, 0)},
+		{name: "sunday as 7", expr: "0 8 * * 7", after: at(2024, 1, 25, 9, 0), want: at(2024, 1, 28, 8, 0)},
+		{name: "day of month", expr: "0 9 1 * *", after: at(2024, 1, 25, 9, 0), want: at(2024, 2, 1, 9, 0)},
+		{name: "day of month or weekday", expr: "0 9 1 * mon", after: at(2024, 1, 25, 9, 0), want: at(2024, 1, 29, 9, 0)},
+		{name: "month name", expr: "0 0 1 jun *", after: at(2024, 1, 25, 9, 0), want: at(2024, 6, 1, 0, 0)},
+		{name: "leap day", expr: "0 0 29 2 *", after: at(2024, 3, 1, 0, 0), want: at(2028, 2, 29, 0, 0)},
+		{name: "end of year", expr: "@yearly", after: at(2024, 12, 31, 23, 59), want: at(2025, 1, 1, 0, 0)},
+		{name: "never matches", expr: "0 0 30 2 *", after: at(2024, 1, 25, 9, 0), want: time.Time{}},
+		{name: "converted to location", expr: "0 8 * * *", after: time.Date(2024, 1, 25, 1, 30, 0, 0, time.UTC), want: at(2024, 1, 26, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := cron.Next(tt.after, jakarta); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits on a scheduled message's content
+const (
+	MaxMessageLength = 4096
+	MaxMediaSize     = 10 << 20 // Media is stored with the schedule until it is sent
+)
+
+// ContentType is the kind of message a schedule sends
+type ContentType string
+
+const (
+	ContentText  ContentType = "text"
+	ContentImage ContentType = "image"
+	ContentVideo ContentType = "video"
+	ContentAudio ContentType = "audio"
+	ContentFile  ContentType = "file"
+)
+
+// IsMedia reports whether the content carries a file
+func (t ContentType) IsMedia() bool {
+	switch t {
+	case ContentImage, ContentVideo, ContentAudio, ContentFile:
+		return true
+	}
+	return false
+}
+
+// ScheduleStatus represents where a scheduled message is in its lifecycle
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "active"    // Sent at its next run
+	SchedulePaused    ScheduleStatus = "paused"    // Stopped by a user until resumed
+	ScheduleCompleted ScheduleStatus = "completed" // A one-off message that has run
+	ScheduleCancelled ScheduleStatus = "cancelled" // Stopped for good
+)
+
+// ScheduledMessage represents a message sent from a device once at a given
+// time, or repeatedly following a cron expression
+type ScheduledMessage struct {
+	ID            string
+	DeviceName    string // Device the message is sent from
+	To            string // Phone number, JID or group JID
+	IsGroup       bool
+	ContentType   ContentType
+	Message       string // Text, or the caption of media
+	FileName      string // Name of the media file
+	MediaSize     int64
+	SendAt        *time.Time // When a one-off message is sent
+	Cron          string     // When a recurring message is sent, e.g. "0 9 * * mon"
+	Timezone      string     // IANA timezone the cron expression is read in, e.g. "Asia/Jakarta"
+	Status        ScheduleStatus
+	StatusReason  string // Who paused or cancelled the schedule
+	NextRunAt     *time.Time
+	LastRunAt     *time.Time
+	LastRunStatus RunStatus
+	LastError     string
+	RunCount      int
+	CreatedBy     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Recurring reports whether the message is sent following a cron expression
+func (s *ScheduledMessage) Recurring() bool {
+	return s.Cron != ""
+}
+
+// Finished reports whether the schedule will no longer run
+func (s *ScheduledMessage) Finished() bool {
+	return s.Status == ScheduleCompleted || s.Status == ScheduleCancelled
+}
+
+// Location returns the schedule timezone, falling back to UTC when unset or unknown
+func (s *ScheduledMessage) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Validate checks that the message can be scheduled
+func (s *ScheduledMessage) Validate() error {
+	if strings.TrimSpace(s.DeviceName) == "" {
+		return fmt.Errorf("device is required")
+	}
+	if strings.TrimSpace(s.To) == "" {
+		return fmt.Errorf("to is required")
+	}
+
+	switch {
+	case s.ContentType == ContentText:
+		if strings.TrimSpace(s.Message) == "" {
+			return fmt.Errorf("message is required")
+		}
+		if s.MediaSize > 0 {
+			return fmt.Errorf("type must be image, video, audio or file when a file is given")
+		}
+	case s.ContentType.IsMedia():
+		if s.MediaSize <= 0 {
+			return fmt.Errorf("file is required for %s messages", s.ContentType)
+		}
+		if s.MediaSize > MaxMediaSize {
+			return fmt.Errorf("file must be at most %d MB", MaxMediaSize>>20)
+		}
+	default:
+		return fmt.Errorf("type must be text, image, video, audio or file")
+	}
+	if utf8.RuneCountInString(s.Message) > MaxMessageLength {
+		return fmt.Errorf("message must be at most %d characters", MaxMessageLength)
+	}
+
+	if (s.SendAt == nil) == (s.Cron == "") {
+		return fmt.Errorf("give either send_at or cron")
+	}
+	if s.Cron != "" {
+		if _, err := ParseCron(s.Cron); err != nil {
+			return err
+		}
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone: %s", s.Timezone)
+		}
+	}
+	return nil
+}
+
+// NextRun returns the first time the message is due strictly after the given
+// time, or the zero time when it will not run again
+func (s *ScheduledMessage) NextRun(after time.Time) time.Time {
+	if !s.Recurring() {
+		if s.SendAt != nil && s.SendAt.After(after) {
+			return *s.SendAt
+		}
+		return time.Time{}
+	}
+
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return cron.Next(after, s.Location())
+}
+
+// RunStatus is the outcome of a run of a scheduled message
+type RunStatus string
+
+const (
+	RunSent   RunStatus = "sent"
+	RunFailed RunStatus = "failed"
+)
+
+// ScheduleRun records one run of a scheduled message
+type ScheduleRun struct {
+	ID           string
+	ScheduleID   string
+	DeviceName   string
+	To           string
+	ScheduledFor time.Time // When the run was due
+	RanAt        time.Time
+	Status       RunStatus
+	Error        string // Why the message was not sent
+}
+
+// ScheduleFilter selects scheduled messages; empty fields match all
+type ScheduleFilter struct {
+	DeviceName string
+	CreatedBy  string
+	Status     ScheduleStatus
+}
+
+// ScheduleRepository defines the contract for scheduled message and run persistence
+type ScheduleRepository interface {
+	// Create saves a new scheduled message with its media, if any
+	Create(schedule *ScheduledMessage, media []byte) error
+
+	// FindByID retrieves a scheduled message by ID
+	FindByID(id string) (*ScheduledMessage, error)
+
+	// FindMedia retrieves the media of a scheduled message
+	FindMedia(id string) ([]byte, error)
+
+	// DeleteMedia removes the media of a scheduled message that will not run again
+	DeleteMedia(id string) error
+
+	// Find retrieves the scheduled messages matching the filter, newest first
+	Find(filter ScheduleFilter, skip, limit int) ([]*ScheduledMessage, error)
+
+	// Count counts the scheduled messages matching the filter
+	Count(filter ScheduleFilter) (int64, error)
+
+	// FindDue retrieves up to limit active scheduled messages whose next run
+	// is at or before now, most overdue first. When after is not nil, only
+	// messages ordered after it are returned, so callers can page past
+	// messages they had to leave due.
+	FindDue(now time.Time, after *ScheduledMessage, limit int) ([]*ScheduledMessage, error)
+
+	// Claim moves an active scheduled message from the run due at its next
+	// run to the following one, or completes it when next is zero. It
+	// reports false when another replica or request got there first.
+	Claim(id string, due, next time.Time) (bool, error)
+
+	// Transition moves a scheduled message to a new status if it is in one of
+	// the from statuses, setting its next run, and returns it; otherwise it
+	// returns a conflict error. A zero next run clears it.
+	Transition(id string, from []ScheduleStatus, to ScheduleStatus, reason string, next time.Time) (*ScheduledMessage, error)
+
+	// RecordRun saves a run and updates the last run of its scheduled message
+	RecordRun(run *ScheduleRun) error
+
+	// FindRuns retrieves the runs of a scheduled message, newest first
+	FindRuns(scheduleID string, skip, limit int) ([]*ScheduleRun, error)
+
+	// CountRuns counts the runs of a scheduled message
+	CountRuns(scheduleID string) (int64, error)
+}
@@ -0,0 +1,568 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ScheduleMongoRepository implements ScheduleRepository using MongoDB
+type ScheduleMongoRepository struct {
+	schedules *mongo.Collection
+	media     *mongo.Collection
+	runs      *mongo.Collection
+	logger    *logger.Logger
+}
+
+// mongoSchedule represents the MongoDB scheduled message document
+type mongoSchedule struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	DeviceName    string             `bson:"device_name"`
+	To            string             `bson:"to"`
+	IsGroup       bool               `bson:"is_group"`
+	ContentType   string             `bson:"content_type"`
+	Message       string             `bson:"message,omitempty"`
+	FileName      string             `bson:"file_name,omitempty"`
+	MediaSize     int64              `bson:"media_size,omitempty"`
+	SendAt        int64              `bson:"send_at,omitempty"`
+	Cron          string             `bson:"cron,omitempty"`
+	Timezone      string             `bson:"timezone"`
+	Status        string             `bson:"status"`
+	StatusReason  string             `bson:"status_reason,omitempty"`
+	NextRunAt     int64              `bson:"next_run_at,omitempty"`
+	LastRunAt     int64              `bson:"last_run_at,omitempty"`
+	LastRunStatus string             `bson:"last_run_status,omitempty"`
+	LastError     string             `bson:"last_error,omitempty"`
+	RunCount      int                `bson:"run_count"`
+	CreatedBy     string             `bson:"created_by"`
+	CreatedAt     int64              `bson:"created_at"`
+	UpdatedAt     int64              `bson:"updated_at"`
+}
+
+// mongoMedia represents the MongoDB document holding a scheduled message's
+// media, kept apart so that listing schedules does not load files
+type mongoMedia struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	Data []byte             `bson:"data"`
+}
+
+// mongoRun represents the MongoDB run document
+type mongoRun struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	ScheduleID   string             `bson:"schedule_id"`
+	DeviceName   string             `bson:"device_name"`
+	To           string             `bson:"to"`
+	ScheduledFor int64              `bson:"scheduled_for"`
+	RanAt        int64              `bson:"ran_at"`
+	Status       string             `bson:"status"`
+	Error        string             `bson:"error,omitempty"`
+}
+
+// NewScheduleMongoRepository creates a new MongoDB repository for scheduled messages
+func NewScheduleMongoRepository(db *mongo.Database) domain.ScheduleRepository {
+	schedules := db.Collection("scheduled_messages")
+	media := db.Collection("scheduled_message_media")
+	runs := db.Collection("scheduled_message_runs")
+
+	// Create indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index on status, next run and ID, for paging through due messages
+	_, _ = schedules.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}, {Key: "_id", Value: 1}},
+	})
+
+	// Indexes on device and creator with creation time, for listing
+	_, _ = schedules.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "device_name", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	_, _ = schedules.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	// Index on schedule and run time, for run history
+	_, _ = runs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "schedule_id", Value: 1}, {Key: "ran_at", Value: -1}},
+	})
+
+	return &ScheduleMongoRepository{
+		schedules: schedules,
+		media:     media,
+		runs:      runs,
+		logger:    logger.New("ScheduleRepository"),
+	}
+}
+
+// Create saves a new scheduled message with its media, if any
+func (r *ScheduleMongoRepository) Create(schedule *domain.ScheduledMessage, media []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	doc := toMongoSchedule(schedule)
+	doc.ID = primitive.NewObjectID()
+
+	// Save the media first, so that the runner never finds a schedule without it
+	if len(media) > 0 {
+		if _, err := r.media.InsertOne(ctx, mongoMedia{ID: doc.ID, Data: media}); err != nil {
+			r.logger.Error("Failed to insert scheduled message media: %v", err)
+			return apperrors.NewDatabaseError("Failed to save scheduled message media", err)
+		}
+	}
+
+	if _, err := r.schedules.InsertOne(ctx, doc); err != nil {
+		r.logger.Error("Failed to insert scheduled message: %v", err)
+		_, _ = r.media.DeleteOne(ctx, bson.M{"_id": doc.ID})
+		return apperrors.NewDatabaseError("Failed to save scheduled message", err)
+	}
+	schedule.ID = doc.ID.Hex()
+
+	r.logger.WithField("id", schedule.ID).Success("Scheduled message saved")
+	return nil
+}
+
+// FindByID retrieves a scheduled message by ID
+func (r *ScheduleMongoRepository) FindByID(id string) (*domain.ScheduledMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoSchedule
+	err = r.schedules.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Scheduled message")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find scheduled message: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve scheduled message", err)
+	}
+
+	return toDomainSchedule(&doc), nil
+}
+
+// FindMedia retrieves the media of a scheduled message
+func (r *ScheduleMongoRepository) FindMedia(id string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	var doc mongoMedia
+	err = r.media.FindOne(ctx, bson.M{"_id": objectID}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, apperrors.NewNotFoundError("Scheduled message media")
+	}
+	if err != nil {
+		r.logger.Error("Failed to find scheduled message media: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve scheduled message media", err)
+	}
+
+	return doc.Data, nil
+}
+
+// DeleteMedia removes the media of a scheduled message that will not run again
+func (r *ScheduleMongoRepository) DeleteMedia(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	if _, err := r.media.DeleteOne(ctx, bson.M{"_id": objectID}); err != nil {
+		r.logger.Error("Failed to delete scheduled message media: %v", err)
+		return apperrors.NewDatabaseError("Failed to delete scheduled message media", err)
+	}
+
+	return nil
+}
+
+// Find retrieves the scheduled messages matching the filter, newest first
+func (r *ScheduleMongoRepository) Find(filter domain.ScheduleFilter, skip, limit int) ([]*domain.ScheduledMessage, error) {
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	return r.find(scheduleFilter(filter), opts)
+}
+
+// Count counts the scheduled messages matching the filter
+func (r *ScheduleMongoRepository) Count(filter domain.ScheduleFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := r.schedules.CountDocuments(ctx, scheduleFilter(filter))
+	if err != nil {
+		r.logger.Error("Failed to count scheduled messages: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to count scheduled messages", err)
+	}
+
+	return count, nil
+}
+
+// FindDue retrieves up to limit active scheduled messages whose next run is
+// at or before now, most overdue first, and after the given message if any
+func (r *ScheduleMongoRepository) FindDue(now time.Time, after *domain.ScheduledMessage, limit int) ([]*domain.ScheduledMessage, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "next_run_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	filter := bson.M{
+		"status":      string(domain.ScheduleActive),
+		"next_run_at": bson.M{"$gt": 0, "$lte": now.Unix()},
+	}
+	if after != nil && after.NextRunAt != nil {
+		afterID, err := primitive.ObjectIDFromHex(after.ID)
+		if err != nil {
+			return nil, apperrors.NewValidationError("Invalid ID format")
+		}
+		afterRun := after.NextRunAt.Unix()
+		filter["$or"] = bson.A{
+			bson.M{"next_run_at": bson.M{"$gt": afterRun}},
+			bson.M{"next_run_at": afterRun, "_id": bson.M{"$gt": afterID}},
+		}
+	}
+
+	return r.find(filter, opts)
+}
+
+// Claim moves an active scheduled message from the run due at its next run
+// to the following one, or completes it when next is zero. It reports false
+// when another replica or request got there first.
+func (r *ScheduleMongoRepository) Claim(id string, due, next time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	set := bson.M{"updated_at": time.Now().Unix()}
+	update := bson.M{"$set": set}
+	if next.IsZero() {
+		set["status"] = string(domain.ScheduleCompleted)
+		update["$unset"] = bson.M{"next_run_at": ""}
+	} else {
+		set["next_run_at"] = next.Unix()
+	}
+
+	result, err := r.schedules.UpdateOne(ctx, bson.M{
+		"_id":         objectID,
+		"status":      string(domain.ScheduleActive),
+		"next_run_at": due.Unix(),
+	}, update)
+	if err != nil {
+		r.logger.Error("Failed to claim scheduled message: %v", err)
+		return false, apperrors.NewDatabaseError("Failed to update scheduled message", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// Transition moves a scheduled message to a new status if it is in one of
+// the from statuses, setting its next run, and returns it; otherwise it
+// returns a conflict error. A zero next run clears it.
+func (r *ScheduleMongoRepository) Transition(id string, from []domain.ScheduleStatus, to domain.ScheduleStatus, reason string, next time.Time) (*domain.ScheduledMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, apperrors.NewValidationError("Invalid ID format")
+	}
+
+	statuses := make(bson.A, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	set := bson.M{
+		"status":        string(to),
+		"status_reason": reason,
+		"updated_at":    time.Now().Unix(),
+	}
+	update := bson.M{"$set": set}
+	if next.IsZero() {
+		update["$unset"] = bson.M{"next_run_at": ""}
+	} else {
+		set["next_run_at"] = next.Unix()
+	}
+
+	var doc mongoSchedule
+	err = r.schedules.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "status": bson.M{"$in": statuses}},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		current, err := r.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("Scheduled message is %s", current.Status))
+	}
+	if err != nil {
+		r.logger.Error("Failed to update scheduled message status: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to update scheduled message", err)
+	}
+
+	r.logger.WithFields(map[string]interface{}{
+		"id":     id,
+		"status": to,
+	}).Info("Scheduled message status changed")
+	return toDomainSchedule(&doc), nil
+}
+
+// RecordRun saves a run and updates the last run of its scheduled message
+func (r *ScheduleMongoRepository) RecordRun(run *domain.ScheduleRun) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(run.ScheduleID)
+	if err != nil {
+		return apperrors.NewValidationError("Invalid ID format")
+	}
+
+	doc := toMongoRun(run)
+	result, err := r.runs.InsertOne(ctx, doc)
+	if err != nil {
+		r.logger.Error("Failed to insert scheduled message run: %v", err)
+		return apperrors.NewDatabaseError("Failed to save scheduled message run", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		run.ID = oid.Hex()
+	}
+
+	_, err = r.schedules.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set": bson.M{
+			"last_run_at":     doc.RanAt,
+			"last_run_status": doc.Status,
+			"last_error":      doc.Error,
+			"updated_at":      time.Now().Unix(),
+		},
+		"$inc": bson.M{"run_count": 1},
+	})
+	if err != nil {
+		r.logger.Error("Failed to update scheduled message last run: %v", err)
+		return apperrors.NewDatabaseError("Failed to update scheduled message", err)
+	}
+
+	return nil
+}
+
+// FindRuns retrieves the runs of a scheduled message, newest first
+func (r *ScheduleMongoRepository) FindRuns(scheduleID string, skip, limit int) ([]*domain.ScheduleRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "ran_at", Value: -1}})
+
+	cursor, err := r.runs.Find(ctx, bson.M{"schedule_id": scheduleID}, opts)
+	if err != nil {
+		r.logger.Error("Failed to find scheduled message runs: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve scheduled message runs", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.ScheduleRun, 0)
+	for cursor.Next(ctx) {
+		var doc mongoRun
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainRun(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate scheduled message runs", err)
+	}
+
+	return results, nil
+}
+
+// CountRuns counts the runs of a scheduled message
+func (r *ScheduleMongoRepository) CountRuns(scheduleID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := r.runs.CountDocuments(ctx, bson.M{"schedule_id": scheduleID})
+	if err != nil {
+		r.logger.Error("Failed to count scheduled message runs: %v", err)
+		return 0, apperrors.NewDatabaseError("Failed to count scheduled message runs", err)
+	}
+
+	return count, nil
+}
+
+// find retrieves scheduled messages matching the filter
+func (r *ScheduleMongoRepository) find(filter bson.M, opts *options.FindOptions) ([]*domain.ScheduledMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.schedules.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("Failed to find scheduled messages: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to retrieve scheduled messages", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]*domain.ScheduledMessage, 0)
+	for cursor.Next(ctx) {
+		var doc mongoSchedule
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Warn("Failed to decode document: %v", err)
+			continue
+		}
+		results = append(results, toDomainSchedule(&doc))
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("Cursor error: %v", err)
+		return nil, apperrors.NewDatabaseError("Failed to iterate scheduled messages", err)
+	}
+
+	return results, nil
+}
+
+// scheduleFilter converts a schedule filter to a MongoDB filter
+func scheduleFilter(filter domain.ScheduleFilter) bson.M {
+	query := bson.M{}
+	if filter.DeviceName != "" {
+		query["device_name"] = filter.DeviceName
+	}
+	if filter.CreatedBy != "" {
+		query["created_by"] = filter.CreatedBy
+	}
+	if filter.Status != "" {
+		query["status"] = string(filter.Status)
+	}
+	return query
+}
+
+// toMongoSchedule converts domain entity to MongoDB document
+func toMongoSchedule(schedule *domain.ScheduledMessage) *mongoSchedule {
+	doc := &mongoSchedule{
+		DeviceName:    schedule.DeviceName,
+		To:            schedule.To,
+		IsGroup:       schedule.IsGroup,
+		ContentType:   string(schedule.ContentType),
+		Message:       schedule.Message,
+		FileName:      schedule.FileName,
+		MediaSize:     schedule.MediaSize,
+		Cron:          schedule.Cron,
+		Timezone:      schedule.Timezone,
+		Status:        string(schedule.Status),
+		StatusReason:  schedule.StatusReason,
+		LastRunStatus: string(schedule.LastRunStatus),
+		LastError:     schedule.LastError,
+		RunCount:      schedule.RunCount,
+		CreatedBy:     schedule.CreatedBy,
+		CreatedAt:     schedule.CreatedAt.Unix(),
+		UpdatedAt:     schedule.UpdatedAt.Unix(),
+	}
+
+	if schedule.SendAt != nil {
+		doc.SendAt = schedule.SendAt.Unix()
+	}
+	if schedule.NextRunAt != nil {
+		doc.NextRunAt = schedule.NextRunAt.Unix()
+	}
+	if schedule.LastRunAt != nil {
+		doc.LastRunAt = schedule.LastRunAt.Unix()
+	}
+
+	return doc
+}
+
+// toDomainSchedule converts MongoDB document to domain entity
+func toDomainSchedule(doc *mongoSchedule) *domain.ScheduledMessage {
+	schedule := &domain.ScheduledMessage{
+		ID:            doc.ID.Hex(),
+		DeviceName:    doc.DeviceName,
+		To:            doc.To,
+		IsGroup:       doc.IsGroup,
+		ContentType:   domain.ContentType(doc.ContentType),
+		Message:       doc.Message,
+		FileName:      doc.FileName,
+		MediaSize:     doc.MediaSize,
+		Cron:          doc.Cron,
+		Timezone:      doc.Timezone,
+		Status:        domain.ScheduleStatus(doc.Status),
+		StatusReason:  doc.StatusReason,
+		LastRunStatus: domain.RunStatus(doc.LastRunStatus),
+		LastError:     doc.LastError,
+		RunCount:      doc.RunCount,
+		CreatedBy:     doc.CreatedBy,
+		CreatedAt:     time.Unix(doc.CreatedAt, 0),
+		UpdatedAt:     time.Unix(doc.UpdatedAt, 0),
+	}
+
+	if doc.SendAt != 0 {
+		sendAt := time.Unix(doc.SendAt, 0)
+		schedule.SendAt = &sendAt
+	}
+	if doc.NextRunAt != 0 {
+		nextRunAt := time.Unix(doc.NextRunAt, 0)
+		schedule.NextRunAt = &nextRunAt
+	}
+	if doc.LastRunAt != 0 {
+		lastRunAt := time.Unix(doc.LastRunAt, 0)
+		schedule.LastRunAt = &lastRunAt
+	}
+
+	return schedule
+}
+
+// toMongoRun converts domain entity to MongoDB document
+func toMongoRun(run *domain.ScheduleRun) *mongoRun {
+	return &mongoRun{
+		ScheduleID:   run.ScheduleID,
+		DeviceName:   run.DeviceName,
+		To:           run.To,
+		ScheduledFor: run.ScheduledFor.Unix(),
+		RanAt:        run.RanAt.Unix(),
+		Status:       string(run.Status),
+		Error:        run.Error,
+	}
+}
+
+// toDomainRun converts MongoDB document to domain entity
+func toDomainRun(doc *mongoRun) *domain.ScheduleRun {
+	return &domain.ScheduleRun{
+		ID:           doc.ID.Hex(),
+		ScheduleID:   doc.ScheduleID,
+		DeviceName:   doc.DeviceName,
+		To:           doc.To,
+		ScheduledFor: time.Unix(doc.ScheduledFor, 0),
+		RanAt:        time.Unix(doc.RanAt, 0),
+		Status:       domain.RunStatus(doc.Status),
+		Error:        doc.Error,
+	}
+}
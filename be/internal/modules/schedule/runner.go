@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+const (
+	// scheduleSendTimeout bounds how long sending a single message may take
+	scheduleSendTimeout = 2 * time.Minute
+
+	// scheduleBatchSize is how many due messages are loaded per check
+	scheduleBatchSize = 100
+)
+
+// ScheduleRunner sends scheduled messages when they are due. Every interval
+// it loads the due messages from MongoDB, so messages that fell due while
+// the server was down are sent once it is back. Each run is claimed before
+// it is sent, so with several replicas only one sends it; a message is
+// never sent twice, but may be lost if the replica dies while sending.
+//
+// A due message waits for its device to connect. A recurring message whose
+// following run is also due by then has missed its run, which is recorded
+// as failed.
+type ScheduleRunner struct {
+	schedules domain.ScheduleRepository
+	whatsapp  ports.WhatsAppService
+	interval  time.Duration
+	logger    *logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScheduleRunner creates a new ScheduleRunner that checks for due messages every interval
+func NewScheduleRunner(schedules domain.ScheduleRepository, whatsapp ports.WhatsAppService, interval time.Duration) *ScheduleRunner {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &ScheduleRunner{
+		schedules: schedules,
+		whatsapp:  whatsapp,
+		interval:  interval,
+		logger:    logger.New("ScheduleRunner"),
+	}
+}
+
+// Start begins sending due messages in the background
+func (r *ScheduleRunner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go r.loop(ctx, r.done)
+	r.logger.WithField("interval", r.interval.String()).Info("Schedule runner started")
+}
+
+// Stop stops the runner and waits for the message in progress to be sent
+func (r *ScheduleRunner) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	r.logger.Info("Schedule runner stopped")
+}
+
+func (r *ScheduleRunner) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	r.runDue(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runDue(ctx)
+		}
+	}
+}
+
+// runDue runs the messages that are due now. Messages left waiting for their
+// device stay due, so it pages past them rather than load them again.
+func (r *ScheduleRunner) runDue(ctx context.Context) {
+	now := time.Now()
+
+	var after *domain.ScheduledMessage
+	for {
+		schedules, err := r.schedules.FindDue(now, after, scheduleBatchSize)
+		if err != nil {
+			r.logger.Error("Failed to load due messages: %v", err)
+			return
+		}
+
+		for _, schedule := range schedules {
+			if ctx.Err() != nil {
+				return
+			}
+			r.run(schedule, now)
+		}
+
+		if len(schedules) < scheduleBatchSize {
+			return
+		}
+		after = schedules[len(schedules)-1]
+	}
+}
+
+// run sends a due message if its device is connected to this replica, and
+// records the run
+func (r *ScheduleRunner) run(schedule *domain.ScheduledMessage, now time.Time) {
+	log := r.logger.WithFields(map[string]interface{}{
+		"schedule": schedule.ID,
+		"device":   schedule.DeviceName,
+	})
+
+	due := *schedule.NextRunAt
+	next := schedule.NextRun(latest(now, due))
+
+	if !r.whatsapp.IsDeviceConnected(schedule.DeviceName) {
+		following := schedule.NextRun(due)
+		if !schedule.Recurring() || following.After(now) {
+			// Wait for the device to connect, here or on another replica
+			return
+		}
+
+		claimed, err := r.schedules.Claim(schedule.ID, due, next)
+		if err != nil {
+			log.Error("Failed to claim scheduled run: %v", err)
+			return
+		}
+		if !claimed {
+			return
+		}
+		log.Warn("Device was not connected, scheduled run missed")
+		r.record(schedule, due, apperrors.New(apperrors.ErrorTypeConnection, "Device was not connected"))
+		return
+	}
+
+	claimed, err := r.schedules.Claim(schedule.ID, due, next)
+	if err != nil {
+		log.Error("Failed to claim scheduled run: %v", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	err = r.send(schedule)
+	if err != nil {
+		log.Warn("Failed to send scheduled message: %v", err)
+	} else {
+		log.Success("Scheduled message sent")
+	}
+	r.record(schedule, due, err)
+
+	if next.IsZero() && schedule.ContentType.IsMedia() {
+		if err := r.schedules.DeleteMedia(schedule.ID); err != nil {
+			log.Warn("Failed to delete media of completed schedule: %v", err)
+		}
+	}
+}
+
+// send sends a scheduled message
+func (r *ScheduleRunner) send(schedule *domain.ScheduledMessage) error {
+	receiverType := coreDomain.ReceiverIndividual
+	if schedule.IsGroup {
+		receiverType = coreDomain.ReceiverGroup
+	}
+
+	params := coreDomain.SendMessageParams{
+		DeviceName:   schedule.DeviceName,
+		To:           schedule.To,
+		ReceiverType: receiverType,
+		MessageType:  coreDomain.MessageType(schedule.ContentType),
+	}
+	if schedule.ContentType.IsMedia() {
+		data, err := r.schedules.FindMedia(schedule.ID)
+		if err != nil {
+			return err
+		}
+		params.FileData = data
+		params.FileName = schedule.FileName
+		params.Caption = schedule.Message
+	} else {
+		params.Message = schedule.Message
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), scheduleSendTimeout)
+	defer cancel()
+
+	return r.whatsapp.SendMessage(ctx, params)
+}
+
+// record saves the outcome of a run
+func (r *ScheduleRunner) record(schedule *domain.ScheduledMessage, due time.Time, err error) {
+	run := &domain.ScheduleRun{
+		ScheduleID:   schedule.ID,
+		DeviceName:   schedule.DeviceName,
+		To:           schedule.To,
+		ScheduledFor: due,
+		RanAt:        time.Now(),
+		Status:       domain.RunSent,
+	}
+	if err != nil {
+		run.Status = domain.RunFailed
+		run.Error = errorText(err)
+	}
+
+	if err := r.schedules.RecordRun(run); err != nil {
+		r.logger.WithField("schedule", schedule.ID).Error("Failed to record scheduled run: %v", err)
+	}
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// errorText describes why sending failed, without the error type prefix
+func errorText(err error) string {
+	appErr := apperrors.GetAppError(err)
+	if appErr.Err != nil {
+		return appErr.Message + ": " + appErr.Err.Error()
+	}
+	return appErr.Message
+}
@@ -0,0 +1,151 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	coreDomain "github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+)
+
+// memorySchedules keeps scheduled messages in memory, ordered and claimed
+// like the MongoDB repository does
+type memorySchedules struct {
+	domain.ScheduleRepository
+
+	mu        sync.Mutex
+	schedules map[string]*domain.ScheduledMessage
+	runs      []*domain.ScheduleRun
+}
+
+func (m *memorySchedules) FindDue(now time.Time, after *domain.ScheduledMessage, limit int) ([]*domain.ScheduledMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ordered := func(a, b *domain.ScheduledMessage) bool {
+		if !a.NextRunAt.Equal(*b.NextRunAt) {
+			return a.NextRunAt.Before(*b.NextRunAt)
+		}
+		return a.ID < b.ID
+	}
+
+	var due []*domain.ScheduledMessage
+	for _, schedule := range m.schedules {
+		if schedule.Status != domain.ScheduleActive || schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+			continue
+		}
+		if after != nil && !ordered(after, schedule) {
+			continue
+		}
+		copied := *schedule
+		due = append(due, &copied)
+	}
+
+	sort.Slice(due, func(i, j int) bool { return ordered(due[i], due[j]) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *memorySchedules) Claim(id string, due, next time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	schedule := m.schedules[id]
+	if schedule.Status != domain.ScheduleActive || !schedule.NextRunAt.Equal(due) {
+		return false, nil
+	}
+	if next.IsZero() {
+		schedule.Status = domain.ScheduleCompleted
+		schedule.NextRunAt = nil
+	} else {
+		schedule.NextRunAt = &next
+	}
+	return true, nil
+}
+
+func (m *memorySchedules) RecordRun(run *domain.ScheduleRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+// connectedDevices stands in for the WhatsApp service of a replica with some
+// devices connected
+type connectedDevices struct {
+	ports.WhatsAppService
+
+	connected map[string]bool
+
+	mu   sync.Mutex
+	sent []coreDomain.SendMessageParams
+}
+
+func (d *connectedDevices) IsDeviceConnected(deviceName string) bool {
+	return d.connected[deviceName]
+}
+
+func (d *connectedDevices) SendMessage(ctx context.Context, params coreDomain.SendMessageParams) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sent = append(d.sent, params)
+	return nil
+}
+
+func TestRunDuePagesPastWaitingMessages(t *testing.T) {
+	now := time.Now()
+	overdue := now.Add(-time.Hour)
+	due := now.Add(-time.Minute)
+
+	repository := &memorySchedules{schedules: make(map[string]*domain.ScheduledMessage)}
+	add := func(id, deviceName string, nextRun time.Time) {
+		repository.schedules[id] = &domain.ScheduledMessage{
+			ID:          id,
+			DeviceName:  deviceName,
+			To:          "6281234567890",
+			ContentType: domain.ContentText,
+			Message:     "Laporan harian",
+			SendAt:      &nextRun,
+			Status:      domain.ScheduleActive,
+			NextRunAt:   &nextRun,
+		}
+	}
+
+	// More one-off messages waiting for an offline device than fit in a
+	// batch, all more overdue than the message of the connected device
+	waiting := 2*scheduleBatchSize + 10
+	for i := 0; i < waiting; i++ {
+		add(fmt.Sprintf("waiting-%03d", i), "offline", overdue)
+	}
+	add("ready", "online", due)
+
+	whatsapp := &connectedDevices{connected: map[string]bool{"online": true}}
+	runner := NewScheduleRunner(repository, whatsapp, time.Minute)
+	runner.runDue(context.Background())
+
+	if len(whatsapp.sent) != 1 || whatsapp.sent[0].DeviceName != "online" {
+		t.Fatalf("sent %+v, want the message of the connected device", whatsapp.sent)
+	}
+	if status := repository.schedules["ready"].Status; status != domain.ScheduleCompleted {
+		t.Errorf("ready message status = %s, want %s", status, domain.ScheduleCompleted)
+	}
+
+	for i := 0; i < waiting; i++ {
+		schedule := repository.schedules[fmt.Sprintf("waiting-%03d", i)]
+		if schedule.Status != domain.ScheduleActive || !schedule.NextRunAt.Equal(overdue) {
+			t.Fatalf("%s was claimed while its device was offline", schedule.ID)
+		}
+	}
+	if len(repository.runs) != 1 {
+		t.Errorf("recorded %d runs, want 1", len(repository.runs))
+	}
+}
@@ -0,0 +1,227 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/modules/schedule/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/phone"
+)
+
+// ScheduleService manages scheduled messages: creating them and moving them
+// between active, paused and cancelled. Sending is left to the ScheduleRunner.
+type ScheduleService struct {
+	schedules domain.ScheduleRepository
+	timezone  string
+	logger    *logger.Logger
+}
+
+// NewScheduleService creates a new ScheduleService; timezone is used for
+// schedules that do not name their own
+func NewScheduleService(schedules domain.ScheduleRepository, timezone string) *ScheduleService {
+	return &ScheduleService{
+		schedules: schedules,
+		timezone:  timezone,
+		logger:    logger.New("ScheduleService"),
+	}
+}
+
+// DefaultLocation returns the timezone of schedules that do not name their own
+func (s *ScheduleService) DefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(s.timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// sendAtLayouts are the accepted forms of a send time without an offset
+var sendAtLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseSendAt parses the send time of a one-off message. Times in RFC 3339
+// format carry their own offset; times without one, like "2026-11-02 09:00",
+// are read in the given timezone, or the default one when empty.
+func (s *ScheduleService) ParseSendAt(value, timezone string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	loc := s.DefaultLocation()
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return time.Time{}, apperrors.NewValidationError(fmt.Sprintf("unknown timezone: %s", timezone))
+		}
+	}
+
+	for _, layout := range sendAtLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, apperrors.NewValidationError("send_at must be a time like 2006-01-02T15:04:05+07:00 or 2006-01-02 15:04")
+}
+
+// Create saves a new active scheduled message with its media, if any
+func (s *ScheduleService) Create(schedule *domain.ScheduledMessage, media []byte) error {
+	schedule.Status = domain.ScheduleActive
+	schedule.MediaSize = int64(len(media))
+	if schedule.Timezone == "" {
+		schedule.Timezone = s.timezone
+	}
+
+	to, isGroup, err := recipient(schedule.To, schedule.IsGroup)
+	if err != nil {
+		return apperrors.NewValidationError(err.Error())
+	}
+	schedule.To, schedule.IsGroup = to, isGroup
+
+	if err := schedule.Validate(); err != nil {
+		return apperrors.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	if schedule.SendAt != nil && !schedule.SendAt.After(now) {
+		return apperrors.NewValidationError("send_at must be in the future")
+	}
+	next := schedule.NextRun(now)
+	if next.IsZero() {
+		return apperrors.NewValidationError(fmt.Sprintf("cron %q never matches", schedule.Cron))
+	}
+	schedule.NextRunAt = &next
+
+	if err := s.schedules.Create(schedule, media); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"id":       schedule.ID,
+		"device":   schedule.DeviceName,
+		"next_run": next.In(schedule.Location()).Format(time.RFC3339),
+	}).Info("Message scheduled")
+	return nil
+}
+
+// Get retrieves a scheduled message
+func (s *ScheduleService) Get(id string) (*domain.ScheduledMessage, error) {
+	return s.schedules.FindByID(id)
+}
+
+// List lists the scheduled messages matching the filter, newest first, with
+// the number of matching messages
+func (s *ScheduleService) List(filter domain.ScheduleFilter, skip, limit int) ([]*domain.ScheduledMessage, int64, error) {
+	total, err := s.schedules.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	schedules, err := s.schedules.Find(filter, skip, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return schedules, total, nil
+}
+
+// Runs lists the runs of a scheduled message, newest first, with their number
+func (s *ScheduleService) Runs(id string, skip, limit int) ([]*domain.ScheduleRun, int64, error) {
+	total, err := s.schedules.CountRuns(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	runs, err := s.schedules.FindRuns(id, skip, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// Pause stops an active scheduled message from running until it is resumed
+func (s *ScheduleService) Pause(id, username string) (*domain.ScheduledMessage, error) {
+	return s.schedules.Transition(id,
+		[]domain.ScheduleStatus{domain.ScheduleActive},
+		domain.SchedulePaused, reason("Paused", username), time.Time{})
+}
+
+// Resume makes a paused scheduled message active again. Runs missed while
+// it was paused are not sent, except a one-off message, which is sent now.
+func (s *ScheduleService) Resume(id string) (*domain.ScheduledMessage, error) {
+	schedule, err := s.schedules.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	next := schedule.NextRun(now)
+	if next.IsZero() {
+		if schedule.Recurring() {
+			return nil, apperrors.New(apperrors.ErrorTypeConflict, fmt.Sprintf("cron %q no longer matches", schedule.Cron))
+		}
+		next = now
+	}
+
+	return s.schedules.Transition(id,
+		[]domain.ScheduleStatus{domain.SchedulePaused},
+		domain.ScheduleActive, "", next)
+}
+
+// Cancel stops a scheduled message for good
+func (s *ScheduleService) Cancel(id, username string) (*domain.ScheduledMessage, error) {
+	schedule, err := s.schedules.Transition(id,
+		[]domain.ScheduleStatus{domain.ScheduleActive, domain.SchedulePaused},
+		domain.ScheduleCancelled, reason("Cancelled", username), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.ContentType.IsMedia() {
+		if err := s.schedules.DeleteMedia(schedule.ID); err != nil {
+			s.logger.WithField("id", schedule.ID).Warn("Failed to delete media of cancelled schedule: %v", err)
+		}
+	}
+	return schedule, nil
+}
+
+// recipient checks the address a message is scheduled for. Groups are given
+// by JID, or by ID when isGroup is set; people by JID or phone number, which
+// is stored in international digits.
+func recipient(to string, isGroup bool) (string, bool, error) {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return "", false, fmt.Errorf("to is required")
+	}
+
+	user, server, isJID := strings.Cut(to, "@")
+	if isGroup || server == "g.us" {
+		if !isJID {
+			to += "@g.us"
+		}
+		return to, true, nil
+	}
+	if isJID {
+		return to, false, nil
+	}
+
+	number, err := phone.Normalize(user)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid phone number %q: must be in international format, e.g. +62812..., or a national number", to)
+	}
+	return number, false, nil
+}
+
+// reason describes who changed a scheduled message's status
+func reason(action, username string) string {
+	if username == "" {
+		return action
+	}
+	return action + " by " + username
+}
@@ -0,0 +1,301 @@
+// Package chatexport parses the .txt files produced by WhatsApp's "Export chat"
+// feature, either on their own or inside the zip that is created when media is
+// included.
+package chatexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxChatSize limits the chat text unpacked from a zip, which may be far
+// larger than the zip itself
+const maxChatSize = 100 << 20
+
+// Message is a single message recovered from an export
+type Message struct {
+	Line       int // Line number of the message header, starting at 1
+	Timestamp  time.Time
+	Sender     string // Contact name or phone number as shown in the export
+	Content    string
+	Attachment string // File name of attached media, if any
+}
+
+// SenderJID returns the WhatsApp JID of the sender when the export shows a
+// phone number instead of a saved contact name, or an empty string otherwise
+func (m Message) SenderJID() string {
+	var digits strings.Builder
+	for _, r := range m.Sender {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == '-' || r == ' ' || r == '(' || r == ')':
+		default:
+			return ""
+		}
+	}
+	if digits.Len() < 8 {
+		return ""
+	}
+	return digits.String() + "@s.whatsapp.net"
+}
+
+var (
+	// iOS: [25/01/24, 08.15.30] Name: message
+	iosHeader = regexp.MustCompile(`^\[(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{2,4}),?\s+(\d{1,2})[.:](\d{2})(?:[.:](\d{2}))?(?:\s*([AaPp])\.?\s?[Mm]\.?)?\]\s(.*)$`)
+
+	// Android: 25/01/24 08.15 - Name: message
+	androidHeader = regexp.MustCompile(`^(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{2,4}),?\s+(\d{1,2})[.:](\d{2})(?:[.:](\d{2}))?(?:\s*([AaPp])\.?\s?[Mm]\.?)?\s-\s(.*)$`)
+
+	// iOS: <attached: 00000012-PHOTO-2024-01-25-08-15-30.jpg>
+	iosAttachment = regexp.MustCompile(`^<attached: (.+)>$`)
+
+	// Android: IMG-20240125-WA0001.jpg (file attached)
+	androidAttachment = regexp.MustCompile(`^(.+\.\w+) \(file attached\)$`)
+
+	// Invisible and non-breaking characters WhatsApp inserts around timestamps and names
+	invisibleReplacer = strings.NewReplacer("\u200e", "", "\u200f", "", "\ufeff", "", "\u202f", " ", "\u00a0", " ")
+)
+
+// header holds the raw fields of a message header before the date order is known
+type header struct {
+	line    int
+	first   int
+	second  int
+	year    int
+	hour    int
+	minute  int
+	second2 int
+	period  string // "a" or "p" for 12-hour clocks
+	rest    string
+}
+
+// ReadFile parses an uploaded export. Zip archives are searched for the chat
+// .txt file; anything else is parsed as the chat text itself.
+func ReadFile(data []byte, loc *time.Location) ([]Message, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		text, err := chatFromZip(data)
+		if err != nil {
+			return nil, err
+		}
+		data = text
+	}
+	return Parse(bytes.NewReader(data), loc)
+}
+
+// Parse splits an exported chat into messages. Lines without a header are
+// continuations of the previous message. System notices (joins, encryption
+// notices, ...) have no sender and are dropped. Whether dates are day-first or
+// month-first is detected from the export itself, defaulting to day-first.
+func Parse(r io.Reader, loc *time.Location) ([]Message, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var headers []header
+	var bodies [][]string
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(invisibleReplacer.Replace(scanner.Text()), "\r")
+
+		if h, ok := parseHeader(line); ok {
+			h.line = lineNo
+			headers = append(headers, h)
+			bodies = append(bodies, []string{h.rest})
+			continue
+		}
+
+		// Text before the first header cannot belong to any message
+		if len(bodies) > 0 {
+			bodies[len(bodies)-1] = append(bodies[len(bodies)-1], line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat export: %w", err)
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("no WhatsApp messages found in chat export")
+	}
+
+	dayFirst := detectDayFirst(headers)
+
+	messages := make([]Message, 0, len(headers))
+	for i, h := range headers {
+		sender, first, ok := splitSender(bodies[i][0])
+		if !ok {
+			continue
+		}
+
+		timestamp, err := h.time(dayFirst, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", h.line, err)
+		}
+
+		lines := append([]string{first}, bodies[i][1:]...)
+		msg := Message{
+			Line:      h.line,
+			Timestamp: timestamp,
+			Sender:    sender,
+			Content:   strings.TrimSpace(strings.Join(lines, "\n")),
+		}
+		msg.Attachment, msg.Content = splitAttachment(msg.Content)
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// parseHeader matches the timestamp prefix of a message line
+func parseHeader(line string) (header, bool) {
+	match := iosHeader.FindStringSubmatch(line)
+	if match == nil {
+		match = androidHeader.FindStringSubmatch(line)
+	}
+	if match == nil {
+		return header{}, false
+	}
+
+	h := header{rest: match[8], period: strings.ToLower(match[7])}
+	h.first, _ = strconv.Atoi(match[1])
+	h.second, _ = strconv.Atoi(match[2])
+	h.year, _ = strconv.Atoi(match[3])
+	h.hour, _ = strconv.Atoi(match[4])
+	h.minute, _ = strconv.Atoi(match[5])
+	if match[6] != "" {
+		h.second2, _ = strconv.Atoi(match[6])
+	}
+	return h, true
+}
+
+// detectDayFirst decides the date order: a first component above 12 means
+// day-first, a second component above 12 means month-first
+func detectDayFirst(headers []header) bool {
+	for _, h := range headers {
+		if h.first > 12 {
+			return true
+		}
+		if h.second > 12 {
+			return false
+		}
+	}
+	return true
+}
+
+// time converts the header fields to a timestamp in loc
+func (h header) time(dayFirst bool, loc *time.Location) (time.Time, error) {
+	day, month := h.first, h.second
+	if !dayFirst {
+		day, month = h.second, h.first
+	}
+
+	year := h.year
+	if year < 100 {
+		year += 2000
+	}
+
+	hour := h.hour
+	switch h.period {
+	case "a":
+		if hour == 12 {
+			hour = 0
+		}
+	case "p":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || h.minute > 59 || h.second2 > 59 {
+		return time.Time{}, fmt.Errorf("invalid timestamp %02d/%02d/%d %02d:%02d", day, month, year, hour, h.minute)
+	}
+
+	return time.Date(year, time.Month(month), day, hour, h.minute, h.second2, 0, loc), nil
+}
+
+// splitSender separates "Name: message". Lines without a sender are system notices.
+func splitSender(rest string) (string, string, bool) {
+	i := strings.Index(rest, ": ")
+	if i <= 0 {
+		// A sender followed by an empty first line
+		if strings.HasSuffix(rest, ":") {
+			return strings.TrimSuffix(rest, ":"), "", true
+		}
+		return "", "", false
+	}
+	return strings.TrimSpace(rest[:i]), rest[i+2:], true
+}
+
+// splitAttachment extracts the media file name from an attachment message
+func splitAttachment(content string) (string, string) {
+	first, caption, _ := strings.Cut(content, "\n")
+	if match := iosAttachment.FindStringSubmatch(first); match != nil {
+		return match[1], strings.TrimSpace(caption)
+	}
+	if match := androidAttachment.FindStringSubmatch(first); match != nil {
+		return match[1], strings.TrimSpace(caption)
+	}
+	return "", content
+}
+
+// chatFromZip returns the chat text from an export zip. iOS names it
+// _chat.txt, Android "WhatsApp Chat with <name>.txt".
+func chatFromZip(data []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	var candidates []*zip.File
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".txt") {
+			continue
+		}
+		candidates = append(candidates, file)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("zip archive does not contain a chat .txt file")
+	}
+
+	rank := func(file *zip.File) int {
+		name := path.Base(file.Name)
+		switch {
+		case name == "_chat.txt":
+			return 0
+		case strings.HasPrefix(name, "WhatsApp Chat"):
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rank(candidates[i]) < rank(candidates[j])
+	})
+
+	f, err := candidates[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", candidates[0].Name, err)
+	}
+	defer f.Close()
+
+	text, err := io.ReadAll(io.LimitReader(f, maxChatSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", candidates[0].Name, err)
+	}
+	if len(text) > maxChatSize {
+		return nil, fmt.Errorf("%s is larger than %d MB", candidates[0].Name, maxChatSize>>20)
+	}
+	return text, nil
+}
@@ -0,0 +1,195 @@
+package chatexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	at := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, loc)
+	}
+
+	tests := []struct {
+		name    string
+		chat    string
+		want    []Message
+		wantErr bool
+	}{
+		{
+			name: "ios",
+			chat: "[25/01/24, 08.15.30] Budi: Laporan pagi\n[25/01/24, 09.00.05] +62 812-3456-7890: Siap",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 30), Sender: "Budi", Content: "Laporan pagi"},
+				{Line: 2, Timestamp: at(2024, 1, 25, 9, 0, 5), Sender: "+62 812-3456-7890", Content: "Siap"},
+			},
+		},
+		{
+			name: "android",
+			chat: "25/01/2024 08.15 - Budi: Laporan pagi\n25/01/2024 21:40 - Sari: Selesai",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 0), Sender: "Budi", Content: "Laporan pagi"},
+				{Line: 2, Timestamp: at(2024, 1, 25, 21, 40, 0), Sender: "Sari", Content: "Selesai"},
+			},
+		},
+		{
+			name: "12-hour clock",
+			chat: "[1/2/24, 12:05:00 AM] Budi: tengah malam\n[1/2/24, 3:30:00 PM] Budi: sore",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 2, 1, 0, 5, 0), Sender: "Budi", Content: "tengah malam"},
+				{Line: 2, Timestamp: at(2024, 2, 1, 15, 30, 0), Sender: "Budi", Content: "sore"},
+			},
+		},
+		{
+			name: "month-first detected",
+			chat: "1/2/24, 10:00 - Budi: first\n1/25/24, 10:00 - Budi: second",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 2, 10, 0, 0), Sender: "Budi", Content: "first"},
+				{Line: 2, Timestamp: at(2024, 1, 25, 10, 0, 0), Sender: "Budi", Content: "second"},
+			},
+		},
+		{
+			name: "day-first by default",
+			chat: "1/2/24, 10:00 - Budi: ambiguous",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 2, 1, 10, 0, 0), Sender: "Budi", Content: "ambiguous"},
+			},
+		},
+		{
+			name: "continuation lines",
+			chat: "25/01/24 08.15 - Budi: Nama: Budi\nJabatan: Babinsa\n\nKegiatan: patroli\n25/01/24 08.20 - Sari: ok",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 0), Sender: "Budi", Content: "Nama: Budi\nJabatan: Babinsa\n\nKegiatan: patroli"},
+				{Line: 5, Timestamp: at(2024, 1, 25, 8, 20, 0), Sender: "Sari", Content: "ok"},
+			},
+		},
+		{
+			name: "sender with empty first line",
+			chat: "25/01/24 08.15 - Budi:\nLaporan",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 0), Sender: "Budi", Content: "Laporan"},
+			},
+		},
+		{
+			name: "system notices and preamble dropped",
+			chat: "exported chat\n25/01/24 08.00 - Messages are end-to-end encrypted.\n25/01/24 08.01 - Budi joined using this group's invite link\n25/01/24 08.15 - Budi: halo",
+			want: []Message{
+				{Line: 4, Timestamp: at(2024, 1, 25, 8, 15, 0), Sender: "Budi", Content: "halo"},
+			},
+		},
+		{
+			name: "invisible characters and carriage returns",
+			chat: "\ufeff[25/01/24, 08.15.30] \u200eBudi: halo\r\n[25/01/24, 08.16.00] Sari: ok\r",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 30), Sender: "Budi", Content: "halo"},
+				{Line: 2, Timestamp: at(2024, 1, 25, 8, 16, 0), Sender: "Sari", Content: "ok"},
+			},
+		},
+		{
+			name: "ios attachment with caption",
+			chat: "[25/01/24, 08.15.30] Budi: <attached: 00000012-PHOTO-2024-01-25-08-15-30.jpg>\nfoto patroli",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 30), Sender: "Budi", Content: "foto patroli", Attachment: "00000012-PHOTO-2024-01-25-08-15-30.jpg"},
+			},
+		},
+		{
+			name: "android attachment",
+			chat: "25/01/24 08.15 - Budi: IMG-20240125-WA0001.jpg (file attached)",
+			want: []Message{
+				{Line: 1, Timestamp: at(2024, 1, 25, 8, 15, 0), Sender: "Budi", Attachment: "IMG-20240125-WA0001.jpg"},
+			},
+		},
+		{
+			name:    "no messages",
+			chat:    "not an export\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp",
+			chat:    "25/13/24 08.15 - Budi: halo\n25/01/24 08.15 - Budi: halo",
+			wantErr: true,
+		},
+		{
+			name:    "invalid hour",
+			chat:    "25/01/24 25.15 - Budi: halo",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.chat), loc)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() returned %d messages, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !got[i].Timestamp.Equal(tt.want[i].Timestamp) {
+					t.Errorf("message %d timestamp = %v, want %v", i, got[i].Timestamp, tt.want[i].Timestamp)
+				}
+				got[i].Timestamp = tt.want[i].Timestamp
+				if got[i] != tt.want[i] {
+					t.Errorf("message %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMessageSenderJID(t *testing.T) {
+	tests := []struct {
+		sender string
+		want   string
+	}{
+		{sender: "+62 812-3456-7890", want: "6281234567890@s.whatsapp.net"},
+		{sender: "+62 (812) 3456 7890", want: "6281234567890@s.whatsapp.net"},
+		{sender: "Budi", want: ""},
+		{sender: "Budi 2", want: ""},
+		{sender: "+62 812", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := (Message{Sender: tt.sender}).SenderJID(); got != tt.want {
+			t.Errorf("SenderJID(%q) = %q, want %q", tt.sender, got, tt.want)
+		}
+	}
+}
+
+func TestReadFileZip(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"notes.txt": "25/01/24 08.15 - Budi: wrong file",
+		"_chat.txt": "[25/01/24, 08.15.30] Budi: Laporan pagi",
+	} {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+
+	messages, err := ReadFile(buf.Bytes(), time.UTC)
+	if err != nil {
+		t.Fatalf("ReadFile() unexpected error: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Content != "Laporan pagi" {
+		t.Errorf("ReadFile() = %+v, want the message of _chat.txt", messages)
+	}
+}
@@ -23,6 +23,12 @@ type ServerConfig struct {
 	Port            string
 	Environment     string
 	ShutdownTimeout time.Duration
+
+	// Bearer token required by /metrics; empty disables the metrics routes
+	MetricsToken string
+
+	// Bearer token required by /admin; empty disables the admin routes
+	AdminToken string
 }
 
 // MongoDBConfig holds MongoDB configuration
@@ -46,6 +52,35 @@ type WhatsAppConfig struct {
 	StoresDir      string
 	UploadsDir     string
 	MaxConcurrency int
+
+	// Reconnect backoff of the connection supervisor
+	ReconnectInitialBackoff time.Duration
+	ReconnectMaxBackoff     time.Duration
+
+	// Session store backend: "sqlite" (one file per device in StoresDir) or
+	// "postgres" (one shared database at StoreDSN)
+	StoreBackend string
+	StoreDSN     string
+
+	// Import the SQLite stores of StoresDir when the backend is postgres
+	StoreMigrateSQLite bool
+
+	// How long a cached profile picture is served before checking for a new one
+	ProfilePictureTTL time.Duration
+
+	// How long connection history is kept for uptime reports; zero keeps it forever
+	ConnectionHistoryRetention time.Duration
+
+	// Device leases let several replicas share one registry; each device runs
+	// on the replica holding its lease
+	LeasesEnabled  bool
+	ReplicaID      string
+	ReplicaAddress string // Base URL other replicas forward this replica's devices to
+	LeaseTTL       time.Duration
+
+	// Default timezone of scheduled messages, used for send times without an
+	// offset and for recurrences
+	ScheduleTimezone string
 }
 
 // CORSConfig holds CORS configuration
@@ -66,7 +101,9 @@ func Load() (*Config, error) {
 		Server: ServerConfig{
 			Port:            getEnv("PORT", "3000"),
 			Environment:     getEnv("ENVIRONMENT", "development"),
-			ShutdownTimeout: 10 * time.Second,
+			ShutdownTimeout: time.Duration(getEnvAsInt("SHUTDOWN_TIMEOUT_SECONDS", 10)) * time.Second,
+			MetricsToken:    getEnv("METRICS_TOKEN", ""),
+			AdminToken:      getEnv("ADMIN_TOKEN", ""),
 		},
 		MongoDB: MongoDBConfig{
 			User:     getEnv("MONGO_USER", ""),
@@ -83,6 +120,24 @@ func Load() (*Config, error) {
 			StoresDir:      getEnv("WHATSAPP_STORES_DIR", "./stores"),
 			UploadsDir:     getEnv("WHATSAPP_UPLOADS_DIR", "./uploads/whatsapp"),
 			MaxConcurrency: getEnvAsInt("WHATSAPP_MAX_CONCURRENCY", 10),
+
+			ReconnectInitialBackoff: time.Duration(getEnvAsInt("WHATSAPP_RECONNECT_INITIAL_SECONDS", 2)) * time.Second,
+			ReconnectMaxBackoff:     time.Duration(getEnvAsInt("WHATSAPP_RECONNECT_MAX_SECONDS", 300)) * time.Second,
+
+			StoreBackend:       getEnv("WHATSAPP_STORE_BACKEND", "sqlite"),
+			StoreDSN:           getEnv("WHATSAPP_STORE_DSN", ""),
+			StoreMigrateSQLite: getEnvAsBool("WHATSAPP_STORE_MIGRATE_SQLITE", true),
+
+			ProfilePictureTTL: time.Duration(getEnvAsInt("WHATSAPP_PROFILE_PICTURE_TTL_MINUTES", 360)) * time.Minute,
+
+			ConnectionHistoryRetention: time.Duration(getEnvAsInt("WHATSAPP_CONNECTION_HISTORY_RETENTION_DAYS", 90)) * 24 * time.Hour,
+
+			LeasesEnabled:  getEnvAsBool("WHATSAPP_LEASES_ENABLED", false),
+			ReplicaID:      getEnv("WHATSAPP_REPLICA_ID", defaultReplicaID()),
+			ReplicaAddress: getEnv("WHATSAPP_REPLICA_ADDRESS", ""),
+			LeaseTTL:       time.Duration(getEnvAsInt("WHATSAPP_LEASE_TTL_SECONDS", 30)) * time.Second,
+
+			ScheduleTimezone: getEnv("WHATSAPP_SCHEDULE_TIMEZONE", "Asia/Jakarta"),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: []string{
@@ -148,9 +203,43 @@ func validate(config *Config) error {
 		return fmt.Errorf("MONGO_DB is required")
 	}
 
+	switch config.WhatsApp.StoreBackend {
+	case "sqlite":
+	case "postgres":
+		if config.WhatsApp.StoreDSN == "" {
+			return fmt.Errorf("WHATSAPP_STORE_DSN is required when WHATSAPP_STORE_BACKEND is postgres")
+		}
+	default:
+		return fmt.Errorf("WHATSAPP_STORE_BACKEND must be sqlite or postgres, got %q", config.WhatsApp.StoreBackend)
+	}
+
+	if config.WhatsApp.LeasesEnabled {
+		// Every replica must be able to open every session
+		if config.WhatsApp.StoreBackend != "postgres" {
+			return fmt.Errorf("WHATSAPP_LEASES_ENABLED requires WHATSAPP_STORE_BACKEND=postgres")
+		}
+		if config.WhatsApp.LeaseTTL < 6*time.Second {
+			return fmt.Errorf("WHATSAPP_LEASE_TTL_SECONDS must be at least 6")
+		}
+	}
+
+	if _, err := time.LoadLocation(config.WhatsApp.ScheduleTimezone); err != nil {
+		return fmt.Errorf("WHATSAPP_SCHEDULE_TIMEZONE is not a known timezone: %q", config.WhatsApp.ScheduleTimezone)
+	}
+
 	return nil
 }
 
+// defaultReplicaID identifies the replica by its host name, which is unique
+// per container or pod
+func defaultReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return fmt.Sprintf("replica-%d", os.Getpid())
+	}
+	return hostname
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -99,6 +99,12 @@ func IsAppError(err error) bool {
 	return errors.As(err, &appErr)
 }
 
+// IsNotFound checks if an error is a not found AppError
+func IsNotFound(err error) bool {
+	var appErr *AppError
+	return errors.As(err, &appErr) && appErr.Type == ErrorTypeNotFound
+}
+
 // GetAppError extracts AppError from error
 func GetAppError(err error) *AppError {
 	var appErr *AppError
@@ -125,6 +131,13 @@ func NewUnauthorizedError(message string) *AppError {
 	return New(ErrorTypeUnauthorized, message)
 }
 
+func NewForbiddenError(message string) *AppError {
+	if message == "" {
+		message = "Access denied"
+	}
+	return New(ErrorTypeForbidden, message)
+}
+
 func NewWhatsAppError(message string, err error) *AppError {
 	return Wrap(err, ErrorTypeWhatsApp, message)
 }
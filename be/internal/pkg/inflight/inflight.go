@@ -0,0 +1,64 @@
+// Package inflight counts work that is still running so that shutdown can
+// wait for it.
+package inflight
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker counts running tasks. Unlike sync.WaitGroup, tasks may start while
+// Wait is waiting, as happens when messages keep arriving during shutdown.
+type Tracker struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // Closed when count drops to zero
+}
+
+// Start records the start of a task; every Start must be followed by Done
+func (t *Tracker) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.count == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count++
+}
+
+// Done records the end of a task
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.count--
+	if t.count == 0 {
+		close(t.idle)
+	}
+}
+
+// Count returns the number of running tasks
+func (t *Tracker) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// Wait blocks until no task is running or ctx is done, in which case it
+// returns the context's error
+func (t *Tracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -100,6 +100,13 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 	return newLogger
 }
 
+// WithPrefix returns a copy of the logger with another prefix, keeping its fields
+func (l *Logger) WithPrefix(prefix string) *Logger {
+	newLogger := l.WithFields(nil)
+	newLogger.prefix = prefix
+	return newLogger
+}
+
 // WithContext extracts fields from context
 func (l *Logger) WithContext(ctx context.Context) *Logger {
 	// You can extract request ID, user ID, etc. from context here
@@ -192,7 +199,7 @@ func (l *Logger) ErrorWithStack(err error, msg string) {
 
 	l.WithField("error", err.Error()).
 		WithField("stack", stack).
-		Error(msg)
+		Error("%s", msg)
 }
 
 // Success logs a success message (INFO level with ✅ emoji)
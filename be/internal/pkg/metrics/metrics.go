@@ -0,0 +1,335 @@
+// Package metrics keeps labelled counters and histograms in memory and
+// writes them, with gauges read at scrape time, in the Prometheus text
+// exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds, suited to request and
+// message processing latencies
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample is a single value of a metric with its label values, in the order of
+// the metric's labels
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// Registry holds metrics in the order they were registered
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// metric is a registered counter, gauge or histogram
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewCounter registers a counter with the given labels
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	counter := &Counter{desc: desc{name, help, labels}, values: make(map[string]*Sample)}
+	r.register(counter)
+	return counter
+}
+
+// NewGaugeFunc registers a gauge whose samples are read from collect on every write
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func() []Sample) {
+	r.register(&gaugeFunc{desc: desc{name, help, labels}, collect: collect})
+}
+
+// NewHistogram registers a histogram with the given upper bounds, in
+// ascending order, and labels
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	histogram := &Histogram{desc: desc{name, help, labels}, buckets: buckets, values: make(map[string]*HistogramValue)}
+	r.register(histogram)
+	return histogram
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	metrics := make([]metric, len(r.metrics))
+	copy(metrics, r.metrics)
+	r.mu.Unlock()
+
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	return buf.Flush()
+}
+
+// desc describes a metric
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (d desc) writeHeader(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, kind)
+}
+
+// writeSample writes one line; extra is an additional label, such as le, written last
+func (d desc) writeSample(w *bufio.Writer, suffix string, labels []string, extra string, value float64) {
+	w.WriteString(d.name + suffix)
+
+	pairs := make([]string, 0, len(labels)+1)
+	for i, label := range d.labels {
+		if i < len(labels) {
+			pairs = append(pairs, label+`="`+escapeLabel(labels[i])+`"`)
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) > 0 {
+		w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	w.WriteString(" " + formatValue(value) + "\n")
+}
+
+// Counter is a value per label set that only goes up
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]*Sample
+}
+
+// Inc adds one to the counter of the label values
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+// Add adds delta to the counter of the label values
+func (c *Counter) Add(delta float64, labels ...string) {
+	key := labelKey(labels)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sample, ok := c.values[key]
+	if !ok {
+		sample = &Sample{Labels: append([]string(nil), labels...)}
+		c.values[key] = sample
+	}
+	sample.Value += delta
+}
+
+// Delete removes the counters whose label values start with labels
+func (c *Counter) Delete(labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, sample := range c.values {
+		if hasPrefix(sample.Labels, labels) {
+			delete(c.values, key)
+		}
+	}
+}
+
+// Samples returns the counters of all label sets, sorted by label values
+func (c *Counter) Samples() []Sample {
+	c.mu.Lock()
+	samples := make([]Sample, 0, len(c.values))
+	for _, sample := range c.values {
+		samples = append(samples, *sample)
+	}
+	c.mu.Unlock()
+
+	sortSamples(samples)
+	return samples
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.writeHeader(w, "counter")
+	for _, sample := range c.Samples() {
+		c.writeSample(w, "", sample.Labels, "", sample.Value)
+	}
+}
+
+// gaugeFunc is a gauge read at write time
+type gaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+func (g *gaugeFunc) write(w *bufio.Writer) {
+	samples := g.collect()
+	sortSamples(samples)
+
+	g.writeHeader(w, "gauge")
+	for _, sample := range samples {
+		g.writeSample(w, "", sample.Labels, "", sample.Value)
+	}
+}
+
+// Histogram counts observations per label set in cumulative buckets
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*HistogramValue
+}
+
+// HistogramValue holds the observations of one label set
+type HistogramValue struct {
+	Labels []string
+	Counts []uint64 // Observations per bucket, not cumulative
+	Count  uint64
+	Sum    float64
+	Max    float64
+}
+
+// Mean returns the average observation, or 0 without observations
+func (v HistogramValue) Mean() float64 {
+	if v.Count == 0 {
+		return 0
+	}
+	return v.Sum / float64(v.Count)
+}
+
+// Observe records a value for the label values
+func (h *Histogram) Observe(value float64, labels ...string) {
+	key := labelKey(labels)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hv, ok := h.values[key]
+	if !ok {
+		hv = &HistogramValue{
+			Labels: append([]string(nil), labels...),
+			Counts: make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = hv
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hv.Counts[i]++
+			break
+		}
+	}
+	hv.Count++
+	hv.Sum += value
+	if value > hv.Max {
+		hv.Max = value
+	}
+}
+
+// Delete removes the observations whose label values start with labels
+func (h *Histogram) Delete(labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, hv := range h.values {
+		if hasPrefix(hv.Labels, labels) {
+			delete(h.values, key)
+		}
+	}
+}
+
+// Values returns the observations of all label sets, sorted by label values
+func (h *Histogram) Values() []HistogramValue {
+	h.mu.Lock()
+	values := make([]HistogramValue, 0, len(h.values))
+	for _, hv := range h.values {
+		value := *hv
+		value.Labels = append([]string(nil), hv.Labels...)
+		value.Counts = append([]uint64(nil), hv.Counts...)
+		values = append(values, value)
+	}
+	h.mu.Unlock()
+
+	sort.Slice(values, func(i, j int) bool {
+		return labelKey(values[i].Labels) < labelKey(values[j].Labels)
+	})
+	return values
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.writeHeader(w, "histogram")
+	for _, value := range h.Values() {
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += value.Counts[i]
+			h.writeSample(w, "_bucket", value.Labels, `le="`+formatValue(bound)+`"`, float64(cumulative))
+		}
+		h.writeSample(w, "_bucket", value.Labels, `le="+Inf"`, float64(value.Count))
+		h.writeSample(w, "_sum", value.Labels, "", value.Sum)
+		h.writeSample(w, "_count", value.Labels, "", float64(value.Count))
+	}
+}
+
+// labelKey joins label values into a map key
+func labelKey(labels []string) string {
+	return strings.Join(labels, "\xff")
+}
+
+// hasPrefix reports whether labels starts with prefix
+func hasPrefix(labels, prefix []string) bool {
+	if len(prefix) > len(labels) {
+		return false
+	}
+	for i := range prefix {
+		if labels[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortSamples(samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return labelKey(samples[i].Labels) < labelKey(samples[j].Labels)
+	})
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
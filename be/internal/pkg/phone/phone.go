@@ -0,0 +1,122 @@
+// Package phone normalises phone numbers written in international or
+// national format to their international digits, as WhatsApp expects them.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is wrapped by every error Normalize returns
+var ErrInvalid = errors.New("invalid phone number")
+
+// Country describes how national numbers are written in a country
+type Country struct {
+	CallingCode    string   // e.g. "62"
+	TrunkPrefix    string   // Dialled before national numbers, e.g. "0" in 0812...
+	MobilePrefixes []string // Starts of national numbers also written without the trunk prefix, e.g. "8" in 812...
+
+	// Lengths of national numbers written without the trunk prefix. Longer
+	// or shorter numbers starting with a mobile prefix are read as
+	// international, e.g. 8613812345678 as a Chinese number.
+	MobileMinDigits int
+	MobileMaxDigits int
+}
+
+// Indonesia writes mobile numbers as 0812..., and often as 812... with 9 to
+// 12 digits. Numbers of that length starting with 8 are therefore Indonesian
+// even where they could be read as starting with the calling code of Japan
+// (81), Korea (82), Vietnam (84) or China (86); write those with + or 00.
+var Indonesia = Country{
+	CallingCode:     "62",
+	TrunkPrefix:     "0",
+	MobilePrefixes:  []string{"8"},
+	MobileMinDigits: 9,
+	MobileMaxDigits: 12,
+}
+
+// DefaultCountry is assumed for numbers written in national format
+var DefaultCountry = Indonesia
+
+// International numbers have 8 to 15 digits including the calling code
+const (
+	minDigits = 8
+	maxDigits = 15
+)
+
+// Normalize converts a number to its international digits without the plus,
+// assuming DefaultCountry for numbers in national format
+func Normalize(number string) (string, error) {
+	return DefaultCountry.Normalize(number)
+}
+
+// Normalize converts a number to its international digits without the plus.
+// Spaces, dashes, dots and brackets are ignored. Numbers starting with + or
+// 00 are international, and a trunk prefix written after the country's own
+// calling code is dropped, as in +62 0812...; numbers starting with the trunk
+// prefix, or with a mobile prefix and of national length, are national
+// numbers of the country; anything else must already start with a calling code.
+func (c Country) Normalize(number string) (string, error) {
+	number = strings.TrimSpace(number)
+
+	international := false
+	switch {
+	case strings.HasPrefix(number, "+"):
+		international = true
+		number = number[1:]
+	case strings.HasPrefix(number, "00"):
+		international = true
+		number = number[2:]
+	}
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("%w: %q may only contain digits", ErrInvalid, number)
+		}
+	}
+	normalized := digits.String()
+
+	if international {
+		normalized = c.dropTrunkPrefix(normalized)
+	} else {
+		normalized = c.toInternational(normalized)
+	}
+
+	if strings.HasPrefix(normalized, "0") {
+		return "", fmt.Errorf("%w: %s has no country code", ErrInvalid, normalized)
+	}
+	if len(normalized) < minDigits || len(normalized) > maxDigits {
+		return "", fmt.Errorf("%w: must be %d to %d digits with the country code", ErrInvalid, minDigits, maxDigits)
+	}
+	return normalized, nil
+}
+
+// toInternational prefixes a national number with the calling code
+func (c Country) toInternational(digits string) string {
+	if c.TrunkPrefix != "" && strings.HasPrefix(digits, c.TrunkPrefix) {
+		return c.CallingCode + strings.TrimPrefix(digits, c.TrunkPrefix)
+	}
+	if len(digits) < c.MobileMinDigits || (c.MobileMaxDigits > 0 && len(digits) > c.MobileMaxDigits) {
+		return c.dropTrunkPrefix(digits)
+	}
+	for _, prefix := range c.MobilePrefixes {
+		if strings.HasPrefix(digits, prefix) {
+			return c.CallingCode + digits
+		}
+	}
+	return c.dropTrunkPrefix(digits)
+}
+
+// dropTrunkPrefix removes a trunk prefix written after the country's calling code
+func (c Country) dropTrunkPrefix(digits string) string {
+	if c.TrunkPrefix == "" || !strings.HasPrefix(digits, c.CallingCode+c.TrunkPrefix) {
+		return digits
+	}
+	return c.CallingCode + digits[len(c.CallingCode)+len(c.TrunkPrefix):]
+}
@@ -0,0 +1,58 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		want    string
+		wantErr bool
+	}{
+		{name: "international with plus", number: "+62 812-3456-7890", want: "6281234567890"},
+		{name: "international with 00", number: "0062 812 3456 7890", want: "6281234567890"},
+		{name: "calling code without plus", number: "6281234567890", want: "6281234567890"},
+		{name: "trunk prefix", number: "0812-3456-7890", want: "6281234567890"},
+		{name: "brackets and dots", number: "(0812) 3456.7890", want: "6281234567890"},
+		{name: "mobile prefix", number: "81234567890", want: "6281234567890"},
+		{name: "shortest mobile number", number: "812345678", want: "62812345678"},
+		{name: "longest mobile number", number: "812345678901", want: "62812345678901"},
+		{name: "trunk prefix after plus and calling code", number: "+62 0812 3456 7890", want: "6281234567890"},
+		{name: "trunk prefix after calling code", number: "6208123456789", want: "628123456789"},
+		{name: "foreign number with plus", number: "+81 90 1234 5678", want: "819012345678"},
+		{name: "trunk prefix kept for other countries", number: "+44 020 7946 0958", want: "4402079460958"},
+		{name: "bare Chinese number", number: "8613812345678", want: "8613812345678"},
+		{name: "bare number starting with 8 of national length", number: "819012345678", want: "62819012345678"},
+		{name: "surrounding spaces", number: "  +6281234567890 ", want: "6281234567890"},
+		{name: "letters", number: "0812abc", wantErr: true},
+		{name: "too short", number: "+6281", wantErr: true},
+		{name: "too long", number: "+6281234567890123", wantErr: true},
+		{name: "trunk prefix only", number: "0", wantErr: true},
+		{name: "no calling code", number: "+0812345678", wantErr: true},
+		{name: "empty", number: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.number)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Normalize(%q) = %q, want error", tt.number, got)
+				}
+				if !errors.Is(err, ErrInvalid) {
+					t.Errorf("Normalize(%q) error %v does not wrap ErrInvalid", tt.number, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize(%q) unexpected error: %v", tt.number, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
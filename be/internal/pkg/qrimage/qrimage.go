@@ -0,0 +1,54 @@
+// Package qrimage renders QR codes as PNG, SVG and terminal text.
+package qrimage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// DefaultSize is the default width and height of PNG images in pixels
+const DefaultSize = 256
+
+// PNG renders content as a square PNG image of size pixels
+func PNG(content string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// SVG renders content as an SVG image that scales to any size
+func SVG(content string) (string, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	bitmap := code.Bitmap()
+
+	// One unit per module, dark modules as a single path
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	size := len(bitmap)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		size, size, size, size, path.String()), nil
+}
+
+// Terminal renders content as block characters, two modules per line, for
+// terminals with light text on a dark background
+func Terminal(content string) (string, error) {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	return code.ToSmallString(false), nil
+}
@@ -0,0 +1,115 @@
+// Package secretbox encrypts data with a key derived from a passphrase, using
+// scrypt for the key and AES-256-GCM for the data. Sealed data carries its own
+// salt and nonce, so the passphrase is all that is needed to open it.
+package secretbox
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// MinPassphraseLength is the shortest passphrase Seal accepts
+const MinPassphraseLength = 12
+
+const (
+	magic    = "WASB"
+	version  = 1
+	saltSize = 16
+	keySize  = 32
+
+	// scrypt parameters recommended for interactive use
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// headerSize is the size of magic, version and salt
+const headerSize = len(magic) + 1 + saltSize
+
+var (
+	// ErrPassphraseTooShort is returned by Seal for passphrases under MinPassphraseLength
+	ErrPassphraseTooShort = fmt.Errorf("passphrase must be at least %d characters", MinPassphraseLength)
+
+	// ErrInvalidFormat is returned by Open for data that was not produced by Seal
+	ErrInvalidFormat = errors.New("data is not a sealed archive")
+
+	// ErrDecrypt is returned by Open for a wrong passphrase or tampered data
+	ErrDecrypt = errors.New("wrong passphrase or corrupted data")
+)
+
+// Seal encrypts plaintext with a key derived from passphrase
+func Seal(plaintext []byte, passphrase string) ([]byte, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, ErrPassphraseTooShort
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	header[len(magic)] = version
+	salt := header[len(magic)+1:]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The header is authenticated so that it cannot be swapped
+	sealed := append(header, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, header), nil
+}
+
+// Open decrypts data produced by Seal
+func Open(sealed []byte, passphrase string) ([]byte, error) {
+	if len(sealed) < headerSize || !bytes.Equal(sealed[:len(magic)], []byte(magic)) {
+		return nil, ErrInvalidFormat
+	}
+	if sealed[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported archive version %d", sealed[len(magic)])
+	}
+
+	header := sealed[:headerSize]
+	aead, err := newAEAD(passphrase, header[len(magic)+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	rest := sealed[headerSize:]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidFormat
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// newAEAD derives the key for a passphrase and salt
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,86 @@
+package secretbox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+const testPassphrase = "correct horse battery"
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte("session archive")
+
+	sealed, err := Seal(plaintext, testPassphrase)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Error("sealed data contains the plaintext")
+	}
+
+	again, err := Seal(plaintext, testPassphrase)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Error("sealing twice produced the same output")
+	}
+
+	opened, err := Open(sealed, testPassphrase)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open() = %q, want %q", opened, plaintext)
+	}
+}
+
+func TestSealRejectsShortPassphrase(t *testing.T) {
+	if _, err := Seal([]byte("data"), "short"); !errors.Is(err, ErrPassphraseTooShort) {
+		t.Errorf("Seal() error = %v, want ErrPassphraseTooShort", err)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	sealed, err := Seal([]byte("session archive"), testPassphrase)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	tamper := func(i int) []byte {
+		data := bytes.Clone(sealed)
+		data[i] ^= 0xff
+		return data
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		want       error
+	}{
+		{name: "wrong passphrase", data: sealed, passphrase: "incorrect horse battery", want: ErrDecrypt},
+		{name: "tampered ciphertext", data: tamper(len(sealed) - 1), passphrase: testPassphrase, want: ErrDecrypt},
+		{name: "tampered salt", data: tamper(headerSize - 1), passphrase: testPassphrase, want: ErrDecrypt},
+		{name: "empty", data: nil, passphrase: testPassphrase, want: ErrInvalidFormat},
+		{name: "wrong magic", data: tamper(0), passphrase: testPassphrase, want: ErrInvalidFormat},
+		{name: "header only", data: sealed[:headerSize], passphrase: testPassphrase, want: ErrInvalidFormat},
+		{name: "truncated", data: sealed[:headerSize+20], passphrase: testPassphrase, want: ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Open(tt.data, tt.passphrase); !errors.Is(err, tt.want) {
+				t.Errorf("Open() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported version", func(t *testing.T) {
+		data := bytes.Clone(sealed)
+		data[len(magic)] = version + 1
+		if _, err := Open(data, testPassphrase); err == nil {
+			t.Error("Open() accepted an unknown version")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -83,7 +84,7 @@ func validateWhatsAppJID(fl validator.FieldLevel) bool {
 	// Group: 1234567890-1234567890@g.us
 	patterns := []string{
 		`^\d+@s\.whatsapp\.net$`,
-		`^\d+-\d+@g\.us$`,
+		`^\d+(-\d+)?@g\.us$`,
 	}
 
 	for _, pattern := range patterns {
@@ -170,7 +171,7 @@ func GetPaginationParams(page, limit int) (int, int, error) {
 func ValidateWhatsAppJID(jid string) bool {
 	patterns := []string{
 		`^\d+@s\.whatsapp\.net$`,
-		`^\d+-\d+@g\.us$`,
+		`^\d+(-\d+)?@g\.us$`,
 	}
 
 	for _, pattern := range patterns {
@@ -191,3 +192,27 @@ func ValidateDeviceName(name string) bool {
 	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_-]+$`, name)
 	return matched
 }
+
+// ValidateProxyURL validates a proxy URL of the form scheme://[user:pass@]host:port,
+// where scheme is http, https or socks5
+func ValidateProxyURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("proxy URL is malformed")
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("proxy URL scheme must be http, https or socks5")
+	}
+
+	if parsed.Hostname() == "" || parsed.Port() == "" {
+		return fmt.Errorf("proxy URL must include a host and port")
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("proxy URL must not have a path, query or fragment")
+	}
+
+	return nil
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	r, err := setup()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	application, err := setup(ctx)
 	if err != nil {
 		log.Fatalf("❌ Setup failed: %v", err)
 	}
@@ -16,13 +24,43 @@ func main() {
 		log.Fatal("❌ JWT_SECRET not set in .env")
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	cfg := application.container.Config.Server
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: application.router,
 	}
+	// Event streams last until the client leaves, so end them when shutting down
+	srv.RegisterOnShutdown(application.container.EndStreams)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("🚀 Server running on :%s", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
 
-	log.Printf("🚀 Server running on :%s", port)
-	if err := r.Run(":" + port); err != nil {
+	select {
+	case err := <-serverErr:
 		log.Fatalf("❌ Failed to run server: %v", err)
+	case <-ctx.Done():
 	}
+	stop()
+
+	log.Printf("🛑 Shutting down (timeout %s per phase)", cfg.ShutdownTimeout)
+
+	// Stop accepting requests and wait for those in flight, including sends
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	if err := srv.Shutdown(httpCtx); err != nil {
+		log.Printf("⚠️  HTTP server did not stop cleanly: %v", err)
+	}
+	cancelHTTP()
+
+	// Drain message processing, disconnect the clients and close MongoDB. This
+	// phase gets its own deadline so a slow HTTP phase cannot leave leases held.
+	appCtx, cancelApp := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelApp()
+	application.shutdown(appCtx)
+
+	log.Println("👋 Server stopped")
 }
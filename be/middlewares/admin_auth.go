@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// AdminAuthMiddleware creates a middleware that requires the admin token as
+// a bearer token. An empty token refuses every request, so admin routes are
+// closed unless an admin token is configured.
+func AdminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			handleError(c, errors.NewForbiddenError("Admin routes are disabled; set ADMIN_TOKEN to enable them"))
+			return
+		}
+
+		presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			handleError(c, errors.NewUnauthorizedError("Invalid admin token"))
+			return
+		}
+
+		c.Next()
+	}
+}
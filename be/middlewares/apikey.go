@@ -23,7 +23,7 @@ func APIKeyMiddleware(validateUC *apikey.ValidateKeyUseCase) gin.HandlerFunc {
 		apiKeyHeader := c.GetHeader(APIKeyHeader)
 		if apiKeyHeader == "" {
 			// No API key provided - reject request
-			handleError(c, errors.New(errors.ErrTypeUnauthorized, "API key is required"))
+			handleError(c, errors.New(errors.ErrorTypeUnauthorized, "API key is required"))
 			return
 		}
 
@@ -72,7 +72,7 @@ func APIKeyOrJWTMiddleware(validateUC *apikey.ValidateKeyUseCase) gin.HandlerFun
 		}
 
 		// No authentication provided
-		handleError(c, errors.New(errors.ErrTypeUnauthorized, "authentication required: provide either X-API-Key or Authorization header"))
+		handleError(c, errors.New(errors.ErrorTypeUnauthorized, "authentication required: provide either X-API-Key or Authorization header"))
 	}
 }
 
@@ -82,7 +82,7 @@ func APIKeyWithPermissionMiddleware(validateUC *apikey.ValidateKeyUseCase, resou
 		// Check for API key in header
 		apiKeyHeader := c.GetHeader(APIKeyHeader)
 		if apiKeyHeader == "" {
-			handleError(c, errors.New(errors.ErrTypeUnauthorized, "API key is required"))
+			handleError(c, errors.New(errors.ErrorTypeUnauthorized, "API key is required"))
 			return
 		}
 
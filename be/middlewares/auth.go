@@ -32,6 +32,9 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
 			c.Set("username", claims["username"])
+			if organization, ok := claims["organization"].(string); ok && organization != "" {
+				c.Set(ContextKeyOrganization, organization)
+			}
 		}
 
 		c.Next()
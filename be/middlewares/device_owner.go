@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+)
+
+const (
+	// ContextKeyDevice is the context key for storing the authorized device
+	ContextKeyDevice = "device"
+
+	// ContextKeyOrganization is the context key for storing the caller's organisation
+	ContextKeyOrganization = "organization"
+)
+
+// DeviceOwnershipMiddleware creates a middleware that only lets the owner of the
+// device named by the :device parameter, or the owner's organisation, through.
+// It must run after an authentication middleware has set the username.
+func DeviceOwnershipMiddleware(authorizeUC *device.AuthorizeDeviceUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorized, err := authorizeUC.Execute(c.Request.Context(), c.Param("device"), GetCallerFromContext(c))
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+
+		// Store the device for handlers that need its record
+		c.Set(ContextKeyDevice, authorized)
+
+		c.Next()
+	}
+}
+
+// GetCallerFromContext returns the caller identified by the authentication middlewares
+func GetCallerFromContext(c *gin.Context) domain.Caller {
+	username, _ := c.Get("username")
+	organization, _ := c.Get(ContextKeyOrganization)
+
+	caller := domain.Caller{}
+	caller.Username, _ = username.(string)
+	caller.Organization, _ = organization.(string)
+	return caller
+}
+
+// GetDeviceFromContext retrieves the device authorized by DeviceOwnershipMiddleware
+func GetDeviceFromContext(c *gin.Context) (*domain.Device, bool) {
+	value, exists := c.Get(ContextKeyDevice)
+	if !exists {
+		return nil, false
+	}
+	authorized, ok := value.(*domain.Device)
+	return authorized, ok
+}
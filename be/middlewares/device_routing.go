@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/ports"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/logger"
+)
+
+// HeaderForwardedBy names the replica that forwarded a request. A forwarded
+// request is never forwarded again, so that replicas disagreeing about a lease
+// cannot bounce it between them.
+const HeaderForwardedBy = "X-Forwarded-By"
+
+// DeviceRoutingMiddleware creates a middleware that sends requests for the
+// device named by the :device parameter to the replica holding its lease. The
+// request is proxied to the holder's address, credentials included, and the
+// holder authorizes it again. Devices loaded here or without a live lease
+// elsewhere are handled locally.
+func DeviceRoutingMiddleware(leases ports.LeaseRepository, manager domain.WhatsAppManagerInterface, replicaID string) gin.HandlerFunc {
+	log := logger.New("DeviceRouting").WithField("replica", replicaID)
+
+	return func(c *gin.Context) {
+		deviceName := c.Param("device")
+		if _, loaded := manager.GetClient(deviceName); loaded {
+			c.Next()
+			return
+		}
+
+		lease, err := leases.Find(c.Request.Context(), deviceName)
+		if err != nil {
+			if errors.GetAppError(err).Type != errors.ErrorTypeNotFound {
+				log.WithField("device", deviceName).Warn("Failed to look up lease: %v", err)
+			}
+			c.Next()
+			return
+		}
+		if lease.Holder == replicaID || !lease.IsLive(time.Now()) {
+			c.Next()
+			return
+		}
+
+		target, err := url.Parse(lease.Address)
+		if lease.Address == "" || err != nil || c.GetHeader(HeaderForwardedBy) != "" {
+			// The holder cannot be reached from here; the lease moves or is
+			// renewed within its TTL
+			retryAfter := int(time.Until(lease.ExpiresAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			handleError(c, errors.New(errors.ErrorTypeConnection, "Device is running on another replica").
+				WithDetails("replica", lease.Holder))
+			return
+		}
+
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.FlushInterval = -1 // Pass event streams through as they are written
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			log.WithField("device", deviceName).Warn("Failed to forward request to %s: %v", lease.Holder, err)
+			handleError(c, errors.NewConnectionError("Replica running the device is unreachable", err).
+				WithDetails("replica", lease.Holder))
+		}
+
+		c.Request.Header.Set(HeaderForwardedBy, replicaID)
+		proxy.ServeHTTP(c.Writer, c.Request)
+		c.Abort()
+	}
+}
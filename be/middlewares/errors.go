@@ -0,0 +1,12 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// handleError aborts the request with an application error as a JSON response using its mapped HTTP status
+func handleError(c *gin.Context, err error) {
+	appErr := errors.GetAppError(err)
+	c.AbortWithStatusJSON(appErr.StatusCode, gin.H{"error": appErr})
+}
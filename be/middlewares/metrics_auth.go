@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+)
+
+// MetricsAuthMiddleware creates a middleware that requires the metrics token
+// as a bearer token, as Prometheus sends it with its authorization setting.
+// An empty token refuses every request, so the metrics stay closed unless a
+// metrics token is configured.
+func MetricsAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			handleError(c, errors.NewForbiddenError("Metrics routes are disabled; set METRICS_TOKEN to enable them"))
+			return
+		}
+
+		presented := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			handleError(c, errors.NewUnauthorizedError("Invalid metrics token"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/ubaidillahfaris/whatsapp.git/db"
 	"github.com/ubaidillahfaris/whatsapp.git/handlers"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/repositories"
 	"github.com/ubaidillahfaris/whatsapp.git/internal/app"
+	deviceUsecase "github.com/ubaidillahfaris/whatsapp.git/internal/core/usecases/device"
+	qrDomain "github.com/ubaidillahfaris/whatsapp.git/internal/modules/quickresponse/domain"
 	"github.com/ubaidillahfaris/whatsapp.git/middlewares"
 	"github.com/ubaidillahfaris/whatsapp.git/services"
 )
@@ -81,11 +84,42 @@ func RegisterRoutes(r *gin.Engine, mongo *db.MongoService, manager *services.Wha
 	})
 
 	// Device routes
-	deviceHandler := handlers.NewDeviceHandler(mongo)
-
 	device := r.Group("/devices")
 	device.Use(middlewares.JWTAuthMiddleware())
-	{
+	if appContainer, ok := container.(*app.Container); ok {
+		// The device registry owns the devices' WhatsApp clients and session stores
+		registryHandler := handlers.NewDeviceRegistryHandler(
+			appContainer.CreateDeviceUC,
+			appContainer.GetDeviceUC,
+			appContainer.ListDevicesUC,
+			appContainer.UpdateDeviceUC,
+			appContainer.DeleteDeviceUC,
+			appContainer.ReconcileDevicesUC,
+			appContainer.BackupDeviceUC,
+			appContainer.RestoreDeviceUC,
+		)
+
+		device.POST("", registryHandler.CreateDevice)
+		device.GET("", registryHandler.ListDevices)
+		device.GET("/:id", registryHandler.GetDevice)
+		device.PUT("/:id", registryHandler.UpdateDevice)
+		device.DELETE("/:id", registryHandler.DeleteDevice)
+		device.GET("/:id/backup", registryHandler.Backup)
+		device.POST("/:id/transfer", registryHandler.Transfer)
+		device.POST("/:id/restore", registryHandler.Restore)
+
+		// Organisation assignment and reconciling every tenant's devices (admin token only)
+		adminHandler := handlers.NewAdminHandler(mongo, appContainer.DeviceRepository)
+		admin := r.Group("/admin")
+		admin.Use(middlewares.AdminAuthMiddleware(appContainer.Config.Server.AdminToken))
+		{
+			admin.PUT("/users/:username/organization", adminHandler.SetUserOrganization)
+			admin.PUT("/devices/:name/organization", adminHandler.SetDeviceOrganization)
+			admin.POST("/devices/reconcile", registryHandler.Reconcile)
+		}
+	} else {
+		deviceHandler := handlers.NewDeviceHandler(mongo)
+
 		device.POST("", deviceHandler.CreateDevice)
 		device.GET("", deviceHandler.ListDevices)
 		device.GET(":id", deviceHandler.GetDevice)
@@ -93,31 +127,55 @@ func RegisterRoutes(r *gin.Engine, mongo *db.MongoService, manager *services.Wha
 		device.DELETE(":id", deviceHandler.DeleteDevice)
 	}
 
-	// WhatsApp routes
-	whatsapp := handlers.NewWhatsAppHandler()
+	// WhatsApp and send message routes act only on registered devices owned by
+	// the caller or the caller's organisation
+	deviceAuth := []gin.HandlerFunc{middlewares.JWTAuthMiddleware()}
+	var authorizeDevice *deviceUsecase.AuthorizeDeviceUseCase
+	if appContainer, ok := container.(*app.Container); ok {
+		deviceAuth = []gin.HandlerFunc{middlewares.APIKeyOrJWTMiddleware(appContainer.ValidateAPIKeyUC)}
+		authorizeDevice = appContainer.AuthorizeDeviceUC
+	} else {
+		authorizeDevice = deviceUsecase.NewAuthorizeDeviceUseCase(repositories.NewDeviceMongoRepository(mongo.Database))
+	}
+	deviceAuth = append(deviceAuth, middlewares.DeviceOwnershipMiddleware(authorizeDevice))
+	if appContainer, ok := container.(*app.Container); ok && appContainer.LeaseRepository != nil {
+		// Devices run on the replica holding their lease
+		deviceAuth = append(deviceAuth, middlewares.DeviceRoutingMiddleware(
+			appContainer.LeaseRepository, appContainer.WhatsAppManager, appContainer.Config.WhatsApp.ReplicaID))
+	}
+
+	// WhatsApp and send message routes. With the container its manager serves
+	// them (registered below); the legacy manager is only built without it, so
+	// a device never has two clients on the same session.
 	wa := r.Group("/whatsapp")
-	{
+	wa.Use(deviceAuth...)
+	msg := r.Group("/send_message")
+	msg.Use(deviceAuth...)
+	if _, ok := container.(*app.Container); !ok {
+		if manager == nil {
+			manager = services.GetWhatsAppManager()
+		}
+		whatsapp := handlers.NewWhatsAppHandler(manager)
 		wa.GET("/:device/qrcode", whatsapp.GenerateQR)
 		wa.GET("/:device/disconnect", whatsapp.Disconnect)
 		wa.GET("/:device/contacts", whatsapp.ListContacts)
 		wa.GET("/:device/groups", whatsapp.ListGroups)
+		msg.POST("/:device", whatsapp.SendMessage)
 	}
 
-	// Quick Response routes
-	qrHandler := handlers.NewQuickResponseHandler()
+	// Quick Response routes; with the container, reports of both schemas are
+	// listed in the legacy shape
+	var qrRepository qrDomain.QuickResponseRepository
+	if appContainer, ok := container.(*app.Container); ok {
+		qrRepository = appContainer.QRRepository
+	}
+	qrHandler := handlers.NewQuickResponseHandler(qrRepository)
 	qr := r.Group("/quick_response")
 	{
 		qr.GET("/", qrHandler.GetAll)
 		qr.DELETE("/:id", qrHandler.DeleteId)
 	}
 
-	// Send Message routes
-
-	msg := r.Group("/send_message")
-	{
-		msg.POST("/:device", whatsapp.SendMessage)
-	}
-
 	// API Key routes (JWT protected for management)
 	// Only register if container is provided (new architecture)
 	if container != nil {
@@ -140,6 +198,172 @@ func RegisterRoutes(r *gin.Engine, mongo *db.MongoService, manager *services.Wha
 				apiKeyGroup.PUT("/:id", apiKeyHandler.UpdateKey)      // Update API key
 				apiKeyGroup.DELETE("/:id", apiKeyHandler.RevokeKey)   // Revoke (delete) API key
 			}
+
+			// Phone-number pairing, connection state and device event stream (device owner only)
+			pairingHandler := handlers.NewDevicePairingHandler(appContainer.WhatsAppService, appContainer.WhatsAppEventHandler, appContainer.Streams)
+			wa.POST("/:device/pair-phone", pairingHandler.PairPhone)
+			wa.GET("/:device/events", pairingHandler.StreamEvents)
+			wa.GET("/:device/connection", pairingHandler.GetConnection)
+			wa.GET("/:device/disconnect", pairingHandler.Disconnect)
+
+			// QR pairing sessions with refreshing codes (device owner only)
+			qrPairingHandler := handlers.NewQRPairingHandler(appContainer.QRPairingUC, appContainer.Streams)
+			wa.GET("/:device/pairing", qrPairingHandler.GetPairing)
+			wa.GET("/:device/pairing/qr", qrPairingHandler.GetQRCode)
+			wa.GET("/:device/pairing/qr/stream", qrPairingHandler.StreamQRCode)
+			wa.GET("/:device/qrcode", qrPairingHandler.GetLegacyQRCode)
+
+			// Sending messages (device owner only)
+			messageHandler := handlers.NewMessageHandler(appContainer.WhatsAppService)
+			msg.POST("/:device", messageHandler.SendMessage)
+
+			// Device traffic and health metrics: JSON per device (device owner only), and
+			// JSON for every device and Prometheus format (metrics token)
+			metricsHandler := handlers.NewMetricsHandler(appContainer.Metrics)
+			wa.GET("/:device/metrics", metricsHandler.GetDeviceMetrics)
+			metrics := r.Group("/metrics")
+			metrics.Use(middlewares.MetricsAuthMiddleware(appContainer.Config.Server.MetricsToken))
+			{
+				metrics.GET("", metricsHandler.Prometheus)
+				metrics.GET("/devices", metricsHandler.ListDeviceMetrics)
+			}
+
+			// Persisted session, live status and uptime (device owner only)
+			sessionHandler := handlers.NewSessionHandler(appContainer.SessionStatusUC)
+			wa.GET("/:device/status", sessionHandler.GetStatus)
+			wa.GET("/:device/uptime", sessionHandler.GetUptime)
+
+			// Device profile and contact/group profile pictures (device owner only)
+			profileHandler := handlers.NewProfileHandler(
+				appContainer.GetProfileUC,
+				appContainer.UpdateProfileUC,
+				appContainer.SetProfilePhotoUC,
+				appContainer.ProfilePictureUC,
+			)
+			wa.GET("/:device/profile", profileHandler.GetProfile)
+			wa.PUT("/:device/profile", profileHandler.UpdateProfile)
+			wa.PUT("/:device/profile/photo", profileHandler.SetPhoto)
+			wa.GET("/:device/profile-pictures/:jid", profileHandler.GetProfilePicture)
+			wa.GET("/:device/profile-pictures/:jid/image", profileHandler.GetProfilePictureImage)
+
+			// Device presence and followed contacts' online status (device owner only)
+			presenceHandler := handlers.NewPresenceHandler(appContainer.WhatsAppService, appContainer.FollowPresenceUC, appContainer.GetPresenceUC)
+			wa.PUT("/:device/presence", presenceHandler.SetPresence)
+			wa.POST("/:device/presence/:jid", presenceHandler.FollowPresence)
+			wa.GET("/:device/presence/:jid", presenceHandler.GetPresence)
+			wa.DELETE("/:device/presence/:jid", presenceHandler.UnfollowPresence)
+
+			// Contact directory with tags and notes, synced from the device (device owner only)
+			contactHandler := handlers.NewContactHandler(appContainer.ContactDirectoryUC, appContainer.SyncContactsUC)
+			wa.GET("/:device/contacts", contactHandler.ListContacts)
+			wa.GET("/:device/contacts/tags", contactHandler.ListTags)
+			wa.POST("/:device/contacts/sync", contactHandler.SyncContacts)
+			wa.GET("/:device/contacts/:jid", contactHandler.GetContact)
+			wa.PUT("/:device/contacts/:jid", contactHandler.UpdateContact)
+
+			// Check which phone numbers are on WhatsApp (device owner only)
+			numberHandler := handlers.NewNumberHandler(appContainer.CheckNumbersUC)
+			wa.POST("/:device/numbers/check", numberHandler.CheckNumbers)
+
+			// Broadcast campaigns and saved contact segments (device owner only)
+			campaignHandler := handlers.NewCampaignHandler(appContainer.CampaignService)
+			wa.POST("/:device/campaigns", campaignHandler.CreateCampaign)
+			wa.GET("/:device/campaigns", campaignHandler.ListCampaigns)
+			wa.GET("/:device/campaigns/:id", campaignHandler.GetCampaign)
+			wa.DELETE("/:device/campaigns/:id", campaignHandler.DeleteCampaign)
+			wa.POST("/:device/campaigns/:id/recipients", campaignHandler.AddRecipients)
+			wa.POST("/:device/campaigns/:id/recipients/csv", campaignHandler.ImportRecipients)
+			wa.GET("/:device/campaigns/:id/recipients", campaignHandler.ListRecipients)
+			wa.POST("/:device/campaigns/:id/start", campaignHandler.StartCampaign)
+			wa.POST("/:device/campaigns/:id/pause", campaignHandler.PauseCampaign)
+			wa.POST("/:device/campaigns/:id/resume", campaignHandler.ResumeCampaign)
+			wa.POST("/:device/campaigns/:id/cancel", campaignHandler.CancelCampaign)
+
+			segmentHandler := handlers.NewSegmentHandler(appContainer.SegmentRepository)
+			wa.GET("/:device/segments", segmentHandler.ListSegments)
+			wa.POST("/:device/segments", segmentHandler.CreateSegment)
+			wa.PUT("/:device/segments/:id", segmentHandler.UpdateSegment)
+			wa.DELETE("/:device/segments/:id", segmentHandler.DeleteSegment)
+
+			// Group listing from the group cache, and group administration (device owner only)
+			groupHandler := handlers.NewGroupHandler(appContainer.WhatsAppService)
+			wa.GET("/:device/groups", groupHandler.ListGroups)
+			wa.POST("/:device/groups", groupHandler.CreateGroup)
+			wa.POST("/:device/groups/join", groupHandler.JoinGroup)
+			wa.GET("/:device/groups/:group", groupHandler.GetGroup)
+			wa.PUT("/:device/groups/:group", groupHandler.UpdateGroup)
+			wa.POST("/:device/groups/:group/participants", groupHandler.UpdateParticipants)
+			wa.PUT("/:device/groups/:group/photo", groupHandler.SetPhoto)
+			wa.GET("/:device/groups/:group/invite-link", groupHandler.GetInviteLink)
+			wa.POST("/:device/groups/:group/invite-link/reset", groupHandler.ResetInviteLink)
+			wa.POST("/:device/groups/:group/leave", groupHandler.LeaveGroup)
+
+			// Quick Response report history
+			qrRevisionHandler := handlers.NewQuickResponseRevisionHandler(appContainer.QRRepository)
+			qr.GET("/:id/revisions", middlewares.JWTAuthMiddleware(), qrRevisionHandler.GetRevisions)
+
+			// Quick Response digest schedules (requires JWT authentication)
+			digestHandler := handlers.NewDigestScheduleHandler(appContainer.DigestScheduleRepository, appContainer.DigestScheduler, appContainer.AuthorizeDeviceUC)
+			digests := qr.Group("/digests")
+			digests.Use(middlewares.JWTAuthMiddleware())
+			{
+				digests.POST("", digestHandler.CreateSchedule)
+				digests.GET("", digestHandler.ListSchedules)
+				digests.GET("/:id", digestHandler.GetSchedule)
+				digests.PUT("/:id", digestHandler.UpdateSchedule)
+				digests.DELETE("/:id", digestHandler.DeleteSchedule)
+				digests.POST("/:id/run", digestHandler.RunSchedule)
+			}
+
+			// Officer roster (requires JWT authentication)
+			officerHandler := handlers.NewOfficerHandler(appContainer.OfficerRepository)
+			officers := qr.Group("/officers")
+			officers.Use(middlewares.JWTAuthMiddleware())
+			{
+				officers.POST("", officerHandler.CreateOfficer)
+				officers.GET("", officerHandler.ListOfficers)
+				officers.GET("/:id", officerHandler.GetOfficer)
+				officers.PUT("/:id", officerHandler.UpdateOfficer)
+				officers.DELETE("/:id", officerHandler.DeleteOfficer)
+			}
+
+			// Missing-report reminders (requires JWT authentication)
+			reminderHandler := handlers.NewReminderHandler(appContainer.ReminderRepository, appContainer.ReminderService, appContainer.AuthorizeDeviceUC)
+			reminders := qr.Group("/reminders")
+			reminders.Use(middlewares.JWTAuthMiddleware())
+			{
+				reminders.GET("/config", reminderHandler.GetConfig)
+				reminders.PUT("/config", reminderHandler.UpdateConfig)
+				reminders.GET("/logs", reminderHandler.ListLogs)
+				reminders.POST("/run", reminderHandler.RunReminders)
+			}
+
+			// Legacy to schema version 2 migration (requires JWT authentication)
+			migrationHandler := handlers.NewQuickResponseMigrationHandler(appContainer.QRMigrator)
+			migrations := qr.Group("/migrations")
+			migrations.Use(middlewares.JWTAuthMiddleware())
+			{
+				migrations.GET("/v2", migrationHandler.GetStatus)
+				migrations.POST("/v2", migrationHandler.Migrate)
+			}
+
+			// Backfill from exported WhatsApp chats (requires JWT authentication)
+			chatImportHandler := handlers.NewChatImportHandler(appContainer.ImportChatUC)
+			qr.POST("/import", middlewares.JWTAuthMiddleware(), chatImportHandler.ImportChat)
+
+			// Scheduled and recurring messages; each schedule is only visible to the owners of its device
+			scheduleHandler := handlers.NewScheduledMessageHandler(appContainer.ScheduleService, appContainer.AuthorizeDeviceUC)
+			scheduled := r.Group("/scheduled-messages")
+			scheduled.Use(middlewares.APIKeyOrJWTMiddleware(appContainer.ValidateAPIKeyUC))
+			{
+				scheduled.POST("", scheduleHandler.CreateScheduledMessage)
+				scheduled.GET("", scheduleHandler.ListScheduledMessages)
+				scheduled.GET("/:id", scheduleHandler.GetScheduledMessage)
+				scheduled.GET("/:id/runs", scheduleHandler.ListRuns)
+				scheduled.POST("/:id/pause", scheduleHandler.PauseScheduledMessage)
+				scheduled.POST("/:id/resume", scheduleHandler.ResumeScheduledMessage)
+				scheduled.POST("/:id/cancel", scheduleHandler.CancelScheduledMessage)
+			}
 		}
 	}
 
@@ -7,8 +7,12 @@ import (
 	"mime"
 	"net/http"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	apperrors "github.com/ubaidillahfaris/whatsapp.git/internal/pkg/errors"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/phone"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
@@ -32,6 +36,9 @@ func (s *MessageService) SendMessage(ctx context.Context, jidStr string, text st
 	client := s.Parent.Client
 	deviceName := s.Parent.DeviceName
 
+	s.Parent.work.Start()
+	defer s.Parent.work.Done()
+
 	// ✅ Cek login
 	if client.Store.ID == nil {
 		return fmt.Errorf("[%s] belum login, scan QR dulu", deviceName)
@@ -104,21 +111,33 @@ func (s *MessageService) SendMessage(ctx context.Context, jidStr string, text st
 	}
 }
 
+// markAvailable menandai device online sebelum kirim pesan, kecuali kebijakan
+// presence device berkata lain
+func (s *MessageService) markAvailable() {
+	switch s.Parent.PresencePolicy() {
+	case "", domain.PresenceOnSend:
+		_ = s.Parent.Client.SendPresence(types.PresenceAvailable)
+	}
+}
+
 func (s *MessageService) sendUserMessage(ctx context.Context, jidStr string, msg *waE2E.Message, typing bool) error {
 	client := s.Parent.Client
 	deviceName := s.Parent.DeviceName
 
-	targetJID := types.NewJID(jidStr, types.DefaultUserServer)
+	targetJID, err := s.resolveUserJID(jidStr)
+	if err != nil {
+		return err
+	}
 
 	// Kirim presence & efek typing
-	_ = client.SendPresence(types.PresenceAvailable)
+	s.markAvailable()
 	if typing {
 		_ = client.SendChatPresence(targetJID, types.ChatPresenceComposing, types.ChatPresenceMediaText)
 		time.Sleep(time.Duration(rand.Intn(1000)+700) * time.Millisecond)
 	}
 
 	// Kirim pesan
-	_, err := client.SendMessage(ctx, targetJID, msg)
+	_, err = client.SendMessage(ctx, targetJID, msg)
 	if err != nil {
 		return fmt.Errorf("[%s] gagal kirim pesan ke %s: %w", deviceName, jidStr, err)
 	}
@@ -126,6 +145,37 @@ func (s *MessageService) sendUserMessage(ctx context.Context, jidStr string, msg
 	fmt.Printf("✅ [%s] Message sent to user %s: %s\n", deviceName, jidStr, msg)
 	return nil
 }
+
+// resolveUserJID mengubah nomor (format internasional atau nasional) menjadi
+// JID yang terdaftar di WhatsApp. JID lengkap dipakai apa adanya.
+func (s *MessageService) resolveUserJID(jidStr string) (types.JID, error) {
+	if strings.Contains(jidStr, "@") {
+		jid, err := types.ParseJID(jidStr)
+		if err != nil {
+			return types.JID{}, apperrors.NewValidationError(fmt.Sprintf("JID tidak valid: %s", jidStr))
+		}
+		return jid, nil
+	}
+
+	number, err := phone.Normalize(jidStr)
+	if err != nil {
+		return types.JID{}, apperrors.NewValidationError(err.Error())
+	}
+
+	// Pastikan nomor terdaftar dan pakai JID kanonik dari WhatsApp
+	results, err := s.Parent.Client.IsOnWhatsApp([]string{"+" + number})
+	if err != nil {
+		return types.JID{}, fmt.Errorf("[%s] gagal cek nomor %s: %w", s.Parent.DeviceName, number, err)
+	}
+	for _, result := range results {
+		if result.IsIn {
+			return result.JID, nil
+		}
+	}
+	return types.JID{}, apperrors.NewValidationError(fmt.Sprintf("+%s is not registered on WhatsApp", number)).
+		WithDetails("phone", number)
+}
+
 func (s *MessageService) sendGroupMessage(ctx context.Context, jidStr string, msg *waE2E.Message, typing bool) error {
 	client := s.Parent.Client
 	deviceName := s.Parent.DeviceName
@@ -150,7 +200,7 @@ func (s *MessageService) sendGroupMessage(ctx context.Context, jidStr string, ms
 	}
 
 	// Presence & efek typing
-	_ = client.SendPresence(types.PresenceAvailable)
+	s.markAvailable()
 	if typing {
 		_ = client.SendChatPresence(groupJID, types.ChatPresenceComposing, types.ChatPresenceMediaText)
 		time.Sleep(time.Duration(rand.Intn(1000)+700) * time.Millisecond)
@@ -2,6 +2,8 @@ package services
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,7 +11,21 @@ import (
 	"github.com/ubaidillahfaris/whatsapp.git/models"
 )
 
+// LegacyQuickResponseIngestEnabled reports whether incoming messages are still stored
+// through this legacy path. Set QR_LEGACY_INGEST_ENABLED=false once the Quick Response
+// module handles ingestion and legacy documents have been migrated.
+func LegacyQuickResponseIngestEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("QR_LEGACY_INGEST_ENABLED"))
+	if err != nil {
+		return true
+	}
+	return enabled
+}
+
 func (w *WhatsAppService) HandleIncomingMessage(sender, message string) {
+	if !LegacyQuickResponseIngestEnabled() {
+		return
+	}
 
 	// Parsing / Mapping pesan ke struct Mongo
 	qr := parseMessageToQuickResponse(message, sender)
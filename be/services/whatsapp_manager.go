@@ -3,13 +3,16 @@ package services
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"sync"
+
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/config"
 )
 
 type WhatsAppManager struct {
 	instances map[string]*WhatsAppService
+	store     sessionstore.Backend
 	mu        sync.RWMutex
 }
 
@@ -23,24 +26,29 @@ func NewWhatsAppManager() *WhatsAppManager {
 		instances: make(map[string]*WhatsAppService),
 	}
 
-	storesDir := "./stores"
+	cfg, err := config.Load()
+	if err != nil {
+		panic("❌ gagal load config: " + err.Error())
+	}
 
-	if err := os.MkdirAll(storesDir, 0755); err != nil {
-		panic("❌ gagal buat folder stores: " + err.Error())
+	store, err := sessionstore.New(context.Background(), sessionstore.Config{
+		Backend:       cfg.WhatsApp.StoreBackend,
+		StoresDir:     cfg.WhatsApp.StoresDir,
+		DSN:           cfg.WhatsApp.StoreDSN,
+		MigrateSQLite: cfg.WhatsApp.StoreMigrateSQLite,
+	})
+	if err != nil {
+		panic("❌ gagal buka session store: " + err.Error())
 	}
+	m.store = store
 
-	files, err := os.ReadDir(storesDir)
+	deviceNames, err := store.List(context.Background())
 	if err != nil {
-		fmt.Println("⚠️ gagal membaca folder stores:", err)
+		fmt.Println("⚠️ gagal membaca session store:", err)
 		return m
 	}
 
-	for _, f := range files {
-		if !strings.HasSuffix(f.Name(), "_store.db") {
-			continue
-		}
-
-		deviceName := strings.TrimSuffix(f.Name(), "_store.db")
+	for _, deviceName := range deviceNames {
 		ctx := context.Background()
 
 		svc, err := m.NewWhatsAppService(ctx, deviceName)
@@ -101,3 +109,32 @@ func (m *WhatsAppManager) ListDevices() []string {
 	}
 	return devices
 }
+
+// Shutdown menunggu semua device menyelesaikan pesan dan pengiriman yang
+// tertunda, paling lama sampai ctx berakhir, lalu memutus semua koneksi dan
+// menutup session store
+func (m *WhatsAppManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []string
+	for name, svc := range m.instances {
+		if err := svc.Drain(ctx); err != nil {
+			pending = append(pending, name)
+		}
+	}
+
+	for name, svc := range m.instances {
+		svc.Disconnect()
+		delete(m.instances, name)
+	}
+
+	if err := m.store.Close(); err != nil {
+		fmt.Println("⚠️ gagal menutup session store:", err)
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("pekerjaan tertunda belum selesai: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
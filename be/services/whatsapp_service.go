@@ -6,10 +6,11 @@ import (
 	"sync"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/ubaidillahfaris/whatsapp.git/db"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/adapters/whatsapp/sessionstore"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/core/domain"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/pkg/inflight"
 	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
 )
@@ -21,13 +22,21 @@ type WhatsAppService struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	DeviceName string
+	store      sessionstore.Backend
 
 	qrMu     sync.Mutex
-	latestQR string
+	latestQR string // Kode QR yang berlaku; kosong bila tidak ada login yang menunggu scan
+
+	// Kebijakan presence dari registry device
+	presenceMu     sync.RWMutex
+	presencePolicy domain.PresencePolicy
 
 	IsConnected bool
 	ConnectedMu sync.Mutex
 	sem         chan struct{}
+
+	// Pesan yang sedang diproses dan pengiriman yang sedang berjalan
+	work inflight.Tracker
 }
 
 type ContactInfo struct {
@@ -51,21 +60,11 @@ type GroupSummary struct {
 func (m *WhatsAppManager) NewWhatsAppService(parent context.Context, deviceName string) (*WhatsAppService, error) {
 	ctx, cancel := context.WithCancel(parent)
 
-	dbPath := fmt.Sprintf("file:./stores/%s_store.db?_foreign_keys=on", deviceName)
-	container, err := sqlstore.New(ctx, "sqlite3", dbPath, waLog.Stdout("DB-"+deviceName, "ERROR", true))
-	if err != nil {
-		cancel()
-		return nil, fmt.Errorf("gagal buat sqlstore: %w", err)
-	}
-
-	deviceStore, err := container.GetFirstDevice(ctx)
+	deviceStore, err := m.store.Open(ctx, deviceName)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("gagal ambil device: %w", err)
 	}
-	if deviceStore == nil {
-		deviceStore = container.NewDevice()
-	}
 
 	clientLog := waLog.Stdout("Client-"+deviceName, "INFO", true)
 	client := whatsmeow.NewClient(deviceStore, clientLog)
@@ -75,6 +74,7 @@ func (m *WhatsAppManager) NewWhatsAppService(parent context.Context, deviceName
 		ctx:        ctx,
 		cancel:     cancel,
 		DeviceName: deviceName,
+		store:      m.store,
 		sem:        make(chan struct{}, 10),
 	}
 
@@ -84,6 +84,20 @@ func (m *WhatsAppManager) NewWhatsAppService(parent context.Context, deviceName
 	return svc, nil
 }
 
+// SetPresencePolicy menyamakan kebijakan presence dengan data device di registry
+func (w *WhatsAppService) SetPresencePolicy(policy domain.PresencePolicy) {
+	w.presenceMu.Lock()
+	w.presencePolicy = policy
+	w.presenceMu.Unlock()
+}
+
+// PresencePolicy mengembalikan kebijakan presence device
+func (w *WhatsAppService) PresencePolicy() domain.PresencePolicy {
+	w.presenceMu.RLock()
+	defer w.presenceMu.RUnlock()
+	return w.presencePolicy
+}
+
 func (w *WhatsAppService) registerEventHandlers() {
 	w.Client.AddEventHandler(func(evt interface{}) {
 		switch v := evt.(type) {
@@ -93,6 +107,12 @@ func (w *WhatsAppService) registerEventHandlers() {
 			w.ConnectedMu.Unlock()
 			fmt.Printf("🟢 [%s] Connected\n", w.DeviceName)
 
+			if w.Client.Store.ID != nil {
+				if err := w.store.Bind(w.ctx, w.DeviceName, *w.Client.Store.ID); err != nil {
+					fmt.Printf("⚠️ [%s] gagal simpan JID ke session store: %v\n", w.DeviceName, err)
+				}
+			}
+
 		case *events.Disconnected:
 			w.ConnectedMu.Lock()
 			w.IsConnected = false
@@ -105,7 +125,9 @@ func (w *WhatsAppService) registerEventHandlers() {
 				msg := v.Message.GetConversation()
 				fmt.Printf("📩 [%s] Pesan dari %s: %s\n", w.DeviceName, sender, msg)
 
+				w.work.Start()
 				go func() {
+					defer w.work.Done()
 					w.sem <- struct{}{}
 					defer func() { <-w.sem }()
 					w.HandleIncomingMessage(sender, msg)
@@ -115,6 +137,8 @@ func (w *WhatsAppService) registerEventHandlers() {
 	})
 }
 
+// GenerateQR mengembalikan kode QR yang sedang berlaku. WhatsApp mengganti
+// kode secara berkala; bila semua kode sudah kedaluwarsa, login baru dimulai.
 func (w *WhatsAppService) GenerateQR() (string, error) {
 	w.qrMu.Lock()
 	defer w.qrMu.Unlock()
@@ -127,7 +151,10 @@ func (w *WhatsAppService) GenerateQR() (string, error) {
 		return w.latestQR, nil
 	}
 
-	qrChan, _ := w.Client.GetQRChannel(w.ctx)
+	qrChan, err := w.Client.GetQRChannel(w.ctx)
+	if err != nil {
+		return "", fmt.Errorf("[%s] gagal menyiapkan QR: %w", w.DeviceName, err)
+	}
 
 	if err := w.Client.Connect(); err != nil {
 		return "", fmt.Errorf("[%s] gagal connect: %w", w.DeviceName, err)
@@ -135,16 +162,36 @@ func (w *WhatsAppService) GenerateQR() (string, error) {
 
 	select {
 	case evt := <-qrChan:
-		if evt.Event == "code" {
-			w.latestQR = evt.Code
-			return evt.Code, nil
+		if evt.Event != whatsmeow.QRChannelEventCode {
+			return "", fmt.Errorf("[%s] event tak dikenal: %s", w.DeviceName, evt.Event)
 		}
-		return "", fmt.Errorf("[%s] event tak dikenal: %s", w.DeviceName, evt.Event)
+		w.latestQR = evt.Code
+		go w.followQR(qrChan)
+		return evt.Code, nil
 	case <-time.After(30 * time.Second):
 		return "", fmt.Errorf("[%s] timeout menunggu QR", w.DeviceName)
 	}
 }
 
+// followQR memperbarui kode QR setiap kali WhatsApp menggantinya, dan
+// mengosongkannya begitu login berhasil, gagal atau kedaluwarsa
+func (w *WhatsAppService) followQR(qrChan <-chan whatsmeow.QRChannelItem) {
+	for evt := range qrChan {
+		w.qrMu.Lock()
+		if evt.Event == whatsmeow.QRChannelEventCode {
+			w.latestQR = evt.Code
+		} else {
+			w.latestQR = ""
+			fmt.Printf("🔑 [%s] Sesi QR berakhir: %s\n", w.DeviceName, evt.Event)
+		}
+		w.qrMu.Unlock()
+	}
+
+	w.qrMu.Lock()
+	w.latestQR = ""
+	w.qrMu.Unlock()
+}
+
 func (w *WhatsAppService) LatestQR() string {
 	return w.latestQR
 }
@@ -162,6 +209,12 @@ func (w *WhatsAppService) Status() string {
 	return "disconnected"
 }
 
+// Drain menunggu pesan yang sudah diterima selesai diproses dan pengiriman
+// yang sedang berjalan selesai, paling lama sampai ctx berakhir
+func (w *WhatsAppService) Drain(ctx context.Context) error {
+	return w.work.Wait(ctx)
+}
+
 func (w *WhatsAppService) Disconnect() {
 	defer func() {
 		if r := recover(); r != nil {
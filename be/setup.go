@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/ubaidillahfaris/whatsapp.git/db"
+	"github.com/ubaidillahfaris/whatsapp.git/internal/app"
 	"github.com/ubaidillahfaris/whatsapp.git/routes"
-	"github.com/ubaidillahfaris/whatsapp.git/services"
 )
 
-// setup mengembalikan *gin.Engine dan error
-func setup() (*gin.Engine, error) {
+// application berisi router beserta komponen yang harus ditutup saat shutdown
+type application struct {
+	router    *gin.Engine
+	container *app.Container
+	mongo     *db.MongoService
+}
+
+// setup menyiapkan router dan container. WhatsAppManager lama tidak dibuat:
+// manager milik container yang melayani semua device.
+func setup(ctx context.Context) (*application, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using system environment")
 	}
@@ -22,7 +31,10 @@ func setup() (*gin.Engine, error) {
 		return nil, err
 	}
 
-	manager := services.GetWhatsAppManager()
+	container, err := app.NewContainer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	r := gin.Default()
 	config := cors.DefaultConfig()
@@ -36,9 +48,22 @@ func setup() (*gin.Engine, error) {
 	r.Use(cors.New(config))
 	r.OPTIONS("/*path", func(c *gin.Context) { c.Status(200) })
 
-	// Pass nil for container to maintain backward compatibility
-	// TODO: Migrate to container-based initialization for full Clean Architecture support
-	routes.RegisterRoutes(r, mongo, manager, nil)
+	routes.RegisterRoutes(r, mongo, nil, container)
 
-	return r, nil
+	return &application{
+		router:    r,
+		container: container,
+		mongo:     mongo,
+	}, nil
+}
+
+// shutdown menutup container lalu MongoDB, paling lama sampai ctx berakhir
+func (a *application) shutdown(ctx context.Context) {
+	if err := a.container.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Container shutdown: %v", err)
+	}
+
+	if err := a.mongo.Close(ctx); err != nil {
+		log.Printf("⚠️  Failed to disconnect MongoDB: %v", err)
+	}
 }
@@ -7,7 +7,8 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateToken(username string) (string, error) {
+// GenerateToken membuat JWT untuk user; organization boleh kosong
+func GenerateToken(username, organization string) (string, error) {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		secret = "fallback_supersecret" // fallback jika env belum di-set
@@ -17,6 +18,9 @@ func GenerateToken(username string) (string, error) {
 		"username": username,
 		"exp":      time.Now().Add(time.Hour * 24).Unix(),
 	}
+	if organization != "" {
+		claims["organization"] = organization
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))